@@ -4,50 +4,31 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 
 	"github.com/joho/godotenv"
+	"github.com/mateuse/desktop-builder-backend/internal/handlers"
 	"github.com/mateuse/desktop-builder-backend/internal/routes"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
 	"github.com/mateuse/desktop-builder-backend/internal/utils"
 )
 
-func withCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get allowed origins from environment variable or use defaults
-		allowedOriginsStr := os.Getenv("CORS_ALLOWED_ORIGINS")
-		if allowedOriginsStr == "" {
-			allowedOriginsStr = "http://localhost:3000,http://localhost:3001,http://localhost:3002,http://localhost:5173"
-		}
-
-		// Parse allowed origins into a slice
-		allowedOrigins := strings.Split(allowedOriginsStr, ",")
-
-		// Get the origin from the request
-		requestOrigin := r.Header.Get("Origin")
+// reloadRulesOnSIGHUP reloads the compatibility rules whenever the process receives SIGHUP.
+// A failed reload keeps the previously loaded rules active.
+func reloadRulesOnSIGHUP() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
 
-		// Check if the request origin is in our allowed list
-		originAllowed := false
-		for _, origin := range allowedOrigins {
-			if strings.TrimSpace(origin) == requestOrigin {
-				originAllowed = true
-				break
+	go func() {
+		for range signals {
+			if err := services.ReloadCompatibilityRules(); err != nil {
+				log.Printf("Failed to reload compatibility rules, keeping previous rules: %v", err)
+				continue
 			}
+			log.Println("Reloaded compatibility rules")
 		}
-
-		// Set CORS headers
-		if originAllowed {
-			w.Header().Set("Access-Control-Allow-Origin", requestOrigin)
-		}
-		w.Header().Set("Vary", "Origin")
-		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
+	}()
 }
 
 func main() {
@@ -76,9 +57,18 @@ func main() {
 		}
 	}()
 
+	// Load compatibility rule definitions; only an explicit COMPATIBILITY_RULES_DIR is required to load
+	if err := services.InitializeCompatibilityRules(); err != nil {
+		log.Fatalf("Failed to load compatibility rules: %v", err)
+	}
+	reloadRulesOnSIGHUP()
+
 	mux := http.NewServeMux()
 	routes.RegisterHealthRoutes(mux)
 	routes.RegisterComponentRoutes(mux)
+	routes.RegisterBuildRoutes(mux)
+	routes.RegisterSharedRoutes(mux)
+	routes.RegisterTemplateRoutes(mux)
 
 	// Get port from environment variable or use default
 	port := os.Getenv("PORT")
@@ -87,7 +77,7 @@ func main() {
 	}
 
 	log.Printf("Backend is running on port %s", port)
-	if err := http.ListenAndServe(":"+port, withCORS(mux)); err != nil {
+	if err := http.ListenAndServe(":"+port, handlers.WithCORS(mux)); err != nil {
 		log.Fatal(err)
 	}
 }
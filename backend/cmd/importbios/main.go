@@ -0,0 +1,53 @@
+// Command importbios loads minimum BIOS versions for motherboard/CPU pairs from a CSV file.
+//
+// Usage:
+//
+//	go run ./cmd/importbios -file requirements.csv
+//
+// The CSV header must contain motherboard_id, cpu_id and min_bios_version; notes is optional.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+func main() {
+	file := flag.String("file", "", "path of the CSV file to import")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using system environment variables")
+	}
+
+	if err := utils.InitializeDatabase(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() {
+		if err := utils.CloseDatabase(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *file, err)
+	}
+	defer f.Close()
+
+	count, err := services.ImportBIOSRequirementsCSV(f)
+	if err != nil {
+		log.Fatalf("Failed to import BIOS requirements: %v", err)
+	}
+	log.Printf("Imported %d BIOS requirements from %s", count, *file)
+}
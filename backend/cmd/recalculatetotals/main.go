@@ -0,0 +1,59 @@
+// Command recalculatetotals refreshes the stored total price of builds after prices or exchange rates change.
+//
+// Usage:
+//
+//	go run ./cmd/recalculatetotals [-components 12,34]
+//
+// Without -components every build is recalculated.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+func main() {
+	components := flag.String("components", "", "comma separated IDs of components whose prices changed")
+	flag.Parse()
+
+	componentIDs := []int64{}
+	for _, raw := range strings.Split(*components, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Printf("Invalid component ID %q", raw)
+			flag.Usage()
+			os.Exit(2)
+		}
+		componentIDs = append(componentIDs, id)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using system environment variables")
+	}
+
+	if err := utils.InitializeDatabase(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() {
+		if err := utils.CloseDatabase(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	count, err := services.RecalculateBuildTotals(componentIDs...)
+	if err != nil {
+		log.Fatalf("Failed to recalculate build totals after %d builds: %v", count, err)
+	}
+	log.Printf("Recalculated the totals of %d builds", count)
+}
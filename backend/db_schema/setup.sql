@@ -71,6 +71,12 @@ CREATE TABLE prices (
   created_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
+CREATE TABLE exchange_rates (
+  currency TEXT PRIMARY KEY,
+  usd_rate NUMERIC(18,8) NOT NULL CHECK (usd_rate > 0),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 CREATE TABLE user_builds (
   id BIGSERIAL PRIMARY KEY,
   user_id TEXT NOT NULL,
@@ -81,8 +87,16 @@ CREATE TABLE user_builds (
   total_price DECIMAL(10,2),
   currency TEXT DEFAULT 'USD',
   region TEXT DEFAULT 'USA',
+  parent_build_id BIGINT REFERENCES user_builds(id) ON DELETE SET NULL,
+  fork_count INTEGER NOT NULL DEFAULT 0,
+  use_case TEXT,
+  like_count INTEGER NOT NULL DEFAULT 0,
+  comment_count INTEGER NOT NULL DEFAULT 0,
+  rating_count INTEGER NOT NULL DEFAULT 0,
+  rating_average NUMERIC(3,2),
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
-  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revision INTEGER NOT NULL DEFAULT 1
 );
 
 CREATE TABLE build_components (
@@ -96,6 +110,95 @@ CREATE TABLE build_components (
   UNIQUE(build_id, component_id)
 );
 
+CREATE TABLE build_shares (
+  id BIGSERIAL PRIMARY KEY,
+  build_id BIGINT NOT NULL REFERENCES user_builds(id) ON DELETE CASCADE,
+  token_hash TEXT NOT NULL UNIQUE,
+  access TEXT NOT NULL CHECK (access IN ('read', 'edit')),
+  created_by TEXT NOT NULL,
+  expires_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE build_collaborators (
+  id BIGSERIAL PRIMARY KEY,
+  build_id BIGINT NOT NULL REFERENCES user_builds(id) ON DELETE CASCADE,
+  user_id TEXT NOT NULL,
+  role TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+  invited_by TEXT NOT NULL,
+  accepted_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE(build_id, user_id)
+);
+
+CREATE TABLE build_likes (
+  build_id BIGINT NOT NULL REFERENCES user_builds(id) ON DELETE CASCADE,
+  user_id TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (build_id, user_id)
+);
+
+CREATE TABLE build_ratings (
+  build_id BIGINT NOT NULL REFERENCES user_builds(id) ON DELETE CASCADE,
+  user_id TEXT NOT NULL,
+  rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (build_id, user_id)
+);
+
+CREATE TABLE build_comments (
+  id BIGSERIAL PRIMARY KEY,
+  build_id BIGINT NOT NULL REFERENCES user_builds(id) ON DELETE CASCADE,
+  parent_id BIGINT REFERENCES build_comments(id) ON DELETE CASCADE,
+  user_id TEXT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  edited_at TIMESTAMPTZ,
+  deleted_at TIMESTAMPTZ,
+  deleted_by TEXT
+);
+
+CREATE TABLE build_versions (
+  id BIGSERIAL PRIMARY KEY,
+  build_id BIGINT NOT NULL REFERENCES user_builds(id) ON DELETE CASCADE,
+  version INTEGER NOT NULL,
+  parts JSONB NOT NULL,
+  total_price DECIMAL(10,2),
+  currency TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  created_by TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE(build_id, version)
+);
+
+CREATE TABLE build_templates (
+  id BIGSERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  description TEXT,
+  use_case TEXT NOT NULL,
+  resolution TEXT,
+  target_budget DECIMAL(10,2) NOT NULL CHECK (target_budget > 0),
+  currency TEXT NOT NULL DEFAULT 'USD',
+  slots JSONB NOT NULL,
+  created_by TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE bios_requirements (
+  id BIGSERIAL PRIMARY KEY,
+  motherboard_id BIGINT NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+  cpu_id BIGINT NOT NULL REFERENCES components(id) ON DELETE CASCADE,
+  min_bios_version TEXT NOT NULL,
+  notes TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE(motherboard_id, cpu_id)
+);
+
 -- Create indexes
 CREATE INDEX idx_components_category ON components(category);
 CREATE INDEX idx_components_specs_gin ON components USING GIN (specs);
@@ -107,9 +210,17 @@ CREATE INDEX idx_prices_in_stock ON prices(in_stock);
 CREATE INDEX idx_user_builds_user_id ON user_builds(user_id);
 CREATE INDEX idx_user_builds_public ON user_builds(is_public);
 CREATE INDEX idx_user_builds_complete ON user_builds(is_complete);
+CREATE INDEX idx_user_builds_parent_build_id ON user_builds(parent_build_id);
+CREATE INDEX idx_user_builds_public_created ON user_builds(created_at DESC, id DESC) WHERE is_public;
+CREATE INDEX idx_user_builds_use_case ON user_builds(use_case);
 
 CREATE INDEX idx_build_components_build_id ON build_components(build_id);
 
+CREATE INDEX idx_build_shares_build_id ON build_shares(build_id);
+CREATE INDEX idx_build_collaborators_user_id ON build_collaborators(user_id);
+CREATE INDEX idx_build_comments_build_id ON build_comments(build_id, created_at);
+CREATE INDEX idx_build_comments_parent_id ON build_comments(parent_id);
+
 -- Grant permissions to database user (replace 'your_db_user' with actual username)
 -- Note: These grants assume the user exists. If running as superuser, these will be applied.
 -- If you get permission errors, run the fix_permissions.sql script as postgres user.
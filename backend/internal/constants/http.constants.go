@@ -1,11 +1,47 @@
 package constants
 
 const (
-	METHOD_NOT_ALLOWED_MESSAGE    = "Method not allowed"
-	INTERNAL_SERVER_ERROR_MESSAGE = "Internal server error"
-	BAD_REQUEST_MESSAGE           = "Bad request"
-	COMPONENT_NOT_FOUND_MESSAGE   = "Component not found"
-	PAGE_NOT_FOUND_MESSAGE        = "Page not found"
-	PAGE_NUMBER_NOT_FOUND_MESSAGE = "Page number not found"
-	PAGE_NUMBER_INVALID_MESSAGE   = "Page number is invalid"
+	METHOD_NOT_ALLOWED_MESSAGE     = "Method not allowed"
+	INTERNAL_SERVER_ERROR_MESSAGE  = "Internal server error"
+	BAD_REQUEST_MESSAGE            = "Bad request"
+	COMPONENT_NOT_FOUND_MESSAGE    = "Component not found"
+	PAGE_NOT_FOUND_MESSAGE         = "Page not found"
+	PAGE_NUMBER_NOT_FOUND_MESSAGE  = "Page number not found"
+	PAGE_NUMBER_INVALID_MESSAGE    = "Page number is invalid"
+	BUILD_NOT_FOUND_MESSAGE        = "Build not found"
+	BUILD_ID_INVALID_MESSAGE       = "Build ID is invalid"
+	CATEGORY_REQUIRED_MESSAGE      = "Category is required"
+	USE_CASE_INVALID_MESSAGE       = "Use case is invalid"
+	RESOLUTION_INVALID_MESSAGE     = "Resolution is invalid"
+	USER_ID_REQUIRED_MESSAGE       = "X-User-ID header is required"
+	BUILD_FORBIDDEN_MESSAGE        = "Build belongs to another user"
+	REQUEST_BODY_INVALID_MESSAGE   = "Request body is invalid"
+	BUILD_FILTER_INVALID_MESSAGE   = "Build filter is invalid"
+	COMPONENT_ID_INVALID_MESSAGE   = "Component ID is invalid"
+	BUILD_PART_NOT_FOUND_MESSAGE   = "Component is not part of the build"
+	SHARE_ID_INVALID_MESSAGE       = "Share ID is invalid"
+	SHARE_NOT_FOUND_MESSAGE        = "Share link not found or expired"
+	SHARE_READ_ONLY_MESSAGE        = "Share link is read-only"
+	VERSION_INVALID_MESSAGE        = "Build version is invalid"
+	VERSION_NOT_FOUND_MESSAGE      = "Build version not found"
+	EXPORT_FORMAT_INVALID_MESSAGE  = "Export format is not supported"
+	BUDGET_TOO_LOW_MESSAGE         = "No compatible build fits the budget"
+	COMMENT_ID_INVALID_MESSAGE     = "Comment ID is invalid"
+	COMMENT_NOT_FOUND_MESSAGE      = "Comment not found"
+	COMMENT_FORBIDDEN_MESSAGE      = "Comment belongs to another user"
+	MAX_RETAILERS_INVALID_MESSAGE  = "max_retailers must be a positive integer"
+	TOLERANCE_INVALID_MESSAGE      = "tolerance must be a percentage between 0 and 50"
+	BUDGET_INVALID_MESSAGE         = "budget must be a positive number"
+	ADMIN_REQUIRED_MESSAGE         = "Only administrators can manage templates"
+	TEMPLATE_ID_INVALID_MESSAGE    = "Template ID is invalid"
+	TEMPLATE_NOT_FOUND_MESSAGE     = "Template not found"
+	TEMPLATE_UNFILLABLE_MESSAGE    = "No priced part matches a required template slot"
+	BUILD_CONFLICT_MESSAGE         = "Build was changed by someone else; reload it and try again"
+	IF_MATCH_REQUIRED_MESSAGE      = "If-Match header with the build's ETag is required"
+	COLLABORATOR_NOT_FOUND_MESSAGE = "Collaborator not found"
+	COLLABORATOR_EXISTS_MESSAGE    = "User is already a collaborator on the build"
+	INVITATION_NOT_FOUND_MESSAGE   = "Invitation not found"
+
+	// Identifies the caller until authentication is in place
+	USER_ID_HEADER = "X-User-ID"
 )
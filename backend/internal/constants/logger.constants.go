@@ -17,6 +17,165 @@ const (
 	HANDLER_GET_ALL_COMPONENTS_START           = "Getting all components"
 	HANDLER_GET_ALL_COMPONENTS_ERROR           = "Error getting all components"
 	HANDLER_GET_ALL_COMPONENTS_SUCCESS         = "Successfully retrieved all components"
+	HANDLER_GET_COMPATIBLE_COMPONENTS_START    = "Getting components compatible with build %d - Category: %s"
+	HANDLER_GET_COMPATIBLE_COMPONENTS_ERROR    = "Error getting components compatible with build %d - Category: %s"
+	HANDLER_GET_COMPATIBLE_COMPONENTS_SUCCESS  = "Successfully retrieved components compatible with build %d - Category: %s"
+	HANDLER_INVALID_BUILD_ID                   = "Invalid build ID: %s"
+	HANDLER_CHECK_BUILD_COMPATIBILITY_START    = "Checking compatibility of build: %d"
+	HANDLER_CHECK_BUILD_COMPATIBILITY_ERROR    = "Error checking compatibility of build: %d"
+	HANDLER_CHECK_BUILD_COMPATIBILITY_SUCCESS  = "Successfully checked compatibility of build: %d"
+	HANDLER_GET_STORAGE_ALLOCATION_START       = "Allocating storage for build: %d"
+	HANDLER_GET_STORAGE_ALLOCATION_ERROR       = "Error allocating storage for build: %d"
+	HANDLER_GET_STORAGE_ALLOCATION_SUCCESS     = "Successfully allocated storage for build: %d"
+	HANDLER_ANALYZE_BOTTLENECK_START           = "Analyzing bottleneck of build: %d"
+	HANDLER_ANALYZE_BOTTLENECK_ERROR           = "Error analyzing bottleneck of build: %d"
+	HANDLER_ANALYZE_BOTTLENECK_SUCCESS         = "Successfully analyzed bottleneck of build: %d"
+	HANDLER_GET_POWER_CONNECTORS_START         = "Checking power connectors of build: %d"
+	HANDLER_GET_POWER_CONNECTORS_ERROR         = "Error checking power connectors of build: %d"
+	HANDLER_LIST_BUILDS_START                  = "Listing builds"
+	HANDLER_LIST_BUILDS_ERROR                  = "Error listing builds"
+	HANDLER_LIST_BUILDS_SUCCESS                = "Successfully listed builds"
+	HANDLER_LIST_PUBLIC_BUILDS_START           = "Listing public builds sorted by %s"
+	HANDLER_LIST_PUBLIC_BUILDS_ERROR           = "Error listing public builds"
+	HANDLER_LIST_PUBLIC_BUILDS_SUCCESS         = "Successfully listed %d public builds"
+	HANDLER_CREATE_BUILD_START                 = "Creating build for user: %s"
+	HANDLER_CREATE_BUILD_ERROR                 = "Error creating build for user: %s"
+	HANDLER_CREATE_BUILD_SUCCESS               = "Successfully created build: %d"
+	HANDLER_GET_BUILD_START                    = "Getting build: %d"
+	HANDLER_GET_BUILD_ERROR                    = "Error getting build: %d"
+	HANDLER_GET_BUILD_SUCCESS                  = "Successfully retrieved build: %d"
+	HANDLER_UPDATE_BUILD_START                 = "Updating build: %d"
+	HANDLER_UPDATE_BUILD_ERROR                 = "Error updating build: %d"
+	HANDLER_UPDATE_BUILD_SUCCESS               = "Successfully updated build: %d"
+	HANDLER_DELETE_BUILD_START                 = "Deleting build: %d"
+	HANDLER_DELETE_BUILD_ERROR                 = "Error deleting build: %d"
+	HANDLER_DELETE_BUILD_SUCCESS               = "Successfully deleted build: %d"
+	HANDLER_FORK_BUILD_START                   = "Forking build: %d"
+	HANDLER_FORK_BUILD_ERROR                   = "Error forking build: %d"
+	HANDLER_FORK_BUILD_SUCCESS                 = "Successfully forked build %d into build: %d"
+	HANDLER_SET_BUILD_LIKE_START               = "Setting like of build: %d"
+	HANDLER_SET_BUILD_LIKE_ERROR               = "Error setting like of build: %d"
+	HANDLER_SET_BUILD_LIKE_SUCCESS             = "Successfully set like of build: %d"
+	HANDLER_SET_BUILD_RATING_START             = "Setting rating of build: %d"
+	HANDLER_SET_BUILD_RATING_ERROR             = "Error setting rating of build: %d"
+	HANDLER_SET_BUILD_RATING_SUCCESS           = "Successfully set rating of build: %d"
+	HANDLER_LIST_BUILD_COMMENTS_START          = "Listing comments of build: %d"
+	HANDLER_LIST_BUILD_COMMENTS_ERROR          = "Error listing comments of build: %d"
+	HANDLER_LIST_BUILD_COMMENTS_SUCCESS        = "Successfully listed comments of build: %d"
+	HANDLER_CREATE_BUILD_COMMENT_START         = "Commenting on build: %d"
+	HANDLER_CREATE_BUILD_COMMENT_ERROR         = "Error commenting on build: %d"
+	HANDLER_CREATE_BUILD_COMMENT_SUCCESS       = "Successfully created comment %d on build: %d"
+	HANDLER_UPDATE_BUILD_COMMENT_START         = "Editing comment: %d"
+	HANDLER_UPDATE_BUILD_COMMENT_ERROR         = "Error editing comment: %d"
+	HANDLER_UPDATE_BUILD_COMMENT_SUCCESS       = "Successfully edited comment: %d"
+	HANDLER_DELETE_BUILD_COMMENT_START         = "Deleting comment: %d"
+	HANDLER_DELETE_BUILD_COMMENT_ERROR         = "Error deleting comment: %d"
+	HANDLER_DELETE_BUILD_COMMENT_SUCCESS       = "Successfully deleted comment: %d"
+	HANDLER_INVALID_COMMENT_ID                 = "Invalid comment ID: %s"
+	HANDLER_OPTIMIZE_BUILD_PURCHASE_START      = "Optimizing purchase of build: %d"
+	HANDLER_OPTIMIZE_BUILD_PURCHASE_ERROR      = "Error optimizing purchase of build: %d"
+	HANDLER_OPTIMIZE_BUILD_PURCHASE_SUCCESS    = "Successfully optimized purchase of build: %d"
+	HANDLER_FIND_CHEAPER_ALTERNATIVES_START    = "Finding cheaper alternatives for build: %d"
+	HANDLER_FIND_CHEAPER_ALTERNATIVES_ERROR    = "Error finding cheaper alternatives for build: %d"
+	HANDLER_FIND_CHEAPER_ALTERNATIVES_SUCCESS  = "Successfully found cheaper alternatives for build: %d"
+	HANDLER_FIND_BUILD_UPGRADES_START          = "Finding upgrades for build: %d"
+	HANDLER_FIND_BUILD_UPGRADES_ERROR          = "Error finding upgrades for build: %d"
+	HANDLER_FIND_BUILD_UPGRADES_SUCCESS        = "Successfully found upgrades for build: %d"
+	HANDLER_LIST_BUILD_TEMPLATES_START         = "Listing build templates"
+	HANDLER_LIST_BUILD_TEMPLATES_ERROR         = "Error listing build templates"
+	HANDLER_LIST_BUILD_TEMPLATES_SUCCESS       = "Successfully listed %d build templates"
+	HANDLER_GET_BUILD_TEMPLATE_START           = "Getting build template: %d"
+	HANDLER_GET_BUILD_TEMPLATE_ERROR           = "Error getting build template: %d"
+	HANDLER_GET_BUILD_TEMPLATE_SUCCESS         = "Successfully retrieved build template: %d"
+	HANDLER_CREATE_BUILD_TEMPLATE_START        = "Creating build template"
+	HANDLER_CREATE_BUILD_TEMPLATE_ERROR        = "Error creating build template"
+	HANDLER_CREATE_BUILD_TEMPLATE_SUCCESS      = "Successfully created build template: %d"
+	HANDLER_UPDATE_BUILD_TEMPLATE_START        = "Updating build template: %d"
+	HANDLER_UPDATE_BUILD_TEMPLATE_ERROR        = "Error updating build template: %d"
+	HANDLER_UPDATE_BUILD_TEMPLATE_SUCCESS      = "Successfully updated build template: %d"
+	HANDLER_DELETE_BUILD_TEMPLATE_START        = "Deleting build template: %d"
+	HANDLER_DELETE_BUILD_TEMPLATE_ERROR        = "Error deleting build template: %d"
+	HANDLER_DELETE_BUILD_TEMPLATE_SUCCESS      = "Successfully deleted build template: %d"
+	HANDLER_CREATE_BUILD_FROM_TEMPLATE_START   = "Creating build from template: %d"
+	HANDLER_CREATE_BUILD_FROM_TEMPLATE_ERROR   = "Error creating build from template: %d"
+	HANDLER_CREATE_BUILD_FROM_TEMPLATE_SUCCESS = "Successfully created build %d from template: %d"
+	HANDLER_INVALID_TEMPLATE_ID                = "Invalid template ID: %s"
+	HANDLER_ADMIN_REQUIRED                     = "User %s is not an administrator"
+	HANDLER_GET_BUILD_LINEAGE_START            = "Getting fork lineage of build: %d"
+	HANDLER_GET_BUILD_LINEAGE_ERROR            = "Error getting fork lineage of build: %d"
+	HANDLER_GET_BUILD_LINEAGE_SUCCESS          = "Successfully retrieved fork lineage of build: %d"
+	HANDLER_CREATE_BUILD_SHARE_START           = "Creating share link for build: %d"
+	HANDLER_CREATE_BUILD_SHARE_ERROR           = "Error creating share link for build: %d"
+	HANDLER_CREATE_BUILD_SHARE_SUCCESS         = "Successfully created share link %d for build: %d"
+	HANDLER_LIST_BUILD_SHARES_START            = "Listing share links of build: %d"
+	HANDLER_LIST_BUILD_SHARES_ERROR            = "Error listing share links of build: %d"
+	HANDLER_LIST_BUILD_SHARES_SUCCESS          = "Successfully listed share links of build: %d"
+	HANDLER_REVOKE_BUILD_SHARE_START           = "Revoking share link %d of build: %d"
+	HANDLER_REVOKE_BUILD_SHARE_ERROR           = "Error revoking share link of build: %d"
+	HANDLER_REVOKE_BUILD_SHARE_SUCCESS         = "Successfully revoked share link of build: %d"
+	HANDLER_INVALID_SHARE_ID                   = "Invalid share ID: %s"
+	HANDLER_SHARED_BUILD_START                 = "Handling %s on a shared build"
+	HANDLER_SHARED_BUILD_ERROR                 = "Error handling %s on a shared build"
+	HANDLER_SHARED_BUILD_SUCCESS               = "Successfully handled %s on a shared build"
+	HANDLER_LIST_BUILD_COLLABORATORS_START     = "Listing collaborators of build: %d"
+	HANDLER_LIST_BUILD_COLLABORATORS_ERROR     = "Error listing collaborators of build: %d"
+	HANDLER_LIST_BUILD_COLLABORATORS_SUCCESS   = "Successfully listed collaborators of build: %d"
+	HANDLER_INVITE_BUILD_COLLABORATOR_START    = "Inviting a collaborator to build: %d"
+	HANDLER_INVITE_BUILD_COLLABORATOR_ERROR    = "Error inviting a collaborator to build: %d"
+	HANDLER_INVITE_BUILD_COLLABORATOR_SUCCESS  = "Successfully invited collaborator %s to build: %d"
+	HANDLER_UPDATE_BUILD_COLLABORATOR_START    = "Changing the role of collaborator %s on build: %d"
+	HANDLER_UPDATE_BUILD_COLLABORATOR_ERROR    = "Error changing the role of a collaborator on build: %d"
+	HANDLER_UPDATE_BUILD_COLLABORATOR_SUCCESS  = "Successfully changed the role of collaborator %s on build: %d"
+	HANDLER_REMOVE_BUILD_COLLABORATOR_START    = "Removing collaborator %s from build: %d"
+	HANDLER_REMOVE_BUILD_COLLABORATOR_ERROR    = "Error removing a collaborator from build: %d"
+	HANDLER_REMOVE_BUILD_COLLABORATOR_SUCCESS  = "Successfully removed collaborator %s from build: %d"
+	HANDLER_LIST_BUILD_INVITATIONS_START       = "Listing build invitations of user: %s"
+	HANDLER_LIST_BUILD_INVITATIONS_ERROR       = "Error listing build invitations of user: %s"
+	HANDLER_LIST_BUILD_INVITATIONS_SUCCESS     = "Successfully listed %d build invitations of user: %s"
+	HANDLER_RESPOND_BUILD_INVITATION_START     = "Handling %s on the invitation to build: %d"
+	HANDLER_RESPOND_BUILD_INVITATION_ERROR     = "Error handling %s on the invitation to build: %d"
+	HANDLER_RESPOND_BUILD_INVITATION_SUCCESS   = "Successfully handled %s on the invitation to build: %d"
+	HANDLER_LIST_BUILD_VERSIONS_START          = "Listing versions of build: %d"
+	HANDLER_LIST_BUILD_VERSIONS_ERROR          = "Error listing versions of build: %d"
+	HANDLER_LIST_BUILD_VERSIONS_SUCCESS        = "Successfully listed versions of build: %d"
+	HANDLER_DIFF_BUILD_VERSIONS_START          = "Comparing versions %d and %d of build: %d"
+	HANDLER_DIFF_BUILD_VERSIONS_ERROR          = "Error comparing versions of build: %d"
+	HANDLER_DIFF_BUILD_VERSIONS_SUCCESS        = "Successfully compared versions of build: %d"
+	HANDLER_RESTORE_BUILD_VERSION_START        = "Restoring version %d of build: %d"
+	HANDLER_RESTORE_BUILD_VERSION_ERROR        = "Error restoring version of build: %d"
+	HANDLER_RESTORE_BUILD_VERSION_SUCCESS      = "Successfully restored version of build: %d"
+	HANDLER_INVALID_BUILD_VERSION              = "Invalid build version: %s"
+	HANDLER_EXPORT_BUILD_START                 = "Exporting build %d as %s"
+	HANDLER_EXPORT_BUILD_ERROR                 = "Error exporting build: %d"
+	HANDLER_EXPORT_BUILD_SUCCESS               = "Successfully exported build %d as %s"
+	HANDLER_RENDER_BUILD_SHEET_START           = "Rendering spec sheet for build: %d"
+	HANDLER_RENDER_BUILD_SHEET_ERROR           = "Error rendering spec sheet for build: %d"
+	HANDLER_RENDER_BUILD_SHEET_SUCCESS         = "Successfully rendered spec sheet for build: %d"
+	HANDLER_INVALID_EXPORT_FORMAT              = "Invalid export format: %s"
+	HANDLER_IMPORT_BUILD_START                 = "Importing a parts list for user: %s"
+	HANDLER_IMPORT_BUILD_ERROR                 = "Error importing a parts list for user: %s"
+	HANDLER_IMPORT_BUILD_SUCCESS               = "Successfully imported build %d with %d unmatched lines"
+	HANDLER_GENERATE_BUILD_START               = "Generating a %s build for a budget of %.2f"
+	HANDLER_GENERATE_BUILD_ERROR               = "Error generating a %s build"
+	HANDLER_GENERATE_BUILD_SUCCESS             = "Successfully generated a %s build with %d alternatives"
+	HANDLER_ADD_BUILD_PART_START               = "Adding component %d to build: %d"
+	HANDLER_ADD_BUILD_PART_ERROR               = "Error adding component to build: %d"
+	HANDLER_ADD_BUILD_PART_SUCCESS             = "Successfully added component to build: %d"
+	HANDLER_UPDATE_BUILD_PART_START            = "Updating component %d of build: %d"
+	HANDLER_UPDATE_BUILD_PART_ERROR            = "Error updating component of build: %d"
+	HANDLER_UPDATE_BUILD_PART_SUCCESS          = "Successfully updated component of build: %d"
+	HANDLER_REMOVE_BUILD_PART_START            = "Removing component %d from build: %d"
+	HANDLER_REMOVE_BUILD_PART_ERROR            = "Error removing component from build: %d"
+	HANDLER_REMOVE_BUILD_PART_SUCCESS          = "Successfully removed component from build: %d"
+	HANDLER_INVALID_COMPONENT_ID               = "Invalid component ID: %s"
+	HANDLER_INVALID_REQUEST_BODY               = "Invalid request body"
+	HANDLER_ANALYZE_COOLING_START              = "Analyzing cooling of build: %d"
+	HANDLER_ANALYZE_COOLING_ERROR              = "Error analyzing cooling of build: %d"
+	HANDLER_ANALYZE_COOLING_SUCCESS            = "Successfully analyzed cooling of build: %d"
+	HANDLER_GET_BUILD_COMPLETENESS_START       = "Checking completeness of build: %d"
+	HANDLER_GET_BUILD_COMPLETENESS_ERROR       = "Error checking completeness of build: %d"
+	HANDLER_GET_BUILD_COMPLETENESS_SUCCESS     = "Successfully checked completeness of build: %d"
+	HANDLER_GET_POWER_CONNECTORS_SUCCESS       = "Successfully checked power connectors of build: %d"
 
 	// Service log messages
 	SERVICE_GET_ALL_COMPONENTS_START           = "Service: Getting all components"
@@ -31,6 +190,153 @@ const (
 	SERVICE_GET_COMPONENT_BY_ID_START          = "Service: Getting component by ID: %s"
 	SERVICE_GET_COMPONENT_BY_ID_ERROR          = "Service: Error getting component by ID: %s"
 	SERVICE_GET_COMPONENT_BY_ID_SUCCESS        = "Service: Successfully retrieved component by ID: %s"
+	SERVICE_GET_COMPATIBLE_COMPONENTS_START    = "Service: Getting components compatible with build %d - Category: %s"
+	SERVICE_GET_COMPATIBLE_COMPONENTS_ERROR    = "Service: Error getting components compatible with build %d - Category: %s"
+	SERVICE_GET_COMPATIBLE_COMPONENTS_SUCCESS  = "Service: Successfully retrieved %d components compatible with build %d - Category: %s"
+	SERVICE_CHECK_BUILD_COMPATIBILITY_START    = "Service: Checking compatibility of build %d"
+	SERVICE_CHECK_BUILD_COMPATIBILITY_ERROR    = "Service: Error checking compatibility of build %d"
+	SERVICE_CHECK_BUILD_COMPATIBILITY_SUCCESS  = "Service: Checked compatibility of build %d, found %d issues"
+	SERVICE_GET_STORAGE_ALLOCATION_START       = "Service: Allocating storage for build %d"
+	SERVICE_GET_STORAGE_ALLOCATION_ERROR       = "Service: Error allocating storage for build %d"
+	SERVICE_GET_STORAGE_ALLOCATION_SUCCESS     = "Service: Allocated storage for build %d, %d placed, %d unallocated"
+	SERVICE_ANALYZE_BOTTLENECK_START           = "Service: Analyzing bottleneck of build %d - Use case: %s, Resolution: %s"
+	SERVICE_ANALYZE_BOTTLENECK_ERROR           = "Service: Error analyzing bottleneck of build %d"
+	SERVICE_ANALYZE_BOTTLENECK_SUCCESS         = "Service: Analyzed bottleneck of build %d - Limiter: %s, Balance: %.1f"
+	SERVICE_GET_POWER_CONNECTORS_START         = "Service: Checking power connectors of build %d"
+	SERVICE_GET_POWER_CONNECTORS_ERROR         = "Service: Error checking power connectors of build %d"
+	SERVICE_GET_POWER_CONNECTORS_SUCCESS       = "Service: Checked power connectors of build %d, %d checks, %d issues"
+	SERVICE_LIST_BUILDS_START                  = "Service: Listing builds for viewer %s"
+	SERVICE_LIST_BUILDS_ERROR                  = "Service: Error listing builds for viewer %s"
+	SERVICE_LIST_BUILDS_SUCCESS                = "Service: Listed %d builds for viewer %s"
+	SERVICE_LIST_PUBLIC_BUILDS_START           = "Service: Listing public builds sorted by %s"
+	SERVICE_LIST_PUBLIC_BUILDS_ERROR           = "Service: Error listing public builds"
+	SERVICE_LIST_PUBLIC_BUILDS_SUCCESS         = "Service: Listed %d public builds"
+	SERVICE_CREATE_BUILD_START                 = "Service: Creating build for user %s"
+	SERVICE_CREATE_BUILD_ERROR                 = "Service: Error creating build for user %s"
+	SERVICE_CREATE_BUILD_SUCCESS               = "Service: Created build %d for user %s"
+	SERVICE_GET_BUILD_START                    = "Service: Getting build %d for viewer %s"
+	SERVICE_GET_BUILD_ERROR                    = "Service: Error getting build %d for viewer %s"
+	SERVICE_GET_BUILD_SUCCESS                  = "Service: Retrieved build %d for viewer %s"
+	SERVICE_UPDATE_BUILD_START                 = "Service: Updating build %d for user %s"
+	SERVICE_UPDATE_BUILD_ERROR                 = "Service: Error updating build %d for user %s"
+	SERVICE_UPDATE_BUILD_SUCCESS               = "Service: Updated build %d for user %s"
+	SERVICE_DELETE_BUILD_START                 = "Service: Deleting build %d for user %s"
+	SERVICE_DELETE_BUILD_ERROR                 = "Service: Error deleting build %d for user %s"
+	SERVICE_DELETE_BUILD_SUCCESS               = "Service: Deleted build %d for user %s"
+	SERVICE_FORK_BUILD_START                   = "Service: Forking build %d for user %s"
+	SERVICE_FORK_BUILD_ERROR                   = "Service: Error forking build %d for user %s"
+	SERVICE_FORK_BUILD_SUCCESS                 = "Service: Forked build %d into build %d for user %s"
+	SERVICE_SET_BUILD_LIKE_START               = "Service: Setting like of build %d for user %s to %t"
+	SERVICE_SET_BUILD_LIKE_ERROR               = "Service: Error setting like of build %d for user %s"
+	SERVICE_SET_BUILD_LIKE_SUCCESS             = "Service: Build %d has %d likes"
+	SERVICE_SET_BUILD_RATING_START             = "Service: Setting rating of build %d for user %s"
+	SERVICE_SET_BUILD_RATING_ERROR             = "Service: Error setting rating of build %d for user %s"
+	SERVICE_SET_BUILD_RATING_SUCCESS           = "Service: Build %d has %d ratings"
+	SERVICE_LIST_BUILD_COMMENTS_START          = "Service: Listing comments of build %d for user %s"
+	SERVICE_LIST_BUILD_COMMENTS_ERROR          = "Service: Error listing comments of build %d for user %s"
+	SERVICE_LIST_BUILD_COMMENTS_SUCCESS        = "Service: Listed %d comments of build %d"
+	SERVICE_CREATE_BUILD_COMMENT_START         = "Service: Commenting on build %d for user %s"
+	SERVICE_CREATE_BUILD_COMMENT_ERROR         = "Service: Error commenting on build %d for user %s"
+	SERVICE_CREATE_BUILD_COMMENT_SUCCESS       = "Service: Created comment %d on build %d"
+	SERVICE_UPDATE_BUILD_COMMENT_START         = "Service: Editing comment %d for user %s"
+	SERVICE_UPDATE_BUILD_COMMENT_ERROR         = "Service: Error editing comment %d for user %s"
+	SERVICE_UPDATE_BUILD_COMMENT_SUCCESS       = "Service: Comment %d edited by %s"
+	SERVICE_DELETE_BUILD_COMMENT_START         = "Service: Deleting comment %d for user %s"
+	SERVICE_DELETE_BUILD_COMMENT_ERROR         = "Service: Error deleting comment %d for user %s"
+	SERVICE_DELETE_BUILD_COMMENT_SUCCESS       = "Service: Comment %d deleted by %s"
+	SERVICE_OPTIMIZE_BUILD_PURCHASE_START      = "Service: Optimizing purchase of build %d"
+	SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR      = "Service: Error optimizing purchase of build %d"
+	SERVICE_OPTIMIZE_BUILD_PURCHASE_SUCCESS    = "Service: Cheapest plan for build %d costs %.2f %s from %d retailers"
+	SERVICE_FIND_CHEAPER_ALTERNATIVES_START    = "Service: Finding cheaper alternatives for build %d within %.1f%%"
+	SERVICE_FIND_CHEAPER_ALTERNATIVES_ERROR    = "Service: Error finding cheaper alternatives for build %d"
+	SERVICE_FIND_CHEAPER_ALTERNATIVES_SUCCESS  = "Service: Build %d could save %.2f %s"
+	SERVICE_FIND_BUILD_UPGRADES_START          = "Service: Finding upgrades for build %d for user %s"
+	SERVICE_FIND_BUILD_UPGRADES_ERROR          = "Service: Error finding upgrades for build %d"
+	SERVICE_FIND_BUILD_UPGRADES_SUCCESS        = "Service: Found %d upgrades for build %d"
+	SERVICE_LIST_BUILD_TEMPLATES_START         = "Service: Listing build templates"
+	SERVICE_LIST_BUILD_TEMPLATES_ERROR         = "Service: Error listing build templates"
+	SERVICE_LIST_BUILD_TEMPLATES_SUCCESS       = "Service: Listed %d build templates"
+	SERVICE_GET_BUILD_TEMPLATE_START           = "Service: Getting build template %d"
+	SERVICE_GET_BUILD_TEMPLATE_ERROR           = "Service: Error getting build template %d"
+	SERVICE_GET_BUILD_TEMPLATE_SUCCESS         = "Service: Retrieved build template %d"
+	SERVICE_SAVE_BUILD_TEMPLATE_START          = "Service: Saving build template %s for user %s"
+	SERVICE_SAVE_BUILD_TEMPLATE_ERROR          = "Service: Error saving build template %s"
+	SERVICE_SAVE_BUILD_TEMPLATE_SUCCESS        = "Service: Saved build template %d"
+	SERVICE_DELETE_BUILD_TEMPLATE_START        = "Service: Deleting build template %d"
+	SERVICE_DELETE_BUILD_TEMPLATE_ERROR        = "Service: Error deleting build template %d"
+	SERVICE_DELETE_BUILD_TEMPLATE_SUCCESS      = "Service: Deleted build template %d"
+	SERVICE_CREATE_BUILD_FROM_TEMPLATE_START   = "Service: Creating a build from template %d for user %s"
+	SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR   = "Service: Error creating a build from template %d for user %s"
+	SERVICE_CREATE_BUILD_FROM_TEMPLATE_SUCCESS = "Service: Created build %d from template %d with %d unfilled slots"
+	SERVICE_GET_BUILD_LINEAGE_START            = "Service: Getting fork lineage of build %d for viewer %s"
+	SERVICE_GET_BUILD_LINEAGE_ERROR            = "Service: Error getting fork lineage of build %d for viewer %s"
+	SERVICE_GET_BUILD_LINEAGE_SUCCESS          = "Service: Retrieved fork lineage of build %d for viewer %s"
+	SERVICE_CREATE_BUILD_SHARE_START           = "Service: Creating share link for build %d for user %s"
+	SERVICE_CREATE_BUILD_SHARE_ERROR           = "Service: Error creating share link for build %d for user %s"
+	SERVICE_CREATE_BUILD_SHARE_SUCCESS         = "Service: Created share link %d for build %d"
+	SERVICE_REVOKE_BUILD_SHARE_START           = "Service: Revoking share link %d of build %d"
+	SERVICE_REVOKE_BUILD_SHARE_ERROR           = "Service: Error revoking share link %d of build %d"
+	SERVICE_REVOKE_BUILD_SHARE_SUCCESS         = "Service: Revoked share link %d of build %d"
+	SERVICE_RESOLVE_BUILD_SHARE_ERROR          = "Service: Error resolving share link"
+	SERVICE_INVITE_BUILD_COLLABORATOR_START    = "Service: Inviting %s to build %d as %s"
+	SERVICE_INVITE_BUILD_COLLABORATOR_ERROR    = "Service: Error inviting %s to build %d"
+	SERVICE_INVITE_BUILD_COLLABORATOR_SUCCESS  = "Service: Invited %s to build %d"
+	SERVICE_UPDATE_BUILD_COLLABORATOR_ERROR    = "Service: Error changing the role of %s on build %d"
+	SERVICE_UPDATE_BUILD_COLLABORATOR_SUCCESS  = "Service: Changed the role of %s on build %d to %s"
+	SERVICE_REMOVE_BUILD_COLLABORATOR_ERROR    = "Service: Error removing %s from build %d"
+	SERVICE_REMOVE_BUILD_COLLABORATOR_SUCCESS  = "Service: Removed %s from build %d"
+	SERVICE_ACCEPT_BUILD_INVITATION_ERROR      = "Service: Error accepting the invitation of %s to build %d"
+	SERVICE_ACCEPT_BUILD_INVITATION_SUCCESS    = "Service: %s accepted the invitation to build %d"
+	SERVICE_RECORD_BUILD_VERSION_ERROR         = "Service: Error recording a version of build %d"
+	SERVICE_LIST_BUILD_VERSIONS_START          = "Service: Listing versions of build %d for user %s"
+	SERVICE_LIST_BUILD_VERSIONS_ERROR          = "Service: Error listing versions of build %d for user %s"
+	SERVICE_LIST_BUILD_VERSIONS_SUCCESS        = "Service: Listed %d versions of build %d"
+	SERVICE_DIFF_BUILD_VERSIONS_START          = "Service: Comparing versions %d and %d of build %d"
+	SERVICE_DIFF_BUILD_VERSIONS_ERROR          = "Service: Error comparing versions %d and %d of build %d"
+	SERVICE_DIFF_BUILD_VERSIONS_SUCCESS        = "Service: Compared versions %d and %d of build %d"
+	SERVICE_RESTORE_BUILD_VERSION_START        = "Service: Restoring version %d of build %d"
+	SERVICE_RESTORE_BUILD_VERSION_ERROR        = "Service: Error restoring version %d of build %d"
+	SERVICE_RESTORE_BUILD_VERSION_SUCCESS      = "Service: Restored version %d of build %d"
+	SERVICE_EXPORT_BUILD_START                 = "Service: Exporting build %d as %s"
+	SERVICE_EXPORT_BUILD_ERROR                 = "Service: Error exporting build %d"
+	SERVICE_EXPORT_BUILD_SUCCESS               = "Service: Exported build %d as %s"
+	SERVICE_RENDER_BUILD_SHEET_START           = "Service: Rendering spec sheet for build %d"
+	SERVICE_RENDER_BUILD_SHEET_ERROR           = "Service: Error rendering spec sheet for build %d"
+	SERVICE_RENDER_BUILD_SHEET_SUCCESS         = "Service: Rendered spec sheet for build %d"
+	SERVICE_IMPORT_BUILD_START                 = "Service: Importing a parts list for user %s"
+	SERVICE_IMPORT_BUILD_ERROR                 = "Service: Error importing a parts list for user %s"
+	SERVICE_IMPORT_BUILD_SUCCESS               = "Service: Imported build %d with %d matched and %d unmatched lines"
+	SERVICE_GENERATE_BUILD_START               = "Service: Generating a %s build for %.2f %s in %s"
+	SERVICE_GENERATE_BUILD_ERROR               = "Service: Error generating a %s build"
+	SERVICE_GENERATE_BUILD_SUCCESS             = "Service: Generated a %s build scoring %.1f for %.2f %s"
+	SERVICE_ADD_BUILD_PART_START               = "Service: Adding component %d to build %d"
+	SERVICE_ADD_BUILD_PART_ERROR               = "Service: Error adding component %d to build %d"
+	SERVICE_ADD_BUILD_PART_SUCCESS             = "Service: Added component %d to build %d"
+	SERVICE_UPDATE_BUILD_PART_START            = "Service: Updating component %d of build %d"
+	SERVICE_UPDATE_BUILD_PART_ERROR            = "Service: Error updating component %d of build %d"
+	SERVICE_UPDATE_BUILD_PART_SUCCESS          = "Service: Updated component %d of build %d"
+	SERVICE_REMOVE_BUILD_PART_START            = "Service: Removing component %d from build %d"
+	SERVICE_REMOVE_BUILD_PART_ERROR            = "Service: Error removing component %d from build %d"
+	SERVICE_REMOVE_BUILD_PART_SUCCESS          = "Service: Removed component %d from build %d"
+	SERVICE_CALCULATE_BUILD_PRICE_START        = "Service: Calculating price of build %d"
+	SERVICE_CALCULATE_BUILD_PRICE_ERROR        = "Service: Error calculating price of build %d"
+	SERVICE_CALCULATE_BUILD_PRICE_SUCCESS      = "Service: Calculated price of build %d: %.2f %s"
+	SERVICE_RECALCULATE_BUILD_TOTALS_START     = "Service: Recalculating build totals"
+	SERVICE_RECALCULATE_BUILD_TOTALS_ERROR     = "Service: Error recalculating build totals"
+	SERVICE_RECALCULATE_BUILD_TOTALS_SUCCESS   = "Service: Recalculated the totals of %d builds"
+	SERVICE_ANALYZE_COOLING_START              = "Service: Analyzing cooling of build %d"
+	SERVICE_ANALYZE_COOLING_ERROR              = "Service: Error analyzing cooling of build %d"
+	SERVICE_ANALYZE_COOLING_SUCCESS            = "Service: Analyzed cooling of build %d - Rating: %s"
+	SERVICE_GET_BUILD_COMPLETENESS_START       = "Service: Checking completeness of build %d"
+	SERVICE_GET_BUILD_COMPLETENESS_ERROR       = "Service: Error checking completeness of build %d"
+	SERVICE_GET_BUILD_COMPLETENESS_SUCCESS     = "Service: Checked completeness of build %d - Missing: %d"
+	SERVICE_IMPORT_BIOS_REQUIREMENTS_START     = "Service: Importing BIOS requirements"
+	SERVICE_IMPORT_BIOS_REQUIREMENTS_ERROR     = "Service: Error importing BIOS requirements"
+	SERVICE_IMPORT_BIOS_REQUIREMENTS_SUCCESS   = "Service: Imported %d BIOS requirements"
+	SERVICE_LOAD_COMPATIBILITY_RULES_START     = "Service: Loading compatibility rules from %s"
+	SERVICE_LOAD_COMPATIBILITY_RULES_ERROR     = "Service: Error loading compatibility rules from %s"
+	SERVICE_LOAD_COMPATIBILITY_RULES_SUCCESS   = "Service: Loaded %d compatibility rules from %s"
+	SERVICE_LOAD_COMPATIBILITY_RULES_FALLBACK  = "Service: Warning: could not load the default compatibility rules from %s, continuing without rules"
 
 	// Repository log messages
 	REPOSITORY_GET_ALL_COMPONENTS_START               = "Repository: Getting all components"
@@ -53,6 +359,96 @@ const (
 	REPOSITORY_GET_COMPONENT_BY_ID_DB_ERROR           = "Repository: Database error getting component by ID: %s"
 	REPOSITORY_GET_COMPONENT_BY_ID_SCAN_ERROR         = "Repository: Error scanning component row for ID: %s"
 	REPOSITORY_GET_COMPONENT_BY_ID_SUCCESS            = "Repository: Successfully retrieved component by ID: %s"
+	REPOSITORY_GET_COMPONENTS_BY_SPECS_START          = "Repository: Getting components by spec predicates - Category: %s, Predicates: %d"
+	REPOSITORY_GET_COMPONENTS_BY_SPECS_QUERY_ERROR    = "Repository: Error generating query for components by spec predicates - Category: %s"
+	REPOSITORY_GET_COMPONENTS_BY_SPECS_DB_ERROR       = "Repository: Database error getting components by spec predicates - Category: %s"
+	REPOSITORY_GET_COMPONENTS_BY_SPECS_SCAN_ERROR     = "Repository: Error scanning component row for spec predicates - Category: %s"
+	REPOSITORY_GET_COMPONENTS_BY_SPECS_SUCCESS        = "Repository: Successfully retrieved %d components by spec predicates - Category: %s"
+	REPOSITORY_GET_BUILD_BY_ID_START                  = "Repository: Getting build by ID: %d"
+	REPOSITORY_GET_BUILD_BY_ID_SCAN_ERROR             = "Repository: Error scanning build row for ID: %d"
+	REPOSITORY_GET_BUILD_BY_ID_SUCCESS                = "Repository: Successfully retrieved build by ID: %d"
+	REPOSITORY_GET_BUILD_PARTS_START                  = "Repository: Getting parts for build: %d"
+	REPOSITORY_GET_BUILD_PARTS_DB_ERROR               = "Repository: Database error getting parts for build: %d"
+	REPOSITORY_GET_BUILD_PARTS_SUCCESS                = "Repository: Successfully retrieved %d parts for build: %d"
+	REPOSITORY_LIST_BUILDS_START                      = "Repository: Listing builds"
+	REPOSITORY_LIST_BUILDS_DB_ERROR                   = "Repository: Database error listing builds"
+	REPOSITORY_LIST_BUILDS_SCAN_ERROR                 = "Repository: Error scanning build row"
+	REPOSITORY_LIST_BUILDS_SUCCESS                    = "Repository: Successfully listed %d builds"
+	REPOSITORY_LIST_PUBLIC_BUILDS_DB_ERROR            = "Repository: Database error listing public builds"
+	REPOSITORY_LIST_PUBLIC_BUILDS_SCAN_ERROR          = "Repository: Error scanning public build"
+	REPOSITORY_CREATE_BUILD_START                     = "Repository: Creating build for user: %s"
+	REPOSITORY_CREATE_BUILD_DB_ERROR                  = "Repository: Database error creating build for user: %s"
+	REPOSITORY_CREATE_BUILD_SUCCESS                   = "Repository: Successfully created build: %d"
+	REPOSITORY_UPDATE_BUILD_START                     = "Repository: Updating build: %d"
+	REPOSITORY_UPDATE_BUILD_DB_ERROR                  = "Repository: Database error updating build: %d"
+	REPOSITORY_UPDATE_BUILD_SUCCESS                   = "Repository: Successfully updated build: %d"
+	REPOSITORY_DELETE_BUILD_START                     = "Repository: Deleting build: %d"
+	REPOSITORY_DELETE_BUILD_DB_ERROR                  = "Repository: Database error deleting build: %d"
+	REPOSITORY_DELETE_BUILD_SUCCESS                   = "Repository: Successfully deleted build: %d"
+	REPOSITORY_FORK_BUILD_START                       = "Repository: Forking build %d for user: %s"
+	REPOSITORY_FORK_BUILD_DB_ERROR                    = "Repository: Database error forking build %d for user: %s"
+	REPOSITORY_FORK_BUILD_SUCCESS                     = "Repository: Successfully forked build %d into build: %d"
+	REPOSITORY_SET_BUILD_LIKE_DB_ERROR                = "Repository: Database error setting like of build %d for user: %s"
+	REPOSITORY_SET_BUILD_RATING_DB_ERROR              = "Repository: Database error setting rating of build %d for user: %s"
+	REPOSITORY_CREATE_BUILD_COMMENT_DB_ERROR          = "Repository: Database error creating comment on build: %d"
+	REPOSITORY_GET_BUILD_COMMENT_DB_ERROR             = "Repository: Database error getting comment: %d"
+	REPOSITORY_LIST_BUILD_COMMENTS_DB_ERROR           = "Repository: Database error listing comments of build: %d"
+	REPOSITORY_UPDATE_BUILD_COMMENT_DB_ERROR          = "Repository: Database error updating comment: %d"
+	REPOSITORY_DELETE_BUILD_COMMENT_DB_ERROR          = "Repository: Database error deleting comment: %d"
+	REPOSITORY_LIST_BUILD_TEMPLATES_DB_ERROR          = "Repository: Database error listing build templates"
+	REPOSITORY_GET_BUILD_TEMPLATE_DB_ERROR            = "Repository: Database error getting build template: %d"
+	REPOSITORY_CREATE_BUILD_TEMPLATE_DB_ERROR         = "Repository: Database error creating build template: %s"
+	REPOSITORY_UPDATE_BUILD_TEMPLATE_DB_ERROR         = "Repository: Database error updating build template: %d"
+	REPOSITORY_DELETE_BUILD_TEMPLATE_DB_ERROR         = "Repository: Database error deleting build template: %d"
+	REPOSITORY_GET_BUILD_ANCESTORS_DB_ERROR           = "Repository: Database error getting ancestors of build: %d"
+	REPOSITORY_GET_BUILD_FORKS_DB_ERROR               = "Repository: Database error getting forks of build: %d"
+	REPOSITORY_CREATE_BUILD_SHARE_DB_ERROR            = "Repository: Database error creating share link for build: %d"
+	REPOSITORY_LIST_BUILD_SHARES_DB_ERROR             = "Repository: Database error listing share links of build: %d"
+	REPOSITORY_REVOKE_BUILD_SHARE_DB_ERROR            = "Repository: Database error revoking share link %d of build: %d"
+	REPOSITORY_GET_BUILD_SHARE_DB_ERROR               = "Repository: Database error getting share link"
+	REPOSITORY_CREATE_BUILD_COLLABORATOR_DB_ERROR     = "Repository: Database error inviting %s to build: %d"
+	REPOSITORY_LIST_BUILD_COLLABORATORS_DB_ERROR      = "Repository: Database error listing collaborators of build: %d"
+	REPOSITORY_GET_BUILD_COLLABORATOR_DB_ERROR        = "Repository: Database error getting collaborator %s of build: %d"
+	REPOSITORY_UPDATE_BUILD_COLLABORATOR_DB_ERROR     = "Repository: Database error updating collaborator %s of build: %d"
+	REPOSITORY_DELETE_BUILD_COLLABORATOR_DB_ERROR     = "Repository: Database error removing collaborator %s from build: %d"
+	REPOSITORY_LIST_BUILD_INVITATIONS_DB_ERROR        = "Repository: Database error listing build invitations of user: %s"
+	REPOSITORY_CREATE_BUILD_VERSION_DB_ERROR          = "Repository: Database error recording a version of build: %d"
+	REPOSITORY_LIST_BUILD_VERSIONS_DB_ERROR           = "Repository: Database error listing versions of build: %d"
+	REPOSITORY_GET_BUILD_VERSION_DB_ERROR             = "Repository: Database error getting version %d of build: %d"
+	REPOSITORY_REPLACE_BUILD_PARTS_DB_ERROR           = "Repository: Database error replacing the parts of build: %d"
+	REPOSITORY_UPSERT_BUILD_PART_START                = "Repository: Adding component %d to build: %d"
+	REPOSITORY_UPSERT_BUILD_PART_DB_ERROR             = "Repository: Database error adding component %d to build: %d"
+	REPOSITORY_UPSERT_BUILD_PART_SUCCESS              = "Repository: Successfully added component %d to build: %d"
+	REPOSITORY_UPDATE_BUILD_PART_START                = "Repository: Updating component %d of build: %d"
+	REPOSITORY_UPDATE_BUILD_PART_DB_ERROR             = "Repository: Database error updating component %d of build: %d"
+	REPOSITORY_UPDATE_BUILD_PART_SUCCESS              = "Repository: Successfully updated component %d of build: %d"
+	REPOSITORY_DELETE_BUILD_PART_START                = "Repository: Removing component %d from build: %d"
+	REPOSITORY_DELETE_BUILD_PART_DB_ERROR             = "Repository: Database error removing component %d from build: %d"
+	REPOSITORY_DELETE_BUILD_PART_SUCCESS              = "Repository: Successfully removed component %d from build: %d"
+	REPOSITORY_GET_PRICE_BY_ID_START                  = "Repository: Getting price by ID: %d"
+	REPOSITORY_GET_PRICE_BY_ID_SCAN_ERROR             = "Repository: Error scanning price row for ID: %d"
+	REPOSITORY_GET_PRICE_BY_ID_SUCCESS                = "Repository: Successfully retrieved price by ID: %d"
+	REPOSITORY_GET_IN_STOCK_PRICES_START              = "Repository: Getting in-stock prices of %d components in region: %s"
+	REPOSITORY_GET_IN_STOCK_PRICES_DB_ERROR           = "Repository: Database error getting in-stock prices in region: %s"
+	REPOSITORY_GET_IN_STOCK_PRICES_SUCCESS            = "Repository: Successfully retrieved %d in-stock prices in region: %s"
+	REPOSITORY_GET_EXCHANGE_RATES_START               = "Repository: Getting exchange rates"
+	REPOSITORY_GET_EXCHANGE_RATES_DB_ERROR            = "Repository: Database error getting exchange rates"
+	REPOSITORY_GET_EXCHANGE_RATES_SUCCESS             = "Repository: Successfully retrieved %d exchange rates"
+	REPOSITORY_GET_RETAILER_NAMES_START               = "Repository: Getting names of %d retailers"
+	REPOSITORY_GET_RETAILER_NAMES_DB_ERROR            = "Repository: Database error getting retailer names"
+	REPOSITORY_GET_RETAILER_NAMES_SUCCESS             = "Repository: Successfully retrieved %d retailer names"
+	REPOSITORY_GET_RETAILER_SHIPPING_DB_ERROR         = "Repository: Database error getting shipping of retailers"
+	REPOSITORY_FIND_COMPONENT_CANDIDATES_DB_ERROR     = "Repository: Database error finding components matching: %s"
+	REPOSITORY_LIST_IN_STOCK_COMPONENTS_DB_ERROR      = "Repository: Database error listing in-stock components in region: %s"
+	REPOSITORY_GET_COMPONENTS_BY_IDS_DB_ERROR         = "Repository: Database error getting %d components"
+	REPOSITORY_SET_BUILD_TOTALS_DB_ERROR              = "Repository: Database error setting total price and completeness of build: %d"
+	REPOSITORY_LIST_BUILD_IDS_DB_ERROR                = "Repository: Database error listing build IDs"
+	REPOSITORY_GET_BIOS_REQUIREMENT_START             = "Repository: Getting BIOS requirement - Motherboard: %d, CPU: %d"
+	REPOSITORY_GET_BIOS_REQUIREMENT_SCAN_ERROR        = "Repository: Error scanning BIOS requirement - Motherboard: %d, CPU: %d"
+	REPOSITORY_GET_BIOS_REQUIREMENT_SUCCESS           = "Repository: Successfully retrieved BIOS requirement - Motherboard: %d, CPU: %d"
+	REPOSITORY_UPSERT_BIOS_REQUIREMENTS_START         = "Repository: Upserting %d BIOS requirements"
+	REPOSITORY_UPSERT_BIOS_REQUIREMENTS_DB_ERROR      = "Repository: Database error upserting BIOS requirements"
+	REPOSITORY_UPSERT_BIOS_REQUIREMENTS_SUCCESS       = "Repository: Successfully upserted %d BIOS requirements"
 
 	// Database utility log messages
 	DB_UTIL_GENERATE_SELECT_QUERY_START   = "Generating select query for table: %s"
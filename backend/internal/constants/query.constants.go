@@ -1,13 +1,38 @@
 package constants
 
 const (
-	ALL_COLUMNS       = "*"
-	COMPONENTS_TABLE  = "components"
-	DEFAULT_PAGE_SIZE = 50
+	ALL_COLUMNS               = "*"
+	COMPONENTS_TABLE          = "components"
+	USER_BUILDS_TABLE         = "user_builds"
+	BUILD_COMPONENTS_TABLE    = "build_components"
+	BIOS_REQUIREMENTS_TABLE   = "bios_requirements"
+	PRICES_TABLE              = "prices"
+	RETAILERS_TABLE           = "retailers"
+	EXCHANGE_RATES_TABLE      = "exchange_rates"
+	BUILD_SHARES_TABLE        = "build_shares"
+	BUILD_VERSIONS_TABLE      = "build_versions"
+	BUILD_LIKES_TABLE         = "build_likes"
+	BUILD_RATINGS_TABLE       = "build_ratings"
+	BUILD_COMMENTS_TABLE      = "build_comments"
+	BUILD_TEMPLATES_TABLE     = "build_templates"
+	BUILD_COLLABORATORS_TABLE = "build_collaborators"
+	DEFAULT_PAGE_SIZE         = 50
+	GALLERY_PAGE_SIZE         = 24
+	GALLERY_MAX_PAGE_SIZE     = 100
+
+	DEFAULT_COMPATIBILITY_RULES_DIR = "compatibility_rules"
 )
 
 var (
-	COMPONENTS_SELECT_COLUMNS = []string{"id", "category", "brand", "model", "sku", "upc", "specs", "created_at"}
+	COMPONENTS_SELECT_COLUMNS          = []string{"id", "category", "brand", "model", "sku", "upc", "specs", "created_at"}
+	BIOS_REQUIREMENTS_SELECT_COLUMNS   = []string{"id", "motherboard_id", "cpu_id", "min_bios_version", "notes", "created_at", "updated_at"}
+	PRICES_SELECT_COLUMNS              = []string{"id", "component_id", "retailer_id", "region", "currency", "price", "in_stock", "product_url", "last_updated", "created_at"}
+	BUILD_SHARES_SELECT_COLUMNS        = []string{"id", "build_id", "access", "created_by", "expires_at", "revoked_at", "created_at"}
+	BUILD_VERSIONS_SELECT_COLUMNS      = []string{"id", "build_id", "version", "parts", "total_price", "currency", "reason", "created_by", "created_at"}
+	BUILD_COMMENTS_SELECT_COLUMNS      = []string{"id", "build_id", "parent_id", "user_id", "body", "created_at", "edited_at", "deleted_at", "deleted_by"}
+	BUILD_TEMPLATES_SELECT_COLUMNS     = []string{"id", "name", "description", "use_case", "resolution", "target_budget", "currency", "slots", "created_by", "created_at", "updated_at"}
+	BUILD_COLLABORATORS_SELECT_COLUMNS = []string{"id", "build_id", "user_id", "role", "invited_by", "accepted_at", "created_at", "updated_at"}
+	USER_BUILDS_SELECT_COLUMNS         = []string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "COALESCE(currency, 'USD')", "COALESCE(region, 'USA')", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}
 )
 
 type LimitAndOffset struct {
@@ -0,0 +1,215 @@
+package constants
+
+// Keys used inside the components.specs JSONB document
+const (
+	SPEC_SOCKET                   = "socket"
+	SPEC_SUPPORTED_SOCKETS        = "supported_sockets"
+	SPEC_MEMORY_TYPE              = "memory_type"
+	SPEC_FORM_FACTOR              = "form_factor"
+	SPEC_SUPPORTED_FORM_FACTORS   = "supported_form_factors"
+	SPEC_SUPPORTED_PSU_FORMS      = "supported_psu_form_factors"
+	SPEC_LENGTH_MM                = "length_mm"
+	SPEC_HEIGHT_MM                = "height_mm"
+	SPEC_MAX_GPU_LENGTH_MM        = "max_gpu_length_mm"
+	SPEC_MAX_CPU_COOLER_HEIGHT_MM = "max_cpu_cooler_height_mm"
+
+	// Storage
+	SPEC_INTERFACE      = "interface"
+	SPEC_M2_SLOTS       = "m2_slots"
+	SPEC_SATA_PORTS     = "sata_ports"
+	SPEC_PCIE_SLOTS     = "pcie_slots"
+	SPEC_GPU_SLOT       = "gpu_slot"
+	STORAGE_NVME        = "nvme"
+	STORAGE_SATA        = "sata"
+	STORAGE_FORM_M2     = "m.2"
+	STORAGE_FORM_AIC    = "add-in card"
+	STORAGE_SLOT_M2     = "m2"
+	STORAGE_SLOT_SATA   = "sata"
+	STORAGE_SLOT_PCIE   = "pcie"
+	DEFAULT_GPU_SLOT    = "PCIEX16_1"
+	SATA_PORT_NAME_BASE = "SATA_%d"
+
+	// Power connectors
+	SPEC_POWER_CONNECTORS  = "power_connectors"
+	SPEC_PSU_CONNECTORS    = "connectors"
+	SPEC_EPS_8PIN_HEADERS  = "eps_8pin_headers"
+	CONNECTOR_PCIE_8PIN    = "pcie_8pin"
+	CONNECTOR_PCIE_6PIN    = "pcie_6pin"
+	CONNECTOR_12VHPWR      = "12vhpwr"
+	CONNECTOR_EPS_8PIN     = "eps_8pin"
+	CONNECTOR_SATA         = "sata"
+	CONNECTOR_MOLEX        = "molex"
+	PCIE_8PIN_PER_12VHPWR  = 4
+	SATA_DEVICES_PER_SPLIT = 2
+
+	// BIOS
+	SPEC_BIOS_VERSION   = "bios_version"
+	SPEC_BIOS_FLASHBACK = "bios_flashback"
+
+	// Cooling
+	SPEC_TDP                = "tdp"
+	SPEC_SUSTAINED_POWER    = "sustained_power_w"
+	SPEC_INCLUDES_COOLER    = "includes_cooler"
+	SPEC_RATED_TDP          = "rated_tdp"
+	SPEC_FAN_MOUNTS         = "fan_mounts"
+	SPEC_INTAKE_FAN_MOUNTS  = "intake_fan_mounts"
+	SPEC_EXHAUST_FAN_MOUNTS = "exhaust_fan_mounts"
+	SPEC_INCLUDED_FANS      = "included_fans"
+	SPEC_AIRFLOW_CFM        = "airflow_cfm"
+	SPEC_RADIATOR_FANS      = "radiator_fans"
+
+	// Power
+	SPEC_WATTAGE = "wattage"
+
+	// Capacity of memory and storage, such as "32GB" or "2TB"
+	SPEC_CAPACITY = "capacity"
+
+	// Shown on build spec sheets
+	SPEC_CORES       = "cores"
+	SPEC_THREADS     = "threads"
+	SPEC_BASE_CLOCK  = "base_clock"
+	SPEC_BOOST_CLOCK = "boost_clock"
+	SPEC_CHIPSET     = "chipset"
+	SPEC_TYPE        = "type"
+	SPEC_SPEED       = "speed"
+	SPEC_VRAM        = "memory"
+	SPEC_EFFICIENCY  = "efficiency"
+
+	// Graphics
+	SPEC_INTEGRATED_GRAPHICS = "integrated_graphics"
+
+	// Performance scores (0-100) stored alongside the other specs
+	SPEC_PERFORMANCE_GAMING        = "performance_gaming"
+	SPEC_PERFORMANCE_SINGLE_THREAD = "performance_single_thread"
+	SPEC_PERFORMANCE_MULTI_THREAD  = "performance_multi_thread"
+	SPEC_PERFORMANCE_COMPUTE       = "performance_compute"
+)
+
+// Use cases and resolutions accepted by the performance analyzers
+const (
+	USE_CASE_GAMING      = "gaming"
+	USE_CASE_WORKSTATION = "workstation"
+	USE_CASE_OFFICE      = "office"
+	// Small form factor gaming, only accepted by the build generator
+	USE_CASE_SFF = "sff"
+
+	RESOLUTION_1080P = "1080p"
+	RESOLUTION_1440P = "1440p"
+	RESOLUTION_4K    = "4k"
+
+	DEFAULT_USE_CASE   = USE_CASE_GAMING
+	DEFAULT_RESOLUTION = RESOLUTION_1440P
+
+	// Parts whose effective scores are within this ratio are considered balanced
+	BALANCED_RATIO          = 0.9
+	MAX_UPGRADE_SUGGESTIONS = 5
+)
+
+// Limits of build parts
+const (
+	// Units of one component a build may hold; analyzers count units, so this also bounds their work
+	MAX_PART_QUANTITY = 32
+)
+
+// Thresholds used by the cooling analyzer
+const (
+	// Cooler rating over CPU power needed for an excellent rating
+	COOLING_EXCELLENT_HEADROOM = 1.3
+	// Fans needed for an excellent rating
+	COOLING_EXCELLENT_FANS = 3
+	// Airflow assumed for fans that do not list it, such as the fans bundled with a case
+	DEFAULT_FAN_CFM = 50.0
+	// Intake over exhaust airflow considered balanced; slightly positive pressure keeps dust out
+	AIRFLOW_BALANCE_MIN = 1.0
+	AIRFLOW_BALANCE_MAX = 2.0
+)
+
+// Limits used by the parts list importer
+const (
+	// Lines naming a component accepted in one import
+	IMPORT_MAX_LINES = 100
+	// Components fetched from the database for each line before they are scored
+	IMPORT_CANDIDATE_POOL = 50
+	// Candidates returned for a line that could not be matched
+	IMPORT_MAX_CANDIDATES = 3
+	// Lowest score a candidate needs to be picked without asking the user
+	IMPORT_MATCH_THRESHOLD = 0.8
+	// How far the best candidate must score above the next one to be picked without asking the user
+	IMPORT_MATCH_MARGIN = 0.02
+)
+
+// Settings of the build generator
+const (
+	// Motherboard form factor small form factor builds are limited to
+	FORM_FACTOR_MINI_ITX = "Mini-ITX"
+	// Power supply wattage over the estimated draw of the parts
+	GENERATOR_PSU_HEADROOM = 1.3
+	// Draw of the motherboard, memory, storage and fans added to the CPU and GPU
+	GENERATOR_PLATFORM_POWER_W = 75.0
+	// Runner-up builds returned next to the best one
+	GENERATOR_MAX_ALTERNATIVES = 3
+	// Parts a request can pin
+	GENERATOR_MAX_PINNED = 10
+	// Region priced when a request names none, matching the default of user_builds.region
+	DEFAULT_BUILD_REGION = "USA"
+)
+
+// Limits of build comments and ratings
+const (
+	// Characters allowed in a comment
+	COMMENT_MAX_LENGTH = 2000
+	// Replies allowed below a top-level comment
+	COMMENT_MAX_DEPTH = 5
+	RATING_MIN        = 1
+	RATING_MAX        = 5
+)
+
+// Settings of the purchase optimizer
+const (
+	// Retailers weighed when optimizing a purchase; past this, those carrying the fewest of the parts are dropped unless
+	// one is the only seller of a part
+	OPTIMIZER_MAX_RETAILERS = 12
+)
+
+// Settings of cheaper alternative suggestions
+const (
+	// Percentage a part's metric may drop by when no tolerance is given
+	ALTERNATIVES_DEFAULT_TOLERANCE = 10.0
+	ALTERNATIVES_MAX_TOLERANCE     = 50.0
+	// Alternatives suggested for each part
+	ALTERNATIVES_PER_SLOT = 3
+)
+
+// Settings of upgrade recommendations
+const (
+	// Upgrades returned for a build
+	UPGRADES_MAX_RESULTS = 10
+	// Best single CPU and video card upgrades paired up into two-part upgrades
+	UPGRADES_PAIR_POOL = 5
+	// Rounds of required replacements tried before an upgrade is given up on
+	UPGRADES_MAX_CASCADE_ROUNDS = 3
+)
+
+// Limits of build templates
+const (
+	// Slots a template may define
+	TEMPLATE_MAX_SLOTS = 20
+	// Spec constraints a template slot may define
+	TEMPLATE_MAX_CONSTRAINTS = 10
+	// Units of a part a template slot may ask for
+	TEMPLATE_MAX_QUANTITY = 8
+)
+
+// Settings of printable build spec sheets
+const (
+	// Power supply wattages are recommended in steps of this size
+	SHEET_PSU_WATTAGE_STEP = 50.0
+	// Site the QR code links to when PUBLIC_BASE_URL is not set
+	DEFAULT_PUBLIC_BASE_URL = "http://localhost:3000"
+)
+
+// Limits of build collaboration
+const (
+	// Collaborators, invited or accepted, a build may have besides its creator
+	BUILD_MAX_COLLABORATORS = 20
+)
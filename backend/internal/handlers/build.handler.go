@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildsHandler lists builds on GET and creates a build on POST
+func BuildsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		handleListBuilds(w, r)
+	case http.MethodPost:
+		handleCreateBuild(w, r)
+	default:
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+	}
+}
+
+// BuildHandler gets, updates or deletes the build identified by the {id} path value
+func BuildHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPatch && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handleGetBuild(w, r, buildID)
+	case http.MethodPatch:
+		handleUpdateBuild(w, r, buildID)
+	case http.MethodDelete:
+		handleDeleteBuild(w, r, buildID)
+	}
+}
+
+func handleListBuilds(w http.ResponseWriter, r *http.Request) {
+	utils.Log(constants.HANDLER_LIST_BUILDS_START, nil)
+
+	filter, err := parseUserBuildFilter(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, constants.BUILD_FILTER_INVALID_MESSAGE, err.Error())
+		return
+	}
+	page := utils.GetPageNumberFromQueryString(r.URL.Query())
+
+	builds, err := services.ListUserBuilds(filter, getRequestUserID(r), page)
+	if err != nil {
+		utils.Log(constants.HANDLER_LIST_BUILDS_ERROR, err)
+		utils.WriteError(w, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR_MESSAGE, err)
+		return
+	}
+
+	utils.Log(constants.HANDLER_LIST_BUILDS_SUCCESS, nil)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, builds)
+}
+
+func handleCreateBuild(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_CREATE_BUILD_START, nil, userID)
+
+	var input models.UserBuildCreate
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	build, err := services.CreateUserBuild(userID, input)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_CREATE_BUILD_ERROR, userID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_CREATE_BUILD_SUCCESS, nil, build.ID)
+	utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, build)
+}
+
+func handleGetBuild(w http.ResponseWriter, r *http.Request, buildID int64) {
+	utils.Log(constants.HANDLER_GET_BUILD_START, nil, buildID)
+
+	build, err := services.GetUserBuild(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_GET_BUILD_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GET_BUILD_SUCCESS, nil, buildID)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, build)
+}
+
+func handleUpdateBuild(w http.ResponseWriter, r *http.Request, buildID int64) {
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_UPDATE_BUILD_START, nil, buildID)
+
+	var update models.UserBuildUpdate
+	if !decodeJSONBody(w, r, &update) {
+		return
+	}
+
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	build, err := services.UpdateUserBuild(buildID, userID, revision, update)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_UPDATE_BUILD_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_UPDATE_BUILD_SUCCESS, nil, buildID)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, build)
+}
+
+func handleDeleteBuild(w http.ResponseWriter, r *http.Request, buildID int64) {
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_DELETE_BUILD_START, nil, buildID)
+
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteUserBuild(buildID, userID, revision); err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_DELETE_BUILD_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_DELETE_BUILD_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, nil)
+}
+
+// parseUserBuildFilter reads the UserBuildFilter fields from the query string
+func parseUserBuildFilter(r *http.Request) (models.UserBuildFilter, error) {
+	query := r.URL.Query()
+	filter := models.UserBuildFilter{}
+
+	optionalString := func(key string) *string {
+		value := strings.TrimSpace(query.Get(key))
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	optionalBool := func(key string) (*bool, error) {
+		raw := strings.TrimSpace(query.Get(key))
+		if raw == "" {
+			return nil, nil
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", key)
+		}
+		return &value, nil
+	}
+
+	var err error
+	filter.UserID = optionalString("user_id")
+	filter.Region = optionalString("region")
+	if filter.UseCase = optionalString("use_case"); filter.UseCase != nil {
+		useCase := strings.ToLower(*filter.UseCase)
+		filter.UseCase = &useCase
+	}
+	if filter.Currency = optionalString("currency"); filter.Currency != nil {
+		currency := strings.ToUpper(*filter.Currency)
+		filter.Currency = &currency
+	}
+	if filter.IsPublic, err = optionalBool("is_public"); err != nil {
+		return filter, err
+	}
+	if filter.IsComplete, err = optionalBool("is_complete"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// getRequestUserID returns the caller's user ID, or an empty string for anonymous requests
+func getRequestUserID(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(constants.USER_ID_HEADER))
+}
+
+// requireRequestUserID returns the caller's user ID, writing a 401 response when it is missing
+func requireRequestUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := getRequestUserID(r)
+	if userID == "" {
+		utils.WriteError(w, http.StatusUnauthorized, constants.USER_ID_REQUIRED_MESSAGE, nil)
+		return "", false
+	}
+	return userID, true
+}
+
+// requireIfMatchRevision returns the build revision a change is based on, writing a 428 response when the If-Match
+// header does not name a revision so editors cannot silently overwrite each other's changes. If-Match: * only says the
+// build must exist, which every change already requires, so it is rejected the same way.
+func requireIfMatchRevision(w http.ResponseWriter, r *http.Request) (*int, bool) {
+	revision := getIfMatchRevision(r)
+	if revision == nil {
+		utils.WriteError(w, http.StatusPreconditionRequired, constants.IF_MATCH_REQUIRED_MESSAGE, nil)
+		return nil, false
+	}
+	return revision, true
+}
+
+// getIfMatchRevision reads the build revision a change is based on from the If-Match header. A missing header or *
+// returns nil as neither names a revision; a value that is not a revision never matches, so the change fails with a
+// conflict.
+func getIfMatchRevision(r *http.Request) *int {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil
+	}
+
+	revision, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(raw, "W/"), `"`))
+	if err != nil || revision < 1 {
+		revision = 0
+	}
+	return &revision
+}
+
+// setBuildETag sends the revision of a build as its ETag, for clients to send back in If-Match
+func setBuildETag(w http.ResponseWriter, revision int) {
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, revision))
+}
+
+// decodeJSONBody decodes the request body into out, writing a 400 response when it is not valid JSON for out
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, out interface{}) bool {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		utils.Log(constants.HANDLER_INVALID_REQUEST_BODY, err)
+		utils.WriteError(w, http.StatusBadRequest, constants.REQUEST_BODY_INVALID_MESSAGE, err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,944 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBuildsMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/builds", BuildsHandler)
+	mux.HandleFunc("/builds/import", ImportBuildHandler)
+	mux.HandleFunc("/builds/generate", GenerateBuildHandler)
+	mux.HandleFunc("/builds/public", GetPublicBuildsHandler)
+	mux.HandleFunc("/builds/invitations", GetBuildInvitationsHandler)
+	mux.HandleFunc("/builds/{id}", BuildHandler)
+	mux.HandleFunc("/builds/{id}/components", BuildComponentsHandler)
+	mux.HandleFunc("/builds/{id}/components/{componentId}", BuildComponentHandler)
+	mux.HandleFunc("/builds/{id}/collaborators", BuildCollaboratorsHandler)
+	mux.HandleFunc("/builds/{id}/collaborators/{userId}", BuildCollaboratorHandler)
+	mux.HandleFunc("/builds/{id}/invitation", BuildInvitationHandler)
+	mux.HandleFunc("/builds/{id}/export", ExportBuildHandler)
+	mux.HandleFunc("/builds/{id}/sheet", GetBuildSheetHandler)
+	mux.HandleFunc("/builds/{id}/compatibility", GetBuildCompatibilityHandler)
+	mux.HandleFunc("/builds/{id}/storage", GetBuildStorageAllocationHandler)
+	mux.HandleFunc("/builds/{id}/bottleneck", GetBuildBottleneckHandler)
+	mux.HandleFunc("/builds/{id}/power-connectors", GetBuildPowerConnectorsHandler)
+	mux.HandleFunc("/builds/{id}/cooling", GetBuildCoolingHandler)
+	mux.HandleFunc("/builds/{id}/completeness", GetBuildCompletenessHandler)
+	mux.HandleFunc("/builds/{id}/optimize", OptimizeBuildPurchaseHandler)
+	mux.HandleFunc("/builds/{id}/cheaper-alternatives", GetCheaperAlternativesHandler)
+	mux.HandleFunc("/builds/{id}/upgrades", GetBuildUpgradesHandler)
+	mux.HandleFunc("/builds/{id}/fork", ForkBuildHandler)
+	mux.HandleFunc("/builds/{id}/versions", GetBuildVersionsHandler)
+	mux.HandleFunc("/builds/{id}/versions/{a}/diff/{b}", DiffBuildVersionsHandler)
+	mux.HandleFunc("/builds/{id}/versions/{version}/restore", RestoreBuildVersionHandler)
+	mux.HandleFunc("/builds/{id}/forks", GetBuildForksHandler)
+	mux.HandleFunc("/builds/{id}/like", BuildLikeHandler)
+	mux.HandleFunc("/builds/{id}/rating", BuildRatingHandler)
+	mux.HandleFunc("/builds/{id}/comments", BuildCommentsHandler)
+	mux.HandleFunc("/builds/{id}/comments/{commentId}", BuildCommentHandler)
+	mux.HandleFunc("/builds/{action}/{id}", CreateBuildFromTemplateHandler)
+	mux.HandleFunc("/components/{category}", GetComponentsHandler)
+	mux.HandleFunc("/templates", BuildTemplatesHandler)
+	mux.HandleFunc("/templates/{id}", BuildTemplateHandler)
+	return mux
+}
+
+// TestBuildHandlers_Validation tests method, identity, path and body validation of the build endpoints
+func TestBuildHandlers_Validation(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		userID          string
+		body            string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "PUT on the collection",
+			method:          http.MethodPut,
+			path:            "/builds",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "POST on a build",
+			method:          http.MethodPost,
+			path:            "/builds/1",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Create without user",
+			method:          http.MethodPost,
+			path:            "/builds",
+			body:            `{"name": "Gaming PC"}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Create with unknown field",
+			method:          http.MethodPost,
+			path:            "/builds",
+			userID:          "user-1",
+			body:            `{"name": "Gaming PC", "owner": "someone"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.REQUEST_BODY_INVALID_MESSAGE,
+		},
+		{
+			name:            "Create without name",
+			method:          http.MethodPost,
+			path:            "/builds",
+			userID:          "user-1",
+			body:            `{"name": "  "}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Create for another user",
+			method:          http.MethodPost,
+			path:            "/builds",
+			userID:          "user-1",
+			body:            `{"user_id": "user-2", "name": "Gaming PC"}`,
+			expectedStatus:  http.StatusForbidden,
+			expectedMessage: constants.BUILD_FORBIDDEN_MESSAGE,
+		},
+		{
+			name:            "Import without user",
+			method:          http.MethodPost,
+			path:            "/builds/import",
+			body:            `{"text": "CPU: AMD Ryzen 7 7800X3D"}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Import without parts",
+			method:          http.MethodPost,
+			path:            "/builds/import",
+			userID:          "user-1",
+			body:            `{"text": "Total: $1,234.00"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "GET on import",
+			method:          http.MethodGet,
+			path:            "/builds/import",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Generate without budget",
+			method:          http.MethodPost,
+			path:            "/builds/generate",
+			body:            `{"use_case": "gaming"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Generate for unknown use case",
+			method:          http.MethodPost,
+			path:            "/builds/generate",
+			body:            `{"budget": 1200, "use_case": "mining"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Gallery with non numeric price",
+			method:          http.MethodGet,
+			path:            "/builds/public?min_price=cheap",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_FILTER_INVALID_MESSAGE,
+		},
+		{
+			name:            "Gallery with malformed cursor",
+			method:          http.MethodGet,
+			path:            "/builds/public?cursor=abc",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_FILTER_INVALID_MESSAGE,
+		},
+		{
+			name:            "Gallery with unknown sort",
+			method:          http.MethodGet,
+			path:            "/builds/public?sort=random",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "POST on gallery",
+			method:          http.MethodPost,
+			path:            "/builds/public",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "POST on completeness",
+			method:          http.MethodPost,
+			path:            "/builds/1/completeness",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "GET on generate",
+			method:          http.MethodGet,
+			path:            "/builds/generate",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Invalid filter",
+			method:          http.MethodGet,
+			path:            "/builds?is_public=maybe",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_FILTER_INVALID_MESSAGE,
+		},
+		{
+			name:            "Invalid build ID",
+			method:          http.MethodGet,
+			path:            "/builds/abc",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "Update without user",
+			method:          http.MethodPatch,
+			path:            "/builds/1",
+			body:            `{"name": "Renamed"}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Update with invalid currency",
+			method:          http.MethodPatch,
+			path:            "/builds/1",
+			userID:          "user-1",
+			body:            `{"currency": "dollars"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Update with client total price",
+			method:          http.MethodPatch,
+			path:            "/builds/1",
+			userID:          "user-1",
+			body:            `{"total_price": 1}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.REQUEST_BODY_INVALID_MESSAGE,
+		},
+		{
+			name:            "Fork without user",
+			method:          http.MethodPost,
+			path:            "/builds/1/fork",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "GET on fork",
+			method:          http.MethodGet,
+			path:            "/builds/1/fork",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Unknown export format",
+			method:          http.MethodGet,
+			path:            "/builds/1/export?format=pdf",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.EXPORT_FORMAT_INVALID_MESSAGE,
+		},
+		{
+			name:            "POST on export",
+			method:          http.MethodPost,
+			path:            "/builds/1/export",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Invalid build id on sheet",
+			method:          http.MethodGet,
+			path:            "/builds/abc/sheet",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "POST on sheet",
+			method:          http.MethodPost,
+			path:            "/builds/1/sheet",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Invite a collaborator without a user",
+			method:          http.MethodPost,
+			path:            "/builds/1/collaborators",
+			body:            `{"user_id": "friend"}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "PUT on a collaborator",
+			method:          http.MethodPut,
+			path:            "/builds/1/collaborators/friend",
+			userID:          "user-1",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Invalid build id on invitation",
+			method:          http.MethodPost,
+			path:            "/builds/abc/invitation",
+			userID:          "user-1",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "List invitations without a user",
+			method:          http.MethodGet,
+			path:            "/builds/invitations",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Invalid diff version",
+			method:          http.MethodGet,
+			path:            "/builds/1/versions/1/diff/latest",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.VERSION_INVALID_MESSAGE,
+		},
+		{
+			name:            "Restore without user",
+			method:          http.MethodPost,
+			path:            "/builds/1/versions/2/restore",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "GET on restore",
+			method:          http.MethodGet,
+			path:            "/builds/1/versions/2/restore",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Delete without user",
+			method:          http.MethodDelete,
+			path:            "/builds/1",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "PUT on a build part",
+			method:          http.MethodPut,
+			path:            "/builds/1/components/2",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Add part without user",
+			method:          http.MethodPost,
+			path:            "/builds/1/components",
+			body:            `{"component_id": 2}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Add part with zero quantity",
+			method:          http.MethodPost,
+			path:            "/builds/1/components",
+			userID:          "user-1",
+			body:            `{"component_id": 2, "quantity": 0}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Add part to another build",
+			method:          http.MethodPost,
+			path:            "/builds/1/components",
+			userID:          "user-1",
+			body:            `{"build_id": 2, "component_id": 2}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Invalid component ID",
+			method:          http.MethodDelete,
+			path:            "/builds/1/components/abc",
+			userID:          "user-1",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.COMPONENT_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "Update part with negative quantity",
+			method:          http.MethodPatch,
+			path:            "/builds/1/components/2",
+			userID:          "user-1",
+			body:            `{"quantity": -1}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "POST on optimize",
+			method:          http.MethodPost,
+			path:            "/builds/1/optimize",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Optimize with zero retailers",
+			method:          http.MethodGet,
+			path:            "/builds/1/optimize?max_retailers=0",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.MAX_RETAILERS_INVALID_MESSAGE,
+		},
+		{
+			name:            "Alternatives with a tolerance above the maximum",
+			method:          http.MethodGet,
+			path:            "/builds/1/cheaper-alternatives?tolerance=80",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.TOLERANCE_INVALID_MESSAGE,
+		},
+		{
+			name:            "Alternatives for an unknown use case",
+			method:          http.MethodGet,
+			path:            "/builds/1/cheaper-alternatives?use_case=mining",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.USE_CASE_INVALID_MESSAGE,
+		},
+		{
+			name:            "Upgrades with a budget that is not positive",
+			method:          http.MethodGet,
+			path:            "/builds/1/upgrades?budget=-100",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUDGET_INVALID_MESSAGE,
+		},
+		{
+			name:            "Upgrades for an unknown resolution",
+			method:          http.MethodGet,
+			path:            "/builds/1/upgrades?resolution=8k",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.RESOLUTION_INVALID_MESSAGE,
+		},
+		{
+			name:            "POST on upgrades",
+			method:          http.MethodPost,
+			path:            "/builds/1/upgrades",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Create template without user",
+			method:          http.MethodPost,
+			path:            "/templates",
+			body:            `{"name": "4K Gaming"}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Create template as a non-administrator",
+			method:          http.MethodPost,
+			path:            "/templates",
+			userID:          "user-1",
+			body:            `{"name": "4K Gaming"}`,
+			expectedStatus:  http.StatusForbidden,
+			expectedMessage: constants.ADMIN_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Delete template as a non-administrator",
+			method:          http.MethodDelete,
+			path:            "/templates/1",
+			userID:          "user-1",
+			expectedStatus:  http.StatusForbidden,
+			expectedMessage: constants.ADMIN_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Invalid template ID",
+			method:          http.MethodGet,
+			path:            "/templates/abc",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.TEMPLATE_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "Build from template without user",
+			method:          http.MethodPost,
+			path:            "/builds/from-template/1",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Build from an invalid template ID",
+			method:          http.MethodPost,
+			path:            "/builds/from-template/0",
+			userID:          "user-1",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.TEMPLATE_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "GET on build from template",
+			method:          http.MethodGet,
+			path:            "/builds/from-template/1",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "POST on a like",
+			method:          http.MethodPost,
+			path:            "/builds/1/like",
+			userID:          "user-1",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Like without user",
+			method:          http.MethodPut,
+			path:            "/builds/1/like",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Rating above five stars",
+			method:          http.MethodPut,
+			path:            "/builds/1/rating",
+			userID:          "user-1",
+			body:            `{"rating": 6}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Comment without user",
+			method:          http.MethodPost,
+			path:            "/builds/1/comments",
+			body:            `{"body": "Nice build"}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Empty comment",
+			method:          http.MethodPost,
+			path:            "/builds/1/comments",
+			userID:          "user-1",
+			body:            `{"body": "   "}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Edit comment with invalid ID",
+			method:          http.MethodPatch,
+			path:            "/builds/1/comments/abc",
+			userID:          "user-1",
+			body:            `{"body": "Edited"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.COMMENT_ID_INVALID_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.userID != "" {
+				req.Header.Set(constants.USER_ID_HEADER, tt.userID)
+			}
+			req.Header.Set("If-Match", `"1"`)
+			w := httptest.NewRecorder()
+			newBuildsMux().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.ErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, response.Message)
+		})
+	}
+
+	t.Run("Unknown build action", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/builds/1/unknown", nil)
+		req.Header.Set(constants.USER_ID_HEADER, "user-1")
+		w := httptest.NewRecorder()
+		newBuildsMux().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// TestBuildHandler_Ownership tests that only the owner can change a build and private builds stay hidden
+func TestBuildHandler_Ownership(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	buildRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+			AddRow(1, "owner", "Gaming PC", nil, false, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 3)
+	}
+	selectBuild := "SELECT (.+) FROM user_builds WHERE id = \\$1"
+	selectCollaborator := "SELECT (.+) FROM build_collaborators WHERE build_id = \\$1 AND user_id = \\$2"
+	collaboratorRow := func(userID, role string, acceptedAt interface{}) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "build_id", "user_id", "role", "invited_by", "accepted_at", "created_at", "updated_at"}).
+			AddRow(4, 1, userID, role, "owner", acceptedAt, time.Now(), time.Now())
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		userID         string
+		body           string
+		collaborator   *sqlmock.Rows
+		expectedStatus int
+	}{
+		{name: "Update by another user", method: http.MethodPatch, userID: "intruder", body: `{"name": "Mine now"}`, expectedStatus: http.StatusNotFound},
+		{name: "Delete by another user", method: http.MethodDelete, userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build read by another user", method: http.MethodGet, userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build parts read by another user", method: http.MethodGet, path: "/builds/1/components", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build forked by another user", method: http.MethodPost, path: "/builds/1/fork", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build lineage read by another user", method: http.MethodGet, path: "/builds/1/forks", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build compatibility read by another user", method: http.MethodGet, path: "/builds/1/compatibility", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build storage read by another user", method: http.MethodGet, path: "/builds/1/storage", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build bottleneck read by another user", method: http.MethodGet, path: "/builds/1/bottleneck", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build power connectors read by another user", method: http.MethodGet, path: "/builds/1/power-connectors", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build cooling read anonymously", method: http.MethodGet, path: "/builds/1/cooling", expectedStatus: http.StatusNotFound},
+		{name: "Private build compatible components read by another user", method: http.MethodGet, path: "/components/gpu?compatible_with_build=1", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build completeness read by another user", method: http.MethodGet, path: "/builds/1/completeness", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build versions read by another user", method: http.MethodGet, path: "/builds/1/versions", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Version restored by another user", method: http.MethodPost, path: "/builds/1/versions/1/restore", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build sheet read by another user", method: http.MethodGet, path: "/builds/1/sheet", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build optimized by another user", method: http.MethodGet, path: "/builds/1/optimize", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build alternatives read by another user", method: http.MethodGet, path: "/builds/1/cheaper-alternatives", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build upgrades read by another user", method: http.MethodGet, path: "/builds/1/upgrades", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build liked by another user", method: http.MethodPut, path: "/builds/1/like", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build rated by another user", method: http.MethodPut, path: "/builds/1/rating", userID: "intruder", body: `{"rating": 4}`, expectedStatus: http.StatusNotFound},
+		{name: "Private build commented on by another user", method: http.MethodPost, path: "/builds/1/comments", userID: "intruder", body: `{"body": "Nice"}`, expectedStatus: http.StatusNotFound},
+		{name: "Own build rated by its owner", method: http.MethodPut, path: "/builds/1/rating", userID: "owner", body: `{"rating": 5}`, expectedStatus: http.StatusBadRequest},
+		{name: "Part removed by another user", method: http.MethodDelete, path: "/builds/1/components/2", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Private build read with a pending invitation", method: http.MethodGet, userID: "friend", collaborator: collaboratorRow("friend", "editor", nil), expectedStatus: http.StatusNotFound},
+		{name: "Part removed by a viewer", method: http.MethodDelete, path: "/builds/1/components/2", userID: "friend", collaborator: collaboratorRow("friend", "viewer", time.Now()), expectedStatus: http.StatusForbidden},
+		{name: "Visibility changed by an editor", method: http.MethodPatch, userID: "friend", body: `{"is_public": true}`, collaborator: collaboratorRow("friend", "editor", time.Now()), expectedStatus: http.StatusForbidden},
+		{name: "Build deleted by an editor", method: http.MethodDelete, userID: "friend", collaborator: collaboratorRow("friend", "editor", time.Now()), expectedStatus: http.StatusForbidden},
+		{name: "Collaborator invited by an editor", method: http.MethodPost, path: "/builds/1/collaborators", userID: "friend", body: `{"user_id": "other", "role": "viewer"}`, collaborator: collaboratorRow("friend", "editor", time.Now()), expectedStatus: http.StatusForbidden},
+		{name: "Collaborators listed by another user", method: http.MethodGet, path: "/builds/1/collaborators", userID: "intruder", expectedStatus: http.StatusNotFound},
+		{name: "Creator invited to their own build", method: http.MethodPost, path: "/builds/1/collaborators", userID: "owner", body: `{"user_id": "owner", "role": "editor"}`, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(selectBuild).WithArgs(int64(1)).WillReturnRows(buildRow())
+			if tt.userID != "owner" && tt.userID != "" {
+				rows := tt.collaborator
+				if rows == nil {
+					rows = sqlmock.NewRows([]string{"id"})
+				}
+				mock.ExpectQuery(selectCollaborator).WithArgs(int64(1), tt.userID).WillReturnRows(rows)
+			}
+
+			path := tt.path
+			if path == "" {
+				path = "/builds/1"
+			}
+			req := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			req.Header.Set(constants.USER_ID_HEADER, tt.userID)
+			req.Header.Set("If-Match", `"3"`)
+			w := httptest.NewRecorder()
+			newBuildsMux().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	t.Run("Remove a part the build does not have", func(t *testing.T) {
+		mock.ExpectQuery(selectBuild).WithArgs(int64(1)).WillReturnRows(buildRow())
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components").WithArgs(int64(1), int64(5)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		req := httptest.NewRequest(http.MethodDelete, "/builds/1/components/5", nil)
+		req.Header.Set(constants.USER_ID_HEADER, "owner")
+		req.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+		newBuildsMux().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response models.ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, constants.BUILD_PART_NOT_FOUND_MESSAGE, response.Message)
+	})
+
+	t.Run("Remove a part when its version cannot be recorded", func(t *testing.T) {
+		mock.ExpectQuery(selectBuild).WithArgs(int64(1)).WillReturnRows(buildRow())
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components").WithArgs(int64(1), int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE user_builds SET updated_at = now\\(\\), revision = revision \\+ 1 WHERE id = \\$1 AND revision = \\$2").
+			WithArgs(int64(1), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectBuild).WithArgs(int64(1)).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		req := httptest.NewRequest(http.MethodDelete, "/builds/1/components/2", nil)
+		req.Header.Set(constants.USER_ID_HEADER, "owner")
+		req.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+		newBuildsMux().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("Remove a part with a stale revision", func(t *testing.T) {
+		mock.ExpectQuery(selectBuild).WithArgs(int64(1)).WillReturnRows(buildRow())
+		mock.ExpectQuery(selectCollaborator).WithArgs(int64(1), "friend").WillReturnRows(collaboratorRow("friend", "editor", time.Now()))
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components").WithArgs(int64(1), int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE user_builds SET updated_at = now\\(\\), revision = revision \\+ 1 WHERE id = \\$1 AND revision = \\$2").
+			WithArgs(int64(1), 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		req := httptest.NewRequest(http.MethodDelete, "/builds/1/components/2", nil)
+		req.Header.Set(constants.USER_ID_HEADER, "friend")
+		req.Header.Set("If-Match", `"2"`)
+		w := httptest.NewRecorder()
+		newBuildsMux().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		var response models.ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, constants.BUILD_CONFLICT_MESSAGE, response.Message)
+	})
+
+	t.Run("Delete by the owner", func(t *testing.T) {
+		mock.ExpectQuery(selectBuild).WithArgs(int64(1)).WillReturnRows(buildRow())
+		mock.ExpectExec("DELETE FROM user_builds WHERE id = \\$1 AND revision = \\$2").WithArgs(int64(1), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		req := httptest.NewRequest(http.MethodDelete, "/builds/1", nil)
+		req.Header.Set(constants.USER_ID_HEADER, "owner")
+		req.Header.Set("If-Match", `W/"3"`)
+		w := httptest.NewRecorder()
+		newBuildsMux().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestBuildHandlers_IfMatchRequired tests that build and part changes without an If-Match header naming a revision are
+// rejected before anything is written
+func TestBuildHandlers_IfMatchRequired(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "Update a build", method: http.MethodPatch, path: "/builds/1", body: `{"name": "Renamed"}`},
+		{name: "Delete a build", method: http.MethodDelete, path: "/builds/1"},
+		{name: "Add a part", method: http.MethodPost, path: "/builds/1/components", body: `{"component_id": 5, "quantity": 1}`},
+		{name: "Update a part", method: http.MethodPatch, path: "/builds/1/components/2", body: `{"quantity": 2}`},
+		{name: "Remove a part", method: http.MethodDelete, path: "/builds/1/components/2"},
+		{name: "Restore a version", method: http.MethodPost, path: "/builds/1/versions/1/restore"},
+		{name: "Update a shared build", method: http.MethodPatch, path: "/shared/edit-token", body: `{"name": "Renamed"}`},
+		{name: "Add a part to a shared build", method: http.MethodPost, path: "/shared/edit-token/components", body: `{"component_id": 5, "quantity": 1}`},
+		{name: "Update a part of a shared build", method: http.MethodPatch, path: "/shared/edit-token/components/2", body: `{"quantity": 2}`},
+		{name: "Remove a part of a shared build", method: http.MethodDelete, path: "/shared/edit-token/components/2"},
+	}
+	mux := newBuildsMux()
+	mux.Handle("/shared/", newSharesMux())
+
+	for _, header := range []string{"", "*"} {
+		for _, tt := range tests {
+			t.Run(tt.name+" with If-Match "+strconv.Quote(header), func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				req.Header.Set(constants.USER_ID_HEADER, "owner")
+				if header != "" {
+					req.Header.Set("If-Match", header)
+				}
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+				var response models.ErrorResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, constants.IF_MATCH_REQUIRED_MESSAGE, response.Message)
+			})
+		}
+	}
+}
+
+// TestBuildHandlers_StaleRevision tests that every build and part change based on an old revision fails with a
+// conflict and rolls back
+func TestBuildHandlers_StaleRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	buildRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+			AddRow(1, "owner", "Gaming PC", nil, false, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 3)
+	}
+	partRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "build_id", "component_id", "quantity", "selected_price_id", "notes", "created_at"}).
+			AddRow(2, 1, 5, 2, nil, nil, time.Now())
+	}
+	expectBuild := func() {
+		mock.ExpectQuery("SELECT (.+) FROM user_builds WHERE id = \\$1").WithArgs(int64(1)).WillReturnRows(buildRow())
+	}
+	expectShare := func() {
+		mock.ExpectQuery("SELECT (.+) FROM build_shares WHERE token_hash = \\$1 AND revoked_at IS NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "build_id", "access", "created_by", "expires_at", "revoked_at", "created_at"}).
+				AddRow(1, 1, models.BuildShareAccessEdit, "owner", nil, nil, time.Now()))
+	}
+	expectComponent := func() {
+		mock.ExpectQuery("SELECT (.+) FROM components").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "category", "brand", "model", "sku", "upc", "specs", "created_at"}).
+				AddRow(5, "cpu", "Acme", "X1", nil, nil, []byte(`{}`), time.Now()))
+	}
+	expectStaleBump := func() {
+		mock.ExpectExec("UPDATE user_builds SET updated_at = now\\(\\), revision = revision \\+ 1 WHERE id = \\$1 AND revision = \\$2").
+			WithArgs(int64(1), 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+	}
+	expectStaleUpdate := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE user_builds SET (.+) WHERE id = \\$2 AND revision = \\$3").
+			WithArgs("Renamed", int64(1), 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+	}
+	expectStaleAdd := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO build_components").WillReturnRows(partRow())
+		expectStaleBump()
+	}
+	expectStalePartUpdate := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE build_components").WillReturnRows(partRow())
+		expectStaleBump()
+	}
+	expectStaleRemove := func() {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components").WithArgs(int64(1), int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectStaleBump()
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		expect func()
+	}{
+		{name: "Update a build", method: http.MethodPatch, path: "/builds/1", body: `{"name": "Renamed"}`, expect: func() {
+			expectBuild()
+			expectStaleUpdate()
+		}},
+		{name: "Delete a build", method: http.MethodDelete, path: "/builds/1", expect: func() {
+			expectBuild()
+			mock.ExpectExec("DELETE FROM user_builds WHERE id = \\$1 AND revision = \\$2").WithArgs(int64(1), 2).WillReturnResult(sqlmock.NewResult(0, 0))
+		}},
+		{name: "Add a part", method: http.MethodPost, path: "/builds/1/components", body: `{"component_id": 5, "quantity": 1}`, expect: func() {
+			expectComponent()
+			expectBuild()
+			expectStaleAdd()
+		}},
+		{name: "Update a part", method: http.MethodPatch, path: "/builds/1/components/5", body: `{"quantity": 2}`, expect: func() {
+			expectBuild()
+			expectStalePartUpdate()
+		}},
+		{name: "Remove a part", method: http.MethodDelete, path: "/builds/1/components/2", expect: func() {
+			expectBuild()
+			expectStaleRemove()
+		}},
+		{name: "Restore a version", method: http.MethodPost, path: "/builds/1/versions/1/restore", expect: func() {
+			expectBuild()
+			mock.ExpectQuery("SELECT (.+) FROM build_versions WHERE build_id = \\$1 AND version = \\$2").WithArgs(int64(1), 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "build_id", "version", "parts", "total_price", "currency", "reason", "created_by", "created_at"}).
+					AddRow(1, 1, 1, []byte(`[]`), 0, "USD", "Created", "owner", time.Now()))
+			mock.ExpectBegin()
+			mock.ExpectExec("DELETE FROM build_components WHERE build_id = \\$1").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 0))
+			expectStaleBump()
+		}},
+		{name: "Update a shared build", method: http.MethodPatch, path: "/shared/edit-token", body: `{"name": "Renamed"}`, expect: func() {
+			expectShare()
+			expectStaleUpdate()
+		}},
+		{name: "Add a part to a shared build", method: http.MethodPost, path: "/shared/edit-token/components", body: `{"component_id": 5, "quantity": 1}`, expect: func() {
+			expectShare()
+			expectComponent()
+			expectStaleAdd()
+		}},
+		{name: "Update a part of a shared build", method: http.MethodPatch, path: "/shared/edit-token/components/5", body: `{"quantity": 2}`, expect: func() {
+			expectShare()
+			expectStalePartUpdate()
+		}},
+		{name: "Remove a part of a shared build", method: http.MethodDelete, path: "/shared/edit-token/components/2", expect: func() {
+			expectShare()
+			expectStaleRemove()
+		}},
+	}
+	mux := newBuildsMux()
+	mux.Handle("/shared/", newSharesMux())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(constants.USER_ID_HEADER, "owner")
+			req.Header.Set("If-Match", `"2"`)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+			var response models.ErrorResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, constants.BUILD_CONFLICT_MESSAGE, response.Message)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestGetIfMatchRevision tests parsing of the revision a change is based on
+func TestGetIfMatchRevision(t *testing.T) {
+	revision := func(value int) *int { return &value }
+	tests := []struct {
+		header   string
+		expected *int
+	}{
+		{header: "", expected: nil},
+		{header: "*", expected: nil},
+		{header: `"7"`, expected: revision(7)},
+		{header: `W/"7"`, expected: revision(7)},
+		{header: "7", expected: revision(7)},
+		{header: `"abc"`, expected: revision(0)},
+		{header: `"-1"`, expected: revision(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/builds/1", nil)
+			req.Header.Set("If-Match", tt.header)
+
+			assert.Equal(t, tt.expected, getIfMatchRevision(req))
+		})
+	}
+}
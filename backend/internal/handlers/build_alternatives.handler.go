@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetCheaperAlternativesHandler suggests cheaper parts that keep a build compatible. The optional tolerance query
+// parameter is the percentage a part's performance, capacity or wattage may drop by, and use_case picks the
+// performance score compared.
+func GetCheaperAlternativesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	var tolerance *float64
+	if raw := strings.TrimSpace(r.URL.Query().Get("tolerance")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 || value > constants.ALTERNATIVES_MAX_TOLERANCE {
+			utils.WriteError(w, http.StatusBadRequest, constants.TOLERANCE_INVALID_MESSAGE, nil)
+			return
+		}
+		tolerance = &value
+	}
+	useCase := r.URL.Query().Get("use_case")
+	if useCase != "" && !services.IsValidUseCase(strings.ToLower(strings.TrimSpace(useCase))) {
+		utils.WriteError(w, http.StatusBadRequest, constants.USE_CASE_INVALID_MESSAGE, nil)
+		return
+	}
+	utils.Log(constants.HANDLER_FIND_CHEAPER_ALTERNATIVES_START, nil, buildID)
+
+	alternatives, err := services.FindCheaperAlternatives(buildID, getRequestUserID(r), tolerance, useCase)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_FIND_CHEAPER_ALTERNATIVES_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_FIND_CHEAPER_ALTERNATIVES_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, alternatives)
+}
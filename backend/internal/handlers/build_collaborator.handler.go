@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildCollaboratorsHandler lists the collaborators of a build on GET and invites a user on POST. Only owners may invite.
+func BuildCollaboratorsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		utils.Log(constants.HANDLER_LIST_BUILD_COLLABORATORS_START, nil, buildID)
+
+		collaborators, err := services.ListBuildCollaborators(buildID, userID)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_LIST_BUILD_COLLABORATORS_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_LIST_BUILD_COLLABORATORS_SUCCESS, nil, buildID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, collaborators)
+	case http.MethodPost:
+		utils.Log(constants.HANDLER_INVITE_BUILD_COLLABORATOR_START, nil, buildID)
+
+		var input models.BuildCollaboratorInvite
+		if !decodeJSONBody(w, r, &input) {
+			return
+		}
+
+		collaborator, err := services.InviteBuildCollaborator(buildID, userID, input)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_INVITE_BUILD_COLLABORATOR_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_INVITE_BUILD_COLLABORATOR_SUCCESS, nil, collaborator.UserID, buildID)
+		utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, collaborator)
+	}
+}
+
+// BuildCollaboratorHandler changes the role of the collaborator identified by the {userId} path value on PATCH and
+// removes them on DELETE. Collaborators may remove themselves; everything else needs an owner.
+func BuildCollaboratorHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	collaboratorID := r.PathValue("userId")
+
+	switch r.Method {
+	case http.MethodPatch:
+		utils.Log(constants.HANDLER_UPDATE_BUILD_COLLABORATOR_START, nil, collaboratorID, buildID)
+
+		var update models.BuildCollaboratorUpdate
+		if !decodeJSONBody(w, r, &update) {
+			return
+		}
+
+		collaborator, err := services.UpdateBuildCollaborator(buildID, collaboratorID, userID, update)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_UPDATE_BUILD_COLLABORATOR_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_UPDATE_BUILD_COLLABORATOR_SUCCESS, nil, collaboratorID, buildID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, collaborator)
+	case http.MethodDelete:
+		utils.Log(constants.HANDLER_REMOVE_BUILD_COLLABORATOR_START, nil, collaboratorID, buildID)
+
+		if err := services.RemoveBuildCollaborator(buildID, collaboratorID, userID); err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_REMOVE_BUILD_COLLABORATOR_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_REMOVE_BUILD_COLLABORATOR_SUCCESS, nil, collaboratorID, buildID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, nil)
+	}
+}
+
+// BuildInvitationHandler accepts the caller's invitation to the build identified by the {id} path value on POST and
+// declines it on DELETE
+func BuildInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_RESPOND_BUILD_INVITATION_START, nil, r.Method, buildID)
+
+	switch r.Method {
+	case http.MethodPost:
+		collaborator, err := services.AcceptBuildInvitation(buildID, userID)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_RESPOND_BUILD_INVITATION_ERROR, r.Method, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_RESPOND_BUILD_INVITATION_SUCCESS, nil, r.Method, buildID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, collaborator)
+	case http.MethodDelete:
+		if err := services.DeclineBuildInvitation(buildID, userID); err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_RESPOND_BUILD_INVITATION_ERROR, r.Method, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_RESPOND_BUILD_INVITATION_SUCCESS, nil, r.Method, buildID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, nil)
+	}
+}
+
+// GetBuildInvitationsHandler lists the caller's pending invitations to collaborate on builds
+func GetBuildInvitationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_LIST_BUILD_INVITATIONS_START, nil, userID)
+
+	invitations, err := services.ListBuildInvitations(userID)
+	if err != nil {
+		utils.Log(constants.HANDLER_LIST_BUILD_INVITATIONS_ERROR, err, userID)
+		utils.WriteError(w, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR_MESSAGE, err)
+		return
+	}
+
+	utils.Log(constants.HANDLER_LIST_BUILD_INVITATIONS_SUCCESS, nil, len(invitations), userID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, invitations)
+}
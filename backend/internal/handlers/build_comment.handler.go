@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildCommentsHandler lists the comment threads of a build on GET and comments on it on POST
+func BuildCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		utils.Log(constants.HANDLER_LIST_BUILD_COMMENTS_START, nil, buildID)
+
+		comments, err := services.ListBuildComments(buildID, getRequestUserID(r))
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_LIST_BUILD_COMMENTS_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_LIST_BUILD_COMMENTS_SUCCESS, nil, buildID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, comments)
+	case http.MethodPost:
+		userID, ok := requireRequestUserID(w, r)
+		if !ok {
+			return
+		}
+		utils.Log(constants.HANDLER_CREATE_BUILD_COMMENT_START, nil, buildID)
+
+		var input models.BuildCommentCreate
+		if !decodeJSONBody(w, r, &input) {
+			return
+		}
+
+		comment, err := services.CreateBuildComment(buildID, userID, input)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_CREATE_BUILD_COMMENT_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_CREATE_BUILD_COMMENT_SUCCESS, nil, comment.ID, buildID)
+		utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, comment)
+	}
+}
+
+// BuildCommentHandler edits the comment identified by the {commentId} path value on PATCH and deletes it on DELETE
+func BuildCommentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	raw := r.PathValue("commentId")
+	commentID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || commentID <= 0 {
+		utils.Log(constants.HANDLER_INVALID_COMMENT_ID, err, raw)
+		utils.WriteError(w, http.StatusBadRequest, constants.COMMENT_ID_INVALID_MESSAGE, nil)
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPatch:
+		utils.Log(constants.HANDLER_UPDATE_BUILD_COMMENT_START, nil, commentID)
+
+		var input models.BuildCommentUpdate
+		if !decodeJSONBody(w, r, &input) {
+			return
+		}
+
+		comment, err := services.UpdateBuildComment(buildID, commentID, userID, input)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_UPDATE_BUILD_COMMENT_ERROR, commentID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_UPDATE_BUILD_COMMENT_SUCCESS, nil, commentID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, comment)
+	case http.MethodDelete:
+		utils.Log(constants.HANDLER_DELETE_BUILD_COMMENT_START, nil, commentID)
+
+		if err := services.DeleteBuildComment(buildID, commentID, userID); err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_DELETE_BUILD_COMMENT_ERROR, commentID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_DELETE_BUILD_COMMENT_SUCCESS, nil, commentID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, nil)
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ExportBuildHandler renders the build identified by the {id} path value in the format given by the
+// format query parameter, markdown by default
+func ExportBuildHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	name := r.URL.Query().Get("format")
+	if name == "" {
+		name = "markdown"
+	}
+	format, ok := services.GetBuildExportFormat(name)
+	if !ok {
+		utils.Log(constants.HANDLER_INVALID_EXPORT_FORMAT, nil, name)
+		utils.WriteError(w, http.StatusBadRequest, constants.EXPORT_FORMAT_INVALID_MESSAGE, services.BuildExportFormatNames())
+		return
+	}
+	utils.Log(constants.HANDLER_EXPORT_BUILD_START, nil, buildID, format.Name)
+
+	output, err := services.ExportBuild(buildID, getRequestUserID(r), format)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_EXPORT_BUILD_ERROR, buildID)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="build-%d.%s"`, buildID, format.Extension))
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+
+	utils.Log(constants.HANDLER_EXPORT_BUILD_SUCCESS, nil, buildID, format.Name)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ForkBuildHandler copies the build identified by the {id} path value into a new private build owned by the caller
+func ForkBuildHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_FORK_BUILD_START, nil, buildID)
+
+	fork, err := services.ForkUserBuild(buildID, userID)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_FORK_BUILD_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_FORK_BUILD_SUCCESS, nil, buildID, fork.ID)
+	utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, fork)
+}
+
+// GetBuildForksHandler returns the fork count, ancestors and forks of the build identified by the {id} path value
+func GetBuildForksHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_GET_BUILD_LINEAGE_START, nil, buildID)
+
+	lineage, err := services.GetBuildLineage(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_GET_BUILD_LINEAGE_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GET_BUILD_LINEAGE_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, lineage)
+}
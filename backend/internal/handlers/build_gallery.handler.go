@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetPublicBuildsHandler lists public builds as cards, filtered and sorted by the query string.
+// Pass next_cursor from a response as cursor to get the following page.
+func GetPublicBuildsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	filter, err := parseBuildGalleryFilter(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, constants.BUILD_FILTER_INVALID_MESSAGE, err.Error())
+		return
+	}
+	utils.Log(constants.HANDLER_LIST_PUBLIC_BUILDS_START, nil, filter.Sort)
+
+	page, err := services.ListPublicBuilds(filter)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_LIST_PUBLIC_BUILDS_ERROR)
+		return
+	}
+
+	utils.Log(constants.HANDLER_LIST_PUBLIC_BUILDS_SUCCESS, nil, len(page.Builds))
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, page)
+}
+
+// parseBuildGalleryFilter reads the gallery filters from the query string
+func parseBuildGalleryFilter(r *http.Request) (models.BuildGalleryFilter, error) {
+	query := r.URL.Query()
+	filter := models.BuildGalleryFilter{
+		Currency: strings.TrimSpace(query.Get("currency")),
+		Sort:     strings.TrimSpace(query.Get("sort")),
+	}
+
+	optionalString := func(key string) *string {
+		value := strings.TrimSpace(query.Get(key))
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	optionalFloat := func(key string) (*float64, error) {
+		raw := strings.TrimSpace(query.Get(key))
+		if raw == "" {
+			return nil, nil
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", key)
+		}
+		return &value, nil
+	}
+
+	var err error
+	if filter.MinPrice, err = optionalFloat("min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = optionalFloat("max_price"); err != nil {
+		return filter, err
+	}
+	filter.Region = optionalString("region")
+	filter.UseCase = optionalString("use_case")
+	filter.CPUBrand = optionalString("cpu_brand")
+	filter.GPUBrand = optionalString("gpu_brand")
+
+	if raw := optionalString("component_id"); raw != nil {
+		id, err := strconv.ParseInt(*raw, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("component_id must be an integer")
+		}
+		filter.ComponentID = &id
+	}
+	if raw := optionalString("limit"); raw != nil {
+		if filter.Limit, err = strconv.Atoi(*raw); err != nil {
+			return filter, fmt.Errorf("limit must be an integer")
+		}
+	}
+	if raw := optionalString("cursor"); raw != nil {
+		cursor, err := services.DecodeGalleryCursor(*raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = &cursor
+	}
+	return filter, nil
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GenerateBuildHandler suggests the best scoring compatible build within a budget
+func GenerateBuildHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	var request models.BuildGeneratorRequest
+	if !decodeJSONBody(w, r, &request) {
+		return
+	}
+	utils.Log(constants.HANDLER_GENERATE_BUILD_START, nil, request.UseCase, request.Budget)
+
+	result, err := services.GenerateBuild(request)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_GENERATE_BUILD_ERROR, request.UseCase)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GENERATE_BUILD_SUCCESS, nil, result.UseCase, len(result.Alternatives))
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, result)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ImportBuildHandler creates a build for the requesting user from a pasted parts list
+func ImportBuildHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_IMPORT_BUILD_START, nil, userID)
+
+	var input models.BuildImport
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	result, err := services.ImportBuild(userID, input)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_IMPORT_BUILD_ERROR, userID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_IMPORT_BUILD_SUCCESS, nil, result.Build.ID, len(result.Unmatched))
+	utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, result)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// OptimizeBuildPurchaseHandler returns the cheapest plan to buy the parts of a build, shipping included.
+// The optional max_retailers query parameter limits how many retailers the plan orders from.
+func OptimizeBuildPurchaseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	var maxRetailers *int
+	if raw := strings.TrimSpace(r.URL.Query().Get("max_retailers")); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			utils.WriteError(w, http.StatusBadRequest, constants.MAX_RETAILERS_INVALID_MESSAGE, nil)
+			return
+		}
+		maxRetailers = &value
+	}
+	utils.Log(constants.HANDLER_OPTIMIZE_BUILD_PURCHASE_START, nil, buildID)
+
+	plan, err := services.OptimizeBuildPurchase(buildID, getRequestUserID(r), maxRetailers)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_OPTIMIZE_BUILD_PURCHASE_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_OPTIMIZE_BUILD_PURCHASE_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, plan)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildComponentsHandler lists the parts of a build on GET and adds a part on POST
+func BuildComponentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handleListBuildParts(w, r, buildID)
+	case http.MethodPost:
+		handleAddBuildPart(w, r, buildID)
+	}
+}
+
+// BuildComponentHandler updates or removes the part identified by the {componentId} path value
+func BuildComponentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	componentID, ok := parseComponentIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPatch:
+		handleUpdateBuildPart(w, r, buildID, componentID)
+	case http.MethodDelete:
+		handleRemoveBuildPart(w, r, buildID, componentID)
+	}
+}
+
+func handleListBuildParts(w http.ResponseWriter, r *http.Request, buildID int64) {
+	utils.Log(constants.HANDLER_GET_BUILD_START, nil, buildID)
+
+	build, err := services.GetUserBuild(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_GET_BUILD_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GET_BUILD_SUCCESS, nil, buildID)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, build.Components)
+}
+
+func handleAddBuildPart(w http.ResponseWriter, r *http.Request, buildID int64) {
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.BuildComponentCreate
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+	utils.Log(constants.HANDLER_ADD_BUILD_PART_START, nil, input.ComponentID, buildID)
+
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	build, err := services.AddBuildPart(buildID, userID, revision, input)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_ADD_BUILD_PART_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_ADD_BUILD_PART_SUCCESS, nil, buildID)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, build)
+}
+
+func handleUpdateBuildPart(w http.ResponseWriter, r *http.Request, buildID, componentID int64) {
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_UPDATE_BUILD_PART_START, nil, componentID, buildID)
+
+	var update models.BuildComponentUpdate
+	if !decodeJSONBody(w, r, &update) {
+		return
+	}
+
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	build, err := services.UpdateBuildPart(buildID, componentID, userID, revision, update)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_UPDATE_BUILD_PART_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_UPDATE_BUILD_PART_SUCCESS, nil, buildID)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, build)
+}
+
+func handleRemoveBuildPart(w http.ResponseWriter, r *http.Request, buildID, componentID int64) {
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_REMOVE_BUILD_PART_START, nil, componentID, buildID)
+
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	build, err := services.RemoveBuildPart(buildID, componentID, userID, revision)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_REMOVE_BUILD_PART_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_REMOVE_BUILD_PART_SUCCESS, nil, buildID)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, build)
+}
+
+// parseComponentIDPathValue reads the {componentId} path value, writing a 400 response when it is not a valid component ID
+func parseComponentIDPathValue(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.PathValue("componentId")
+	componentID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || componentID <= 0 {
+		utils.Log(constants.HANDLER_INVALID_COMPONENT_ID, err, raw)
+		utils.WriteError(w, http.StatusBadRequest, constants.COMPONENT_ID_INVALID_MESSAGE, nil)
+		return 0, false
+	}
+	return componentID, true
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildLikeHandler reports whether the user likes a build on GET, likes it on PUT and unlikes it on DELETE
+func BuildLikeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var status models.BuildLikeStatus
+	var err error
+	if r.Method == http.MethodGet {
+		status, err = services.GetBuildLikeStatus(buildID, userID)
+	} else {
+		utils.Log(constants.HANDLER_SET_BUILD_LIKE_START, nil, buildID)
+		status, err = services.SetBuildLike(buildID, userID, r.Method == http.MethodPut)
+	}
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_SET_BUILD_LIKE_ERROR, buildID)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_SET_BUILD_LIKE_SUCCESS, nil, buildID)
+	}
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, status)
+}
+
+// BuildRatingHandler returns the user's rating of a build on GET, rates it on PUT and removes the rating on DELETE
+func BuildRatingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var status models.BuildRatingStatus
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		status, err = services.GetBuildRatingStatus(buildID, userID)
+	case http.MethodPut:
+		var input models.BuildRatingInput
+		if !decodeJSONBody(w, r, &input) {
+			return
+		}
+		utils.Log(constants.HANDLER_SET_BUILD_RATING_START, nil, buildID)
+		status, err = services.SetBuildRating(buildID, userID, &input.Rating)
+	case http.MethodDelete:
+		utils.Log(constants.HANDLER_SET_BUILD_RATING_START, nil, buildID)
+		status, err = services.SetBuildRating(buildID, userID, nil)
+	}
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_SET_BUILD_RATING_ERROR, buildID)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_SET_BUILD_RATING_SUCCESS, nil, buildID)
+	}
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, status)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildSharesHandler lists the share links of a build on GET and creates one on POST. Only the owner may do either.
+func BuildSharesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		utils.Log(constants.HANDLER_LIST_BUILD_SHARES_START, nil, buildID)
+
+		shares, err := services.ListBuildShares(buildID, userID)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_LIST_BUILD_SHARES_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_LIST_BUILD_SHARES_SUCCESS, nil, buildID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, shares)
+	case http.MethodPost:
+		utils.Log(constants.HANDLER_CREATE_BUILD_SHARE_START, nil, buildID)
+
+		var input models.BuildShareCreate
+		if !decodeJSONBody(w, r, &input) {
+			return
+		}
+
+		share, err := services.CreateBuildShare(buildID, userID, input)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_CREATE_BUILD_SHARE_ERROR, buildID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_CREATE_BUILD_SHARE_SUCCESS, nil, share.ID, buildID)
+		utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, share)
+	}
+}
+
+// BuildShareHandler revokes the share link identified by the {shareId} path value
+func BuildShareHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	raw := r.PathValue("shareId")
+	shareID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || shareID <= 0 {
+		utils.Log(constants.HANDLER_INVALID_SHARE_ID, err, raw)
+		utils.WriteError(w, http.StatusBadRequest, constants.SHARE_ID_INVALID_MESSAGE, nil)
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_REVOKE_BUILD_SHARE_START, nil, shareID, buildID)
+
+	if err := services.RevokeBuildShare(buildID, shareID, userID); err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_REVOKE_BUILD_SHARE_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_REVOKE_BUILD_SHARE_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, nil)
+}
+
+// SharedBuildHandler returns the build behind the {token} path value on GET and updates it on PATCH when the link allows editing
+func SharedBuildHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+
+	var build models.SharedBuild
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		build, err = services.GetSharedBuild(token)
+	case http.MethodPatch:
+		var update models.UserBuildUpdate
+		if !decodeJSONBody(w, r, &update) {
+			return
+		}
+		revision, ok := requireIfMatchRevision(w, r)
+		if !ok {
+			return
+		}
+		build, err = services.UpdateSharedBuild(token, revision, update)
+	default:
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	writeSharedBuild(w, r, build, err, http.StatusOK)
+}
+
+// SharedBuildComponentsHandler adds a part to a shared build through an editable link
+func SharedBuildComponentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	var input models.BuildComponentCreate
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	build, err := services.AddSharedBuildPart(r.PathValue("token"), revision, input)
+	writeSharedBuild(w, r, build, err, http.StatusCreated)
+}
+
+// SharedBuildComponentHandler updates or removes a part of a shared build through an editable link
+func SharedBuildComponentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	componentID, ok := parseComponentIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	token := r.PathValue("token")
+
+	var update models.BuildComponentUpdate
+	if r.Method == http.MethodPatch && !decodeJSONBody(w, r, &update) {
+		return
+	}
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	var build models.SharedBuild
+	var err error
+	switch r.Method {
+	case http.MethodPatch:
+		build, err = services.UpdateSharedBuildPart(token, componentID, revision, update)
+	case http.MethodDelete:
+		build, err = services.RemoveSharedBuildPart(token, componentID, revision)
+	}
+
+	writeSharedBuild(w, r, build, err, http.StatusOK)
+}
+
+// writeSharedBuild writes the result of a shared build operation. Tokens are never logged.
+func writeSharedBuild(w http.ResponseWriter, r *http.Request, build models.SharedBuild, err error, status int) {
+	utils.Log(constants.HANDLER_SHARED_BUILD_START, nil, r.Method)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_SHARED_BUILD_ERROR, r.Method)
+		return
+	}
+
+	utils.Log(constants.HANDLER_SHARED_BUILD_SUCCESS, nil, r.Method)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, status, constants.SUCCESS_MESSAGE, build)
+}
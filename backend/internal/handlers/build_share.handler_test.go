@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSharesMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/builds/{id}/shares", BuildSharesHandler)
+	mux.HandleFunc("/builds/{id}/shares/{shareId}", BuildShareHandler)
+	mux.HandleFunc("/shared/{token}", SharedBuildHandler)
+	mux.HandleFunc("/shared/{token}/components", SharedBuildComponentsHandler)
+	mux.HandleFunc("/shared/{token}/components/{componentId}", SharedBuildComponentHandler)
+	return mux
+}
+
+// TestBuildShareHandlers_Validation tests method, identity, path and body validation of the share endpoints
+func TestBuildShareHandlers_Validation(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		userID          string
+		body            string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "Create without user",
+			method:          http.MethodPost,
+			path:            "/builds/1/shares",
+			body:            `{"access": "read"}`,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: constants.USER_ID_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Create with unknown access",
+			method:          http.MethodPost,
+			path:            "/builds/1/shares",
+			userID:          "owner",
+			body:            `{"access": "admin"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Create already expired",
+			method:          http.MethodPost,
+			path:            "/builds/1/shares",
+			userID:          "owner",
+			body:            `{"access": "read", "expires_at": "2001-01-01T00:00:00Z"}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+		{
+			name:            "Invalid share ID",
+			method:          http.MethodDelete,
+			path:            "/builds/1/shares/abc",
+			userID:          "owner",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.SHARE_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "DELETE on a shared build",
+			method:          http.MethodDelete,
+			path:            "/shared/token",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Change visibility through a link",
+			method:          http.MethodPatch,
+			path:            "/shared/token",
+			body:            `{"is_public": true}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BAD_REQUEST_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.userID != "" {
+				req.Header.Set(constants.USER_ID_HEADER, tt.userID)
+			}
+			req.Header.Set("If-Match", `"1"`)
+			w := httptest.NewRecorder()
+			newSharesMux().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.ErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, response.Message)
+		})
+	}
+}
+
+// TestSharedBuildHandler_Access tests that unknown tokens are not found and read-only tokens cannot edit
+func TestSharedBuildHandler_Access(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	selectShare := "SELECT (.+) FROM build_shares WHERE token_hash = \\$1 AND revoked_at IS NULL"
+	shareColumns := []string{"id", "build_id", "access", "created_by", "expires_at", "revoked_at", "created_at"}
+
+	t.Run("Unknown token", func(t *testing.T) {
+		mock.ExpectQuery(selectShare).WillReturnRows(sqlmock.NewRows(shareColumns))
+
+		req := httptest.NewRequest(http.MethodGet, "/shared/unknown", nil)
+		w := httptest.NewRecorder()
+		newSharesMux().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response models.ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, constants.SHARE_NOT_FOUND_MESSAGE, response.Message)
+	})
+
+	t.Run("Read-only token removing a part", func(t *testing.T) {
+		mock.ExpectQuery(selectShare).
+			WillReturnRows(sqlmock.NewRows(shareColumns).AddRow(1, 7, models.BuildShareAccessRead, "owner", nil, nil, time.Now()))
+
+		req := httptest.NewRequest(http.MethodDelete, "/shared/read-token/components/3", nil)
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+		newSharesMux().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		var response models.ErrorResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, constants.SHARE_READ_ONLY_MESSAGE, response.Message)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetBuildSheetHandler renders a printable HTML spec sheet of the build identified by the {id} path value
+func GetBuildSheetHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_RENDER_BUILD_SHEET_START, nil, buildID)
+
+	output, err := services.RenderBuildSheet(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_RENDER_BUILD_SHEET_ERROR, buildID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+
+	utils.Log(constants.HANDLER_RENDER_BUILD_SHEET_SUCCESS, nil, buildID)
+}
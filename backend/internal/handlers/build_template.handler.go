@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// fromTemplateAction is the path segment of POST /builds/from-template/{id}
+const fromTemplateAction = "from-template"
+
+// BuildTemplatesHandler lists the build templates on GET and creates one on POST. Only administrators can create templates.
+func BuildTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		utils.Log(constants.HANDLER_LIST_BUILD_TEMPLATES_START, nil)
+
+		templates, err := services.ListBuildTemplates()
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_LIST_BUILD_TEMPLATES_ERROR)
+			return
+		}
+
+		utils.Log(constants.HANDLER_LIST_BUILD_TEMPLATES_SUCCESS, nil, len(templates))
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, templates)
+	case http.MethodPost:
+		userID, ok := requireAdminUserID(w, r)
+		if !ok {
+			return
+		}
+		utils.Log(constants.HANDLER_CREATE_BUILD_TEMPLATE_START, nil)
+
+		var input models.BuildTemplateInput
+		if !decodeJSONBody(w, r, &input) {
+			return
+		}
+
+		template, err := services.CreateBuildTemplate(userID, input)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_CREATE_BUILD_TEMPLATE_ERROR)
+			return
+		}
+
+		utils.Log(constants.HANDLER_CREATE_BUILD_TEMPLATE_SUCCESS, nil, template.ID)
+		utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, template)
+	}
+}
+
+// BuildTemplateHandler returns the template identified by the {id} path value on GET, replaces it on PUT and
+// deletes it on DELETE. Only administrators can change templates.
+func BuildTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	templateID, ok := parseTemplateIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		utils.Log(constants.HANDLER_GET_BUILD_TEMPLATE_START, nil, templateID)
+
+		template, err := services.GetBuildTemplate(templateID)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_GET_BUILD_TEMPLATE_ERROR, templateID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_GET_BUILD_TEMPLATE_SUCCESS, nil, templateID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, template)
+		return
+	}
+
+	userID, ok := requireAdminUserID(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		utils.Log(constants.HANDLER_UPDATE_BUILD_TEMPLATE_START, nil, templateID)
+
+		var input models.BuildTemplateInput
+		if !decodeJSONBody(w, r, &input) {
+			return
+		}
+
+		template, err := services.UpdateBuildTemplate(templateID, userID, input)
+		if err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_UPDATE_BUILD_TEMPLATE_ERROR, templateID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_UPDATE_BUILD_TEMPLATE_SUCCESS, nil, templateID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, template)
+	case http.MethodDelete:
+		utils.Log(constants.HANDLER_DELETE_BUILD_TEMPLATE_START, nil, templateID)
+
+		if err := services.DeleteBuildTemplate(templateID); err != nil {
+			writeBuildServiceError(w, err, constants.HANDLER_DELETE_BUILD_TEMPLATE_ERROR, templateID)
+			return
+		}
+
+		utils.Log(constants.HANDLER_DELETE_BUILD_TEMPLATE_SUCCESS, nil, templateID)
+		utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, nil)
+	}
+}
+
+// CreateBuildFromTemplateHandler creates a build for the caller from the template identified by the {id} path value.
+// It is routed as /builds/{action}/{id}, so any action other than from-template is not found. The body is optional.
+func CreateBuildFromTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("action") != fromTemplateAction {
+		utils.WriteError(w, http.StatusNotFound, constants.PAGE_NOT_FOUND_MESSAGE, nil)
+		return
+	}
+	if r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	templateID, ok := parseTemplateIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_CREATE_BUILD_FROM_TEMPLATE_START, nil, templateID)
+
+	var input models.BuildFromTemplate
+	if r.ContentLength != 0 && !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	result, err := services.CreateBuildFromTemplate(templateID, userID, input)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_CREATE_BUILD_FROM_TEMPLATE_ERROR, templateID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_CREATE_BUILD_FROM_TEMPLATE_SUCCESS, nil, result.Build.ID, templateID)
+	utils.WriteSuccess(w, http.StatusCreated, constants.SUCCESS_MESSAGE, result)
+}
+
+// requireAdminUserID returns the caller's user id, writing a 401 response when it is missing and a 403 response
+// when the caller is not an administrator
+func requireAdminUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return "", false
+	}
+	if !services.IsAdmin(userID) {
+		utils.Log(constants.HANDLER_ADMIN_REQUIRED, nil, userID)
+		utils.WriteError(w, http.StatusForbidden, constants.ADMIN_REQUIRED_MESSAGE, nil)
+		return "", false
+	}
+	return userID, true
+}
+
+// parseTemplateIDPathValue reads the {id} path value, writing a 400 response when it is not a valid template ID
+func parseTemplateIDPathValue(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.PathValue("id")
+	templateID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || templateID <= 0 {
+		utils.Log(constants.HANDLER_INVALID_TEMPLATE_ID, err, raw)
+		utils.WriteError(w, http.StatusBadRequest, constants.TEMPLATE_ID_INVALID_MESSAGE, nil)
+		return 0, false
+	}
+	return templateID, true
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetBuildUpgradesHandler ranks CPU and video card upgrades for a build by performance gain per unit of currency.
+// The optional budget query parameter caps the cost of an upgrade, and use_case and resolution pick the scores
+// compared.
+func GetBuildUpgradesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	var budget *float64
+	if raw := strings.TrimSpace(r.URL.Query().Get("budget")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, constants.BUDGET_INVALID_MESSAGE, nil)
+			return
+		}
+		budget = &value
+	}
+	useCase := r.URL.Query().Get("use_case")
+	if useCase != "" && !services.IsValidUseCase(strings.ToLower(strings.TrimSpace(useCase))) {
+		utils.WriteError(w, http.StatusBadRequest, constants.USE_CASE_INVALID_MESSAGE, nil)
+		return
+	}
+	resolution := r.URL.Query().Get("resolution")
+	if resolution != "" && !services.IsValidResolution(strings.ToLower(strings.TrimSpace(resolution))) {
+		utils.WriteError(w, http.StatusBadRequest, constants.RESOLUTION_INVALID_MESSAGE, nil)
+		return
+	}
+	utils.Log(constants.HANDLER_FIND_BUILD_UPGRADES_START, nil, buildID)
+
+	upgrades, err := services.FindBuildUpgrades(buildID, getRequestUserID(r), budget, useCase, resolution)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_FIND_BUILD_UPGRADES_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_FIND_BUILD_UPGRADES_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, upgrades)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetBuildVersionsHandler lists the recorded versions of the build identified by the {id} path value
+func GetBuildVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_LIST_BUILD_VERSIONS_START, nil, buildID)
+
+	page := utils.GetPageNumberFromQueryString(r.URL.Query())
+	versions, err := services.ListBuildVersions(buildID, getRequestUserID(r), page)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_LIST_BUILD_VERSIONS_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_LIST_BUILD_VERSIONS_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, versions)
+}
+
+// DiffBuildVersionsHandler compares versions {a} and {b} of the build identified by the {id} path value
+func DiffBuildVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	from, ok := parseBuildVersionPathValue(w, r, "a")
+	if !ok {
+		return
+	}
+	to, ok := parseBuildVersionPathValue(w, r, "b")
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_DIFF_BUILD_VERSIONS_START, nil, from, to, buildID)
+
+	diff, err := services.DiffBuildVersions(buildID, from, to, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_DIFF_BUILD_VERSIONS_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_DIFF_BUILD_VERSIONS_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, diff)
+}
+
+// RestoreBuildVersionHandler puts back the parts of version {version} of the build identified by the {id} path value
+func RestoreBuildVersionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	version, ok := parseBuildVersionPathValue(w, r, "version")
+	if !ok {
+		return
+	}
+	userID, ok := requireRequestUserID(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_RESTORE_BUILD_VERSION_START, nil, version, buildID)
+
+	revision, ok := requireIfMatchRevision(w, r)
+	if !ok {
+		return
+	}
+
+	build, err := services.RestoreBuildVersion(buildID, version, userID, revision)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_RESTORE_BUILD_VERSION_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_RESTORE_BUILD_VERSION_SUCCESS, nil, buildID)
+	setBuildETag(w, build.Revision)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, build)
+}
+
+// parseBuildVersionPathValue reads a version number path value, writing a 400 response when it is not a positive integer
+func parseBuildVersionPathValue(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	raw := r.PathValue(name)
+	version, err := strconv.Atoi(raw)
+	if err != nil || version <= 0 {
+		utils.Log(constants.HANDLER_INVALID_BUILD_VERSION, err, raw)
+		utils.WriteError(w, http.StatusBadRequest, constants.VERSION_INVALID_MESSAGE, nil)
+		return 0, false
+	}
+	return version, true
+}
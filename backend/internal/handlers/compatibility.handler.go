@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/services"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+func GetBuildCompatibilityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_CHECK_BUILD_COMPATIBILITY_START, nil, buildID)
+
+	report, err := services.CheckBuildCompatibility(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_CHECK_BUILD_COMPATIBILITY_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_CHECK_BUILD_COMPATIBILITY_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, report)
+}
+
+func GetBuildStorageAllocationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_GET_STORAGE_ALLOCATION_START, nil, buildID)
+
+	report, err := services.GetBuildStorageAllocation(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_GET_STORAGE_ALLOCATION_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GET_STORAGE_ALLOCATION_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, report)
+}
+
+func GetBuildPowerConnectorsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_GET_POWER_CONNECTORS_START, nil, buildID)
+
+	report, err := services.GetBuildPowerConnectors(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_GET_POWER_CONNECTORS_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GET_POWER_CONNECTORS_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, report)
+}
+
+func GetBuildCoolingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_ANALYZE_COOLING_START, nil, buildID)
+
+	report, err := services.AnalyzeBuildCooling(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_ANALYZE_COOLING_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_ANALYZE_COOLING_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, report)
+}
+
+// GetBuildCompletenessHandler lists the required parts a build has and those it is missing
+func GetBuildCompletenessHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+	utils.Log(constants.HANDLER_GET_BUILD_COMPLETENESS_START, nil, buildID)
+
+	completeness, err := services.GetBuildCompleteness(buildID, getRequestUserID(r))
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_GET_BUILD_COMPLETENESS_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GET_BUILD_COMPLETENESS_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, completeness)
+}
+
+func GetBuildBottleneckHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.Log(constants.HANDLER_METHOD_NOT_ALLOWED, fmt.Errorf("method %s not allowed", r.Method))
+		utils.WriteError(w, http.StatusMethodNotAllowed, constants.METHOD_NOT_ALLOWED_MESSAGE, nil)
+		return
+	}
+
+	buildID, ok := parseBuildIDPathValue(w, r)
+	if !ok {
+		return
+	}
+
+	input := models.BottleneckAnalysisInput{
+		BuildID:    buildID,
+		ViewerID:   getRequestUserID(r),
+		UseCase:    getQueryValueOrDefault(r, "use_case", constants.DEFAULT_USE_CASE),
+		Resolution: getQueryValueOrDefault(r, "resolution", constants.DEFAULT_RESOLUTION),
+	}
+	if !services.IsValidUseCase(input.UseCase) {
+		utils.WriteError(w, http.StatusBadRequest, constants.USE_CASE_INVALID_MESSAGE, nil)
+		return
+	}
+	if !services.IsValidResolution(input.Resolution) {
+		utils.WriteError(w, http.StatusBadRequest, constants.RESOLUTION_INVALID_MESSAGE, nil)
+		return
+	}
+	utils.Log(constants.HANDLER_ANALYZE_BOTTLENECK_START, nil, buildID)
+
+	report, err := services.AnalyzeBuildBottleneck(input)
+	if err != nil {
+		writeBuildServiceError(w, err, constants.HANDLER_ANALYZE_BOTTLENECK_ERROR, buildID)
+		return
+	}
+
+	utils.Log(constants.HANDLER_ANALYZE_BOTTLENECK_SUCCESS, nil, buildID)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, report)
+}
+
+// getQueryValueOrDefault returns a lower cased query parameter, or fallback when it is empty
+func getQueryValueOrDefault(r *http.Request, key, fallback string) string {
+	value := strings.ToLower(strings.TrimSpace(r.URL.Query().Get(key)))
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// parseBuildIDPathValue reads the {id} path value, writing a 400 response when it is not a valid build ID
+func parseBuildIDPathValue(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.PathValue("id")
+	buildID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || buildID <= 0 {
+		utils.Log(constants.HANDLER_INVALID_BUILD_ID, err, raw)
+		utils.WriteError(w, http.StatusBadRequest, constants.BUILD_ID_INVALID_MESSAGE, nil)
+		return 0, false
+	}
+	return buildID, true
+}
+
+// writeBuildServiceError maps a build service error to a response, logging unexpected errors with logMessage
+func writeBuildServiceError(w http.ResponseWriter, err error, logMessage string, args ...interface{}) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		utils.WriteError(w, http.StatusNotFound, constants.BUILD_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrBuildPartNotFound):
+		utils.WriteError(w, http.StatusNotFound, constants.BUILD_PART_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrBuildVersionNotFound):
+		utils.WriteError(w, http.StatusNotFound, constants.VERSION_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrShareNotFound):
+		utils.WriteError(w, http.StatusNotFound, constants.SHARE_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrCommentNotFound):
+		utils.WriteError(w, http.StatusNotFound, constants.COMMENT_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrCollaboratorNotFound):
+		utils.WriteError(w, http.StatusNotFound, constants.COLLABORATOR_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrInvitationNotFound):
+		utils.WriteError(w, http.StatusNotFound, constants.INVITATION_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrTemplateNotFound):
+		utils.WriteError(w, http.StatusNotFound, constants.TEMPLATE_NOT_FOUND_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrCommentForbidden):
+		utils.WriteError(w, http.StatusForbidden, constants.COMMENT_FORBIDDEN_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrShareReadOnly):
+		utils.WriteError(w, http.StatusForbidden, constants.SHARE_READ_ONLY_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrBuildForbidden):
+		utils.WriteError(w, http.StatusForbidden, constants.BUILD_FORBIDDEN_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrCollaboratorExists):
+		utils.WriteError(w, http.StatusConflict, constants.COLLABORATOR_EXISTS_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrBuildConflict):
+		utils.WriteError(w, http.StatusPreconditionFailed, constants.BUILD_CONFLICT_MESSAGE, nil)
+		return
+	case errors.Is(err, services.ErrBudgetTooLow):
+		utils.WriteError(w, http.StatusUnprocessableEntity, constants.BUDGET_TOO_LOW_MESSAGE, err.Error())
+		return
+	case errors.Is(err, services.ErrTemplateUnfillable):
+		utils.WriteError(w, http.StatusUnprocessableEntity, constants.TEMPLATE_UNFILLABLE_MESSAGE, err.Error())
+		return
+	case errors.Is(err, services.ErrInvalidBuild):
+		utils.WriteError(w, http.StatusBadRequest, constants.BAD_REQUEST_MESSAGE, err.Error())
+		return
+	}
+	utils.Log(logMessage, err, args...)
+	utils.WriteError(w, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR_MESSAGE, err)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetBuildCompatibilityHandler_Validation tests method and build ID validation
+func TestGetBuildCompatibilityHandler_Validation(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "Invalid POST request",
+			method:          http.MethodPost,
+			path:            "/builds/1/compatibility",
+			expectedStatus:  http.StatusMethodNotAllowed,
+			expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE,
+		},
+		{
+			name:            "Non numeric build ID",
+			method:          http.MethodGet,
+			path:            "/builds/abc/compatibility",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "Zero build ID",
+			method:          http.MethodGet,
+			path:            "/builds/0/compatibility",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/builds/{id}/compatibility", GetBuildCompatibilityHandler)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.ErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, response.Message)
+		})
+	}
+}
+
+// TestGetBuildBottleneckHandler_Validation tests query parameter validation
+func TestGetBuildBottleneckHandler_Validation(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		expectedMessage string
+	}{
+		{
+			name:            "Unknown use case",
+			path:            "/builds/1/bottleneck?use_case=mining",
+			expectedMessage: constants.USE_CASE_INVALID_MESSAGE,
+		},
+		{
+			name:            "Unknown resolution",
+			path:            "/builds/1/bottleneck?resolution=8k",
+			expectedMessage: constants.RESOLUTION_INVALID_MESSAGE,
+		},
+		{
+			name:            "Invalid build ID",
+			path:            "/builds/x/bottleneck",
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/builds/{id}/bottleneck", GetBuildBottleneckHandler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response models.ErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, response.Message)
+		})
+	}
+}
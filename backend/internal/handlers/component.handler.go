@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/mateuse/desktop-builder-backend/internal/constants"
 	"github.com/mateuse/desktop-builder-backend/internal/models"
@@ -30,6 +32,8 @@ func GetComponentsHandler(w http.ResponseWriter, r *http.Request) {
 			Page: page,
 		}
 		handleGetComponentByID(w, input)
+	case params.CompatibleWithBuild != "":
+		handleGetCompatibleComponents(w, params, page, getRequestUserID(r))
 	case params.Category != "" && params.Brand != "":
 		input := models.GetComponentsByBrandInput{
 			Category: params.Category,
@@ -55,9 +59,10 @@ func parseComponentQueryParams(r *http.Request) models.ComponentQueryParams {
 	brand := r.PathValue("brand")
 
 	return models.ComponentQueryParams{
-		Category: r.PathValue("category"),
-		Brand:    brand,
-		ID:       r.PathValue("id"),
+		Category:            r.PathValue("category"),
+		Brand:               brand,
+		ID:                  r.PathValue("id"),
+		CompatibleWithBuild: strings.TrimSpace(r.URL.Query().Get("compatible_with_build")),
 	}
 }
 
@@ -121,3 +126,40 @@ func handleGetAllComponents(w http.ResponseWriter, input models.GetAllComponents
 	utils.Log(constants.HANDLER_GET_ALL_COMPONENTS_SUCCESS, nil)
 	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, components)
 }
+
+func handleGetCompatibleComponents(w http.ResponseWriter, params models.ComponentQueryParams, page, viewerID string) {
+	if params.Category == "" {
+		utils.WriteError(w, http.StatusBadRequest, constants.CATEGORY_REQUIRED_MESSAGE, nil)
+		return
+	}
+
+	buildID, err := strconv.ParseInt(params.CompatibleWithBuild, 10, 64)
+	if err != nil || buildID <= 0 {
+		utils.Log(constants.HANDLER_INVALID_BUILD_ID, err, params.CompatibleWithBuild)
+		utils.WriteError(w, http.StatusBadRequest, constants.BUILD_ID_INVALID_MESSAGE, nil)
+		return
+	}
+
+	input := models.GetCompatibleComponentsInput{
+		Category: params.Category,
+		Brand:    params.Brand,
+		BuildID:  buildID,
+		ViewerID: viewerID,
+		Page:     page,
+	}
+	utils.Log(constants.HANDLER_GET_COMPATIBLE_COMPONENTS_START, nil, input.BuildID, input.Category)
+
+	components, err := services.GetCompatibleComponents(input)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			utils.WriteError(w, http.StatusNotFound, constants.BUILD_NOT_FOUND_MESSAGE, nil)
+			return
+		}
+		utils.Log(constants.HANDLER_GET_COMPATIBLE_COMPONENTS_ERROR, err, input.BuildID, input.Category)
+		utils.WriteError(w, http.StatusInternalServerError, constants.INTERNAL_SERVER_ERROR_MESSAGE, err)
+		return
+	}
+
+	utils.Log(constants.HANDLER_GET_COMPATIBLE_COMPONENTS_SUCCESS, nil, input.BuildID, input.Category)
+	utils.WriteSuccess(w, http.StatusOK, constants.SUCCESS_MESSAGE, components)
+}
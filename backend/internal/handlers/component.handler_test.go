@@ -732,3 +732,64 @@ func BenchmarkParseComponentQueryParams(b *testing.B) {
 		mux.ServeHTTP(w, req)
 	}
 }
+
+// TestGetComponentsHandler_CompatibleWithBuildValidation tests validation of the compatible_with_build query mode
+func TestGetComponentsHandler_CompatibleWithBuildValidation(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		pattern         string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "Missing category",
+			path:            "/components?compatible_with_build=42",
+			pattern:         "/components",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.CATEGORY_REQUIRED_MESSAGE,
+		},
+		{
+			name:            "Non numeric build ID",
+			path:            "/components/motherboard?compatible_with_build=abc",
+			pattern:         "/components/{category}",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+		{
+			name:            "Negative build ID",
+			path:            "/components/motherboard?compatible_with_build=-1",
+			pattern:         "/components/{category}",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: constants.BUILD_ID_INVALID_MESSAGE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc(tt.pattern, GetComponentsHandler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.ErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedMessage, response.Message)
+		})
+	}
+}
+
+// TestParseComponentQueryParams_CompatibleWithBuild tests parsing of the compatible_with_build query parameter
+func TestParseComponentQueryParams_CompatibleWithBuild(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/components/motherboard?compatible_with_build=%2042%20", nil)
+	req.SetPathValue("category", "motherboard")
+
+	params := parseComponentQueryParams(req)
+	assert.Equal(t, "motherboard", params.Category)
+	assert.Equal(t, "42", params.CompatibleWithBuild)
+}
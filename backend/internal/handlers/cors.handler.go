@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// WithCORS lets the allowed origins call the API from a browser. Clients send the build's ETag back in If-Match,
+// so the ETag is exposed to them and If-Match is allowed in preflights.
+func WithCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Get allowed origins from environment variable or use defaults
+		allowedOriginsStr := os.Getenv("CORS_ALLOWED_ORIGINS")
+		if allowedOriginsStr == "" {
+			allowedOriginsStr = "http://localhost:3000,http://localhost:3001,http://localhost:3002,http://localhost:5173"
+		}
+
+		// Parse allowed origins into a slice
+		allowedOrigins := strings.Split(allowedOriginsStr, ",")
+
+		// Get the origin from the request
+		requestOrigin := r.Header.Get("Origin")
+
+		// Check if the request origin is in our allowed list
+		originAllowed := false
+		for _, origin := range allowedOrigins {
+			if strings.TrimSpace(origin) == requestOrigin {
+				originAllowed = true
+				break
+			}
+		}
+
+		// Set CORS headers
+		if originAllowed {
+			w.Header().Set("Access-Control-Allow-Origin", requestOrigin)
+		}
+		w.Header().Set("Vary", "Origin")
+		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-User-ID, If-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
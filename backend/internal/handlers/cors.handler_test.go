@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithCORS tests that browsers may send If-Match and read the ETag of a build
+func TestWithCORS(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "http://localhost:5173")
+	handler := WithCORS(newBuildsMux())
+
+	t.Run("Preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/builds/1", nil)
+		req.Header.Set("Origin", "http://localhost:5173")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		req.Header.Set("Access-Control-Request-Headers", "if-match")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "http://localhost:5173", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "If-Match")
+		assert.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("GET a build", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		originalDB := utils.DB
+		utils.DB = db
+		defer func() { utils.DB = originalDB }()
+
+		mock.ExpectQuery("SELECT (.+) FROM user_builds WHERE id = \\$1").WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+				AddRow(1, "owner", "Gaming PC", nil, true, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 3))
+		mock.ExpectQuery("SELECT (.+) FROM build_components").WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery("SELECT currency, usd_rate FROM exchange_rates").
+			WillReturnRows(sqlmock.NewRows([]string{"currency", "usd_rate"}))
+
+		req := httptest.NewRequest(http.MethodGet, "/builds/1", nil)
+		req.Header.Set("Origin", "http://localhost:5173")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "If-Match")
+		assert.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// BIOSRequirement represents the bios_requirements table
+type BIOSRequirement struct {
+	ID             int64     `json:"id" db:"id"`
+	MotherboardID  int64     `json:"motherboard_id" db:"motherboard_id"`
+	CPUID          int64     `json:"cpu_id" db:"cpu_id"`
+	MinBIOSVersion string    `json:"min_bios_version" db:"min_bios_version"`
+	Notes          *string   `json:"notes,omitempty" db:"notes"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
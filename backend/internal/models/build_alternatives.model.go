@@ -0,0 +1,44 @@
+package models
+
+// CheaperAlternative is a part that can replace one in a build for less without breaking it
+type CheaperAlternative struct {
+	Component  Component `json:"component"`
+	PriceID    int64     `json:"price_id"`
+	RetailerID int64     `json:"retailer_id"`
+	UnitPrice  float64   `json:"unit_price"`
+	// Savings is for the quantity in the build
+	Savings        float64  `json:"savings"`
+	SavingsPercent float64  `json:"savings_percent"`
+	Value          *float64 `json:"value,omitempty"`
+	// ValueChange is the percentage the metric changes by against the current part
+	ValueChange *float64 `json:"value_change,omitempty"`
+}
+
+// CheaperAlternativeSlot holds the cheaper alternatives of one part of a build, best savings first
+type CheaperAlternativeSlot struct {
+	ComponentID int64    `json:"component_id"`
+	Category    Category `json:"category"`
+	Model       string   `json:"model"`
+	Quantity    int      `json:"quantity"`
+	UnitPrice   *float64 `json:"unit_price,omitempty"`
+	// Metric names what the tolerance is applied to, such as a performance score, capacity or wattage.
+	// Parts without one are compared on compatibility and price only.
+	Metric       string               `json:"metric,omitempty"`
+	Value        *float64             `json:"value,omitempty"`
+	Alternatives []CheaperAlternative `json:"alternatives"`
+	Message      string               `json:"message,omitempty"`
+}
+
+// BuildCheaperAlternatives lists cheaper equivalents for the parts of a build, slots with the biggest savings first
+type BuildCheaperAlternatives struct {
+	BuildID  int64  `json:"build_id"`
+	Currency string `json:"currency"`
+	Region   string `json:"region"`
+	UseCase  string `json:"use_case"`
+	// Tolerance is the percentage a metric may drop by
+	Tolerance float64                  `json:"tolerance"`
+	Slots     []CheaperAlternativeSlot `json:"slots"`
+	// PotentialSavings adds up the best alternative of every slot. Each alternative is checked against the rest of
+	// the build as it is, so taking several at once should be checked again.
+	PotentialSavings float64 `json:"potential_savings"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// Roles a user can hold on a build, from least to most access
+const (
+	BuildRoleViewer = "viewer"
+	BuildRoleEditor = "editor"
+	BuildRoleOwner  = "owner"
+)
+
+// BuildCollaborator represents the build_collaborators table. AcceptedAt is nil while the invitation is pending.
+type BuildCollaborator struct {
+	ID         int64      `json:"id" db:"id"`
+	BuildID    int64      `json:"build_id" db:"build_id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	Role       string     `json:"role" db:"role"`
+	InvitedBy  string     `json:"invited_by" db:"invited_by"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// BuildCollaboratorInvite represents the data needed to invite a user to a build
+type BuildCollaboratorInvite struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// BuildCollaboratorUpdate represents the role change of a collaborator
+type BuildCollaboratorUpdate struct {
+	Role string `json:"role"`
+}
+
+// BuildInvitation is a pending invitation seen by the invited user
+type BuildInvitation struct {
+	BuildCollaborator
+	BuildName string `json:"build_name"`
+}
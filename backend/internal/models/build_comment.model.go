@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// BuildComment represents the build_comments table. Deleted comments keep their place in the thread
+// with an empty body so replies to them stay readable.
+type BuildComment struct {
+	ID        int64          `json:"id" db:"id"`
+	BuildID   int64          `json:"build_id" db:"build_id"`
+	ParentID  *int64         `json:"parent_id,omitempty" db:"parent_id"`
+	UserID    string         `json:"user_id" db:"user_id"`
+	Body      string         `json:"body" db:"body"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	EditedAt  *time.Time     `json:"edited_at,omitempty" db:"edited_at"`
+	DeletedAt *time.Time     `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy *string        `json:"-" db:"deleted_by"`
+	Replies   []BuildComment `json:"replies" db:"-"`
+}
+
+// BuildCommentCreate represents the data needed to comment on a build or reply to a comment
+type BuildCommentCreate struct {
+	Body     string `json:"body"`
+	ParentID *int64 `json:"parent_id,omitempty"`
+}
+
+// BuildCommentUpdate represents the data needed to edit a comment
+type BuildCommentUpdate struct {
+	Body string `json:"body"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// CompletenessItem is one category of a build's completeness checklist
+type CompletenessItem struct {
+	Category Category `json:"category"`
+	Required bool     `json:"required"`
+	Present  bool     `json:"present"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// BuildCompleteness reports whether a build has every part it needs to run
+type BuildCompleteness struct {
+	BuildID   int64              `json:"build_id"`
+	Complete  bool               `json:"complete"`
+	Missing   []Category         `json:"missing"`
+	Checklist []CompletenessItem `json:"checklist"`
+}
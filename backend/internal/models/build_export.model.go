@@ -0,0 +1,24 @@
+package models
+
+// BuildExport is the data handed to the export templates of a build
+type BuildExport struct {
+	Name        string
+	Description string
+	Currency    string
+	Region      string
+	Total       *float64
+	Complete    bool
+	Lines       []BuildExportLine
+}
+
+// BuildExportLine is one part of an exported build with the price used for the total
+type BuildExportLine struct {
+	Category   string
+	Brand      string
+	Model      string
+	Quantity   int
+	Retailer   string
+	UnitPrice  *float64
+	LineTotal  *float64
+	ProductURL string
+}
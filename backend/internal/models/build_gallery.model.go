@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Sort orders of the public build gallery
+const (
+	GallerySortNewest     = "newest"
+	GallerySortMostForked = "most_forked"
+	GallerySortMostLiked  = "most_liked"
+	GallerySortPriceLow   = "price_low"
+	GallerySortPriceHigh  = "price_high"
+)
+
+// BuildGalleryFilter narrows and orders the public build gallery.
+// MinPrice and MaxPrice are in Currency and compared with each build's total converted to the same currency.
+type BuildGalleryFilter struct {
+	MinPrice    *float64
+	MaxPrice    *float64
+	Currency    string
+	Region      *string
+	UseCase     *string
+	ComponentID *int64
+	CPUBrand    *string
+	GPUBrand    *string
+	Sort        string
+	Cursor      *GalleryCursor
+	Limit       int
+}
+
+// GalleryCursor marks the last build of a gallery page. Value holds the sort key of that build as text:
+// its creation time, fork count, like count or total in USD depending on the sort.
+type GalleryCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+// BuildCardPart is a headline part shown on a build card
+type BuildCardPart struct {
+	ComponentID int64  `json:"component_id"`
+	Brand       string `json:"brand"`
+	Model       string `json:"model"`
+}
+
+// BuildCard is the compact representation of a build in the gallery
+type BuildCard struct {
+	ID         int64          `json:"id"`
+	UserID     string         `json:"user_id"`
+	Name       string         `json:"name"`
+	UseCase    *string        `json:"use_case,omitempty"`
+	TotalPrice *float64       `json:"total_price,omitempty"`
+	Currency   string         `json:"currency"`
+	Region     string         `json:"region"`
+	IsComplete bool           `json:"is_complete"`
+	CPU        *BuildCardPart `json:"cpu,omitempty"`
+	GPU        *BuildCardPart `json:"gpu,omitempty"`
+	PartCount  int            `json:"part_count"`
+	ForkCount  int            `json:"fork_count"`
+	LikeCount  int            `json:"like_count"`
+	CreatedAt  time.Time      `json:"created_at"`
+	TotalUSD   *float64       `json:"-"`
+}
+
+// BuildGalleryPage is one page of the public build gallery
+type BuildGalleryPage struct {
+	Builds     []BuildCard `json:"builds"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// BuildGeneratorRequest describes the build to generate
+type BuildGeneratorRequest struct {
+	Budget             float64 `json:"budget"`
+	Currency           string  `json:"currency,omitempty"`
+	Region             string  `json:"region,omitempty"`
+	UseCase            string  `json:"use_case,omitempty"`
+	Resolution         string  `json:"resolution,omitempty"`
+	PinnedComponentIDs []int64 `json:"pinned_component_ids,omitempty"`
+}
+
+// GeneratedBuildPart is a catalog part with its cheapest in-stock price, converted to the requested currency
+type GeneratedBuildPart struct {
+	Component Component `json:"component"`
+	Price     Price     `json:"price"`
+	UnitPrice float64   `json:"unit_price"`
+	Pinned    bool      `json:"pinned"`
+}
+
+// GeneratedBuild is a complete, compatible combination of parts found by the generator
+type GeneratedBuild struct {
+	Parts    []GeneratedBuildPart `json:"parts"`
+	Total    float64              `json:"total"`
+	Currency string               `json:"currency"`
+	Score    float64              `json:"score"`
+	Reasons  []string             `json:"reasons"`
+}
+
+// BuildGeneratorResult is the best build found for a request and the runners-up
+type BuildGeneratorResult struct {
+	UseCase      string           `json:"use_case"`
+	Resolution   string           `json:"resolution,omitempty"`
+	Budget       float64          `json:"budget"`
+	Currency     string           `json:"currency"`
+	Region       string           `json:"region"`
+	Build        GeneratedBuild   `json:"build"`
+	Alternatives []GeneratedBuild `json:"alternatives"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// BuildImport is a pasted parts list to create a build from
+type BuildImport struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	IsPublic    *bool   `json:"is_public,omitempty"`
+	Currency    *string `json:"currency,omitempty"`
+	Region      *string `json:"region,omitempty"`
+	Text        string  `json:"text"`
+}
+
+// PartsListLine is a line of a parts list that names a component
+type PartsListLine struct {
+	Line     int       `json:"line"`
+	Text     string    `json:"text"`
+	Category *Category `json:"category,omitempty"`
+	Query    string    `json:"query"`
+	Quantity int       `json:"quantity"`
+}
+
+// ComponentMatch is a component scored against a parts list line, from 0 to 1
+type ComponentMatch struct {
+	Component Component `json:"component"`
+	Score     float64   `json:"score"`
+}
+
+// ImportedPart is a parts list line that was added to the build
+type ImportedPart struct {
+	PartsListLine
+	Match ComponentMatch `json:"match"`
+}
+
+// UnmatchedPart is a parts list line left for the user to resolve, with the closest components
+type UnmatchedPart struct {
+	PartsListLine
+	Candidates []ComponentMatch `json:"candidates"`
+}
+
+// BuildImportResult is the build created from a parts list and how each line was matched
+type BuildImportResult struct {
+	Build     UserBuildWithComponents `json:"build"`
+	Matched   []ImportedPart          `json:"matched"`
+	Unmatched []UnmatchedPart         `json:"unmatched"`
+}
@@ -0,0 +1,57 @@
+package models
+
+// RetailerShipping is the shipping_info of a retailer. A retailer without it is assumed to ship for free.
+type RetailerShipping struct {
+	// FlatRate is charged once per order
+	FlatRate float64 `json:"flat_rate"`
+	// FreeShippingThreshold waives the flat rate for orders of at least this much; nil means never
+	FreeShippingThreshold *float64 `json:"free_shipping_threshold,omitempty"`
+	// Currency of the rate and threshold; the build's currency when empty
+	Currency string `json:"currency,omitempty"`
+}
+
+// PurchasePlanLine is the retailer and price chosen for one part of a build
+type PurchasePlanLine struct {
+	ComponentID int64   `json:"component_id"`
+	Quantity    int     `json:"quantity"`
+	PriceID     int64   `json:"price_id"`
+	RetailerID  int64   `json:"retailer_id"`
+	UnitPrice   float64 `json:"unit_price"`
+	LineTotal   float64 `json:"line_total"`
+	ProductURL  *string `json:"product_url,omitempty"`
+	// Changed is true when the plan buys the part somewhere other than the build's current price
+	Changed bool `json:"changed"`
+}
+
+// PurchasePlanOrder is what a plan buys from one retailer
+type PurchasePlanOrder struct {
+	RetailerID int64   `json:"retailer_id"`
+	Name       string  `json:"name"`
+	Subtotal   float64 `json:"subtotal"`
+	Shipping   float64 `json:"shipping"`
+	Total      float64 `json:"total"`
+}
+
+// BuildPurchasePlan is the cheapest way found to buy the parts of a build, shipping included
+type BuildPurchasePlan struct {
+	BuildID      int64  `json:"build_id"`
+	Currency     string `json:"currency"`
+	Region       string `json:"region"`
+	MaxRetailers *int   `json:"max_retailers,omitempty"`
+	// Lines and Orders are empty when no part has an in-stock price
+	Lines         []PurchasePlanLine  `json:"lines"`
+	Orders        []PurchasePlanOrder `json:"orders"`
+	PartsTotal    float64             `json:"parts_total"`
+	ShippingTotal float64             `json:"shipping_total"`
+	Total         float64             `json:"total"`
+	// CurrentTotal is the cost of the build's current prices with shipping, when every part has one and the shipping
+	// of every retailer they come from is known
+	CurrentTotal *float64 `json:"current_total,omitempty"`
+	// UnknownShipping lists the retailers of the current prices whose shipping cannot be worked out, because they are
+	// inactive or their shipping info is malformed; CurrentTotal is left out when it is not empty
+	UnknownShipping []int64 `json:"unknown_shipping,omitempty"`
+	// Savings is CurrentTotal less Total, when both price every part
+	Savings *float64 `json:"savings,omitempty"`
+	// Unavailable lists the components no retailer has in stock in the region
+	Unavailable []int64 `json:"unavailable"`
+}
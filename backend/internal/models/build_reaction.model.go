@@ -0,0 +1,21 @@
+package models
+
+// BuildLikeStatus reports whether a user likes a build and how many users do
+type BuildLikeStatus struct {
+	BuildID   int64 `json:"build_id"`
+	Liked     bool  `json:"liked"`
+	LikeCount int   `json:"like_count"`
+}
+
+// BuildRatingInput represents a star rating from 1 to 5
+type BuildRatingInput struct {
+	Rating int `json:"rating"`
+}
+
+// BuildRatingStatus reports a user's rating of a build and the build's aggregate rating
+type BuildRatingStatus struct {
+	BuildID       int64    `json:"build_id"`
+	Rating        *int     `json:"rating,omitempty"`
+	RatingCount   int      `json:"rating_count"`
+	RatingAverage *float64 `json:"rating_average,omitempty"`
+}
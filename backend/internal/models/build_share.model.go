@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	BuildShareAccessRead = "read"
+	BuildShareAccessEdit = "edit"
+)
+
+// BuildShare represents the build_shares table. Token is only set when the share is created.
+type BuildShare struct {
+	ID        int64      `json:"id" db:"id"`
+	BuildID   int64      `json:"build_id" db:"build_id"`
+	Token     string     `json:"token,omitempty" db:"-"`
+	Access    string     `json:"access" db:"access"`
+	CreatedBy string     `json:"created_by" db:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// BuildShareCreate represents the data needed to create a share link
+type BuildShareCreate struct {
+	Access    string     `json:"access"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// SharedBuild is a build seen through a share link, without the IDs of the build and its owner
+type SharedBuild struct {
+	Access      string               `json:"access"`
+	ExpiresAt   *time.Time           `json:"expires_at,omitempty"`
+	Name        string               `json:"name"`
+	Description *string              `json:"description,omitempty"`
+	IsComplete  bool                 `json:"is_complete"`
+	TotalPrice  *float64             `json:"total_price,omitempty"`
+	Currency    string               `json:"currency"`
+	Region      string               `json:"region"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	Revision    int                  `json:"revision"`
+	Components  []SharedBuildPart    `json:"components"`
+	Pricing     *BuildPriceBreakdown `json:"pricing,omitempty"`
+}
+
+// SharedBuildPart is a part of a shared build
+type SharedBuildPart struct {
+	ComponentID     int64      `json:"component_id"`
+	Quantity        int        `json:"quantity"`
+	SelectedPriceID *int64     `json:"selected_price_id,omitempty"`
+	Notes           *string    `json:"notes,omitempty"`
+	Component       *Component `json:"component,omitempty"`
+	SelectedPrice   *Price     `json:"selected_price,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// BuildSheet is the data handed to the printable spec sheet template of a build
+type BuildSheet struct {
+	BuildID     int64
+	Name        string
+	Description string
+	Currency    string
+	Region      string
+	Total       *float64
+	Complete    bool
+	Lines       []BuildSheetLine
+	Power       BuildSheetPower
+	// BuildURL is the link encoded in QRCode, an SVG image
+	BuildURL string
+	QRCode   string
+}
+
+// BuildSheetLine is one part of a spec sheet with the specs worth printing next to it
+type BuildSheetLine struct {
+	BuildExportLine
+	Highlights []SpecHighlight
+}
+
+// SpecHighlight is a labelled spec value formatted for display
+type SpecHighlight struct {
+	Label string
+	Value string
+}
+
+// BuildSheetPower is the estimated draw of a build against the wattage of its power supply
+type BuildSheetPower struct {
+	// Known is false when the build has no CPU or its CPU does not list its power
+	Known              bool
+	EstimatedDraw      float64
+	RecommendedWattage float64
+	PSUWattage         *float64
+	Sufficient         bool
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// TemplateSlot is a category a build template fills, with the specs its part must have
+type TemplateSlot struct {
+	Category Category `json:"category"`
+	// Required slots must be filled for a build to be made from the template
+	Required    bool            `json:"required"`
+	Quantity    int             `json:"quantity,omitempty"`
+	Constraints []SpecPredicate `json:"constraints,omitempty"`
+}
+
+// BuildTemplate represents the build_templates table, an admin-managed preset builds can be made from
+type BuildTemplate struct {
+	ID           int64          `json:"id" db:"id"`
+	Name         string         `json:"name" db:"name"`
+	Description  *string        `json:"description,omitempty" db:"description"`
+	UseCase      string         `json:"use_case" db:"use_case"`
+	Resolution   *string        `json:"resolution,omitempty" db:"resolution"`
+	TargetBudget float64        `json:"target_budget" db:"target_budget"`
+	Currency     string         `json:"currency" db:"currency"`
+	Slots        []TemplateSlot `json:"slots" db:"slots"`
+	CreatedBy    string         `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// BuildTemplateInput represents the data needed to create or replace a build template
+type BuildTemplateInput struct {
+	Name         string         `json:"name"`
+	Description  *string        `json:"description,omitempty"`
+	UseCase      string         `json:"use_case,omitempty"`
+	Resolution   *string        `json:"resolution,omitempty"`
+	TargetBudget float64        `json:"target_budget"`
+	Currency     string         `json:"currency,omitempty"`
+	Slots        []TemplateSlot `json:"slots"`
+}
+
+// BuildFromTemplate represents the optional details of a build made from a template
+type BuildFromTemplate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	IsPublic    *bool   `json:"is_public,omitempty"`
+	Region      *string `json:"region,omitempty"`
+}
+
+// BuildFromTemplateResult is a build made from a template and the optional slots that could not be filled
+type BuildFromTemplateResult struct {
+	TemplateID int64                   `json:"template_id"`
+	Build      UserBuildWithComponents `json:"build"`
+	Unfilled   []Category              `json:"unfilled"`
+}
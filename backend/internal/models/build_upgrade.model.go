@@ -0,0 +1,37 @@
+package models
+
+// UpgradePart is a catalog part that goes into a build in place of a current part, if any
+type UpgradePart struct {
+	Category   Category   `json:"category"`
+	Replaces   *Component `json:"replaces,omitempty"`
+	Component  Component  `json:"component"`
+	PriceID    int64      `json:"price_id"`
+	RetailerID int64      `json:"retailer_id"`
+	UnitPrice  float64    `json:"unit_price"`
+}
+
+// BuildUpgrade is a CPU or video card upgrade, or both, with the other parts it forces to be replaced
+type BuildUpgrade struct {
+	Parts []UpgradePart `json:"parts"`
+	// RequiredReplacements are parts that must change for the upgrade to fit, such as a motherboard for a new socket
+	RequiredReplacements []UpgradePart `json:"required_replacements"`
+	Cascades             bool          `json:"cascades"`
+	Cost                 float64       `json:"cost"`
+	Score                float64       `json:"score"`
+	// GainPercent is how much the build score rises by; GainPerDollar divides it by the cost
+	GainPercent   float64  `json:"gain_percent"`
+	GainPerDollar float64  `json:"gain_per_dollar"`
+	Reasons       []string `json:"reasons"`
+}
+
+// BuildUpgrades ranks the upgrades of a build by performance gain per unit of currency
+type BuildUpgrades struct {
+	BuildID      int64          `json:"build_id"`
+	UseCase      string         `json:"use_case"`
+	Resolution   string         `json:"resolution,omitempty"`
+	Currency     string         `json:"currency"`
+	Region       string         `json:"region"`
+	Budget       *float64       `json:"budget,omitempty"`
+	CurrentScore float64        `json:"current_score"`
+	Upgrades     []BuildUpgrade `json:"upgrades"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// BuildVersion represents the build_versions table, a snapshot of a build's parts and prices
+type BuildVersion struct {
+	ID         int64              `json:"id" db:"id"`
+	BuildID    int64              `json:"build_id" db:"build_id"`
+	Version    int                `json:"version" db:"version"`
+	Parts      []BuildVersionPart `json:"parts" db:"parts"`
+	TotalPrice *float64           `json:"total_price,omitempty" db:"total_price"`
+	Currency   string             `json:"currency" db:"currency"`
+	Reason     string             `json:"reason" db:"reason"`
+	CreatedBy  string             `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+}
+
+// BuildVersionPart is a part as it was when a version was recorded
+type BuildVersionPart struct {
+	ComponentID     int64    `json:"component_id"`
+	Category        Category `json:"category,omitempty"`
+	Brand           string   `json:"brand,omitempty"`
+	Model           string   `json:"model,omitempty"`
+	Quantity        int      `json:"quantity"`
+	SelectedPriceID *int64   `json:"selected_price_id,omitempty"`
+	Notes           *string  `json:"notes,omitempty"`
+	UnitPrice       *float64 `json:"unit_price,omitempty"`
+	LineTotal       *float64 `json:"line_total,omitempty"`
+}
+
+// BuildVersionPartChange is a part present in both versions of a diff with different values
+type BuildVersionPartChange struct {
+	ComponentID int64            `json:"component_id"`
+	Before      BuildVersionPart `json:"before"`
+	After       BuildVersionPart `json:"after"`
+	Changes     []string         `json:"changes"`
+}
+
+// BuildVersionDiff compares the parts and totals of two versions of a build
+type BuildVersionDiff struct {
+	BuildID     int64                    `json:"build_id"`
+	FromVersion int                      `json:"from_version"`
+	ToVersion   int                      `json:"to_version"`
+	Added       []BuildVersionPart       `json:"added"`
+	Removed     []BuildVersionPart       `json:"removed"`
+	Changed     []BuildVersionPartChange `json:"changed"`
+	FromTotal   *float64                 `json:"from_total,omitempty"`
+	ToTotal     *float64                 `json:"to_total,omitempty"`
+	Currency    string                   `json:"currency"`
+	// PriceDelta is ToTotal minus FromTotal; it is left out when either total is unknown or the currencies differ
+	PriceDelta *float64 `json:"price_delta,omitempty"`
+}
@@ -0,0 +1,131 @@
+package models
+
+// SpecOperator represents a comparison applied to a component spec value
+type SpecOperator string
+
+const (
+	SpecOperatorEquals   SpecOperator = "equals"
+	SpecOperatorContains SpecOperator = "contains"
+	SpecOperatorIn       SpecOperator = "in"
+	SpecOperatorLTE      SpecOperator = "lte"
+	SpecOperatorGTE      SpecOperator = "gte"
+)
+
+// Valid returns true if the operator is supported
+func (o SpecOperator) Valid() bool {
+	switch o {
+	case SpecOperatorEquals, SpecOperatorContains, SpecOperatorIn, SpecOperatorLTE, SpecOperatorGTE:
+		return true
+	}
+	return false
+}
+
+// Inverse returns the operator to use when the two sides of a comparison are swapped
+func (o SpecOperator) Inverse() SpecOperator {
+	switch o {
+	case SpecOperatorContains:
+		return SpecOperatorIn
+	case SpecOperatorIn:
+		return SpecOperatorContains
+	case SpecOperatorLTE:
+		return SpecOperatorGTE
+	case SpecOperatorGTE:
+		return SpecOperatorLTE
+	}
+	return o
+}
+
+// SpecPredicate is a single condition on a component's specs.
+// Value is a string, a number or a list of strings depending on Operator.
+type SpecPredicate struct {
+	SpecKey  string       `json:"spec_key"`
+	Operator SpecOperator `json:"operator"`
+	Value    interface{}  `json:"value"`
+	Reason   string       `json:"reason"`
+}
+
+// RuleSeverity represents how serious a compatibility rule violation is
+type RuleSeverity string
+
+const (
+	RuleSeverityError   RuleSeverity = "error"
+	RuleSeverityWarning RuleSeverity = "warning"
+	RuleSeverityInfo    RuleSeverity = "info"
+)
+
+// Valid returns true if the severity is valid
+func (s RuleSeverity) Valid() bool {
+	switch s {
+	case RuleSeverityError, RuleSeverityWarning, RuleSeverityInfo:
+		return true
+	}
+	return false
+}
+
+// RuleComparison compares a spec of the target component with a spec of the source component.
+// The comparison passes when target[TargetKey] <Operator> source[SourceKey].
+type RuleComparison struct {
+	SourceKey string       `json:"source_key" yaml:"source_key"`
+	Operator  SpecOperator `json:"operator" yaml:"operator"`
+	TargetKey string       `json:"target_key" yaml:"target_key"`
+}
+
+// CompatibilityRule is a declarative compatibility rule between two component categories
+type CompatibilityRule struct {
+	ID             string           `json:"id" yaml:"id"`
+	Description    string           `json:"description" yaml:"description"`
+	SourceCategory Category         `json:"source_category" yaml:"source_category"`
+	TargetCategory Category         `json:"target_category" yaml:"target_category"`
+	Comparisons    []RuleComparison `json:"comparisons" yaml:"comparisons"`
+	Severity       RuleSeverity     `json:"severity" yaml:"severity"`
+	Message        string           `json:"message" yaml:"message"`
+}
+
+// CompatibilityRuleFile is the document stored in a rule definition file
+type CompatibilityRuleFile struct {
+	Rules []CompatibilityRule `json:"rules" yaml:"rules"`
+}
+
+// CompatibilityIssue is a rule violation found in a build
+type CompatibilityIssue struct {
+	RuleID            string       `json:"rule_id"`
+	Severity          RuleSeverity `json:"severity"`
+	Message           string       `json:"message"`
+	SourceComponentID string       `json:"source_component_id,omitempty"`
+	TargetComponentID string       `json:"target_component_id,omitempty"`
+}
+
+// CompatibilityReport is the result of checking every part of a build against the rules
+type CompatibilityReport struct {
+	BuildID    int64                `json:"build_id"`
+	Compatible bool                 `json:"compatible"`
+	Issues     []CompatibilityIssue `json:"issues"`
+}
+
+// RuleFixtureComponent is a component described inline in a rule test fixture
+type RuleFixtureComponent struct {
+	ID       string                 `json:"id" yaml:"id"`
+	Category Category               `json:"category" yaml:"category"`
+	Brand    string                 `json:"brand" yaml:"brand"`
+	Model    string                 `json:"model" yaml:"model"`
+	Specs    map[string]interface{} `json:"specs" yaml:"specs"`
+}
+
+// RuleFixture describes a set of parts and the rule violations they are expected to produce
+type RuleFixture struct {
+	Name           string                 `json:"name" yaml:"name"`
+	Components     []RuleFixtureComponent `json:"components" yaml:"components"`
+	ExpectedIssues []string               `json:"expected_issues" yaml:"expected_issues"`
+}
+
+// RuleFixtureFile is the document stored in a rule test fixture file
+type RuleFixtureFile struct {
+	Fixtures []RuleFixture `json:"fixtures" yaml:"fixtures"`
+}
+
+// CompatibleComponent is a component returned by a compatibility filtered listing
+type CompatibleComponent struct {
+	Component
+	Borderline  bool     `json:"borderline"`
+	Explanation []string `json:"explanation,omitempty"`
+}
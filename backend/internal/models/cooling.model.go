@@ -0,0 +1,18 @@
+package models
+
+// CoolingReport rates how well a build is cooled
+type CoolingReport struct {
+	BuildID         int64    `json:"build_id"`
+	Rating          string   `json:"rating"`
+	CPUPowerW       *float64 `json:"cpu_power_w,omitempty"`
+	CoolerRatingW   *float64 `json:"cooler_rating_w,omitempty"`
+	ThermalHeadroom *float64 `json:"thermal_headroom,omitempty"`
+	FanMounts       *int     `json:"fan_mounts,omitempty"`
+	Fans            int      `json:"fans"`
+	IntakeFans      int      `json:"intake_fans"`
+	ExhaustFans     int      `json:"exhaust_fans"`
+	IntakeCFM       float64  `json:"intake_cfm"`
+	ExhaustCFM      float64  `json:"exhaust_cfm"`
+	AirflowBalance  string   `json:"airflow_balance"`
+	Reasons         []string `json:"reasons"`
+}
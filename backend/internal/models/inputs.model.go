@@ -4,7 +4,10 @@ type GenerateSelectQueryInput struct {
 	Table       string
 	Columns     []string
 	WhereClause string
+	OrderBy     string
 	Page        string
+	// Unpaged returns every matching row instead of one page
+	Unpaged bool
 }
 
 type GetComponentsByBrandInput struct {
@@ -14,9 +17,10 @@ type GetComponentsByBrandInput struct {
 }
 
 type ComponentQueryParams struct {
-	Category string
-	Brand    string
-	ID       string
+	Category            string
+	Brand               string
+	ID                  string
+	CompatibleWithBuild string
 }
 
 type GetComponentsByCategoryInput struct {
@@ -32,3 +36,29 @@ type GetComponentByIdInput struct {
 	ID   string
 	Page string
 }
+
+type GetCompatibleComponentsInput struct {
+	Category string
+	Brand    string
+	BuildID  int64
+	ViewerID string
+	Page     string
+}
+
+type GetComponentsBySpecPredicatesInput struct {
+	Category        string
+	Brand           string
+	Predicates      []SpecPredicate
+	OrderBySpec     string
+	OrderDescending bool
+	Page            string
+	// Unpaged returns every matching component instead of one page
+	Unpaged bool
+}
+
+type BottleneckAnalysisInput struct {
+	BuildID    int64
+	ViewerID   string
+	Resolution string
+	UseCase    string
+}
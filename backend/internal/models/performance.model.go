@@ -0,0 +1,31 @@
+package models
+
+// PerformancePart is a CPU or GPU scored for a use case
+type PerformancePart struct {
+	ComponentID    string  `json:"component_id"`
+	Brand          string  `json:"brand"`
+	Model          string  `json:"model"`
+	Score          float64 `json:"score"`
+	EffectiveScore float64 `json:"effective_score"`
+}
+
+// UpgradeSuggestion is a catalog component that would improve the balance of a build
+type UpgradeSuggestion struct {
+	Component    Component `json:"component"`
+	Score        float64   `json:"score"`
+	BalanceScore float64   `json:"balance_score"`
+}
+
+// BottleneckReport estimates which of the CPU and GPU limits a build
+type BottleneckReport struct {
+	BuildID           int64               `json:"build_id"`
+	UseCase           string              `json:"use_case"`
+	Resolution        string              `json:"resolution"`
+	CPU               *PerformancePart    `json:"cpu,omitempty"`
+	GPU               *PerformancePart    `json:"gpu,omitempty"`
+	Limiter           string              `json:"limiter"`
+	BalanceScore      float64             `json:"balance_score"`
+	BottleneckPercent float64             `json:"bottleneck_percent"`
+	Summary           string              `json:"summary"`
+	SuggestedUpgrades []UpgradeSuggestion `json:"suggested_upgrades"`
+}
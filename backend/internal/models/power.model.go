@@ -0,0 +1,32 @@
+package models
+
+const (
+	ConnectorStatusOK           = "ok"
+	ConnectorStatusAdapter      = "adapter"
+	ConnectorStatusInsufficient = "insufficient"
+	ConnectorStatusUnknown      = "unknown"
+)
+
+// AdapterSuggestion describes an adapter that covers a connector shortfall
+type AdapterSuggestion struct {
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	Safe        bool   `json:"safe"`
+}
+
+// PowerConnectorCheck compares what the parts of a build need for one connector type with what the PSU provides
+type PowerConnectorCheck struct {
+	Connector string              `json:"connector"`
+	Required  int                 `json:"required"`
+	Available int                 `json:"available"`
+	Status    string              `json:"status"`
+	Message   string              `json:"message"`
+	Adapters  []AdapterSuggestion `json:"adapters,omitempty"`
+}
+
+// PowerConnectorReport is the power cabling check of a build
+type PowerConnectorReport struct {
+	BuildID int64                 `json:"build_id"`
+	Checks  []PowerConnectorCheck `json:"checks"`
+	Issues  []CompatibilityIssue  `json:"issues"`
+}
@@ -53,3 +53,28 @@ type PriceFilter struct {
 	MinPrice    *float64 `json:"min_price,omitempty"`
 	MaxPrice    *float64 `json:"max_price,omitempty"`
 }
+
+// BuildPriceLine is the price of one part of a build, converted to the build's currency
+type BuildPriceLine struct {
+	ComponentID      int64    `json:"component_id"`
+	Quantity         int      `json:"quantity"`
+	Source           string   `json:"source"`
+	PriceID          *int64   `json:"price_id,omitempty"`
+	RetailerID       *int64   `json:"retailer_id,omitempty"`
+	OriginalPrice    *float64 `json:"original_price,omitempty"`
+	OriginalCurrency string   `json:"original_currency,omitempty"`
+	UnitPrice        *float64 `json:"unit_price,omitempty"`
+	LineTotal        *float64 `json:"line_total,omitempty"`
+	ProductURL       *string  `json:"product_url,omitempty"`
+	Message          string   `json:"message,omitempty"`
+}
+
+// BuildPriceBreakdown is the server-computed total of a build with the price used for each part
+type BuildPriceBreakdown struct {
+	BuildID  int64            `json:"build_id,omitempty"`
+	Currency string           `json:"currency"`
+	Region   string           `json:"region"`
+	Total    *float64         `json:"total"`
+	Complete bool             `json:"complete"`
+	Lines    []BuildPriceLine `json:"lines"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// M2Slot describes an entry of a motherboard's m2_slots spec
+type M2Slot struct {
+	Name              string   `json:"name"`
+	Interfaces        []string `json:"interfaces,omitempty"`
+	SharesLanesWith   string   `json:"shares_lanes_with,omitempty"`
+	DisablesSataPorts []int    `json:"disables_sata_ports,omitempty"`
+}
+
+// PCIeSlot describes an entry of a motherboard's pcie_slots spec
+type PCIeSlot struct {
+	Name  string `json:"name"`
+	Lanes int    `json:"lanes"`
+}
+
+// StorageAllocation places one unit of a storage part on a motherboard slot or port
+type StorageAllocation struct {
+	ComponentID string `json:"component_id"`
+	Brand       string `json:"brand"`
+	Model       string `json:"model"`
+	Unit        int    `json:"unit"`
+	Interface   string `json:"interface"`
+	SlotType    string `json:"slot_type"`
+	Slot        string `json:"slot,omitempty"`
+}
+
+// StorageAllocationReport is the proposed placement of every storage device in a build
+type StorageAllocationReport struct {
+	BuildID           int64                        `json:"build_id"`
+	Allocation        map[string]StorageAllocation `json:"allocation"`
+	Unallocated       []StorageAllocation          `json:"unallocated"`
+	SataPortsTotal    int                          `json:"sata_ports_total"`
+	SataPortsDisabled []string                     `json:"sata_ports_disabled"`
+	GPUSlotLanes      int                          `json:"gpu_slot_lanes,omitempty"`
+	Issues            []CompatibilityIssue         `json:"issues"`
+}
@@ -6,23 +6,44 @@ import (
 
 // UserBuild represents the user_builds table
 type UserBuild struct {
-	ID          int64     `json:"id" db:"id"`
-	UserID      string    `json:"user_id" db:"user_id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description,omitempty" db:"description"`
-	IsPublic    bool      `json:"is_public" db:"is_public"`
-	IsComplete  bool      `json:"is_complete" db:"is_complete"`
-	TotalPrice  *float64  `json:"total_price,omitempty" db:"total_price"`
-	Currency    string    `json:"currency" db:"currency"`
-	Region      string    `json:"region" db:"region"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID            int64     `json:"id" db:"id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	Name          string    `json:"name" db:"name"`
+	Description   *string   `json:"description,omitempty" db:"description"`
+	IsPublic      bool      `json:"is_public" db:"is_public"`
+	IsComplete    bool      `json:"is_complete" db:"is_complete"`
+	TotalPrice    *float64  `json:"total_price,omitempty" db:"total_price"`
+	Currency      string    `json:"currency" db:"currency"`
+	Region        string    `json:"region" db:"region"`
+	ParentBuildID *int64    `json:"parent_build_id,omitempty" db:"parent_build_id"`
+	ForkCount     int       `json:"fork_count" db:"fork_count"`
+	UseCase       *string   `json:"use_case,omitempty" db:"use_case"`
+	LikeCount     int       `json:"like_count" db:"like_count"`
+	CommentCount  int       `json:"comment_count" db:"comment_count"`
+	RatingCount   int       `json:"rating_count" db:"rating_count"`
+	RatingAverage *float64  `json:"rating_average,omitempty" db:"rating_average"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	// Revision goes up by one on every change and is sent back as the ETag of the build
+	Revision int `json:"revision" db:"revision"`
 }
 
 // UserBuildWithComponents represents a user build with its associated components
 type UserBuildWithComponents struct {
 	UserBuild
 	Components []BuildComponentWithDetails `json:"components,omitempty"`
+	Pricing    *BuildPriceBreakdown        `json:"pricing,omitempty"`
+	// Completeness is computed from the parts; IsComplete mirrors it
+	Completeness *BuildCompleteness `json:"completeness,omitempty"`
+}
+
+// BuildLineage describes where a build was forked from and the forks made of it
+type BuildLineage struct {
+	BuildID   int64 `json:"build_id"`
+	ForkCount int   `json:"fork_count"`
+	// Ancestors starts with the parent of the build; builds the viewer cannot see are left out
+	Ancestors []UserBuild `json:"ancestors"`
+	Forks     []UserBuild `json:"forks"`
 }
 
 // UserBuildCreate represents the data needed to create a new user build
@@ -31,20 +52,19 @@ type UserBuildCreate struct {
 	Name        string  `json:"name" validate:"required"`
 	Description *string `json:"description,omitempty"`
 	IsPublic    *bool   `json:"is_public,omitempty"`
-	IsComplete  *bool   `json:"is_complete,omitempty"`
 	Currency    *string `json:"currency,omitempty"`
 	Region      *string `json:"region,omitempty"`
+	UseCase     *string `json:"use_case,omitempty"`
 }
 
 // UserBuildUpdate represents the data that can be updated for a user build
 type UserBuildUpdate struct {
-	Name        *string  `json:"name,omitempty"`
-	Description *string  `json:"description,omitempty"`
-	IsPublic    *bool    `json:"is_public,omitempty"`
-	IsComplete  *bool    `json:"is_complete,omitempty"`
-	TotalPrice  *float64 `json:"total_price,omitempty"`
-	Currency    *string  `json:"currency,omitempty"`
-	Region      *string  `json:"region,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	IsPublic    *bool   `json:"is_public,omitempty"`
+	Currency    *string `json:"currency,omitempty"`
+	Region      *string `json:"region,omitempty"`
+	UseCase     *string `json:"use_case,omitempty"`
 }
 
 // UserBuildFilter represents filters for querying user builds
@@ -54,4 +74,5 @@ type UserBuildFilter struct {
 	IsComplete *bool   `json:"is_complete,omitempty"`
 	Currency   *string `json:"currency,omitempty"`
 	Region     *string `json:"region,omitempty"`
+	UseCase    *string `json:"use_case,omitempty"`
 }
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+var upsertBIOSRequirementQuery = fmt.Sprintf(`INSERT INTO %s (motherboard_id, cpu_id, min_bios_version, notes)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (motherboard_id, cpu_id)
+	DO UPDATE SET min_bios_version = EXCLUDED.min_bios_version, notes = EXCLUDED.notes, updated_at = now()`, constants.BIOS_REQUIREMENTS_TABLE)
+
+// GetBIOSRequirement returns the minimum BIOS version a motherboard needs for a CPU, or sql.ErrNoRows
+func GetBIOSRequirement(motherboardID, cpuID int64) (models.BIOSRequirement, error) {
+	utils.Log(constants.REPOSITORY_GET_BIOS_REQUIREMENT_START, nil, motherboardID, cpuID)
+
+	query, err := utils.GenerateSelectQuery(models.GenerateSelectQueryInput{
+		Table:       constants.BIOS_REQUIREMENTS_TABLE,
+		Columns:     constants.BIOS_REQUIREMENTS_SELECT_COLUMNS,
+		WhereClause: "motherboard_id = $1 AND cpu_id = $2",
+	})
+	if err != nil {
+		return models.BIOSRequirement{}, err
+	}
+
+	var requirement models.BIOSRequirement
+	err = utils.GetDB().QueryRow(query, motherboardID, cpuID).Scan(&requirement.ID, &requirement.MotherboardID, &requirement.CPUID,
+		&requirement.MinBIOSVersion, &requirement.Notes, &requirement.CreatedAt, &requirement.UpdatedAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			utils.Log(constants.REPOSITORY_GET_BIOS_REQUIREMENT_SCAN_ERROR, err, motherboardID, cpuID)
+		}
+		return models.BIOSRequirement{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_GET_BIOS_REQUIREMENT_SUCCESS, nil, motherboardID, cpuID)
+	return requirement, nil
+}
+
+// UpsertBIOSRequirements inserts or updates every requirement in a single transaction
+func UpsertBIOSRequirements(requirements []models.BIOSRequirement) error {
+	utils.Log(constants.REPOSITORY_UPSERT_BIOS_REQUIREMENTS_START, nil, len(requirements))
+
+	tx, err := utils.GetDB().Begin()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_UPSERT_BIOS_REQUIREMENTS_DB_ERROR, err)
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(upsertBIOSRequirementQuery)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_UPSERT_BIOS_REQUIREMENTS_DB_ERROR, err)
+		return err
+	}
+	defer stmt.Close()
+
+	for _, requirement := range requirements {
+		if _, err := stmt.Exec(requirement.MotherboardID, requirement.CPUID, requirement.MinBIOSVersion, requirement.Notes); err != nil {
+			utils.Log(constants.REPOSITORY_UPSERT_BIOS_REQUIREMENTS_DB_ERROR, err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.Log(constants.REPOSITORY_UPSERT_BIOS_REQUIREMENTS_DB_ERROR, err)
+		return err
+	}
+
+	utils.Log(constants.REPOSITORY_UPSERT_BIOS_REQUIREMENTS_SUCCESS, nil, len(requirements))
+	return nil
+}
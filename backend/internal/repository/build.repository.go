@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const buildPartsQuery = `SELECT bc.id, bc.build_id, bc.component_id, bc.quantity, bc.selected_price_id, bc.notes, bc.created_at,
+	c.id, c.category, c.brand, c.model, c.sku, c.upc, c.specs, c.created_at,
+	p.id, p.component_id, p.retailer_id, p.region, p.currency, p.price, p.in_stock, p.product_url, p.last_updated, p.created_at
+	FROM build_components bc
+	JOIN components c ON c.id = bc.component_id
+	LEFT JOIN prices p ON p.id = bc.selected_price_id
+	WHERE bc.build_id = $1
+	ORDER BY bc.id`
+
+// buildVisibilityCondition matches the builds a viewer may see: public builds, their own and those they accepted an
+// invitation to. It takes the position of the viewer argument.
+const buildVisibilityCondition = `(is_public = true OR user_id = $%[1]d OR EXISTS (SELECT 1 FROM ` + constants.BUILD_COLLABORATORS_TABLE + ` bcl
+	WHERE bcl.build_id = ` + constants.USER_BUILDS_TABLE + `.id AND bcl.user_id = $%[1]d AND bcl.accepted_at IS NOT NULL))`
+
+// ErrBuildRevisionConflict is returned when a build changed after the revision a change was based on
+var ErrBuildRevisionConflict = errors.New("build was changed by someone else")
+
+func GetUserBuildById(id int64) (models.UserBuild, error) {
+	utils.Log(constants.REPOSITORY_GET_BUILD_BY_ID_START, nil, id)
+
+	queryInput := models.GenerateSelectQueryInput{
+		Table:       constants.USER_BUILDS_TABLE,
+		Columns:     constants.USER_BUILDS_SELECT_COLUMNS,
+		WhereClause: "id = $1",
+	}
+
+	query, err := utils.GenerateSelectQuery(queryInput)
+	if err != nil {
+		return models.UserBuild{}, err
+	}
+
+	row := utils.GetDB().QueryRow(query, id)
+
+	build, err := scanUserBuild(row)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_BUILD_BY_ID_SCAN_ERROR, err, id)
+		return models.UserBuild{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_GET_BUILD_BY_ID_SUCCESS, nil, id)
+	return build, nil
+}
+
+// GetBuildParts returns every part of a build with its component and selected price joined
+func GetBuildParts(buildID int64) ([]models.BuildComponentWithDetails, error) {
+	utils.Log(constants.REPOSITORY_GET_BUILD_PARTS_START, nil, buildID)
+
+	parts, err := queryBuildParts(utils.GetDB(), buildID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_BUILD_PARTS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+
+	utils.Log(constants.REPOSITORY_GET_BUILD_PARTS_SUCCESS, nil, len(parts), buildID)
+	return parts, nil
+}
+
+// queryBuildParts reads the parts of a build through db, which is the database or a transaction that changed them
+func queryBuildParts(db querier, buildID int64) ([]models.BuildComponentWithDetails, error) {
+	rows, err := db.Query(buildPartsQuery, buildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parts := []models.BuildComponentWithDetails{}
+	for rows.Next() {
+		var part models.BuildComponentWithDetails
+		var component models.Component
+		var price nullablePrice
+		err := rows.Scan(&part.ID, &part.BuildID, &part.ComponentID, &part.Quantity, &part.SelectedPriceID, &part.Notes, &part.CreatedAt,
+			&component.ID, &component.Category, &component.Brand, &component.Model, &component.SKU, &component.UPC, &component.Specs, &component.CreatedAt,
+			&price.ID, &price.ComponentID, &price.RetailerID, &price.Region, &price.Currency, &price.Price, &price.InStock, &price.ProductURL,
+			&price.LastUpdated, &price.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		part.Component = &component
+		part.SelectedPrice = price.toPrice()
+		parts = append(parts, part)
+	}
+	return parts, rows.Err()
+}
+
+// ListUserBuilds returns the builds matching a filter that the viewer may see: public builds, their own and
+// those they collaborate on
+func ListUserBuilds(filter models.UserBuildFilter, viewerID, page string) ([]models.UserBuild, error) {
+	utils.Log(constants.REPOSITORY_LIST_BUILDS_START, nil)
+
+	whereClause, args := buildUserBuildFilterClause(filter, viewerID)
+	query, err := utils.GenerateSelectQuery(models.GenerateSelectQueryInput{
+		Table:       constants.USER_BUILDS_TABLE,
+		Columns:     constants.USER_BUILDS_SELECT_COLUMNS,
+		WhereClause: whereClause,
+		OrderBy:     "updated_at DESC, id DESC",
+		Page:        page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := utils.GetDB().Query(query, args...)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILDS_DB_ERROR, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	builds := []models.UserBuild{}
+	for rows.Next() {
+		build, err := scanUserBuild(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILDS_SCAN_ERROR, err)
+			return nil, err
+		}
+		builds = append(builds, build)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILDS_DB_ERROR, err)
+		return nil, err
+	}
+
+	utils.Log(constants.REPOSITORY_LIST_BUILDS_SUCCESS, nil, len(builds))
+	return builds, nil
+}
+
+// buildUserBuildFilterClause translates a filter into a parameterized WHERE clause.
+// Private builds are only visible to their owner and collaborators.
+func buildUserBuildFilterClause(filter models.UserBuildFilter, viewerID string) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	add(buildVisibilityCondition, viewerID)
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+	if filter.IsPublic != nil {
+		add("is_public = $%d", *filter.IsPublic)
+	}
+	if filter.IsComplete != nil {
+		add("is_complete = $%d", *filter.IsComplete)
+	}
+	if filter.Currency != nil {
+		add("COALESCE(currency, 'USD') = $%d", *filter.Currency)
+	}
+	if filter.Region != nil {
+		add("COALESCE(region, 'USA') = $%d", *filter.Region)
+	}
+	if filter.UseCase != nil {
+		add("use_case = $%d", *filter.UseCase)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+var createUserBuildQuery = fmt.Sprintf(`INSERT INTO %s (user_id, name, description, is_public, currency, region, use_case)
+	VALUES ($1, $2, $3, COALESCE($4, false), COALESCE($5, 'USD'), COALESCE($6, 'USA'), NULLIF($7, ''))
+	RETURNING %s`, constants.USER_BUILDS_TABLE, strings.Join(constants.USER_BUILDS_SELECT_COLUMNS, ", "))
+
+func CreateUserBuild(input models.UserBuildCreate) (models.UserBuild, error) {
+	utils.Log(constants.REPOSITORY_CREATE_BUILD_START, nil, input.UserID)
+
+	row := utils.GetDB().QueryRow(createUserBuildQuery, input.UserID, input.Name, input.Description, input.IsPublic, input.Currency, input.Region, input.UseCase)
+	build, err := scanUserBuild(row)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_CREATE_BUILD_DB_ERROR, err, input.UserID)
+		return models.UserBuild{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_CREATE_BUILD_SUCCESS, nil, build.ID)
+	return build, nil
+}
+
+// CreateUserBuildWithParts creates a build together with its parts and first version in one transaction, so a
+// failure leaves no empty or half-filled build behind. Parts are stored as ReplaceBuildParts stores them.
+func CreateUserBuildWithParts(input models.UserBuildCreate, parts []models.BuildVersionPart, snapshot BuildSnapshotter) (models.UserBuild, error) {
+	utils.Log(constants.REPOSITORY_CREATE_BUILD_START, nil, input.UserID)
+
+	build, err := func() (models.UserBuild, error) {
+		tx, err := utils.GetDB().Begin()
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		row := tx.QueryRow(createUserBuildQuery, input.UserID, input.Name, input.Description, input.IsPublic, input.Currency, input.Region, input.UseCase)
+		build, err := scanUserBuild(row)
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		if err := insertBuildParts(tx, build.ID, parts); err != nil {
+			return models.UserBuild{}, err
+		}
+		version, complete, err := recordBuildVersion(tx, build.ID, snapshot)
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		build.TotalPrice, build.IsComplete = version.TotalPrice, complete
+		return build, tx.Commit()
+	}()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_CREATE_BUILD_DB_ERROR, err, input.UserID)
+		return models.UserBuild{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_CREATE_BUILD_SUCCESS, nil, build.ID)
+	return build, nil
+}
+
+// UpdateUserBuild sets the fields present in update and returns the updated build, or sql.ErrNoRows. When revision is
+// set the build must still be at that revision, otherwise ErrBuildRevisionConflict is returned. A set snapshot
+// records the change as a new version in the same transaction.
+func UpdateUserBuild(id int64, update models.UserBuildUpdate, revision *int, snapshot BuildSnapshotter) (models.UserBuild, error) {
+	utils.Log(constants.REPOSITORY_UPDATE_BUILD_START, nil, id)
+
+	assignments := []string{"updated_at = now()", "revision = revision + 1"}
+	args := []interface{}{}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if update.Name != nil {
+		set("name", *update.Name)
+	}
+	if update.Description != nil {
+		set("description", *update.Description)
+	}
+	if update.IsPublic != nil {
+		set("is_public", *update.IsPublic)
+	}
+	if update.Currency != nil {
+		set("currency", *update.Currency)
+	}
+	if update.Region != nil {
+		set("region", *update.Region)
+	}
+	if update.UseCase != nil {
+		args = append(args, *update.UseCase)
+		assignments = append(assignments, fmt.Sprintf("use_case = NULLIF($%d, '')", len(args)))
+	}
+
+	args = append(args, id)
+	where := fmt.Sprintf("id = $%d", len(args))
+	if revision != nil {
+		args = append(args, *revision)
+		where += fmt.Sprintf(" AND revision = $%d", len(args))
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s RETURNING %s", constants.USER_BUILDS_TABLE,
+		strings.Join(assignments, ", "), where, strings.Join(constants.USER_BUILDS_SELECT_COLUMNS, ", "))
+
+	build, err := func() (models.UserBuild, error) {
+		tx, err := utils.GetDB().Begin()
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		build, err := scanUserBuild(tx.QueryRow(query, args...))
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		if snapshot != nil {
+			version, complete, err := recordBuildVersion(tx, id, snapshot)
+			if err != nil {
+				return models.UserBuild{}, err
+			}
+			build.TotalPrice, build.IsComplete = version.TotalPrice, complete
+		}
+		return build, tx.Commit()
+	}()
+	if errors.Is(err, sql.ErrNoRows) && revision != nil {
+		err = ErrBuildRevisionConflict
+	}
+	if err != nil {
+		utils.Log(constants.REPOSITORY_UPDATE_BUILD_DB_ERROR, err, id)
+		return models.UserBuild{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_UPDATE_BUILD_SUCCESS, nil, id)
+	return build, nil
+}
+
+// DeleteUserBuild deletes a build and, through ON DELETE CASCADE, its parts. It returns sql.ErrNoRows when the build does
+// not exist, or ErrBuildRevisionConflict when revision is set and the build is no longer at it.
+func DeleteUserBuild(id int64, revision *int) error {
+	utils.Log(constants.REPOSITORY_DELETE_BUILD_START, nil, id)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", constants.USER_BUILDS_TABLE)
+	args := []interface{}{id}
+	if revision != nil {
+		query += " AND revision = $2"
+		args = append(args, *revision)
+	}
+	result, err := utils.GetDB().Exec(query, args...)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_DB_ERROR, err, id)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_DB_ERROR, err, id)
+		return err
+	}
+	if affected == 0 && revision != nil {
+		return ErrBuildRevisionConflict
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	utils.Log(constants.REPOSITORY_DELETE_BUILD_SUCCESS, nil, id)
+	return nil
+}
+
+// SetUserBuildTotals stores the server-computed total and completeness of a build without touching updated_at
+func SetUserBuildTotals(id int64, total *float64, complete bool) error {
+	query := fmt.Sprintf("UPDATE %s SET total_price = $1, is_complete = $2 WHERE id = $3", constants.USER_BUILDS_TABLE)
+	if _, err := utils.GetDB().Exec(query, total, complete, id); err != nil {
+		utils.Log(constants.REPOSITORY_SET_BUILD_TOTALS_DB_ERROR, err, id)
+		return err
+	}
+	return nil
+}
+
+// ListBuildIDsWithComponents returns the IDs of builds containing any of the components, or of every build when none are given
+func ListBuildIDsWithComponents(componentIDs []int64) ([]int64, error) {
+	query := fmt.Sprintf("SELECT id FROM %s ORDER BY id", constants.USER_BUILDS_TABLE)
+	args := []interface{}{}
+	if len(componentIDs) > 0 {
+		query = fmt.Sprintf("SELECT DISTINCT build_id FROM %s WHERE component_id = ANY($1) ORDER BY build_id", constants.BUILD_COMPONENTS_TABLE)
+		args = append(args, pq.Array(componentIDs))
+	}
+
+	rows, err := utils.GetDB().Query(query, args...)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_IDS_DB_ERROR, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILD_IDS_DB_ERROR, err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func scanUserBuild(row rowScanner) (models.UserBuild, error) {
+	var build models.UserBuild
+	err := row.Scan(&build.ID, &build.UserID, &build.Name, &build.Description, &build.IsPublic, &build.IsComplete,
+		&build.TotalPrice, &build.Currency, &build.Region, &build.ParentBuildID, &build.ForkCount, &build.UseCase, &build.LikeCount,
+		&build.CommentCount, &build.RatingCount, &build.RatingAverage, &build.CreatedAt, &build.UpdatedAt, &build.Revision)
+	return build, err
+}
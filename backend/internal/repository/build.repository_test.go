@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildUserBuildFilterClause tests translation of build filters into parameterized conditions
+func TestBuildUserBuildFilterClause(t *testing.T) {
+	userID, isPublic, currency := "user-1", true, "CAD"
+
+	t.Run("Visibility only", func(t *testing.T) {
+		clause, args := buildUserBuildFilterClause(models.UserBuildFilter{}, "viewer")
+
+		assert.Equal(t, "(is_public = true OR user_id = $1 OR EXISTS (SELECT 1 FROM build_collaborators bcl\n\tWHERE bcl.build_id = user_builds.id AND bcl.user_id = $1 AND bcl.accepted_at IS NOT NULL))", clause)
+		assert.Equal(t, []interface{}{"viewer"}, args)
+	})
+
+	t.Run("Every filter", func(t *testing.T) {
+		clause, args := buildUserBuildFilterClause(models.UserBuildFilter{UserID: &userID, IsPublic: &isPublic, Currency: &currency}, "")
+
+		assert.Contains(t, clause, "bcl.user_id = $1 AND bcl.accepted_at IS NOT NULL)) AND user_id = $2 AND is_public = $3 AND COALESCE(currency, 'USD') = $4")
+		assert.Equal(t, []interface{}{"", "user-1", true, "CAD"}, args)
+	})
+}
+
+// TestUserBuildMutations tests that updates only set the provided fields, a stale revision reports a conflict and
+// deleting a missing build reports sql.ErrNoRows
+func TestUserBuildMutations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	name, isPublic := "Renamed", true
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+		AddRow(7, "user-1", name, nil, true, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 4)
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE user_builds SET updated_at = now\\(\\), revision = revision \\+ 1, name = \\$1, is_public = \\$2 WHERE id = \\$3 RETURNING (.+)").
+		WithArgs(name, isPublic, int64(7)).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	build, err := UpdateUserBuild(7, models.UserBuildUpdate{Name: &name, IsPublic: &isPublic}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", build.Name)
+	assert.True(t, build.IsPublic)
+	assert.Equal(t, 4, build.Revision)
+
+	revision := 2
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE user_builds SET (.+) WHERE id = \\$2 AND revision = \\$3 RETURNING").
+		WithArgs(name, int64(7), 2).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+	_, err = UpdateUserBuild(7, models.UserBuildUpdate{Name: &name}, &revision, nil)
+	assert.ErrorIs(t, err, ErrBuildRevisionConflict)
+
+	mock.ExpectExec("DELETE FROM user_builds WHERE id = \\$1").WithArgs(int64(8)).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, DeleteUserBuild(8, nil), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestCreateUserBuildWithParts tests that the build, its parts and its first version are created together and a
+// failed part rolls the build back
+func TestCreateUserBuildWithParts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	buildRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+			AddRow(9, "user-1", "Imported", nil, false, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 1)
+	}
+	insertPart := "INSERT INTO build_components (.+) SELECT \\$1, c.id, \\$3"
+	input := models.UserBuildCreate{UserID: "user-1", Name: "Imported"}
+	parts := []models.BuildVersionPart{{ComponentID: 5, Quantity: 2}, {ComponentID: 6, Quantity: 1}}
+
+	t.Run("Creates the build with its parts", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO user_builds").WillReturnRows(buildRow())
+		mock.ExpectExec(insertPart).WithArgs(int64(9), int64(5), 2, nil, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertPart).WithArgs(int64(9), int64(6), 1, nil, nil).WillReturnResult(sqlmock.NewResult(2, 1))
+		expectBuildVersion(mock, 9, 1)
+		mock.ExpectCommit()
+
+		build, err := CreateUserBuildWithParts(input, parts, stubSnapshot)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), build.ID)
+		require.NotNil(t, build.TotalPrice)
+		assert.Equal(t, 100.0, *build.TotalPrice)
+		assert.True(t, build.IsComplete)
+	})
+
+	t.Run("Rolls back when a part fails", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO user_builds").WillReturnRows(buildRow())
+		mock.ExpectExec(insertPart).WithArgs(int64(9), int64(5), 2, nil, nil).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err := CreateUserBuildWithParts(input, parts, stubSnapshot)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
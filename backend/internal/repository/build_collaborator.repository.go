@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// CreateBuildCollaborator stores a pending invitation of userID to a build
+func CreateBuildCollaborator(buildID int64, userID, role, invitedBy string) (models.BuildCollaborator, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (build_id, user_id, role, invited_by)
+	VALUES ($1, $2, $3, $4)
+	RETURNING %s`, constants.BUILD_COLLABORATORS_TABLE, strings.Join(constants.BUILD_COLLABORATORS_SELECT_COLUMNS, ", "))
+
+	collaborator, err := scanBuildCollaborator(utils.GetDB().QueryRow(query, buildID, userID, role, invitedBy))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_CREATE_BUILD_COLLABORATOR_DB_ERROR, err, userID, buildID)
+		return models.BuildCollaborator{}, err
+	}
+	return collaborator, nil
+}
+
+// ListBuildCollaborators returns every collaborator of a build, pending invitations included, oldest first
+func ListBuildCollaborators(buildID int64) ([]models.BuildCollaborator, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE build_id = $1 ORDER BY created_at, id",
+		strings.Join(constants.BUILD_COLLABORATORS_SELECT_COLUMNS, ", "), constants.BUILD_COLLABORATORS_TABLE)
+
+	rows, err := utils.GetDB().Query(query, buildID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_COLLABORATORS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	collaborators := []models.BuildCollaborator{}
+	for rows.Next() {
+		collaborator, err := scanBuildCollaborator(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILD_COLLABORATORS_DB_ERROR, err, buildID)
+			return nil, err
+		}
+		collaborators = append(collaborators, collaborator)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_COLLABORATORS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// GetBuildCollaborator returns the collaborator entry of userID on a build, accepted or not, or sql.ErrNoRows
+func GetBuildCollaborator(buildID int64, userID string) (models.BuildCollaborator, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE build_id = $1 AND user_id = $2",
+		strings.Join(constants.BUILD_COLLABORATORS_SELECT_COLUMNS, ", "), constants.BUILD_COLLABORATORS_TABLE)
+
+	collaborator, err := scanBuildCollaborator(utils.GetDB().QueryRow(query, buildID, userID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.Log(constants.REPOSITORY_GET_BUILD_COLLABORATOR_DB_ERROR, err, userID, buildID)
+	}
+	return collaborator, err
+}
+
+// UpdateBuildCollaboratorRole changes the role of a collaborator, returning sql.ErrNoRows when the build has no such collaborator
+func UpdateBuildCollaboratorRole(buildID int64, userID, role string) (models.BuildCollaborator, error) {
+	query := fmt.Sprintf("UPDATE %s SET role = $1, updated_at = now() WHERE build_id = $2 AND user_id = $3 RETURNING %s",
+		constants.BUILD_COLLABORATORS_TABLE, strings.Join(constants.BUILD_COLLABORATORS_SELECT_COLUMNS, ", "))
+
+	collaborator, err := scanBuildCollaborator(utils.GetDB().QueryRow(query, role, buildID, userID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.Log(constants.REPOSITORY_UPDATE_BUILD_COLLABORATOR_DB_ERROR, err, userID, buildID)
+	}
+	return collaborator, err
+}
+
+// AcceptBuildCollaborator accepts the pending invitation of userID, returning sql.ErrNoRows when there is none
+func AcceptBuildCollaborator(buildID int64, userID string) (models.BuildCollaborator, error) {
+	query := fmt.Sprintf(`UPDATE %s SET accepted_at = now(), updated_at = now()
+	WHERE build_id = $1 AND user_id = $2 AND accepted_at IS NULL
+	RETURNING %s`, constants.BUILD_COLLABORATORS_TABLE, strings.Join(constants.BUILD_COLLABORATORS_SELECT_COLUMNS, ", "))
+
+	collaborator, err := scanBuildCollaborator(utils.GetDB().QueryRow(query, buildID, userID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.Log(constants.REPOSITORY_UPDATE_BUILD_COLLABORATOR_DB_ERROR, err, userID, buildID)
+	}
+	return collaborator, err
+}
+
+// DeleteBuildCollaborator removes a collaborator or pending invitation. When pendingOnly is set accepted collaborators
+// are left alone. It returns sql.ErrNoRows when nothing was removed.
+func DeleteBuildCollaborator(buildID int64, userID string, pendingOnly bool) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE build_id = $1 AND user_id = $2", constants.BUILD_COLLABORATORS_TABLE)
+	if pendingOnly {
+		query += " AND accepted_at IS NULL"
+	}
+
+	result, err := utils.GetDB().Exec(query, buildID, userID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_COLLABORATOR_DB_ERROR, err, userID, buildID)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_COLLABORATOR_DB_ERROR, err, userID, buildID)
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListBuildInvitations returns the pending invitations of userID with the name of each build, newest first
+func ListBuildInvitations(userID string) ([]models.BuildInvitation, error) {
+	columns := make([]string, len(constants.BUILD_COLLABORATORS_SELECT_COLUMNS))
+	for i, column := range constants.BUILD_COLLABORATORS_SELECT_COLUMNS {
+		columns[i] = "bcl." + column
+	}
+	query := fmt.Sprintf(`SELECT %s, b.name FROM %s bcl
+	JOIN %s b ON b.id = bcl.build_id
+	WHERE bcl.user_id = $1 AND bcl.accepted_at IS NULL
+	ORDER BY bcl.created_at DESC, bcl.id DESC`, strings.Join(columns, ", "), constants.BUILD_COLLABORATORS_TABLE, constants.USER_BUILDS_TABLE)
+
+	rows, err := utils.GetDB().Query(query, userID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_INVITATIONS_DB_ERROR, err, userID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []models.BuildInvitation{}
+	for rows.Next() {
+		var invitation models.BuildInvitation
+		collaborator := &invitation.BuildCollaborator
+		err := rows.Scan(&collaborator.ID, &collaborator.BuildID, &collaborator.UserID, &collaborator.Role, &collaborator.InvitedBy,
+			&collaborator.AcceptedAt, &collaborator.CreatedAt, &collaborator.UpdatedAt, &invitation.BuildName)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILD_INVITATIONS_DB_ERROR, err, userID)
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_INVITATIONS_DB_ERROR, err, userID)
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func scanBuildCollaborator(row rowScanner) (models.BuildCollaborator, error) {
+	var collaborator models.BuildCollaborator
+	err := row.Scan(&collaborator.ID, &collaborator.BuildID, &collaborator.UserID, &collaborator.Role, &collaborator.InvitedBy,
+		&collaborator.AcceptedAt, &collaborator.CreatedAt, &collaborator.UpdatedAt)
+	return collaborator, err
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// countBuildComments refreshes the comment count of a build, leaving out deleted comments. Run it after lockBuild.
+var countBuildComments = fmt.Sprintf("UPDATE %s SET comment_count = (SELECT COUNT(*) FROM %s WHERE build_id = $1 AND deleted_at IS NULL) WHERE id = $1",
+	constants.USER_BUILDS_TABLE, constants.BUILD_COMMENTS_TABLE)
+
+// CreateBuildComment stores a comment and counts it on the build in one transaction
+func CreateBuildComment(buildID int64, userID string, input models.BuildCommentCreate) (models.BuildComment, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (build_id, parent_id, user_id, body) VALUES ($1, $2, $3, $4)
+	RETURNING %s`, constants.BUILD_COMMENTS_TABLE, strings.Join(constants.BUILD_COMMENTS_SELECT_COLUMNS, ", "))
+
+	comment, err := func() (models.BuildComment, error) {
+		tx, err := utils.GetDB().Begin()
+		if err != nil {
+			return models.BuildComment{}, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := lockBuild(tx, buildID); err != nil {
+			return models.BuildComment{}, err
+		}
+		comment, err := scanBuildComment(tx.QueryRow(query, buildID, input.ParentID, userID, input.Body))
+		if err != nil {
+			return models.BuildComment{}, err
+		}
+		if _, err := tx.Exec(countBuildComments, buildID); err != nil {
+			return models.BuildComment{}, err
+		}
+		return comment, tx.Commit()
+	}()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_CREATE_BUILD_COMMENT_DB_ERROR, err, buildID)
+		return models.BuildComment{}, err
+	}
+	return comment, nil
+}
+
+// GetBuildComment returns a comment of a build, or sql.ErrNoRows
+func GetBuildComment(buildID, commentID int64) (models.BuildComment, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND build_id = $2",
+		strings.Join(constants.BUILD_COMMENTS_SELECT_COLUMNS, ", "), constants.BUILD_COMMENTS_TABLE)
+
+	comment, err := scanBuildComment(utils.GetDB().QueryRow(query, commentID, buildID))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			utils.Log(constants.REPOSITORY_GET_BUILD_COMMENT_DB_ERROR, err, commentID)
+		}
+		return models.BuildComment{}, err
+	}
+	return comment, nil
+}
+
+// ListBuildComments returns every comment of a build, including deleted ones, oldest first
+func ListBuildComments(buildID int64) ([]models.BuildComment, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE build_id = $1 ORDER BY created_at, id",
+		strings.Join(constants.BUILD_COMMENTS_SELECT_COLUMNS, ", "), constants.BUILD_COMMENTS_TABLE)
+
+	rows, err := utils.GetDB().Query(query, buildID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_COMMENTS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.BuildComment{}
+	for rows.Next() {
+		comment, err := scanBuildComment(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILD_COMMENTS_DB_ERROR, err, buildID)
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_COMMENTS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	return comments, nil
+}
+
+// UpdateBuildCommentBody replaces the body of a comment that is not deleted, or returns sql.ErrNoRows
+func UpdateBuildCommentBody(commentID int64, body string) (models.BuildComment, error) {
+	query := fmt.Sprintf("UPDATE %s SET body = $1, edited_at = now() WHERE id = $2 AND deleted_at IS NULL RETURNING %s",
+		constants.BUILD_COMMENTS_TABLE, strings.Join(constants.BUILD_COMMENTS_SELECT_COLUMNS, ", "))
+
+	comment, err := scanBuildComment(utils.GetDB().QueryRow(query, body, commentID))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			utils.Log(constants.REPOSITORY_UPDATE_BUILD_COMMENT_DB_ERROR, err, commentID)
+		}
+		return models.BuildComment{}, err
+	}
+	return comment, nil
+}
+
+// SoftDeleteBuildComment marks a comment deleted by deletedBy and stops counting it on the build.
+// It returns sql.ErrNoRows when the comment is missing or already deleted.
+func SoftDeleteBuildComment(buildID, commentID int64, deletedBy string) error {
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = now(), deleted_by = $1 WHERE id = $2 AND build_id = $3 AND deleted_at IS NULL",
+		constants.BUILD_COMMENTS_TABLE)
+
+	err := func() error {
+		tx, err := utils.GetDB().Begin()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := lockBuild(tx, buildID); err != nil {
+			return err
+		}
+		result, err := tx.Exec(query, deletedBy, commentID, buildID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		if _, err := tx.Exec(countBuildComments, buildID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err != nil && err != sql.ErrNoRows {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_COMMENT_DB_ERROR, err, commentID)
+	}
+	return err
+}
+
+func scanBuildComment(row rowScanner) (models.BuildComment, error) {
+	var comment models.BuildComment
+	err := row.Scan(&comment.ID, &comment.BuildID, &comment.ParentID, &comment.UserID, &comment.Body,
+		&comment.CreatedAt, &comment.EditedAt, &comment.DeletedAt, &comment.DeletedBy)
+	return comment, err
+}
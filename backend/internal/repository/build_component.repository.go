@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const buildComponentColumns = "id, build_id, component_id, quantity, selected_price_id, notes, created_at"
+
+// Adding a component that is already in the build adds to its quantity; a new price or notes replace the old ones.
+// A merge that would go past $6 units updates nothing and returns no row.
+const upsertBuildPartQuery = `INSERT INTO build_components (build_id, component_id, quantity, selected_price_id, notes)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (build_id, component_id)
+	DO UPDATE SET quantity = build_components.quantity + EXCLUDED.quantity,
+		selected_price_id = COALESCE(EXCLUDED.selected_price_id, build_components.selected_price_id),
+		notes = COALESCE(EXCLUDED.notes, build_components.notes)
+	WHERE build_components.quantity + EXCLUDED.quantity <= $6
+	RETURNING ` + buildComponentColumns
+
+// UpsertBuildPart adds a component to a build, merging it with an existing entry for the same component.
+// It returns sql.ErrNoRows when the merged quantity would exceed MAX_PART_QUANTITY.
+func UpsertBuildPart(input models.BuildComponentCreate, revision *int, snapshot BuildSnapshotter) (models.BuildComponent, error) {
+	utils.Log(constants.REPOSITORY_UPSERT_BUILD_PART_START, nil, input.ComponentID, input.BuildID)
+
+	quantity := 1
+	if input.Quantity != nil {
+		quantity = *input.Quantity
+	}
+
+	var part models.BuildComponent
+	err := withBuildTransaction(input.BuildID, revision, snapshot, func(tx *sql.Tx) error {
+		var err error
+		part, err = scanBuildComponent(tx.QueryRow(upsertBuildPartQuery, input.BuildID, input.ComponentID, quantity, input.SelectedPriceID, input.Notes, constants.MAX_PART_QUANTITY))
+		return err
+	})
+	if err != nil {
+		utils.Log(constants.REPOSITORY_UPSERT_BUILD_PART_DB_ERROR, err, input.ComponentID, input.BuildID)
+		return models.BuildComponent{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_UPSERT_BUILD_PART_SUCCESS, nil, input.ComponentID, input.BuildID)
+	return part, nil
+}
+
+// UpdateBuildPart sets the fields present in update on a part, returning sql.ErrNoRows when the build has no such component
+func UpdateBuildPart(buildID, componentID int64, update models.BuildComponentUpdate, revision *int, snapshot BuildSnapshotter) (models.BuildComponent, error) {
+	utils.Log(constants.REPOSITORY_UPDATE_BUILD_PART_START, nil, componentID, buildID)
+
+	assignments := []string{}
+	args := []interface{}{}
+	set := func(column string, value interface{}) {
+		args = append(args, value)
+		assignments = append(assignments, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if update.Quantity != nil {
+		set("quantity", *update.Quantity)
+	}
+	if update.SelectedPriceID != nil {
+		set("selected_price_id", *update.SelectedPriceID)
+	}
+	if update.Notes != nil {
+		set("notes", *update.Notes)
+	}
+	if len(assignments) == 0 {
+		// Nothing to change, but still report a missing part
+		assignments = append(assignments, "quantity = quantity")
+	}
+
+	args = append(args, buildID, componentID)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE build_id = $%d AND component_id = $%d RETURNING %s", constants.BUILD_COMPONENTS_TABLE,
+		strings.Join(assignments, ", "), len(args)-1, len(args), buildComponentColumns)
+
+	var part models.BuildComponent
+	err := withBuildTransaction(buildID, revision, snapshot, func(tx *sql.Tx) error {
+		var err error
+		part, err = scanBuildComponent(tx.QueryRow(query, args...))
+		return err
+	})
+	if err != nil {
+		utils.Log(constants.REPOSITORY_UPDATE_BUILD_PART_DB_ERROR, err, componentID, buildID)
+		return models.BuildComponent{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_UPDATE_BUILD_PART_SUCCESS, nil, componentID, buildID)
+	return part, nil
+}
+
+// DeleteBuildPart removes a component from a build, returning sql.ErrNoRows when the build has no such component
+func DeleteBuildPart(buildID, componentID int64, revision *int, snapshot BuildSnapshotter) error {
+	utils.Log(constants.REPOSITORY_DELETE_BUILD_PART_START, nil, componentID, buildID)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE build_id = $1 AND component_id = $2", constants.BUILD_COMPONENTS_TABLE)
+	err := withBuildTransaction(buildID, revision, snapshot, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, buildID, componentID)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_PART_DB_ERROR, err, componentID, buildID)
+		return err
+	}
+
+	utils.Log(constants.REPOSITORY_DELETE_BUILD_PART_SUCCESS, nil, componentID, buildID)
+	return nil
+}
+
+// withBuildTransaction runs fn in a transaction that also bumps the build's updated_at and revision. When revision is
+// set the build must still be at that revision; the row lock taken by the bump makes a concurrent change wait and
+// then fail with ErrBuildRevisionConflict instead of overwriting this one. A set snapshot records the change as a
+// new version in the same transaction.
+func withBuildTransaction(buildID int64, revision *int, snapshot BuildSnapshotter, fn func(tx *sql.Tx) error) error {
+	tx, err := utils.GetDB().Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET updated_at = now(), revision = revision + 1 WHERE id = $1", constants.USER_BUILDS_TABLE)
+	args := []interface{}{buildID}
+	if revision != nil {
+		query += " AND revision = $2"
+		args = append(args, *revision)
+	}
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 && revision != nil {
+		return ErrBuildRevisionConflict
+	}
+	if snapshot != nil {
+		if _, _, err := recordBuildVersion(tx, buildID, snapshot); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// lockBuild locks the row of a build until the transaction ends. Statements run after taking the lock see the
+// committed changes of every transaction that held it before, so concurrent likes, ratings and comments are never
+// lost from the counts and a fork sees the build's current visibility. It returns sql.ErrNoRows when the build does
+// not exist.
+func lockBuild(tx *sql.Tx, buildID int64) error {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", constants.USER_BUILDS_TABLE)
+	var id int64
+	return tx.QueryRow(query, buildID).Scan(&id)
+}
+
+func scanBuildComponent(row rowScanner) (models.BuildComponent, error) {
+	var part models.BuildComponent
+	err := row.Scan(&part.ID, &part.BuildID, &part.ComponentID, &part.Quantity, &part.SelectedPriceID, &part.Notes, &part.CreatedAt)
+	return part, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildPartMutations tests that adding a part merges quantities up to MAX_PART_QUANTITY, changing a missing part reports sql.ErrNoRows and
+// a change based on a stale revision reports a conflict
+func TestBuildPartMutations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	columns := []string{"id", "build_id", "component_id", "quantity", "selected_price_id", "notes", "created_at"}
+	touchBuild := "UPDATE user_builds SET updated_at = now\\(\\), revision = revision \\+ 1 WHERE id = \\$1"
+
+	t.Run("Add defaults to one and merges on conflict", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO build_components (.+) ON CONFLICT \\(build_id, component_id\\) DO UPDATE SET quantity = build_components.quantity \\+ EXCLUDED.quantity").
+			WithArgs(int64(3), int64(42), 1, nil, nil, constants.MAX_PART_QUANTITY).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(9, 3, 42, 3, nil, nil, time.Now()))
+		mock.ExpectExec(touchBuild).WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		part, err := UpsertBuildPart(models.BuildComponentCreate{BuildID: 3, ComponentID: 42}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, part.Quantity)
+	})
+
+	t.Run("Add past the quantity limit", func(t *testing.T) {
+		quantity := 2
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO build_components (.+) WHERE build_components.quantity \\+ EXCLUDED.quantity <= \\$6").
+			WithArgs(int64(3), int64(42), 2, nil, nil, constants.MAX_PART_QUANTITY).
+			WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectRollback()
+
+		_, err := UpsertBuildPart(models.BuildComponentCreate{BuildID: 3, ComponentID: 42, Quantity: &quantity}, nil, nil)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Update sets only the provided fields", func(t *testing.T) {
+		quantity := 2
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE build_components SET quantity = \\$1 WHERE build_id = \\$2 AND component_id = \\$3 RETURNING (.+)").
+			WithArgs(2, int64(3), int64(42)).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(9, 3, 42, 2, nil, nil, time.Now()))
+		mock.ExpectExec(touchBuild).WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		part, err := UpdateBuildPart(3, 42, models.BuildComponentUpdate{Quantity: &quantity}, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, part.Quantity)
+	})
+
+	t.Run("Update of a missing part", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE build_components SET quantity = quantity WHERE").
+			WithArgs(int64(3), int64(7)).
+			WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectRollback()
+
+		_, err := UpdateBuildPart(3, 7, models.BuildComponentUpdate{}, nil, nil)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Delete of a missing part", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components WHERE build_id = \\$1 AND component_id = \\$2").
+			WithArgs(int64(3), int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, DeleteBuildPart(3, 7, nil, nil), sql.ErrNoRows)
+	})
+
+	t.Run("Stale revision", func(t *testing.T) {
+		revision := 5
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components WHERE build_id = \\$1 AND component_id = \\$2").
+			WithArgs(int64(3), int64(42)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(touchBuild+" AND revision = \\$2").WithArgs(int64(3), 5).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, DeleteBuildPart(3, 42, &revision, nil), ErrBuildRevisionConflict)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// Lineage deeper than this is cut off rather than followed
+const maxBuildAncestors = 100
+
+// ForkUserBuild copies a build and its parts into a new private build owned by userID, records its first version and
+// counts the fork on the original, all in one transaction. The original is locked and copied only while it is public
+// or owned by userID, so a build made private during the fork is not copied. It returns sql.ErrNoRows when the
+// original does not exist or may not be copied.
+func ForkUserBuild(sourceID int64, userID string, snapshot BuildSnapshotter) (models.UserBuild, error) {
+	utils.Log(constants.REPOSITORY_FORK_BUILD_START, nil, sourceID, userID)
+
+	columns := strings.Join(constants.USER_BUILDS_SELECT_COLUMNS, ", ")
+	copyBuild := fmt.Sprintf(`INSERT INTO %[1]s (user_id, name, description, is_public, is_complete, total_price, currency, region, use_case, parent_build_id)
+	SELECT $2, name, description, false, is_complete, total_price, currency, region, use_case, id FROM %[1]s
+	WHERE id = $1 AND (is_public OR user_id = $2)
+	RETURNING %[2]s`, constants.USER_BUILDS_TABLE, columns)
+	copyParts := fmt.Sprintf(`INSERT INTO %[1]s (build_id, component_id, quantity, selected_price_id, notes)
+	SELECT $1, component_id, quantity, selected_price_id, notes FROM %[1]s WHERE build_id = $2 ORDER BY id`, constants.BUILD_COMPONENTS_TABLE)
+	countFork := fmt.Sprintf("UPDATE %s SET fork_count = fork_count + 1 WHERE id = $1", constants.USER_BUILDS_TABLE)
+
+	fork, err := func() (models.UserBuild, error) {
+		tx, err := utils.GetDB().Begin()
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := lockBuild(tx, sourceID); err != nil {
+			return models.UserBuild{}, err
+		}
+		fork, err := scanUserBuild(tx.QueryRow(copyBuild, sourceID, userID))
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		if _, err := tx.Exec(copyParts, fork.ID, sourceID); err != nil {
+			return models.UserBuild{}, err
+		}
+		if _, err := tx.Exec(countFork, sourceID); err != nil {
+			return models.UserBuild{}, err
+		}
+		version, complete, err := recordBuildVersion(tx, fork.ID, snapshot)
+		if err != nil {
+			return models.UserBuild{}, err
+		}
+		fork.TotalPrice, fork.IsComplete = version.TotalPrice, complete
+		return fork, tx.Commit()
+	}()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_FORK_BUILD_DB_ERROR, err, sourceID, userID)
+		return models.UserBuild{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_FORK_BUILD_SUCCESS, nil, sourceID, fork.ID)
+	return fork, nil
+}
+
+// GetBuildAncestors returns the build's parent, the parent's parent and so on, nearest first
+func GetBuildAncestors(buildID int64) ([]models.UserBuild, error) {
+	query := fmt.Sprintf(`WITH RECURSIVE ancestors(ancestor_id, depth) AS (
+		SELECT parent_build_id, 1 FROM %[1]s WHERE id = $1
+		UNION ALL
+		SELECT b.parent_build_id, a.depth + 1 FROM %[1]s b JOIN ancestors a ON b.id = a.ancestor_id WHERE a.depth < %[3]d
+	)
+	SELECT %[2]s FROM %[1]s JOIN ancestors ON ancestors.ancestor_id = %[1]s.id ORDER BY ancestors.depth`,
+		constants.USER_BUILDS_TABLE, strings.Join(constants.USER_BUILDS_SELECT_COLUMNS, ", "), maxBuildAncestors)
+
+	rows, err := utils.GetDB().Query(query, buildID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_BUILD_ANCESTORS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+
+	builds, err := scanUserBuildRows(rows)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_BUILD_ANCESTORS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	return builds, nil
+}
+
+// GetBuildForks returns the direct forks of a build that the viewer may see, newest first
+func GetBuildForks(buildID int64, viewerID string) ([]models.UserBuild, error) {
+	query, err := utils.GenerateSelectQuery(models.GenerateSelectQueryInput{
+		Table:       constants.USER_BUILDS_TABLE,
+		Columns:     constants.USER_BUILDS_SELECT_COLUMNS,
+		WhereClause: "parent_build_id = $1 AND " + fmt.Sprintf(buildVisibilityCondition, 2),
+		OrderBy:     "created_at DESC, id DESC",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := utils.GetDB().Query(query, buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_BUILD_FORKS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+
+	builds, err := scanUserBuildRows(rows)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_BUILD_FORKS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	return builds, nil
+}
+
+// scanUserBuildRows scans and closes rows of USER_BUILDS_SELECT_COLUMNS
+func scanUserBuildRows(rows *sql.Rows) ([]models.UserBuild, error) {
+	defer rows.Close()
+
+	builds := []models.UserBuild{}
+	for rows.Next() {
+		build, err := scanUserBuild(rows)
+		if err != nil {
+			return nil, err
+		}
+		builds = append(builds, build)
+	}
+	return builds, rows.Err()
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForkUserBuild tests that a fork copies the build and its parts, records its first version and counts the fork
+// in one transaction
+func TestForkUserBuild(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	columns := []string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}
+
+	expectLock := func(id int64) {
+		mock.ExpectQuery("SELECT id FROM user_builds WHERE id = \\$1 FOR UPDATE").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	}
+
+	t.Run("Copies build and parts", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock(4)
+		mock.ExpectQuery("INSERT INTO user_builds (.+) SELECT \\$2, name, description, false, (.+) FROM user_builds WHERE id = \\$1 AND \\(is_public OR user_id = \\$2\\)").
+			WithArgs(int64(4), "user-2").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(9, "user-2", "Gaming PC", nil, false, false, nil, "USD", "USA", 4, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 1))
+		mock.ExpectExec("INSERT INTO build_components (.+) SELECT \\$1, component_id, quantity, selected_price_id, notes FROM build_components WHERE build_id = \\$2").
+			WithArgs(int64(9), int64(4)).
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec("UPDATE user_builds SET fork_count = fork_count \\+ 1 WHERE id = \\$1").
+			WithArgs(int64(4)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectBuildVersion(mock, 9, 1)
+		mock.ExpectCommit()
+
+		fork, err := ForkUserBuild(4, "user-2", stubSnapshot)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), fork.ID)
+		assert.False(t, fork.IsPublic)
+		require.NotNil(t, fork.ParentBuildID)
+		assert.Equal(t, int64(4), *fork.ParentBuildID)
+	})
+
+	t.Run("Rolls back when parts cannot be copied", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock(4)
+		mock.ExpectQuery("INSERT INTO user_builds").
+			WithArgs(int64(4), "user-2").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(10, "user-2", "Gaming PC", nil, false, false, nil, "USD", "USA", 4, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 1))
+		mock.ExpectExec("INSERT INTO build_components").WithArgs(int64(10), int64(4)).WillReturnError(errors.New("connection lost"))
+		mock.ExpectRollback()
+
+		_, err := ForkUserBuild(4, "user-2", stubSnapshot)
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing original", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM user_builds WHERE id = \\$1 FOR UPDATE").WithArgs(int64(5)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := ForkUserBuild(5, "user-2", stubSnapshot)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Original made private before the copy", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock(6)
+		mock.ExpectQuery("INSERT INTO user_builds").WithArgs(int64(6), "user-2").WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectRollback()
+
+		_, err := ForkUserBuild(6, "user-2", stubSnapshot)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
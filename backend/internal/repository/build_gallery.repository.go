@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// galleryTotalUSD is a build's total in US dollars at current prices, rounded so it survives a trip through a cursor
+const galleryTotalUSD = "live.total_usd"
+
+// galleryPriceUSD converts a price to US dollars; it is NULL when the price's currency has no rate
+var galleryPriceUSD = fmt.Sprintf("p.price / CASE WHEN upper(p.currency) = 'USD' THEN 1 ELSE (SELECT r.usd_rate FROM %s r WHERE r.currency = upper(p.currency)) END",
+	constants.EXCHANGE_RATES_TABLE)
+
+// galleryLiveTotal prices the parts of a build when the gallery is read, as PriceBuild does: each part at its selected
+// price, or else its cheapest in-stock price in the build's region. Stored totals only change with the build, so
+// sorting and filtering on them would lag behind new prices and exchange rates. Parts without a usable price are
+// left out, and a build with none priced has no total.
+var galleryLiveTotal = fmt.Sprintf(`SELECT round(CASE WHEN COUNT(*) = 0 THEN 0 ELSE SUM(bc.quantity * unit.usd) END, 4) AS total_usd
+		FROM %[1]s bc
+		LEFT JOIN LATERAL (SELECT CASE WHEN bc.selected_price_id IS NOT NULL
+			THEN (SELECT %[3]s FROM %[2]s p WHERE p.id = bc.selected_price_id)
+			ELSE (SELECT MIN(%[3]s) FROM %[2]s p WHERE p.component_id = bc.component_id AND p.region = COALESCE(b.region, 'USA') AND p.in_stock = true)
+		END AS usd) unit ON true
+		WHERE bc.build_id = b.id`, constants.BUILD_COMPONENTS_TABLE, constants.PRICES_TABLE, galleryPriceUSD)
+
+// galleryTotal converts the live total back to the build's currency for the card
+const galleryTotal = "round(live.total_usd * CASE WHEN COALESCE(upper(b.currency), 'USD') = 'USD' THEN 1 ELSE fx.usd_rate END, 2)"
+
+// gallerySortKeys maps each gallery sort to its key expression, the type the cursor value is cast to and the direction
+var gallerySortKeys = map[string]struct {
+	key       string
+	cursorSQL string
+	ascending bool
+}{
+	models.GallerySortNewest:     {key: "b.created_at", cursorSQL: "timestamptz"},
+	models.GallerySortMostForked: {key: "b.fork_count", cursorSQL: "integer"},
+	models.GallerySortMostLiked:  {key: "b.like_count", cursorSQL: "integer"},
+	models.GallerySortPriceLow:   {key: galleryTotalUSD, cursorSQL: "numeric", ascending: true},
+	models.GallerySortPriceHigh:  {key: galleryTotalUSD, cursorSQL: "numeric"},
+}
+
+// IsValidGallerySort returns true if the public build gallery can be ordered by sort
+func IsValidGallerySort(sort string) bool {
+	_, ok := gallerySortKeys[sort]
+	return ok
+}
+
+// ListPublicBuildCards returns up to filter.Limit public builds as cards, after the build in filter.Cursor.
+// Totals are priced when the gallery is read. Price bounds in the filter are in US dollars. Builds without a total are
+// left out when sorting by price.
+func ListPublicBuildCards(filter models.BuildGalleryFilter) ([]models.BuildCard, error) {
+	sort, ok := gallerySortKeys[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown gallery sort %q", filter.Sort)
+	}
+
+	conditions := []string{"b.is_public = true"}
+	args := []interface{}{models.CategoryCPU, models.CategoryVideoCard}
+	add := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if filter.MinPrice != nil {
+		add(galleryTotalUSD+" >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		add(galleryTotalUSD+" <= $%d", *filter.MaxPrice)
+	}
+	if filter.Region != nil {
+		add("COALESCE(b.region, 'USA') = $%d", *filter.Region)
+	}
+	if filter.UseCase != nil {
+		add("b.use_case = $%d", *filter.UseCase)
+	}
+	if filter.ComponentID != nil {
+		add(fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE build_id = b.id AND component_id = $%%d)", constants.BUILD_COMPONENTS_TABLE), *filter.ComponentID)
+	}
+	if filter.CPUBrand != nil {
+		add("lower(cpu.brand) = lower($%d)", *filter.CPUBrand)
+	}
+	if filter.GPUBrand != nil {
+		add("lower(gpu.brand) = lower($%d)", *filter.GPUBrand)
+	}
+	if sort.key == galleryTotalUSD {
+		conditions = append(conditions, galleryTotalUSD+" IS NOT NULL")
+	}
+
+	direction, comparison := "DESC", "<"
+	if sort.ascending {
+		direction, comparison = "ASC", ">"
+	}
+	if filter.Cursor != nil {
+		add(fmt.Sprintf("(%s, b.id) %s ($%%d::%s, $%%d)", sort.key, comparison, sort.cursorSQL), filter.Cursor.Value, filter.Cursor.ID)
+	}
+
+	headline := fmt.Sprintf(`SELECT c.id, c.brand, c.model FROM %s bc JOIN %s c ON c.id = bc.component_id
+		WHERE bc.build_id = b.id AND c.category = $%%d ORDER BY bc.id LIMIT 1`, constants.BUILD_COMPONENTS_TABLE, constants.COMPONENTS_TABLE)
+	// A build whose currency has no rate cannot be priced, so its live total stays NULL
+	query := fmt.Sprintf(`SELECT b.id, b.user_id, b.name, b.use_case, %[11]s, COALESCE(b.currency, 'USD'), COALESCE(b.region, 'USA'),
+		b.is_complete, b.fork_count, b.like_count, b.created_at, %[1]s,
+		cpu.id, cpu.brand, cpu.model, gpu.id, gpu.brand, gpu.model,
+		(SELECT COUNT(*) FROM %[2]s WHERE build_id = b.id)
+	FROM %[3]s b
+	LEFT JOIN %[4]s fx ON fx.currency = upper(b.currency)
+	LEFT JOIN LATERAL (%[12]s) live ON COALESCE(upper(b.currency), 'USD') = 'USD' OR fx.usd_rate IS NOT NULL
+	LEFT JOIN LATERAL (%[5]s) cpu ON true
+	LEFT JOIN LATERAL (%[6]s) gpu ON true
+	WHERE %[7]s
+	ORDER BY %[8]s %[9]s, b.id %[9]s
+	LIMIT %[10]d`,
+		galleryTotalUSD, constants.BUILD_COMPONENTS_TABLE, constants.USER_BUILDS_TABLE, constants.EXCHANGE_RATES_TABLE,
+		fmt.Sprintf(headline, 1), fmt.Sprintf(headline, 2), strings.Join(conditions, " AND "), sort.key, direction, filter.Limit,
+		galleryTotal, galleryLiveTotal)
+
+	rows, err := utils.GetDB().Query(query, args...)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_PUBLIC_BUILDS_DB_ERROR, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	cards := []models.BuildCard{}
+	for rows.Next() {
+		card, err := scanBuildCard(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_PUBLIC_BUILDS_SCAN_ERROR, err)
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_PUBLIC_BUILDS_DB_ERROR, err)
+		return nil, err
+	}
+	return cards, nil
+}
+
+func scanBuildCard(row rowScanner) (models.BuildCard, error) {
+	var card models.BuildCard
+	var cpuID, gpuID sql.NullInt64
+	var cpuBrand, cpuModel, gpuBrand, gpuModel sql.NullString
+	err := row.Scan(&card.ID, &card.UserID, &card.Name, &card.UseCase, &card.TotalPrice, &card.Currency, &card.Region,
+		&card.IsComplete, &card.ForkCount, &card.LikeCount, &card.CreatedAt, &card.TotalUSD,
+		&cpuID, &cpuBrand, &cpuModel, &gpuID, &gpuBrand, &gpuModel, &card.PartCount)
+	if err != nil {
+		return models.BuildCard{}, err
+	}
+
+	if cpuID.Valid {
+		card.CPU = &models.BuildCardPart{ComponentID: cpuID.Int64, Brand: cpuBrand.String, Model: cpuModel.String}
+	}
+	if gpuID.Valid {
+		card.GPU = &models.BuildCardPart{ComponentID: gpuID.Int64, Brand: gpuBrand.String, Model: gpuModel.String}
+	}
+	return card, nil
+}
+
+// GalleryCursorValue returns the sort key of a card as cursor text
+func GalleryCursorValue(card models.BuildCard, sort string) string {
+	switch sort {
+	case models.GallerySortMostForked:
+		return strconv.Itoa(card.ForkCount)
+	case models.GallerySortMostLiked:
+		return strconv.Itoa(card.LikeCount)
+	case models.GallerySortPriceLow, models.GallerySortPriceHigh:
+		if card.TotalUSD != nil {
+			return strconv.FormatFloat(*card.TotalUSD, 'f', 4, 64)
+		}
+		return "0"
+	}
+	return card.CreatedAt.UTC().Format("2006-01-02T15:04:05.999999Z07:00")
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListPublicBuildCards tests gallery filters on live totals, keyset conditions and card scanning
+func TestListPublicBuildCards(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	columns := []string{"id", "user_id", "name", "use_case", "total_price", "currency", "region", "is_complete", "fork_count", "like_count",
+		"created_at", "total_usd", "cpu_id", "cpu_brand", "cpu_model", "gpu_id", "gpu_brand", "gpu_model", "part_count"}
+
+	t.Run("Filters and cursor for a price sort", func(t *testing.T) {
+		maxPrice, brand, componentID := 1500.0, "AMD", int64(42)
+		filter := models.BuildGalleryFilter{
+			MaxPrice:    &maxPrice,
+			CPUBrand:    &brand,
+			ComponentID: &componentID,
+			Sort:        models.GallerySortPriceLow,
+			Cursor:      &models.GalleryCursor{Sort: models.GallerySortPriceLow, Value: "999.5000", ID: 12},
+			Limit:       3,
+		}
+
+		mock.ExpectQuery(`LEFT JOIN LATERAL \(SELECT round\(.+FROM build_components bc.+FROM prices p WHERE p.id = bc.selected_price_id.+p.in_stock = true.+\) live ON .+WHERE b.is_public = true AND live.total_usd <= \$3 AND EXISTS \(SELECT 1 FROM build_components WHERE build_id = b.id AND component_id = \$4\) AND lower\(cpu.brand\) = lower\(\$5\) AND live.total_usd IS NOT NULL AND \(live.total_usd, b.id\) > \(\$6::numeric, \$7\)\s+ORDER BY live.total_usd ASC, b.id ASC\s+LIMIT 3`).
+			WithArgs(models.CategoryCPU, models.CategoryVideoCard, maxPrice, componentID, brand, "999.5000", int64(12)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(15, "user-1", "Ryzen rig", "gaming", 1200.0, "USD", "USA", true, 2, 5, time.Now(), 1200.0, 42, "AMD", "Ryzen 7 7800X3D", 7, "NVIDIA", "RTX 4070", 8).
+				AddRow(16, "user-2", "Office box", nil, 1300.0, "CAD", "CAN", false, 0, 0, time.Now(), 950.0, 42, "AMD", "Ryzen 5 8600G", nil, nil, nil, 5))
+
+		cards, err := ListPublicBuildCards(filter)
+
+		require.NoError(t, err)
+		require.Len(t, cards, 2)
+		assert.Equal(t, "Ryzen 7 7800X3D", cards[0].CPU.Model)
+		assert.Equal(t, "RTX 4070", cards[0].GPU.Model)
+		assert.Equal(t, 8, cards[0].PartCount)
+		assert.Nil(t, cards[1].GPU)
+		assert.Nil(t, cards[1].UseCase)
+		assert.Equal(t, "950.0000", GalleryCursorValue(cards[1], models.GallerySortPriceLow))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Newest first", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE b.is_public = true\s+ORDER BY b.created_at DESC, b.id DESC\s+LIMIT 25`).
+			WithArgs(models.CategoryCPU, models.CategoryVideoCard).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		cards, err := ListPublicBuildCards(models.BuildGalleryFilter{Sort: models.GallerySortNewest, Limit: 25})
+
+		require.NoError(t, err)
+		assert.Empty(t, cards)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown sort", func(t *testing.T) {
+		_, err := ListPublicBuildCards(models.BuildGalleryFilter{Sort: "random", Limit: 1})
+		assert.Error(t, err)
+	})
+}
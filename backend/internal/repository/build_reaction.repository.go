@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// SetBuildLike adds or removes the like of a user on a build and returns the build's like count.
+// Liking twice or removing a missing like changes nothing.
+func SetBuildLike(buildID int64, userID string, liked bool) (int, error) {
+	change := fmt.Sprintf("DELETE FROM %s WHERE build_id = $1 AND user_id = $2", constants.BUILD_LIKES_TABLE)
+	if liked {
+		change = fmt.Sprintf("INSERT INTO %s (build_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", constants.BUILD_LIKES_TABLE)
+	}
+	count := fmt.Sprintf("UPDATE %s SET like_count = (SELECT COUNT(*) FROM %s WHERE build_id = $1) WHERE id = $1 RETURNING like_count",
+		constants.USER_BUILDS_TABLE, constants.BUILD_LIKES_TABLE)
+
+	likeCount, err := func() (int, error) {
+		tx, err := utils.GetDB().Begin()
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := lockBuild(tx, buildID); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(change, buildID, userID); err != nil {
+			return 0, err
+		}
+		var likeCount int
+		if err := tx.QueryRow(count, buildID).Scan(&likeCount); err != nil {
+			return 0, err
+		}
+		return likeCount, tx.Commit()
+	}()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_SET_BUILD_LIKE_DB_ERROR, err, buildID, userID)
+		return 0, err
+	}
+	return likeCount, nil
+}
+
+// HasBuildLike returns true if the user likes the build
+func HasBuildLike(buildID int64, userID string) (bool, error) {
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE build_id = $1 AND user_id = $2)", constants.BUILD_LIKES_TABLE)
+
+	var liked bool
+	if err := utils.GetDB().QueryRow(query, buildID, userID).Scan(&liked); err != nil {
+		utils.Log(constants.REPOSITORY_SET_BUILD_LIKE_DB_ERROR, err, buildID, userID)
+		return false, err
+	}
+	return liked, nil
+}
+
+// SetBuildRating stores the rating of a user on a build, or removes it when rating is nil, and returns the
+// build's rating count and average
+func SetBuildRating(buildID int64, userID string, rating *int) (int, *float64, error) {
+	change := fmt.Sprintf("DELETE FROM %s WHERE build_id = $1 AND user_id = $2", constants.BUILD_RATINGS_TABLE)
+	args := []interface{}{buildID, userID}
+	if rating != nil {
+		change = fmt.Sprintf(`INSERT INTO %s (build_id, user_id, rating) VALUES ($1, $2, $3)
+		ON CONFLICT (build_id, user_id) DO UPDATE SET rating = EXCLUDED.rating, updated_at = now()`, constants.BUILD_RATINGS_TABLE)
+		args = append(args, *rating)
+	}
+	aggregate := fmt.Sprintf(`UPDATE %[1]s SET
+		rating_count = (SELECT COUNT(*) FROM %[2]s WHERE build_id = $1),
+		rating_average = (SELECT round(AVG(rating), 2) FROM %[2]s WHERE build_id = $1)
+	WHERE id = $1 RETURNING rating_count, rating_average`, constants.USER_BUILDS_TABLE, constants.BUILD_RATINGS_TABLE)
+
+	var count int
+	var average *float64
+	err := func() error {
+		tx, err := utils.GetDB().Begin()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := lockBuild(tx, buildID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(change, args...); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(aggregate, buildID).Scan(&count, &average); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_SET_BUILD_RATING_DB_ERROR, err, buildID, userID)
+		return 0, nil, err
+	}
+	return count, average, nil
+}
+
+// GetBuildRating returns the rating a user gave a build, or nil when they have not rated it
+func GetBuildRating(buildID int64, userID string) (*int, error) {
+	query := fmt.Sprintf("SELECT rating FROM %s WHERE build_id = $1 AND user_id = $2", constants.BUILD_RATINGS_TABLE)
+
+	rows, err := utils.GetDB().Query(query, buildID, userID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_SET_BUILD_RATING_DB_ERROR, err, buildID, userID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var rating int
+	if err := rows.Scan(&rating); err != nil {
+		utils.Log(constants.REPOSITORY_SET_BUILD_RATING_DB_ERROR, err, buildID, userID)
+		return nil, err
+	}
+	return &rating, nil
+}
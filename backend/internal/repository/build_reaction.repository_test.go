@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildReactions tests that likes, ratings and comments lock the build before changing it and refresh the
+// counters on the build in the same transaction
+func TestBuildReactions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	expectLock := func() {
+		mock.ExpectQuery("SELECT id FROM user_builds WHERE id = \\$1 FOR UPDATE").
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	}
+
+	t.Run("Like a build", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock()
+		mock.ExpectExec("INSERT INTO build_likes \\(build_id, user_id\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT DO NOTHING").
+			WithArgs(int64(7), "user-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("UPDATE user_builds SET like_count = (.+) RETURNING like_count").
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"like_count"}).AddRow(3))
+		mock.ExpectCommit()
+
+		likeCount, err := SetBuildLike(7, "user-1", true)
+		require.NoError(t, err)
+		assert.Equal(t, 3, likeCount)
+	})
+
+	t.Run("Rate a build", func(t *testing.T) {
+		rating := 4
+		mock.ExpectBegin()
+		expectLock()
+		mock.ExpectExec("INSERT INTO build_ratings \\(build_id, user_id, rating\\) VALUES \\(\\$1, \\$2, \\$3\\)").
+			WithArgs(int64(7), "user-1", 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("UPDATE user_builds SET rating_count = (.+) RETURNING rating_count, rating_average").
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"rating_count", "rating_average"}).AddRow(2, 4.5))
+		mock.ExpectCommit()
+
+		count, average, err := SetBuildRating(7, "user-1", &rating)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 4.5, *average)
+	})
+
+	t.Run("Comment on a build", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock()
+		mock.ExpectQuery("INSERT INTO build_comments \\(build_id, parent_id, user_id, body\\)").
+			WithArgs(int64(7), nil, "user-1", "Nice build").
+			WillReturnRows(sqlmock.NewRows(constants.BUILD_COMMENTS_SELECT_COLUMNS).
+				AddRow(4, 7, nil, "user-1", "Nice build", time.Now(), nil, nil, nil))
+		mock.ExpectExec("UPDATE user_builds SET comment_count = (.+) WHERE id = \\$1").
+			WithArgs(int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		comment, err := CreateBuildComment(7, "user-1", models.BuildCommentCreate{Body: "Nice build"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), comment.ID)
+	})
+
+	t.Run("Like a missing build", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM user_builds WHERE id = \\$1 FOR UPDATE").
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := SetBuildLike(7, "user-1", true)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Delete a comment that is already deleted", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectLock()
+		mock.ExpectExec("UPDATE build_comments SET deleted_at = now\\(\\), deleted_by = \\$1 WHERE id = \\$2 AND build_id = \\$3 AND deleted_at IS NULL").
+			WithArgs("user-1", int64(4), int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, SoftDeleteBuildComment(7, 4, "user-1"), sql.ErrNoRows)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
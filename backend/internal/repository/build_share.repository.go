@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// CreateBuildShare stores a share link by the hash of its token
+func CreateBuildShare(buildID int64, tokenHash, access, createdBy string, expiresAt *time.Time) (models.BuildShare, error) {
+	query := fmt.Sprintf(`INSERT INTO %s (build_id, token_hash, access, created_by, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING %s`, constants.BUILD_SHARES_TABLE, strings.Join(constants.BUILD_SHARES_SELECT_COLUMNS, ", "))
+
+	share, err := scanBuildShare(utils.GetDB().QueryRow(query, buildID, tokenHash, access, createdBy, expiresAt))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_CREATE_BUILD_SHARE_DB_ERROR, err, buildID)
+		return models.BuildShare{}, err
+	}
+	return share, nil
+}
+
+// ListBuildShares returns every share link of a build, including revoked and expired ones, newest first
+func ListBuildShares(buildID int64) ([]models.BuildShare, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE build_id = $1 ORDER BY created_at DESC, id DESC",
+		strings.Join(constants.BUILD_SHARES_SELECT_COLUMNS, ", "), constants.BUILD_SHARES_TABLE)
+
+	rows, err := utils.GetDB().Query(query, buildID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_SHARES_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []models.BuildShare{}
+	for rows.Next() {
+		share, err := scanBuildShare(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILD_SHARES_DB_ERROR, err, buildID)
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_SHARES_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	return shares, nil
+}
+
+// RevokeBuildShare revokes a share link of a build, returning sql.ErrNoRows when the build has no such active link
+func RevokeBuildShare(buildID, shareID int64) error {
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = now() WHERE id = $1 AND build_id = $2 AND revoked_at IS NULL", constants.BUILD_SHARES_TABLE)
+
+	result, err := utils.GetDB().Exec(query, shareID, buildID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_REVOKE_BUILD_SHARE_DB_ERROR, err, shareID, buildID)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_REVOKE_BUILD_SHARE_DB_ERROR, err, shareID, buildID)
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetActiveBuildShare returns the unrevoked, unexpired share link with the token hash, or sql.ErrNoRows
+func GetActiveBuildShare(tokenHash string) (models.BuildShare, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())",
+		strings.Join(constants.BUILD_SHARES_SELECT_COLUMNS, ", "), constants.BUILD_SHARES_TABLE)
+
+	share, err := scanBuildShare(utils.GetDB().QueryRow(query, tokenHash))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.Log(constants.REPOSITORY_GET_BUILD_SHARE_DB_ERROR, err)
+	}
+	return share, err
+}
+
+func scanBuildShare(row rowScanner) (models.BuildShare, error) {
+	var share models.BuildShare
+	err := row.Scan(&share.ID, &share.BuildID, &share.Access, &share.CreatedBy, &share.ExpiresAt, &share.RevokedAt, &share.CreatedAt)
+	return share, err
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ListBuildTemplates returns every build template ordered by name
+func ListBuildTemplates() ([]models.BuildTemplate, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY name, id",
+		strings.Join(constants.BUILD_TEMPLATES_SELECT_COLUMNS, ", "), constants.BUILD_TEMPLATES_TABLE)
+
+	rows, err := utils.GetDB().Query(query)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_TEMPLATES_DB_ERROR, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.BuildTemplate{}
+	for rows.Next() {
+		template, err := scanBuildTemplate(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILD_TEMPLATES_DB_ERROR, err)
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_TEMPLATES_DB_ERROR, err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+// GetBuildTemplate returns a build template, or sql.ErrNoRows
+func GetBuildTemplate(templateID int64) (models.BuildTemplate, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1",
+		strings.Join(constants.BUILD_TEMPLATES_SELECT_COLUMNS, ", "), constants.BUILD_TEMPLATES_TABLE)
+
+	template, err := scanBuildTemplate(utils.GetDB().QueryRow(query, templateID))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			utils.Log(constants.REPOSITORY_GET_BUILD_TEMPLATE_DB_ERROR, err, templateID)
+		}
+		return models.BuildTemplate{}, err
+	}
+	return template, nil
+}
+
+// CreateBuildTemplate stores a build template created by createdBy
+func CreateBuildTemplate(input models.BuildTemplateInput, createdBy string) (models.BuildTemplate, error) {
+	slots, err := json.Marshal(input.Slots)
+	if err != nil {
+		return models.BuildTemplate{}, err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (name, description, use_case, resolution, target_budget, currency, slots, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING %s`,
+		constants.BUILD_TEMPLATES_TABLE, strings.Join(constants.BUILD_TEMPLATES_SELECT_COLUMNS, ", "))
+
+	template, err := scanBuildTemplate(utils.GetDB().QueryRow(query, input.Name, input.Description, input.UseCase,
+		input.Resolution, input.TargetBudget, input.Currency, slots, createdBy))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_CREATE_BUILD_TEMPLATE_DB_ERROR, err, input.Name)
+		return models.BuildTemplate{}, err
+	}
+	return template, nil
+}
+
+// UpdateBuildTemplate replaces the definition of a build template, or returns sql.ErrNoRows
+func UpdateBuildTemplate(templateID int64, input models.BuildTemplateInput) (models.BuildTemplate, error) {
+	slots, err := json.Marshal(input.Slots)
+	if err != nil {
+		return models.BuildTemplate{}, err
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET name = $1, description = $2, use_case = $3, resolution = $4, target_budget = $5,
+	currency = $6, slots = $7, updated_at = now() WHERE id = $8 RETURNING %s`,
+		constants.BUILD_TEMPLATES_TABLE, strings.Join(constants.BUILD_TEMPLATES_SELECT_COLUMNS, ", "))
+
+	template, err := scanBuildTemplate(utils.GetDB().QueryRow(query, input.Name, input.Description, input.UseCase,
+		input.Resolution, input.TargetBudget, input.Currency, slots, templateID))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			utils.Log(constants.REPOSITORY_UPDATE_BUILD_TEMPLATE_DB_ERROR, err, templateID)
+		}
+		return models.BuildTemplate{}, err
+	}
+	return template, nil
+}
+
+// DeleteBuildTemplate removes a build template, or returns sql.ErrNoRows. Builds made from it are kept.
+func DeleteBuildTemplate(templateID int64) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", constants.BUILD_TEMPLATES_TABLE)
+
+	result, err := utils.GetDB().Exec(query, templateID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_TEMPLATE_DB_ERROR, err, templateID)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_DELETE_BUILD_TEMPLATE_DB_ERROR, err, templateID)
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanBuildTemplate(row rowScanner) (models.BuildTemplate, error) {
+	var template models.BuildTemplate
+	var slots []byte
+	err := row.Scan(&template.ID, &template.Name, &template.Description, &template.UseCase, &template.Resolution,
+		&template.TargetBudget, &template.Currency, &slots, &template.CreatedBy, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return models.BuildTemplate{}, err
+	}
+	if err := json.Unmarshal(slots, &template.Slots); err != nil {
+		return models.BuildTemplate{}, err
+	}
+	return template, nil
+}
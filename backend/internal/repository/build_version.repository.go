@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildSnapshotter prices the parts of a build as the transaction changing them sees them and returns the version to
+// record and whether the build has every required part. The version's total and the completeness are stored on the build.
+type BuildSnapshotter func(build models.UserBuild, parts []models.BuildComponentWithDetails) (version models.BuildVersion, complete bool, err error)
+
+// recordBuildVersion stores the total and completeness of a build and records its next version in tx, so they commit
+// or fail together with the change they describe. Callers have already written the build row in tx; the row lock this holds gives
+// concurrent changes consecutive version numbers.
+func recordBuildVersion(tx *sql.Tx, buildID int64, snapshot BuildSnapshotter) (models.BuildVersion, bool, error) {
+	selectBuild := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", strings.Join(constants.USER_BUILDS_SELECT_COLUMNS, ", "), constants.USER_BUILDS_TABLE)
+	setTotals := fmt.Sprintf("UPDATE %s SET total_price = $1, is_complete = $2 WHERE id = $3", constants.USER_BUILDS_TABLE)
+	insert := fmt.Sprintf(`INSERT INTO %[1]s (build_id, version, parts, total_price, currency, reason, created_by)
+	SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5, $6 FROM %[1]s WHERE build_id = $1
+	RETURNING %[2]s`, constants.BUILD_VERSIONS_TABLE, strings.Join(constants.BUILD_VERSIONS_SELECT_COLUMNS, ", "))
+
+	var complete bool
+	created, err := func() (models.BuildVersion, error) {
+		build, err := scanUserBuild(tx.QueryRow(selectBuild, buildID))
+		if err != nil {
+			return models.BuildVersion{}, err
+		}
+		parts, err := queryBuildParts(tx, buildID)
+		if err != nil {
+			return models.BuildVersion{}, err
+		}
+
+		var version models.BuildVersion
+		version, complete, err = snapshot(build, parts)
+		if err != nil {
+			return models.BuildVersion{}, err
+		}
+		encoded, err := json.Marshal(version.Parts)
+		if err != nil {
+			return models.BuildVersion{}, err
+		}
+
+		if _, err := tx.Exec(setTotals, version.TotalPrice, complete, buildID); err != nil {
+			return models.BuildVersion{}, err
+		}
+		return scanBuildVersion(tx.QueryRow(insert, buildID, encoded, version.TotalPrice, version.Currency, version.Reason, version.CreatedBy))
+	}()
+	if err != nil {
+		utils.Log(constants.REPOSITORY_CREATE_BUILD_VERSION_DB_ERROR, err, buildID)
+		return models.BuildVersion{}, false, err
+	}
+	return created, complete, nil
+}
+
+// ListBuildVersions returns the versions of a build, newest first
+func ListBuildVersions(buildID int64, page string) ([]models.BuildVersion, error) {
+	query, err := utils.GenerateSelectQuery(models.GenerateSelectQueryInput{
+		Table:       constants.BUILD_VERSIONS_TABLE,
+		Columns:     constants.BUILD_VERSIONS_SELECT_COLUMNS,
+		WhereClause: "build_id = $1",
+		OrderBy:     "version DESC",
+		Page:        page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := utils.GetDB().Query(query, buildID)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_VERSIONS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []models.BuildVersion{}
+	for rows.Next() {
+		version, err := scanBuildVersion(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_BUILD_VERSIONS_DB_ERROR, err, buildID)
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_BUILD_VERSIONS_DB_ERROR, err, buildID)
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetBuildVersion returns one version of a build, or sql.ErrNoRows
+func GetBuildVersion(buildID int64, version int) (models.BuildVersion, error) {
+	query, err := utils.GenerateSelectQuery(models.GenerateSelectQueryInput{
+		Table:       constants.BUILD_VERSIONS_TABLE,
+		Columns:     constants.BUILD_VERSIONS_SELECT_COLUMNS,
+		WhereClause: "build_id = $1 AND version = $2",
+	})
+	if err != nil {
+		return models.BuildVersion{}, err
+	}
+
+	found, err := scanBuildVersion(utils.GetDB().QueryRow(query, buildID, version))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.Log(constants.REPOSITORY_GET_BUILD_VERSION_DB_ERROR, err, version, buildID)
+	}
+	return found, err
+}
+
+// ReplaceBuildParts swaps every part of a build for the given ones. Parts whose component was deleted are skipped
+// and selected prices that no longer belong to the component are cleared. A set revision is checked and the change
+// recorded as in withBuildTransaction.
+func ReplaceBuildParts(buildID int64, parts []models.BuildVersionPart, revision *int, snapshot BuildSnapshotter) error {
+	deleteParts := fmt.Sprintf("DELETE FROM %s WHERE build_id = $1", constants.BUILD_COMPONENTS_TABLE)
+
+	err := withBuildTransaction(buildID, revision, snapshot, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(deleteParts, buildID); err != nil {
+			return err
+		}
+		return insertBuildParts(tx, buildID, parts)
+	})
+	if err != nil {
+		utils.Log(constants.REPOSITORY_REPLACE_BUILD_PARTS_DB_ERROR, err, buildID)
+	}
+	return err
+}
+
+// insertBuildParts adds parts to a build, skipping deleted components and clearing selected prices that no longer
+// belong to the component
+func insertBuildParts(tx *sql.Tx, buildID int64, parts []models.BuildVersionPart) error {
+	insert := fmt.Sprintf(`INSERT INTO %s (build_id, component_id, quantity, selected_price_id, notes)
+	SELECT $1, c.id, $3, (SELECT p.id FROM %s p WHERE p.id = $4 AND p.component_id = c.id), $5
+	FROM %s c WHERE c.id = $2`, constants.BUILD_COMPONENTS_TABLE, constants.PRICES_TABLE, constants.COMPONENTS_TABLE)
+
+	for _, part := range parts {
+		if _, err := tx.Exec(insert, buildID, part.ComponentID, part.Quantity, part.SelectedPriceID, part.Notes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanBuildVersion(row rowScanner) (models.BuildVersion, error) {
+	var version models.BuildVersion
+	var parts []byte
+	err := row.Scan(&version.ID, &version.BuildID, &version.Version, &parts, &version.TotalPrice, &version.Currency,
+		&version.Reason, &version.CreatedBy, &version.CreatedAt)
+	if err != nil {
+		return models.BuildVersion{}, err
+	}
+	if err := json.Unmarshal(parts, &version.Parts); err != nil {
+		return models.BuildVersion{}, err
+	}
+	return version, nil
+}
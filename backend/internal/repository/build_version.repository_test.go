@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSnapshot records every change with a total of 100 USD and marks the build complete
+func stubSnapshot(build models.UserBuild, parts []models.BuildComponentWithDetails) (models.BuildVersion, bool, error) {
+	total := 100.0
+	version := models.BuildVersion{BuildID: build.ID, Parts: []models.BuildVersionPart{}, TotalPrice: &total, Currency: "USD", Reason: "Changed", CreatedBy: "user-1"}
+	for _, part := range parts {
+		version.Parts = append(version.Parts, models.BuildVersionPart{ComponentID: part.ComponentID, Quantity: part.Quantity})
+	}
+	return version, true, nil
+}
+
+// expectBuildVersion expects recordBuildVersion to read the build and its parts, store the total and completeness and
+// record version
+func expectBuildVersion(mock sqlmock.Sqlmock, buildID int64, version int) {
+	mock.ExpectQuery("SELECT (.+) FROM user_builds WHERE id = \\$1").WithArgs(buildID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+			AddRow(buildID, "user-1", "Gaming PC", nil, false, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 2))
+	mock.ExpectQuery("SELECT bc.id, (.+) FROM build_components bc").WithArgs(buildID).
+		WillReturnRows(sqlmock.NewRows([]string{"bc.id"}))
+	mock.ExpectExec("UPDATE user_builds SET total_price = \\$1, is_complete = \\$2 WHERE id = \\$3").WithArgs(sqlmock.AnyArg(), true, buildID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO build_versions (.+) SELECT \\$1, COALESCE\\(MAX\\(version\\), 0\\) \\+ 1").
+		WithArgs(buildID, []byte(`[]`), sqlmock.AnyArg(), "USD", "Changed", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "build_id", "version", "parts", "total_price", "currency", "reason", "created_by", "created_at"}).
+			AddRow(1, buildID, version, []byte(`[]`), 100.0, "USD", "Changed", "user-1", time.Now()))
+}
+
+// TestBuildVersions tests that a change and its version commit together and replacing parts on restore
+func TestBuildVersions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	touchBuild := "UPDATE user_builds SET updated_at = now\\(\\), revision = revision \\+ 1 WHERE id = \\$1"
+
+	t.Run("Records the version in the transaction of the change", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components WHERE build_id = \\$1 AND component_id = \\$2").
+			WithArgs(int64(7), int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(touchBuild).WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectBuildVersion(mock, 7, 3)
+		mock.ExpectCommit()
+
+		assert.NoError(t, DeleteBuildPart(7, 5, nil, stubSnapshot))
+	})
+
+	t.Run("A failed snapshot rolls the change back", func(t *testing.T) {
+		failing := func(models.UserBuild, []models.BuildComponentWithDetails) (models.BuildVersion, bool, error) {
+			return models.BuildVersion{}, false, errors.New("no exchange rates")
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components WHERE build_id = \\$1 AND component_id = \\$2").
+			WithArgs(int64(7), int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(touchBuild).WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT (.+) FROM user_builds WHERE id = \\$1").WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+				AddRow(7, "user-1", "Gaming PC", nil, false, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 2))
+		mock.ExpectQuery("SELECT bc.id, (.+) FROM build_components bc").WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"bc.id"}))
+		mock.ExpectRollback()
+
+		assert.EqualError(t, DeleteBuildPart(7, 5, nil, failing), "no exchange rates")
+	})
+
+	t.Run("Replaces every part", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM build_components WHERE build_id = \\$1").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("INSERT INTO build_components (.+) SELECT \\$1, c.id, \\$3").
+			WithArgs(int64(7), int64(5), 2, nil, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(touchBuild).WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectBuildVersion(mock, 7, 4)
+		mock.ExpectCommit()
+
+		err := ReplaceBuildParts(7, []models.BuildVersionPart{{ComponentID: 5, Quantity: 2}}, nil, stubSnapshot)
+		assert.NoError(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
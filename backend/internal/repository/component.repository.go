@@ -2,7 +2,9 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/lib/pq"
 	"github.com/mateuse/desktop-builder-backend/internal/constants"
 	"github.com/mateuse/desktop-builder-backend/internal/models"
 	"github.com/mateuse/desktop-builder-backend/internal/utils"
@@ -153,3 +155,264 @@ func GetComponentById(input models.GetComponentByIdInput) (models.Component, err
 	utils.Log(constants.REPOSITORY_GET_COMPONENT_BY_ID_SUCCESS, nil, id, page)
 	return component, nil
 }
+
+// GetComponentsBySpecPredicates returns components of a category whose specs satisfy every predicate.
+// Components missing a spec key (or holding a non numeric value for a numeric comparison) are kept
+// so the caller can report them as borderline instead of hiding them.
+func GetComponentsBySpecPredicates(input models.GetComponentsBySpecPredicatesInput) ([]models.Component, error) {
+	category, page := input.Category, input.Page
+	utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_SPECS_START, nil, category, len(input.Predicates))
+
+	args := []interface{}{category}
+	whereClause := "category = $1"
+	if input.Brand != "" {
+		args = append(args, input.Brand)
+		whereClause += fmt.Sprintf(" AND brand = $%d", len(args))
+	}
+
+	predicateClause, predicateArgs, err := buildSpecPredicateClause(input.Predicates, len(args)+1)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_SPECS_QUERY_ERROR, err, category)
+		return nil, err
+	}
+	if predicateClause != "" {
+		whereClause += " AND " + predicateClause
+		args = append(args, predicateArgs...)
+	}
+
+	orderBy := ""
+	if input.OrderBySpec != "" {
+		args = append(args, input.OrderBySpec)
+		direction := "ASC"
+		if input.OrderDescending {
+			direction = "DESC"
+		}
+		orderBy = fmt.Sprintf("substring(specs->>$%d from %s)::numeric %s NULLS LAST, id", len(args), specNumberPattern, direction)
+	}
+
+	queryInput := models.GenerateSelectQueryInput{
+		Table:       constants.COMPONENTS_TABLE,
+		Columns:     constants.COMPONENTS_SELECT_COLUMNS,
+		WhereClause: whereClause,
+		OrderBy:     orderBy,
+		Page:        page,
+		Unpaged:     input.Unpaged,
+	}
+
+	query, err := utils.GenerateSelectQuery(queryInput)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_SPECS_QUERY_ERROR, err, category)
+		return nil, err
+	}
+
+	rows, err := utils.GetDB().Query(query, args...)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_SPECS_DB_ERROR, err, category)
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := []models.Component{}
+	for rows.Next() {
+		var component models.Component
+		err := rows.Scan(&component.ID, &component.Category, &component.Brand, &component.Model, &component.SKU, &component.UPC, &component.Specs, &component.CreatedAt)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_SPECS_SCAN_ERROR, err, category)
+			return nil, err
+		}
+		components = append(components, component)
+	}
+
+	utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_SPECS_SUCCESS, nil, len(components), category)
+	return components, nil
+}
+
+// specNumberPattern extracts the leading number of a spec such as "320 mm"; the group is
+// non-capturing because substring() would otherwise return only the group
+const specNumberPattern = `'^-?[0-9]+(?:\.[0-9]+)?'`
+
+// buildSpecPredicateClause translates spec predicates into a parameterized SQL condition.
+// Placeholders are numbered from startIndex. The condition decides every predicate the way EvaluateSpecPredicate
+// does, keeping only the rows whose spec is missing or cannot be compared, so pages are never cut short afterwards.
+func buildSpecPredicateClause(predicates []models.SpecPredicate, startIndex int) (string, []interface{}, error) {
+	clauses := []string{}
+	args := []interface{}{}
+	next := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", startIndex+len(args)-1)
+	}
+
+	for _, predicate := range predicates {
+		key := next(predicate.SpecKey)
+		// A JSON null counts as missing, as it does in GetSpecValue
+		missing := fmt.Sprintf("coalesce(jsonb_typeof(specs->%s), 'null') = 'null'", key)
+		text := fmt.Sprintf("lower(btrim(specs->>%s))", key)
+
+		switch predicate.Operator {
+		case models.SpecOperatorEquals:
+			value, ok := utils.SpecValueToString(predicate.Value)
+			if !ok {
+				return "", nil, fmt.Errorf("invalid value for %s predicate on %s", predicate.Operator, predicate.SpecKey)
+			}
+			clauses = append(clauses, fmt.Sprintf("(%s OR %s = lower(%s))", missing, text, next(value)))
+		case models.SpecOperatorContains:
+			value, ok := utils.SpecValueToString(predicate.Value)
+			if !ok {
+				return "", nil, fmt.Errorf("invalid value for %s predicate on %s", predicate.Operator, predicate.SpecKey)
+			}
+			// Objects cannot be read as a list, so they are left for the caller to mark as unverified
+			clauses = append(clauses, fmt.Sprintf(
+				"(%s OR jsonb_typeof(specs->%s) = 'object' OR EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(specs->%s) = 'array' THEN specs->%s ELSE jsonb_build_array(specs->%s) END) AS e(v) WHERE lower(btrim(e.v)) = lower(%s)))",
+				missing, key, key, key, key, next(value)))
+		case models.SpecOperatorIn:
+			values, ok := utils.SpecValueToStringList(predicate.Value)
+			if !ok {
+				return "", nil, fmt.Errorf("invalid value for %s predicate on %s", predicate.Operator, predicate.SpecKey)
+			}
+			lowered := make([]string, len(values))
+			for i, v := range values {
+				lowered[i] = strings.ToLower(v)
+			}
+			clauses = append(clauses, fmt.Sprintf("(%s OR %s = ANY(%s::text[]))", missing, text, next(pq.Array(lowered))))
+		case models.SpecOperatorLTE, models.SpecOperatorGTE:
+			value, ok := utils.SpecValueToNumber(predicate.Value)
+			if !ok {
+				return "", nil, fmt.Errorf("invalid value for %s predicate on %s", predicate.Operator, predicate.SpecKey)
+			}
+			comparison := "<="
+			if predicate.Operator == models.SpecOperatorGTE {
+				comparison = ">="
+			}
+			number := fmt.Sprintf("substring(btrim(specs->>%s) from %s)", key, specNumberPattern)
+			clauses = append(clauses, fmt.Sprintf("(%s OR %s IS NULL OR %s::numeric %s %s)", missing, number, number, comparison, next(value)))
+		default:
+			return "", nil, fmt.Errorf("unsupported spec operator: %s", predicate.Operator)
+		}
+	}
+
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// FindComponentCandidates returns components whose brand and model contain any of the terms, ignoring case
+// and punctuation, those containing the most terms first. An empty category searches every category.
+func FindComponentCandidates(category string, terms []string, limit int) ([]models.Component, error) {
+	components := []models.Component{}
+	if len(terms) == 0 {
+		return components, nil
+	}
+
+	patterns := make([]string, len(terms))
+	for i, term := range terms {
+		patterns[i] = "%" + term + "%"
+	}
+
+	const compact = "regexp_replace(lower(brand || model), '[^a-z0-9]', '', 'g')"
+	query := fmt.Sprintf(`SELECT %s FROM %s
+	WHERE ($1 = '' OR category::text = $1) AND %s LIKE ANY($2)
+	ORDER BY (SELECT count(*) FROM unnest($2::text[]) AS pattern WHERE %s LIKE pattern) DESC, id
+	LIMIT $3`, strings.Join(constants.COMPONENTS_SELECT_COLUMNS, ", "), constants.COMPONENTS_TABLE, compact, compact)
+
+	rows, err := utils.GetDB().Query(query, category, pq.Array(patterns), limit)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_FIND_COMPONENT_CANDIDATES_DB_ERROR, err, strings.Join(terms, " "))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var component models.Component
+		err := rows.Scan(&component.ID, &component.Category, &component.Brand, &component.Model, &component.SKU, &component.UPC, &component.Specs, &component.CreatedAt)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_FIND_COMPONENT_CANDIDATES_DB_ERROR, err, strings.Join(terms, " "))
+			return nil, err
+		}
+		components = append(components, component)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_FIND_COMPONENT_CANDIDATES_DB_ERROR, err, strings.Join(terms, " "))
+		return nil, err
+	}
+	return components, nil
+}
+
+// ListInStockComponents returns the components of the categories with an in-stock price in the region,
+// together with those prices
+func ListInStockComponents(categories []models.Category, region string) ([]models.Component, []models.Price, error) {
+	names := make([]string, len(categories))
+	for i, category := range categories {
+		names[i] = string(category)
+	}
+
+	columns := make([]string, 0, len(constants.COMPONENTS_SELECT_COLUMNS)+len(constants.PRICES_SELECT_COLUMNS))
+	for _, column := range constants.COMPONENTS_SELECT_COLUMNS {
+		columns = append(columns, "c."+column)
+	}
+	for _, column := range constants.PRICES_SELECT_COLUMNS {
+		columns = append(columns, "p."+column)
+	}
+	query := fmt.Sprintf(`SELECT %s FROM %s c JOIN %s p ON p.component_id = c.id
+	WHERE c.category::text = ANY($1) AND p.region = $2 AND p.in_stock = true
+	ORDER BY c.id, p.price`, strings.Join(columns, ", "), constants.COMPONENTS_TABLE, constants.PRICES_TABLE)
+
+	rows, err := utils.GetDB().Query(query, pq.Array(names), region)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_LIST_IN_STOCK_COMPONENTS_DB_ERROR, err, region)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	components := []models.Component{}
+	prices := []models.Price{}
+	for rows.Next() {
+		var component models.Component
+		var price models.Price
+		err := rows.Scan(&component.ID, &component.Category, &component.Brand, &component.Model, &component.SKU, &component.UPC, &component.Specs, &component.CreatedAt,
+			&price.ID, &price.ComponentID, &price.RetailerID, &price.Region, &price.Currency, &price.Price, &price.InStock, &price.ProductURL, &price.LastUpdated, &price.CreatedAt)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_LIST_IN_STOCK_COMPONENTS_DB_ERROR, err, region)
+			return nil, nil, err
+		}
+		// Rows are ordered by component, so each component is added once
+		if len(components) == 0 || components[len(components)-1].ID != component.ID {
+			components = append(components, component)
+		}
+		prices = append(prices, price)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_LIST_IN_STOCK_COMPONENTS_DB_ERROR, err, region)
+		return nil, nil, err
+	}
+	return components, prices, nil
+}
+
+// GetComponentsByIDs returns the components with the ids. Unknown ids are left out.
+func GetComponentsByIDs(ids []int64) ([]models.Component, error) {
+	components := []models.Component{}
+	if len(ids) == 0 {
+		return components, nil
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ANY($1) ORDER BY id",
+		strings.Join(constants.COMPONENTS_SELECT_COLUMNS, ", "), constants.COMPONENTS_TABLE)
+	rows, err := utils.GetDB().Query(query, pq.Array(ids))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_IDS_DB_ERROR, err, len(ids))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var component models.Component
+		err := rows.Scan(&component.ID, &component.Category, &component.Brand, &component.Model, &component.SKU, &component.UPC, &component.Specs, &component.CreatedAt)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_IDS_DB_ERROR, err, len(ids))
+			return nil, err
+		}
+		components = append(components, component)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_GET_COMPONENTS_BY_IDS_DB_ERROR, err, len(ids))
+		return nil, err
+	}
+	return components, nil
+}
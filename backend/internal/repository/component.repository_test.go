@@ -319,6 +319,82 @@ func TestComponentScanningLogic(t *testing.T) {
 	})
 }
 
+// TestBuildSpecPredicateClause tests translation of spec predicates into parameterized SQL
+func TestBuildSpecPredicateClause(t *testing.T) {
+	t.Run("Placeholders start at the given index", func(t *testing.T) {
+		predicates := []models.SpecPredicate{
+			{SpecKey: "socket", Operator: models.SpecOperatorEquals, Value: "AM5"},
+			{SpecKey: "length_mm", Operator: models.SpecOperatorLTE, Value: 340.0},
+		}
+
+		clause, args, err := buildSpecPredicateClause(predicates, 2)
+		assert.NoError(t, err)
+		assert.Contains(t, clause, "coalesce(jsonb_typeof(specs->$2), 'null') = 'null' OR lower(btrim(specs->>$2)) = lower($3)")
+		assert.Contains(t, clause, "substring(btrim(specs->>$4) from")
+		assert.Contains(t, clause, "::numeric <= $5")
+		assert.Equal(t, []interface{}{"socket", "AM5", "length_mm", 340.0}, args)
+	})
+
+	t.Run("Values are never interpolated", func(t *testing.T) {
+		predicates := []models.SpecPredicate{
+			{SpecKey: "socket'; DROP TABLE components; --", Operator: models.SpecOperatorContains, Value: "AM5'"},
+		}
+
+		clause, args, err := buildSpecPredicateClause(predicates, 1)
+		assert.NoError(t, err)
+		assert.NotContains(t, clause, "DROP TABLE")
+		assert.Len(t, args, 2)
+	})
+
+	t.Run("Contains keeps specs that cannot be read as a list", func(t *testing.T) {
+		predicates := []models.SpecPredicate{
+			{SpecKey: "supported_sockets", Operator: models.SpecOperatorContains, Value: "AM5"},
+		}
+
+		clause, args, err := buildSpecPredicateClause(predicates, 1)
+		assert.NoError(t, err)
+		assert.Contains(t, clause, "jsonb_typeof(specs->$1) = 'object'")
+		assert.Contains(t, clause, "lower(btrim(e.v)) = lower($2)")
+		assert.Equal(t, []interface{}{"supported_sockets", "AM5"}, args)
+	})
+
+	t.Run("In operator lowers list values", func(t *testing.T) {
+		predicates := []models.SpecPredicate{
+			{SpecKey: "form_factor", Operator: models.SpecOperatorIn, Value: []interface{}{"ATX", "Micro-ATX"}},
+		}
+
+		clause, args, err := buildSpecPredicateClause(predicates, 1)
+		assert.NoError(t, err)
+		assert.Contains(t, clause, "= ANY($2::text[])")
+		assert.Len(t, args, 2)
+	})
+
+	t.Run("Invalid numeric value", func(t *testing.T) {
+		predicates := []models.SpecPredicate{
+			{SpecKey: "length_mm", Operator: models.SpecOperatorGTE, Value: "long"},
+		}
+
+		_, _, err := buildSpecPredicateClause(predicates, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsupported operator", func(t *testing.T) {
+		predicates := []models.SpecPredicate{
+			{SpecKey: "socket", Operator: models.SpecOperator("like"), Value: "AM5"},
+		}
+
+		_, _, err := buildSpecPredicateClause(predicates, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("No predicates", func(t *testing.T) {
+		clause, args, err := buildSpecPredicateClause(nil, 1)
+		assert.NoError(t, err)
+		assert.Empty(t, clause)
+		assert.Empty(t, args)
+	})
+}
+
 // BenchmarkWhereClauseGeneration benchmarks where clause generation
 func BenchmarkWhereClauseGeneration(b *testing.B) {
 	benchmarks := []struct {
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetExchangeRates returns the amount of each currency one US dollar buys, keyed by upper case currency code
+func GetExchangeRates() (map[string]float64, error) {
+	utils.Log(constants.REPOSITORY_GET_EXCHANGE_RATES_START, nil)
+
+	rows, err := utils.GetDB().Query(fmt.Sprintf("SELECT currency, usd_rate FROM %s", constants.EXCHANGE_RATES_TABLE))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_EXCHANGE_RATES_DB_ERROR, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := map[string]float64{}
+	for rows.Next() {
+		var currency string
+		var rate float64
+		if err := rows.Scan(&currency, &rate); err != nil {
+			utils.Log(constants.REPOSITORY_GET_EXCHANGE_RATES_DB_ERROR, err)
+			return nil, err
+		}
+		rates[strings.ToUpper(currency)] = rate
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_GET_EXCHANGE_RATES_DB_ERROR, err)
+		return nil, err
+	}
+
+	utils.Log(constants.REPOSITORY_GET_EXCHANGE_RATES_SUCCESS, nil, len(rates))
+	return rates, nil
+}
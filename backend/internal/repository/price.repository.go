@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// nullablePrice holds the columns of a LEFT JOINed price, which are all NULL when no price matched
+type nullablePrice struct {
+	ID          sql.NullInt64
+	ComponentID sql.NullInt64
+	RetailerID  sql.NullInt64
+	Region      sql.NullString
+	Currency    sql.NullString
+	Price       sql.NullFloat64
+	InStock     sql.NullBool
+	ProductURL  sql.NullString
+	LastUpdated sql.NullTime
+	CreatedAt   sql.NullTime
+}
+
+func (p nullablePrice) toPrice() *models.Price {
+	if !p.ID.Valid {
+		return nil
+	}
+	price := &models.Price{
+		ID:          p.ID.Int64,
+		ComponentID: p.ComponentID.Int64,
+		RetailerID:  p.RetailerID.Int64,
+		Region:      p.Region.String,
+		Currency:    p.Currency.String,
+		Price:       p.Price.Float64,
+		InStock:     p.InStock.Bool,
+		LastUpdated: p.LastUpdated.Time,
+		CreatedAt:   p.CreatedAt.Time,
+	}
+	if p.ProductURL.Valid {
+		url := p.ProductURL.String
+		price.ProductURL = &url
+	}
+	return price
+}
+
+func GetPriceById(id int64) (models.Price, error) {
+	utils.Log(constants.REPOSITORY_GET_PRICE_BY_ID_START, nil, id)
+
+	query, err := utils.GenerateSelectQuery(models.GenerateSelectQueryInput{
+		Table:       constants.PRICES_TABLE,
+		Columns:     constants.PRICES_SELECT_COLUMNS,
+		WhereClause: "id = $1",
+	})
+	if err != nil {
+		return models.Price{}, err
+	}
+
+	price, err := scanPrice(utils.GetDB().QueryRow(query, id))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_PRICE_BY_ID_SCAN_ERROR, err, id)
+		return models.Price{}, err
+	}
+
+	utils.Log(constants.REPOSITORY_GET_PRICE_BY_ID_SUCCESS, nil, id)
+	return price, nil
+}
+
+// GetInStockPrices returns every in-stock price of the components in a region
+func GetInStockPrices(componentIDs []int64, region string) ([]models.Price, error) {
+	utils.Log(constants.REPOSITORY_GET_IN_STOCK_PRICES_START, nil, len(componentIDs), region)
+
+	prices := []models.Price{}
+	if len(componentIDs) == 0 {
+		return prices, nil
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE component_id = ANY($1) AND region = $2 AND in_stock = true ORDER BY component_id, price",
+		strings.Join(constants.PRICES_SELECT_COLUMNS, ", "), constants.PRICES_TABLE)
+	rows, err := utils.GetDB().Query(query, pq.Array(componentIDs), region)
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_IN_STOCK_PRICES_DB_ERROR, err, region)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		price, err := scanPrice(rows)
+		if err != nil {
+			utils.Log(constants.REPOSITORY_GET_IN_STOCK_PRICES_DB_ERROR, err, region)
+			return nil, err
+		}
+		prices = append(prices, price)
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_GET_IN_STOCK_PRICES_DB_ERROR, err, region)
+		return nil, err
+	}
+
+	utils.Log(constants.REPOSITORY_GET_IN_STOCK_PRICES_SUCCESS, nil, len(prices), region)
+	return prices, nil
+}
+
+func scanPrice(row rowScanner) (models.Price, error) {
+	var price models.Price
+	err := row.Scan(&price.ID, &price.ComponentID, &price.RetailerID, &price.Region, &price.Currency, &price.Price,
+		&price.InStock, &price.ProductURL, &price.LastUpdated, &price.CreatedAt)
+	return price, err
+}
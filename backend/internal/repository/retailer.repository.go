@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetRetailerNames returns the names of the retailers keyed by id. Unknown ids are left out.
+func GetRetailerNames(ids []int64) (map[int64]string, error) {
+	utils.Log(constants.REPOSITORY_GET_RETAILER_NAMES_START, nil, len(ids))
+
+	names := map[int64]string{}
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	query := fmt.Sprintf("SELECT id, name FROM %s WHERE id = ANY($1)", constants.RETAILERS_TABLE)
+	rows, err := utils.GetDB().Query(query, pq.Array(ids))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_RETAILER_NAMES_DB_ERROR, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			utils.Log(constants.REPOSITORY_GET_RETAILER_NAMES_DB_ERROR, err)
+			return nil, err
+		}
+		names[id] = name
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_GET_RETAILER_NAMES_DB_ERROR, err)
+		return nil, err
+	}
+
+	utils.Log(constants.REPOSITORY_GET_RETAILER_NAMES_SUCCESS, nil, len(names))
+	return names, nil
+}
+
+// GetRetailerShipping returns the active retailers among ids with their name and shipping_info, keyed by id
+func GetRetailerShipping(ids []int64) (map[int64]models.Retailer, error) {
+	retailers := map[int64]models.Retailer{}
+	if len(ids) == 0 {
+		return retailers, nil
+	}
+
+	query := fmt.Sprintf("SELECT id, name, shipping_info, is_active FROM %s WHERE id = ANY($1) AND is_active = true", constants.RETAILERS_TABLE)
+	rows, err := utils.GetDB().Query(query, pq.Array(ids))
+	if err != nil {
+		utils.Log(constants.REPOSITORY_GET_RETAILER_SHIPPING_DB_ERROR, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var retailer models.Retailer
+		var shipping []byte
+		if err := rows.Scan(&retailer.ID, &retailer.Name, &shipping, &retailer.IsActive); err != nil {
+			utils.Log(constants.REPOSITORY_GET_RETAILER_SHIPPING_DB_ERROR, err)
+			return nil, err
+		}
+		retailer.ShippingInfo = shipping
+		retailers[retailer.ID] = retailer
+	}
+	if err := rows.Err(); err != nil {
+		utils.Log(constants.REPOSITORY_GET_RETAILER_SHIPPING_DB_ERROR, err)
+		return nil, err
+	}
+	return retailers, nil
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/handlers"
+)
+
+func RegisterBuildRoutes(router *http.ServeMux) {
+	router.HandleFunc("/builds", handlers.BuildsHandler)
+	router.HandleFunc("/builds/import", handlers.ImportBuildHandler)
+	router.HandleFunc("/builds/generate", handlers.GenerateBuildHandler)
+	router.HandleFunc("/builds/public", handlers.GetPublicBuildsHandler)
+	router.HandleFunc("/builds/invitations", handlers.GetBuildInvitationsHandler)
+	router.HandleFunc("/builds/{id}", handlers.BuildHandler)
+	router.HandleFunc("/builds/{id}/components", handlers.BuildComponentsHandler)
+	router.HandleFunc("/builds/{id}/components/{componentId}", handlers.BuildComponentHandler)
+	router.HandleFunc("/builds/{id}/shares", handlers.BuildSharesHandler)
+	router.HandleFunc("/builds/{id}/shares/{shareId}", handlers.BuildShareHandler)
+	router.HandleFunc("/builds/{id}/collaborators", handlers.BuildCollaboratorsHandler)
+	router.HandleFunc("/builds/{id}/collaborators/{userId}", handlers.BuildCollaboratorHandler)
+	router.HandleFunc("/builds/{id}/invitation", handlers.BuildInvitationHandler)
+	router.HandleFunc("/builds/{id}/versions", handlers.GetBuildVersionsHandler)
+	router.HandleFunc("/builds/{id}/versions/{a}/diff/{b}", handlers.DiffBuildVersionsHandler)
+	router.HandleFunc("/builds/{id}/versions/{version}/restore", handlers.RestoreBuildVersionHandler)
+	router.HandleFunc("/builds/{id}/export", handlers.ExportBuildHandler)
+	router.HandleFunc("/builds/{id}/sheet", handlers.GetBuildSheetHandler)
+	router.HandleFunc("/builds/{id}/fork", handlers.ForkBuildHandler)
+	router.HandleFunc("/builds/{id}/forks", handlers.GetBuildForksHandler)
+	router.HandleFunc("/builds/{id}/compatibility", handlers.GetBuildCompatibilityHandler)
+	router.HandleFunc("/builds/{id}/storage", handlers.GetBuildStorageAllocationHandler)
+	router.HandleFunc("/builds/{id}/bottleneck", handlers.GetBuildBottleneckHandler)
+	router.HandleFunc("/builds/{id}/power-connectors", handlers.GetBuildPowerConnectorsHandler)
+	router.HandleFunc("/builds/{id}/cooling", handlers.GetBuildCoolingHandler)
+	router.HandleFunc("/builds/{id}/completeness", handlers.GetBuildCompletenessHandler)
+	router.HandleFunc("/builds/{id}/optimize", handlers.OptimizeBuildPurchaseHandler)
+	router.HandleFunc("/builds/{id}/cheaper-alternatives", handlers.GetCheaperAlternativesHandler)
+	router.HandleFunc("/builds/{id}/upgrades", handlers.GetBuildUpgradesHandler)
+	router.HandleFunc("/builds/{id}/like", handlers.BuildLikeHandler)
+	router.HandleFunc("/builds/{id}/rating", handlers.BuildRatingHandler)
+	router.HandleFunc("/builds/{id}/comments", handlers.BuildCommentsHandler)
+	router.HandleFunc("/builds/{id}/comments/{commentId}", handlers.BuildCommentHandler)
+	// Serves POST /builds/from-template/{id}. ServeMux rejects that literal pattern as conflicting with the
+	// /builds/{id}/... routes above, which in turn take precedence over this one.
+	router.HandleFunc("/builds/{action}/{id}", handlers.CreateBuildFromTemplateHandler)
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegisterBuildRoutes_FromTemplate tests that the /builds/{action}/{id} pattern registered for building from a
+// template only serves from-template and rejects every other two segment path
+func TestRegisterBuildRoutes_FromTemplate(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterBuildRoutes(mux)
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{name: "Unknown action", method: http.MethodPost, path: "/builds/foo/1", expectedStatus: http.StatusNotFound, expectedMessage: constants.PAGE_NOT_FOUND_MESSAGE},
+		{name: "Unknown build subresource", method: http.MethodGet, path: "/builds/1/foo", expectedStatus: http.StatusNotFound, expectedMessage: constants.PAGE_NOT_FOUND_MESSAGE},
+		{name: "GET from template", method: http.MethodGet, path: "/builds/from-template/1", expectedStatus: http.StatusMethodNotAllowed, expectedMessage: constants.METHOD_NOT_ALLOWED_MESSAGE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(constants.USER_ID_HEADER, "user-1")
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response models.ErrorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.expectedMessage, response.Message)
+		})
+	}
+}
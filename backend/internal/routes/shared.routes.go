@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/handlers"
+)
+
+func RegisterSharedRoutes(router *http.ServeMux) {
+	router.HandleFunc("/shared/{token}", handlers.SharedBuildHandler)
+	router.HandleFunc("/shared/{token}/components", handlers.SharedBuildComponentsHandler)
+	router.HandleFunc("/shared/{token}/components/{componentId}", handlers.SharedBuildComponentHandler)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mateuse/desktop-builder-backend/internal/handlers"
+)
+
+func RegisterTemplateRoutes(router *http.ServeMux) {
+	router.HandleFunc("/templates", handlers.BuildTemplatesHandler)
+	router.HandleFunc("/templates/{id}", handlers.BuildTemplateHandler)
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const biosRuleUpdateRequired = "bios-update-required"
+
+var biosRequirementColumns = []string{"motherboard_id", "cpu_id", "min_bios_version"}
+
+// ImportBIOSRequirementsCSV parses a CSV of minimum BIOS versions and stores them, updating existing pairs.
+// Nothing is stored when any line is invalid.
+func ImportBIOSRequirementsCSV(r io.Reader) (int, error) {
+	utils.Log(constants.SERVICE_IMPORT_BIOS_REQUIREMENTS_START, nil)
+
+	requirements, err := ParseBIOSRequirementsCSV(r)
+	if err != nil {
+		utils.Log(constants.SERVICE_IMPORT_BIOS_REQUIREMENTS_ERROR, err)
+		return 0, err
+	}
+
+	if err := repository.UpsertBIOSRequirements(requirements); err != nil {
+		utils.Log(constants.SERVICE_IMPORT_BIOS_REQUIREMENTS_ERROR, err)
+		return 0, err
+	}
+
+	utils.Log(constants.SERVICE_IMPORT_BIOS_REQUIREMENTS_SUCCESS, nil, len(requirements))
+	return len(requirements), nil
+}
+
+// ParseBIOSRequirementsCSV reads rows with a motherboard_id, cpu_id, min_bios_version and optional notes column.
+// Columns are matched by the header, so they can appear in any order. Every invalid line is reported.
+func ParseBIOSRequirementsCSV(r io.Reader) ([]models.BIOSRequirement, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv is empty")
+		}
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range biosRequirementColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header is missing the %s column", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	requirements := []models.BIOSRequirement{}
+	seen := map[[2]int64]int{}
+	problems := []error{}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+
+		var requirement models.BIOSRequirement
+		if requirement.MotherboardID, err = strconv.ParseInt(field(record, "motherboard_id"), 10, 64); err != nil {
+			problems = append(problems, fmt.Errorf("line %d: invalid motherboard_id %q", line, field(record, "motherboard_id")))
+		}
+		if requirement.CPUID, err = strconv.ParseInt(field(record, "cpu_id"), 10, 64); err != nil {
+			problems = append(problems, fmt.Errorf("line %d: invalid cpu_id %q", line, field(record, "cpu_id")))
+		}
+		requirement.MinBIOSVersion = field(record, "min_bios_version")
+		if requirement.MinBIOSVersion == "" {
+			problems = append(problems, fmt.Errorf("line %d: min_bios_version is required", line))
+		}
+		if notes := field(record, "notes"); notes != "" {
+			requirement.Notes = &notes
+		}
+
+		key := [2]int64{requirement.MotherboardID, requirement.CPUID}
+		if other, ok := seen[key]; ok {
+			problems = append(problems, fmt.Errorf("line %d: pair already listed on line %d", line, other))
+		}
+		seen[key] = line
+		requirements = append(requirements, requirement)
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return requirements, nil
+}
+
+// CheckBuildBIOS looks up the BIOS requirement of the CPU and motherboard of a build
+func CheckBuildBIOS(parts []models.BuildComponentWithDetails) ([]models.CompatibilityIssue, error) {
+	issues := []models.CompatibilityIssue{}
+
+	board := findPart(parts, models.CategoryMotherboard)
+	cpu := findPart(parts, models.CategoryCPU)
+	if board == nil || cpu == nil {
+		return issues, nil
+	}
+
+	boardID, err := strconv.ParseInt(board.ID, 10, 64)
+	if err != nil {
+		return issues, nil
+	}
+	cpuID, err := strconv.ParseInt(cpu.ID, 10, 64)
+	if err != nil {
+		return issues, nil
+	}
+
+	requirement, err := repository.GetBIOSRequirement(boardID, cpuID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return issues, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if issue, ok := CheckBIOSRequirement(*board, *cpu, requirement); ok {
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// CheckBIOSRequirement warns when the board may ship with a BIOS older than the CPU needs.
+// No warning is returned when the board's bios_version spec is known to meet the minimum.
+func CheckBIOSRequirement(board, cpu models.Component, requirement models.BIOSRequirement) (models.CompatibilityIssue, bool) {
+	specs := utils.ParseSpecs(board.Specs)
+
+	shipped, hasShipped := utils.GetSpecString(specs, constants.SPEC_BIOS_VERSION)
+	if hasShipped {
+		if cmp, ok := CompareBIOSVersions(shipped, requirement.MinBIOSVersion); ok && cmp >= 0 {
+			return models.CompatibilityIssue{}, false
+		}
+	}
+
+	message := fmt.Sprintf("%s needs BIOS %s or newer to run %s", board.Model, requirement.MinBIOSVersion, cpu.Model)
+	if hasShipped {
+		message += fmt.Sprintf("; boards may ship with BIOS %s", shipped)
+	} else {
+		message += "; boards from older stock may ship with an earlier BIOS"
+	}
+
+	flashback, ok := utils.GetSpecBool(specs, constants.SPEC_BIOS_FLASHBACK)
+	switch {
+	case !ok:
+		message += ". Check whether the board supports BIOS flashback before buying"
+	case flashback:
+		message += ". The board supports BIOS flashback, so it can be updated from a USB drive without a CPU"
+	default:
+		message += ". The board does not support BIOS flashback; updating it needs an older supported CPU or a retailer BIOS update"
+	}
+	if requirement.Notes != nil && *requirement.Notes != "" {
+		message += ". " + *requirement.Notes
+	}
+
+	return models.CompatibilityIssue{
+		RuleID:            biosRuleUpdateRequired,
+		Severity:          models.RuleSeverityWarning,
+		Message:           message,
+		SourceComponentID: cpu.ID,
+		TargetComponentID: board.ID,
+	}, true
+}
+
+// CompareBIOSVersions compares vendor BIOS versions such as "F11", "1.40", "3001" or "7D75v1A".
+// Versions are split into runs of digits and letters, digits compare numerically and letters case-insensitively.
+// ok is false when the versions follow different schemes.
+func CompareBIOSVersions(a, b string) (cmp int, ok bool) {
+	left, right := splitBIOSVersion(a), splitBIOSVersion(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0, false
+	}
+
+	for i := 0; i < len(left) || i < len(right); i++ {
+		if i >= len(left) {
+			return -1, true
+		}
+		if i >= len(right) {
+			return 1, true
+		}
+		l, r := left[i], right[i]
+		ln, lErr := strconv.Atoi(l)
+		rn, rErr := strconv.Atoi(r)
+		switch {
+		case lErr == nil && rErr == nil:
+			if ln != rn {
+				if ln < rn {
+					return -1, true
+				}
+				return 1, true
+			}
+		case lErr != nil && rErr != nil:
+			if c := strings.Compare(strings.ToLower(l), strings.ToLower(r)); c != 0 {
+				return c, true
+			}
+		default:
+			return 0, false
+		}
+	}
+	return 0, true
+}
+
+func splitBIOSVersion(version string) []string {
+	parts := []string{}
+	var current strings.Builder
+	digits := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range strings.TrimSpace(version) {
+		switch {
+		case unicode.IsDigit(r):
+			if !digits {
+				flush()
+			}
+			digits = true
+			current.WriteRune(r)
+		case unicode.IsLetter(r):
+			if digits {
+				flush()
+			}
+			digits = false
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return parts
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompareBIOSVersions tests comparison of vendor BIOS version strings
+func TestCompareBIOSVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected int
+		ok       bool
+	}{
+		{name: "Gigabyte style", a: "F11", b: "F2", expected: 1, ok: true},
+		{name: "Dotted", a: "1.40", b: "1.4", expected: 1, ok: true},
+		{name: "ASUS style", a: "3001", b: "3001", expected: 0, ok: true},
+		{name: "MSI style", a: "7D75v1A", b: "7D75v1C", expected: -1, ok: true},
+		{name: "Longer version is newer", a: "1.40.1", b: "1.40", expected: 1, ok: true},
+		{name: "Different schemes", a: "F11", b: "1.40", ok: false},
+		{name: "Empty", a: "", b: "F11", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmp, ok := CompareBIOSVersions(tt.a, tt.b)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, cmp)
+			}
+		})
+	}
+}
+
+// TestParseBIOSRequirementsCSV tests parsing and validation of BIOS requirement imports
+func TestParseBIOSRequirementsCSV(t *testing.T) {
+	t.Run("Valid rows in any column order", func(t *testing.T) {
+		input := "cpu_id,motherboard_id,min_bios_version,notes\n" +
+			"10,20,F11,Required for Ryzen 5000\n" +
+			"11,20,F13,\n"
+
+		requirements, err := ParseBIOSRequirementsCSV(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, requirements, 2)
+		assert.Equal(t, int64(20), requirements[0].MotherboardID)
+		assert.Equal(t, int64(10), requirements[0].CPUID)
+		assert.Equal(t, "F11", requirements[0].MinBIOSVersion)
+		require.NotNil(t, requirements[0].Notes)
+		assert.Nil(t, requirements[1].Notes)
+	})
+
+	t.Run("Missing column", func(t *testing.T) {
+		_, err := ParseBIOSRequirementsCSV(strings.NewReader("motherboard_id,cpu_id\n1,2\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "min_bios_version")
+	})
+
+	t.Run("Every invalid line is reported", func(t *testing.T) {
+		input := "motherboard_id,cpu_id,min_bios_version\n" +
+			"abc,1,F1\n" +
+			"2,3,\n" +
+			"2,3,F2\n"
+
+		_, err := ParseBIOSRequirementsCSV(strings.NewReader(input))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `line 2: invalid motherboard_id "abc"`)
+		assert.Contains(t, err.Error(), "line 3: min_bios_version is required")
+		assert.Contains(t, err.Error(), "line 4: pair already listed on line 3")
+	})
+
+	t.Run("Empty input", func(t *testing.T) {
+		_, err := ParseBIOSRequirementsCSV(strings.NewReader(""))
+		assert.Error(t, err)
+	})
+}
+
+// TestCheckBIOSRequirement tests the BIOS warning for a motherboard and CPU pair
+func TestCheckBIOSRequirement(t *testing.T) {
+	cpu := models.Component{ID: "10", Category: models.CategoryCPU, Model: "Ryzen 7 5800X3D"}
+	board := func(specs string) models.Component {
+		return models.Component{ID: "20", Category: models.CategoryMotherboard, Model: "B450 Tomahawk", Specs: []byte(specs)}
+	}
+	requirement := models.BIOSRequirement{MotherboardID: 20, CPUID: 10, MinBIOSVersion: "7C02v1C"}
+
+	t.Run("Shipped BIOS is new enough", func(t *testing.T) {
+		_, ok := CheckBIOSRequirement(board(`{"bios_version": "7C02v1D"}`), cpu, requirement)
+		assert.False(t, ok)
+	})
+
+	t.Run("Shipped BIOS too old without flashback", func(t *testing.T) {
+		issue, ok := CheckBIOSRequirement(board(`{"bios_version": "7C02v1A", "bios_flashback": false}`), cpu, requirement)
+		require.True(t, ok)
+		assert.Equal(t, biosRuleUpdateRequired, issue.RuleID)
+		assert.Equal(t, models.RuleSeverityWarning, issue.Severity)
+		assert.Contains(t, issue.Message, "does not support BIOS flashback")
+		assert.Equal(t, "10", issue.SourceComponentID)
+		assert.Equal(t, "20", issue.TargetComponentID)
+	})
+
+	t.Run("Unknown shipped BIOS with flashback", func(t *testing.T) {
+		issue, ok := CheckBIOSRequirement(board(`{"bios_flashback": true}`), cpu, requirement)
+		require.True(t, ok)
+		assert.Contains(t, issue.Message, "supports BIOS flashback")
+	})
+}
+
+// TestCheckBuildBIOS tests the BIOS requirement lookup for a build
+func TestCheckBuildBIOS(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	cpu := storagePart(models.CategoryCPU, "10", "Ryzen 7 5800X3D", `{"socket": "AM4"}`, 1)
+	board := storagePart(models.CategoryMotherboard, "20", "B450 Tomahawk", `{"socket": "AM4"}`, 1)
+	parts := []models.BuildComponentWithDetails{cpu, board}
+	query := "SELECT (.+) FROM bios_requirements WHERE motherboard_id = \\$1 AND cpu_id = \\$2"
+
+	mock.ExpectQuery(query).WithArgs(int64(20), int64(10)).WillReturnError(sql.ErrNoRows)
+	issues, err := CheckBuildBIOS(parts)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	rows := sqlmock.NewRows([]string{"id", "motherboard_id", "cpu_id", "min_bios_version", "notes", "created_at", "updated_at"}).
+		AddRow(1, 20, 10, "7C02v1C", nil, time.Now(), time.Now())
+	mock.ExpectQuery(query).WithArgs(int64(20), int64(10)).WillReturnRows(rows)
+	issues, err = CheckBuildBIOS(parts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{biosRuleUpdateRequired}, issueIDs(issues))
+
+	issues, err = CheckBuildBIOS([]models.BuildComponentWithDetails{cpu})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+var (
+	// ErrBuildForbidden is returned when a user changes a build without a role that allows the change
+	ErrBuildForbidden = errors.New("build belongs to another user")
+	// ErrBuildConflict is returned when a build changed after the revision a change was based on
+	ErrBuildConflict = repository.ErrBuildRevisionConflict
+	// ErrInvalidBuild wraps every validation problem of a build create or update
+	ErrInvalidBuild = errors.New("invalid build")
+	// ErrBuildPartNotFound is returned when a component is not part of a build
+	ErrBuildPartNotFound = errors.New("component is not part of the build")
+)
+
+func ListUserBuilds(filter models.UserBuildFilter, viewerID, page string) ([]models.UserBuild, error) {
+	utils.Log(constants.SERVICE_LIST_BUILDS_START, nil, viewerID)
+
+	builds, err := repository.ListUserBuilds(filter, viewerID, page)
+	if err != nil {
+		utils.Log(constants.SERVICE_LIST_BUILDS_ERROR, err, viewerID)
+		return nil, err
+	}
+
+	utils.Log(constants.SERVICE_LIST_BUILDS_SUCCESS, nil, len(builds), viewerID)
+	return builds, nil
+}
+
+// CreateUserBuild creates a build owned by userID
+func CreateUserBuild(userID string, input models.UserBuildCreate) (models.UserBuild, error) {
+	utils.Log(constants.SERVICE_CREATE_BUILD_START, nil, userID)
+
+	if input.UserID != "" && input.UserID != userID {
+		utils.Log(constants.SERVICE_CREATE_BUILD_ERROR, ErrBuildForbidden, userID)
+		return models.UserBuild{}, ErrBuildForbidden
+	}
+	input.UserID = userID
+
+	if err := normalizeBuildCreate(&input); err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_ERROR, err, userID)
+		return models.UserBuild{}, err
+	}
+
+	build, err := repository.CreateUserBuild(input)
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_ERROR, err, userID)
+		return models.UserBuild{}, err
+	}
+
+	utils.Log(constants.SERVICE_CREATE_BUILD_SUCCESS, nil, build.ID, userID)
+	return build, nil
+}
+
+// GetUserBuild returns a build with its parts. Private builds the viewer does not collaborate on are reported as sql.ErrNoRows.
+func GetUserBuild(buildID int64, viewerID string) (models.UserBuildWithComponents, error) {
+	utils.Log(constants.SERVICE_GET_BUILD_START, nil, buildID, viewerID)
+
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_ERROR, err, buildID, viewerID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	detailed, err := withBuildDetails(build)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_ERROR, err, buildID, viewerID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	utils.Log(constants.SERVICE_GET_BUILD_SUCCESS, nil, buildID, viewerID)
+	return detailed, nil
+}
+
+// withBuildDetails loads the parts of a build, prices them and checks that none are missing
+func withBuildDetails(build models.UserBuild) (models.UserBuildWithComponents, error) {
+	parts, err := repository.GetBuildParts(build.ID)
+	if err != nil {
+		return models.UserBuildWithComponents{}, err
+	}
+
+	// Price at current prices so the response never shows a stale total
+	pricing, err := CalculateBuildPrice(build, parts)
+	if err != nil {
+		return models.UserBuildWithComponents{}, err
+	}
+	build.TotalPrice = pricing.Total
+
+	completeness := EvaluateCompleteness(parts)
+	completeness.BuildID = build.ID
+	build.IsComplete = completeness.Complete
+
+	return models.UserBuildWithComponents{UserBuild: build, Components: parts, Pricing: &pricing, Completeness: &completeness}, nil
+}
+
+// getVisibleBuild returns a build the viewer may see. Private builds the viewer does not collaborate on are reported
+// as sql.ErrNoRows.
+func getVisibleBuild(buildID int64, viewerID string) (models.UserBuild, error) {
+	build, err := repository.GetUserBuildById(buildID)
+	if err != nil {
+		return models.UserBuild{}, err
+	}
+
+	visible, err := canViewBuild(build, viewerID)
+	if err != nil {
+		return models.UserBuild{}, err
+	}
+	if !visible {
+		return models.UserBuild{}, sql.ErrNoRows
+	}
+	return build, nil
+}
+
+// canViewBuild reports whether the viewer may see a build: it is public or they own or collaborate on it
+func canViewBuild(build models.UserBuild, viewerID string) (bool, error) {
+	if build.IsPublic {
+		return true, nil
+	}
+	role, err := getBuildRole(build, viewerID)
+	if err != nil {
+		return false, err
+	}
+	return buildRoleIncludes(role, models.BuildRoleViewer), nil
+}
+
+// UpdateUserBuild applies a partial update to a build userID can edit. Only owners can change its visibility.
+// A set revision must match the build's current revision.
+func UpdateUserBuild(buildID int64, userID string, revision *int, update models.UserBuildUpdate) (models.UserBuild, error) {
+	utils.Log(constants.SERVICE_UPDATE_BUILD_START, nil, buildID, userID)
+
+	if err := normalizeBuildUpdate(&update); err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_ERROR, err, buildID, userID)
+		return models.UserBuild{}, err
+	}
+
+	_, role, err := requireBuildRole(buildID, userID, models.BuildRoleEditor)
+	if err == nil && update.IsPublic != nil && !buildRoleIncludes(role, models.BuildRoleOwner) {
+		err = ErrBuildForbidden
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_ERROR, err, buildID, userID)
+		return models.UserBuild{}, err
+	}
+
+	build, err := updateUserBuild(buildID, update, userID, revision)
+	if err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_ERROR, err, buildID, userID)
+		return models.UserBuild{}, err
+	}
+
+	utils.Log(constants.SERVICE_UPDATE_BUILD_SUCCESS, nil, buildID, userID)
+	return build, nil
+}
+
+// DeleteUserBuild deletes a build owned by userID together with its parts. A set revision must match the build's
+// current revision.
+func DeleteUserBuild(buildID int64, userID string, revision *int) error {
+	utils.Log(constants.SERVICE_DELETE_BUILD_START, nil, buildID, userID)
+
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleOwner); err != nil {
+		utils.Log(constants.SERVICE_DELETE_BUILD_ERROR, err, buildID, userID)
+		return err
+	}
+
+	if err := repository.DeleteUserBuild(buildID, revision); err != nil {
+		utils.Log(constants.SERVICE_DELETE_BUILD_ERROR, err, buildID, userID)
+		return err
+	}
+
+	utils.Log(constants.SERVICE_DELETE_BUILD_SUCCESS, nil, buildID, userID)
+	return nil
+}
+
+// updateUserBuild applies a validated update. A new currency or region reprices the build and records a version in
+// the same transaction.
+func updateUserBuild(buildID int64, update models.UserBuildUpdate, actor string, revision *int) (models.UserBuild, error) {
+	var snapshot repository.BuildSnapshotter
+	if update.Currency != nil || update.Region != nil {
+		snapshot = buildSnapshot(actor, "Changed currency or region")
+	}
+	return repository.UpdateUserBuild(buildID, update, revision, snapshot)
+}
+
+func normalizeBuildCreate(input *models.UserBuildCreate) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidBuild)
+	}
+	if err := normalizeBuildUseCase(input.UseCase); err != nil {
+		return err
+	}
+	return normalizeBuildLocale(input.Currency, input.Region)
+}
+
+func normalizeBuildUpdate(update *models.UserBuildUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return fmt.Errorf("%w: name cannot be empty", ErrInvalidBuild)
+		}
+		update.Name = &name
+	}
+	if err := normalizeBuildUseCase(update.UseCase); err != nil {
+		return err
+	}
+	return normalizeBuildLocale(update.Currency, update.Region)
+}
+
+// normalizeBuildUseCase lower cases the use case tag of a build. An empty tag clears it.
+func normalizeBuildUseCase(useCase *string) error {
+	if useCase == nil {
+		return nil
+	}
+	*useCase = strings.ToLower(strings.TrimSpace(*useCase))
+	if *useCase != "" && !IsValidGeneratorUseCase(*useCase) {
+		return fmt.Errorf("%w: use_case must be one of gaming, workstation, office or sff", ErrInvalidBuild)
+	}
+	return nil
+}
+
+// normalizeBuildLocale upper cases currency codes and checks that currency and region are not blank
+func normalizeBuildLocale(currency, region *string) error {
+	if currency != nil {
+		*currency = strings.ToUpper(strings.TrimSpace(*currency))
+		if len(*currency) != 3 {
+			return fmt.Errorf("%w: currency must be a 3 letter code", ErrInvalidBuild)
+		}
+	}
+	if region != nil {
+		*region = strings.TrimSpace(*region)
+		if *region == "" {
+			return fmt.Errorf("%w: region cannot be empty", ErrInvalidBuild)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// Metrics the tolerance of cheaper alternatives is applied to
+const (
+	AlternativeMetricPerformance = "performance"
+	AlternativeMetricCapacity    = "capacity_gb"
+	AlternativeMetricWattage     = "wattage"
+	AlternativeMetricRatedTDP    = "rated_tdp"
+)
+
+// FindCheaperAlternatives suggests cheaper in-stock replacements for the parts of a build visible to viewerID.
+// tolerance is the percentage a part's metric may drop by; nil uses ALTERNATIVES_DEFAULT_TOLERANCE. useCase picks
+// the performance score of CPUs and video cards and defaults to the build's use case.
+func FindCheaperAlternatives(buildID int64, viewerID string, tolerance *float64, useCase string) (models.BuildCheaperAlternatives, error) {
+	percent := constants.ALTERNATIVES_DEFAULT_TOLERANCE
+	if tolerance != nil {
+		percent = *tolerance
+	}
+	utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_START, nil, buildID, percent)
+
+	if percent < 0 || percent > constants.ALTERNATIVES_MAX_TOLERANCE {
+		err := fmt.Errorf("%w: tolerance must be between 0 and %.0f", ErrInvalidBuild, constants.ALTERNATIVES_MAX_TOLERANCE)
+		utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_ERROR, err, buildID)
+		return models.BuildCheaperAlternatives{}, err
+	}
+
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_ERROR, err, buildID)
+		return models.BuildCheaperAlternatives{}, err
+	}
+	useCase = alternativesUseCase(useCase, build.UseCase)
+	if !IsValidUseCase(useCase) {
+		err := fmt.Errorf("%w: use_case must be gaming, workstation or office", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_ERROR, err, buildID)
+		return models.BuildCheaperAlternatives{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_ERROR, err, buildID)
+		return models.BuildCheaperAlternatives{}, err
+	}
+
+	seen := map[models.Category]bool{}
+	categories := []models.Category{}
+	for _, part := range parts {
+		if part.Component != nil && !seen[part.Component.Category] {
+			seen[part.Component.Category] = true
+			categories = append(categories, part.Component.Category)
+		}
+	}
+	components, prices, err := repository.ListInStockComponents(categories, build.Region)
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_ERROR, err, buildID)
+		return models.BuildCheaperAlternatives{}, err
+	}
+	rates, err := repository.GetExchangeRates()
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_ERROR, err, buildID)
+		return models.BuildCheaperAlternatives{}, err
+	}
+
+	currency := strings.ToUpper(build.Currency)
+	byComponent := map[int64][]models.Price{}
+	for _, price := range prices {
+		byComponent[price.ComponentID] = append(byComponent[price.ComponentID], price)
+	}
+	catalog := priceCatalog(components, byComponent, currency, rates)
+
+	pricing := PriceBuild(build, parts, prices, rates)
+	result := CheaperAlternativesFromCatalog(build, parts, pricing, catalog, GetCompatibilityRules(), useCase, percent)
+
+	utils.Log(constants.SERVICE_FIND_CHEAPER_ALTERNATIVES_SUCCESS, nil, buildID, result.PotentialSavings, result.Currency)
+	return result, nil
+}
+
+// CheaperAlternativesFromCatalog looks through priced catalog parts for replacements of each part of a build that
+// cost less than the part's current price, keep its metric within tolerance percent and, swapped in alone, add no
+// compatibility, storage or power connector error. A swap also has to keep the build powered, cooled and with a
+// display output if it was before.
+func CheaperAlternativesFromCatalog(build models.UserBuild, parts []models.BuildComponentWithDetails, pricing models.BuildPriceBreakdown,
+	catalog []models.GeneratedBuildPart, rules []models.CompatibilityRule, useCase string, tolerance float64) models.BuildCheaperAlternatives {
+	result := models.BuildCheaperAlternatives{
+		BuildID:   build.ID,
+		Currency:  pricing.Currency,
+		Region:    build.Region,
+		UseCase:   useCase,
+		Tolerance: tolerance,
+		Slots:     []models.CheaperAlternativeSlot{},
+	}
+
+	inBuild := map[string]bool{}
+	for _, part := range parts {
+		inBuild[strconv.FormatInt(part.ComponentID, 10)] = true
+	}
+	unitPrices := map[int64]float64{}
+	for _, line := range pricing.Lines {
+		if line.UnitPrice != nil {
+			unitPrices[line.ComponentID] = *line.UnitPrice
+		}
+	}
+	baseline := newBuildSoundness(parts, rules)
+
+	for i, part := range parts {
+		if part.Component == nil {
+			continue
+		}
+		quantity := max(part.Quantity, 1)
+		slot := models.CheaperAlternativeSlot{
+			ComponentID:  part.ComponentID,
+			Category:     part.Component.Category,
+			Model:        part.Component.Model,
+			Quantity:     quantity,
+			Alternatives: []models.CheaperAlternative{},
+		}
+		metric, value, hasValue := alternativeMetric(*part.Component, useCase)
+		slot.Metric = metric
+		if hasValue {
+			slot.Value = &value
+		}
+
+		currentPrice, priced := unitPrices[part.ComponentID]
+		if !priced {
+			slot.Message = "The part has no price to compare with"
+			result.Slots = append(result.Slots, slot)
+			continue
+		}
+		slot.UnitPrice = &currentPrice
+
+		for _, candidate := range catalog {
+			if candidate.Component.Category != part.Component.Category || inBuild[candidate.Component.ID] || candidate.UnitPrice >= currentPrice-0.005 {
+				continue
+			}
+
+			alternative := models.CheaperAlternative{
+				Component:  candidate.Component,
+				PriceID:    candidate.Price.ID,
+				RetailerID: candidate.Price.RetailerID,
+				UnitPrice:  candidate.UnitPrice,
+			}
+			if metric != "" {
+				_, candidateValue, ok := alternativeMetric(candidate.Component, useCase)
+				if hasValue && (!ok || candidateValue < value*(1-tolerance/100)) {
+					continue
+				}
+				if ok {
+					alternative.Value = &candidateValue
+					if hasValue && value > 0 {
+						change := roundTo((candidateValue-value)/value*100, 1)
+						alternative.ValueChange = &change
+					}
+				}
+			}
+
+			swapped := append([]models.BuildComponentWithDetails{}, parts...)
+			swapped[i].Component = &candidate.Component
+			swapped[i].ComponentID, _ = strconv.ParseInt(candidate.Component.ID, 10, 64)
+			if !baseline.keptBy(swapped, candidate.Component.ID, rules) {
+				continue
+			}
+
+			alternative.Savings = roundTo((currentPrice-candidate.UnitPrice)*float64(quantity), 2)
+			alternative.SavingsPercent = roundTo((currentPrice-candidate.UnitPrice)/currentPrice*100, 1)
+			slot.Alternatives = append(slot.Alternatives, alternative)
+		}
+
+		sort.SliceStable(slot.Alternatives, func(a, b int) bool {
+			first, second := slot.Alternatives[a], slot.Alternatives[b]
+			if first.Savings != second.Savings {
+				return first.Savings > second.Savings
+			}
+			if first.Value != nil && second.Value != nil && *first.Value != *second.Value {
+				return *first.Value > *second.Value
+			}
+			return first.Component.ID < second.Component.ID
+		})
+		if len(slot.Alternatives) > constants.ALTERNATIVES_PER_SLOT {
+			slot.Alternatives = slot.Alternatives[:constants.ALTERNATIVES_PER_SLOT]
+		}
+		if len(slot.Alternatives) > 0 {
+			result.PotentialSavings += slot.Alternatives[0].Savings
+		}
+		result.Slots = append(result.Slots, slot)
+	}
+
+	bestSavings := func(slot models.CheaperAlternativeSlot) float64 {
+		if len(slot.Alternatives) == 0 {
+			return 0
+		}
+		return slot.Alternatives[0].Savings
+	}
+	sort.SliceStable(result.Slots, func(a, b int) bool {
+		return bestSavings(result.Slots[a]) > bestSavings(result.Slots[b])
+	})
+	result.PotentialSavings = roundTo(result.PotentialSavings, 2)
+	return result
+}
+
+// buildSoundness records what a build got right before a part is swapped, so a swap is only blamed for what it breaks
+type buildSoundness struct {
+	unattributedErrors int
+	powered            bool
+	cooled             bool
+	hasDisplay         bool
+}
+
+func newBuildSoundness(parts []models.BuildComponentWithDetails, rules []models.CompatibilityRule) buildSoundness {
+	soundness := buildSoundness{powered: isPowered(parts), cooled: isCooled(parts), hasDisplay: hasDisplayOutput(parts)}
+	for _, issue := range buildErrors(parts, rules) {
+		if issue.SourceComponentID == "" && issue.TargetComponentID == "" {
+			soundness.unattributedErrors++
+		}
+	}
+	return soundness
+}
+
+// keptBy checks that a build with componentID swapped in has no error involving it and lost nothing it had
+func (s buildSoundness) keptBy(parts []models.BuildComponentWithDetails, componentID string, rules []models.CompatibilityRule) bool {
+	unattributed := 0
+	for _, issue := range buildErrors(parts, rules) {
+		if issue.SourceComponentID == componentID || issue.TargetComponentID == componentID {
+			return false
+		}
+		if issue.SourceComponentID == "" && issue.TargetComponentID == "" {
+			unattributed++
+		}
+	}
+	if unattributed > s.unattributedErrors {
+		return false
+	}
+	return (!s.powered || isPowered(parts)) && (!s.cooled || isCooled(parts)) && (!s.hasDisplay || hasDisplayOutput(parts))
+}
+
+// isPowered is false when the power supply is rated below the estimated draw with headroom
+func isPowered(parts []models.BuildComponentWithDetails) bool {
+	psu := findPart(parts, models.CategoryPowerSupply)
+	if psu == nil {
+		return true
+	}
+	wattage, ok := utils.GetSpecNumber(utils.ParseSpecs(psu.Specs), constants.SPEC_WATTAGE)
+	if !ok {
+		return true
+	}
+
+	selected := []models.GeneratedBuildPart{}
+	for _, part := range parts {
+		if part.Component != nil {
+			selected = append(selected, models.GeneratedBuildPart{Component: *part.Component})
+		}
+	}
+	draw, known := estimatePowerDraw(selected)
+	return !known || wattage >= draw*constants.GENERATOR_PSU_HEADROOM
+}
+
+// isCooled is false when the air cooler is rated below the CPU's power
+func isCooled(parts []models.BuildComponentWithDetails) bool {
+	cpu, cooler := findPart(parts, models.CategoryCPU), findPart(parts, models.CategoryCPUCooler)
+	return cpu == nil || cooler == nil || coolsCPU(*cooler, *cpu)
+}
+
+func hasDisplayOutput(parts []models.BuildComponentWithDetails) bool {
+	cpu := findPart(parts, models.CategoryCPU)
+	return findPart(parts, models.CategoryVideoCard) != nil || cpu != nil && hasIntegratedGraphics(*cpu)
+}
+
+// alternativeMetric returns the name and value of the figure a part must keep within tolerance: the performance
+// score of CPUs and video cards, the capacity of memory and storage, the wattage of power supplies and the rated
+// TDP of coolers. name is empty for other categories.
+func alternativeMetric(component models.Component, useCase string) (name string, value float64, ok bool) {
+	specs := utils.ParseSpecs(component.Specs)
+	switch component.Category {
+	case models.CategoryCPU, models.CategoryVideoCard:
+		value, ok = PerformanceScore(component, useCase)
+		return AlternativeMetricPerformance, value, ok
+	case models.CategoryMemory, models.CategoryInternalHDD:
+		value, ok = capacityGB(specs)
+		return AlternativeMetricCapacity, value, ok
+	case models.CategoryPowerSupply:
+		value, ok = utils.GetSpecNumber(specs, constants.SPEC_WATTAGE)
+		return AlternativeMetricWattage, value, ok
+	case models.CategoryCPUCooler, models.CategoryWaterCooling:
+		value, ok = utils.GetSpecNumber(specs, constants.SPEC_RATED_TDP, constants.SPEC_TDP)
+		return AlternativeMetricRatedTDP, value, ok
+	}
+	return "", 0, false
+}
+
+// capacityGB reads a capacity such as "32GB", "2TB" or 512 (taken as gigabytes)
+func capacityGB(specs map[string]interface{}) (float64, bool) {
+	raw, ok := utils.GetSpecValue(specs, constants.SPEC_CAPACITY)
+	if !ok {
+		return 0, false
+	}
+	value, ok := utils.SpecValueToNumber(raw)
+	if !ok {
+		return 0, false
+	}
+	if text, isText := raw.(string); isText && strings.HasSuffix(strings.ToUpper(strings.TrimSpace(text)), "TB") {
+		value *= 1000
+	}
+	return value, true
+}
+
+// alternativesUseCase picks the requested use case, else the build's. Small form factor builds are scored as gaming.
+func alternativesUseCase(requested string, buildUseCase *string) string {
+	useCase := strings.ToLower(strings.TrimSpace(requested))
+	if useCase == "" && buildUseCase != nil {
+		useCase = *buildUseCase
+	}
+	switch useCase {
+	case "":
+		return constants.DEFAULT_USE_CASE
+	case constants.USE_CASE_SFF:
+		return constants.USE_CASE_GAMING
+	}
+	return useCase
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheaperAlternativesFromCatalog tests that suggestions are cheaper, within tolerance and keep the build working
+func TestCheaperAlternativesFromCatalog(t *testing.T) {
+	rules := []models.CompatibilityRule{{
+		ID:             "cpu-motherboard-socket",
+		SourceCategory: models.CategoryCPU,
+		TargetCategory: models.CategoryMotherboard,
+		Comparisons:    []models.RuleComparison{{SourceKey: "socket", Operator: models.SpecOperatorEquals, TargetKey: "socket"}},
+		Severity:       models.RuleSeverityError,
+		Message:        "socket mismatch",
+	}}
+	build := models.UserBuild{ID: 1, Currency: "USD", Region: "USA"}
+
+	owned := []models.GeneratedBuildPart{
+		catalogPart(models.CategoryCPU, "1", "Current CPU", `{"socket": "AM5", "tdp": 120, "performance_gaming": 80}`, 300),
+		catalogPart(models.CategoryMotherboard, "20", "AM5 board", `{"socket": "AM5"}`, 150),
+		catalogPart(models.CategoryVideoCard, "10", "Current GPU", `{"tdp": 200, "performance_gaming": 70}`, 500),
+		catalogPart(models.CategoryPowerSupply, "70", "650W", `{"wattage": 650}`, 100),
+		catalogPart(models.CategoryMemory, "30", "32GB kit", `{"capacity": "32GB"}`, 120),
+	}
+	parts := []models.BuildComponentWithDetails{}
+	pricing := models.BuildPriceBreakdown{Currency: "USD"}
+	for i := range owned {
+		id, _ := strconv.ParseInt(owned[i].Component.ID, 10, 64)
+		quantity := 1
+		if owned[i].Component.Category == models.CategoryMemory {
+			quantity = 2
+		}
+		parts = append(parts, models.BuildComponentWithDetails{
+			BuildComponent: models.BuildComponent{BuildID: 1, ComponentID: id, Quantity: quantity},
+			Component:      &owned[i].Component,
+		})
+		unitPrice := owned[i].UnitPrice
+		pricing.Lines = append(pricing.Lines, models.BuildPriceLine{ComponentID: id, Quantity: quantity, UnitPrice: &unitPrice})
+	}
+
+	catalog := []models.GeneratedBuildPart{
+		catalogPart(models.CategoryCPU, "1", "Current CPU elsewhere", `{"socket": "AM5", "tdp": 120, "performance_gaming": 80}`, 280),
+		catalogPart(models.CategoryCPU, "2", "Slightly slower CPU", `{"socket": "AM5", "tdp": 105, "performance_gaming": 75}`, 250),
+		catalogPart(models.CategoryCPU, "3", "Other socket", `{"socket": "AM4", "tdp": 105, "performance_gaming": 79}`, 200),
+		catalogPart(models.CategoryCPU, "4", "Much slower CPU", `{"socket": "AM5", "tdp": 65, "performance_gaming": 60}`, 150),
+		catalogPart(models.CategoryVideoCard, "11", "Similar GPU", `{"tdp": 200, "performance_gaming": 68}`, 420),
+		catalogPart(models.CategoryVideoCard, "12", "Power hungry GPU", `{"tdp": 450, "performance_gaming": 69}`, 450),
+		catalogPart(models.CategoryVideoCard, "13", "Pricier GPU", `{"tdp": 200, "performance_gaming": 90}`, 700),
+		catalogPart(models.CategoryPowerSupply, "71", "550W", `{"wattage": 550}`, 70),
+		catalogPart(models.CategoryPowerSupply, "72", "600W", `{"wattage": 600}`, 80),
+		catalogPart(models.CategoryMemory, "31", "16GB kit", `{"capacity": "16GB"}`, 60),
+		catalogPart(models.CategoryMemory, "32", "Other 32GB kit", `{"capacity": "32 GB"}`, 100),
+	}
+
+	result := CheaperAlternativesFromCatalog(build, parts, pricing, catalog, rules, constants.USE_CASE_GAMING, 10)
+
+	alternativeIDs := func(slot models.CheaperAlternativeSlot) []string {
+		ids := []string{}
+		for _, alternative := range slot.Alternatives {
+			ids = append(ids, alternative.Component.ID)
+		}
+		return ids
+	}
+	require.Len(t, result.Slots, 5)
+	assert.Equal(t, int64(10), result.Slots[0].ComponentID)
+	assert.Equal(t, []string{"11"}, alternativeIDs(result.Slots[0]))
+	assert.Equal(t, 80.0, result.Slots[0].Alternatives[0].Savings)
+
+	assert.Equal(t, int64(1), result.Slots[1].ComponentID)
+	assert.Equal(t, []string{"2"}, alternativeIDs(result.Slots[1]))
+	assert.Equal(t, AlternativeMetricPerformance, result.Slots[1].Metric)
+	require.NotNil(t, result.Slots[1].Alternatives[0].ValueChange)
+	assert.Equal(t, -6.3, *result.Slots[1].Alternatives[0].ValueChange)
+
+	assert.Equal(t, int64(30), result.Slots[2].ComponentID)
+	assert.Equal(t, []string{"32"}, alternativeIDs(result.Slots[2]))
+	assert.Equal(t, 40.0, result.Slots[2].Alternatives[0].Savings)
+
+	assert.Equal(t, int64(70), result.Slots[3].ComponentID)
+	assert.Equal(t, []string{"72"}, alternativeIDs(result.Slots[3]))
+
+	assert.Equal(t, int64(20), result.Slots[4].ComponentID)
+	assert.Empty(t, result.Slots[4].Alternatives)
+	assert.Empty(t, result.Slots[4].Metric)
+	assert.Equal(t, 190.0, result.PotentialSavings)
+}
+
+// TestCapacityGB tests reading memory and storage capacities in gigabytes
+func TestCapacityGB(t *testing.T) {
+	for specs, expected := range map[string]float64{`{"capacity": "2TB"}`: 2000, `{"capacity": "512 GB"}`: 512, `{"capacity": 64}`: 64} {
+		value, ok := capacityGB(utils.ParseSpecs([]byte(specs)))
+		require.True(t, ok, specs)
+		assert.Equal(t, expected, value, specs)
+	}
+
+	_, ok := capacityGB(utils.ParseSpecs([]byte(`{}`)))
+	assert.False(t, ok)
+}
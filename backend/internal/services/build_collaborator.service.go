@@ -0,0 +1,235 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+var (
+	// ErrCollaboratorNotFound is returned when a user is not a collaborator on a build
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	// ErrCollaboratorExists is returned when inviting the creator or a user who is already invited
+	ErrCollaboratorExists = errors.New("user is already a collaborator on the build")
+	// ErrInvitationNotFound is returned when a user has no pending invitation to a build
+	ErrInvitationNotFound = errors.New("invitation not found")
+)
+
+// buildRoleRanks orders the roles so that each one can do everything the roles below it can
+var buildRoleRanks = map[string]int{
+	models.BuildRoleViewer: 1,
+	models.BuildRoleEditor: 2,
+	models.BuildRoleOwner:  3,
+}
+
+// ListBuildCollaborators returns the collaborators and pending invitations of a build the viewer collaborates on.
+// The creator of the build is not listed; they are always an owner.
+func ListBuildCollaborators(buildID int64, viewerID string) ([]models.BuildCollaborator, error) {
+	if _, _, err := requireBuildRole(buildID, viewerID, models.BuildRoleViewer); err != nil {
+		return nil, err
+	}
+	return repository.ListBuildCollaborators(buildID)
+}
+
+// InviteBuildCollaborator invites a user to a build owned by userID. The invitation grants nothing until it is accepted.
+func InviteBuildCollaborator(buildID int64, userID string, input models.BuildCollaboratorInvite) (models.BuildCollaborator, error) {
+	invitee := strings.TrimSpace(input.UserID)
+	role := strings.ToLower(strings.TrimSpace(input.Role))
+	if role == "" {
+		role = models.BuildRoleViewer
+	}
+	utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_START, nil, invitee, buildID, role)
+
+	if invitee == "" {
+		err := fmt.Errorf("%w: user_id is required", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, err, invitee, buildID)
+		return models.BuildCollaborator{}, err
+	}
+	if err := validateBuildRole(role); err != nil {
+		utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, err, invitee, buildID)
+		return models.BuildCollaborator{}, err
+	}
+
+	build, _, err := requireBuildRole(buildID, userID, models.BuildRoleOwner)
+	if err != nil {
+		utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, err, invitee, buildID)
+		return models.BuildCollaborator{}, err
+	}
+	if invitee == build.UserID {
+		utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, ErrCollaboratorExists, invitee, buildID)
+		return models.BuildCollaborator{}, ErrCollaboratorExists
+	}
+
+	existing, err := repository.ListBuildCollaborators(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, err, invitee, buildID)
+		return models.BuildCollaborator{}, err
+	}
+	for _, collaborator := range existing {
+		if collaborator.UserID == invitee {
+			utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, ErrCollaboratorExists, invitee, buildID)
+			return models.BuildCollaborator{}, ErrCollaboratorExists
+		}
+	}
+	if len(existing) >= constants.BUILD_MAX_COLLABORATORS {
+		err := fmt.Errorf("%w: a build can have at most %d collaborators", ErrInvalidBuild, constants.BUILD_MAX_COLLABORATORS)
+		utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, err, invitee, buildID)
+		return models.BuildCollaborator{}, err
+	}
+
+	collaborator, err := repository.CreateBuildCollaborator(buildID, invitee, role, userID)
+	if err != nil {
+		utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_ERROR, err, invitee, buildID)
+		return models.BuildCollaborator{}, err
+	}
+
+	utils.Log(constants.SERVICE_INVITE_BUILD_COLLABORATOR_SUCCESS, nil, invitee, buildID)
+	return collaborator, nil
+}
+
+// UpdateBuildCollaborator changes the role of a collaborator on a build owned by userID
+func UpdateBuildCollaborator(buildID int64, collaboratorID, userID string, update models.BuildCollaboratorUpdate) (models.BuildCollaborator, error) {
+	role := strings.ToLower(strings.TrimSpace(update.Role))
+	if err := validateBuildRole(role); err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COLLABORATOR_ERROR, err, collaboratorID, buildID)
+		return models.BuildCollaborator{}, err
+	}
+
+	build, _, err := requireBuildRole(buildID, userID, models.BuildRoleOwner)
+	if err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COLLABORATOR_ERROR, err, collaboratorID, buildID)
+		return models.BuildCollaborator{}, err
+	}
+	if collaboratorID == build.UserID {
+		err := fmt.Errorf("%w: the creator of a build is always an owner", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COLLABORATOR_ERROR, err, collaboratorID, buildID)
+		return models.BuildCollaborator{}, err
+	}
+
+	collaborator, err := repository.UpdateBuildCollaboratorRole(buildID, collaboratorID, role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.BuildCollaborator{}, ErrCollaboratorNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COLLABORATOR_ERROR, err, collaboratorID, buildID)
+		return models.BuildCollaborator{}, err
+	}
+
+	utils.Log(constants.SERVICE_UPDATE_BUILD_COLLABORATOR_SUCCESS, nil, collaboratorID, buildID, role)
+	return collaborator, nil
+}
+
+// RemoveBuildCollaborator removes a collaborator or pending invitation. Owners can remove anyone but the creator and
+// every collaborator can remove themselves.
+func RemoveBuildCollaborator(buildID int64, collaboratorID, userID string) error {
+	if collaboratorID != userID {
+		if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleOwner); err != nil {
+			utils.Log(constants.SERVICE_REMOVE_BUILD_COLLABORATOR_ERROR, err, collaboratorID, buildID)
+			return err
+		}
+	}
+
+	err := repository.DeleteBuildCollaborator(buildID, collaboratorID, false)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCollaboratorNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_REMOVE_BUILD_COLLABORATOR_ERROR, err, collaboratorID, buildID)
+		return err
+	}
+
+	utils.Log(constants.SERVICE_REMOVE_BUILD_COLLABORATOR_SUCCESS, nil, collaboratorID, buildID)
+	return nil
+}
+
+// ListBuildInvitations returns the pending invitations of userID
+func ListBuildInvitations(userID string) ([]models.BuildInvitation, error) {
+	return repository.ListBuildInvitations(userID)
+}
+
+// AcceptBuildInvitation accepts the pending invitation of userID to a build, granting its role
+func AcceptBuildInvitation(buildID int64, userID string) (models.BuildCollaborator, error) {
+	collaborator, err := repository.AcceptBuildCollaborator(buildID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.BuildCollaborator{}, ErrInvitationNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_ACCEPT_BUILD_INVITATION_ERROR, err, userID, buildID)
+		return models.BuildCollaborator{}, err
+	}
+
+	utils.Log(constants.SERVICE_ACCEPT_BUILD_INVITATION_SUCCESS, nil, userID, buildID)
+	return collaborator, nil
+}
+
+// DeclineBuildInvitation deletes the pending invitation of userID to a build
+func DeclineBuildInvitation(buildID int64, userID string) error {
+	err := repository.DeleteBuildCollaborator(buildID, userID, true)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvitationNotFound
+	}
+	return err
+}
+
+// requireBuildRole returns a build with the role userID holds on it when that role includes required. Users who
+// cannot see the build get sql.ErrNoRows so private builds are not revealed; users who can see it without the role
+// get ErrBuildForbidden.
+func requireBuildRole(buildID int64, userID, required string) (models.UserBuild, string, error) {
+	build, err := repository.GetUserBuildById(buildID)
+	if err != nil {
+		return models.UserBuild{}, "", err
+	}
+	role, err := getBuildRole(build, userID)
+	if err != nil {
+		return models.UserBuild{}, "", err
+	}
+	if !buildRoleIncludes(role, required) {
+		if role == "" && !build.IsPublic {
+			return models.UserBuild{}, "", sql.ErrNoRows
+		}
+		return models.UserBuild{}, "", ErrBuildForbidden
+	}
+	return build, role, nil
+}
+
+// getBuildRole returns the role userID holds on a build: owner for its creator, the role of an accepted invitation
+// for collaborators and an empty role for everyone else
+func getBuildRole(build models.UserBuild, userID string) (string, error) {
+	if userID == "" {
+		return "", nil
+	}
+	if build.UserID == userID {
+		return models.BuildRoleOwner, nil
+	}
+
+	collaborator, err := repository.GetBuildCollaborator(build.ID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if collaborator.AcceptedAt == nil {
+		return "", nil
+	}
+	return collaborator.Role, nil
+}
+
+// buildRoleIncludes reports whether a role grants everything the required role does. An empty role grants nothing.
+func buildRoleIncludes(role, required string) bool {
+	rank, ok := buildRoleRanks[role]
+	return ok && rank >= buildRoleRanks[required]
+}
+
+func validateBuildRole(role string) error {
+	if _, ok := buildRoleRanks[role]; !ok {
+		return fmt.Errorf("%w: role must be %s, %s or %s", ErrInvalidBuild, models.BuildRoleOwner, models.BuildRoleEditor, models.BuildRoleViewer)
+	}
+	return nil
+}
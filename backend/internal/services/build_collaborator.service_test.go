@@ -0,0 +1,332 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildRoleIncludes tests that each role grants the roles below it and nothing grants more than owner
+func TestBuildRoleIncludes(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{role: models.BuildRoleOwner, required: models.BuildRoleOwner, expected: true},
+		{role: models.BuildRoleOwner, required: models.BuildRoleViewer, expected: true},
+		{role: models.BuildRoleEditor, required: models.BuildRoleEditor, expected: true},
+		{role: models.BuildRoleEditor, required: models.BuildRoleOwner, expected: false},
+		{role: models.BuildRoleViewer, required: models.BuildRoleViewer, expected: true},
+		{role: models.BuildRoleViewer, required: models.BuildRoleEditor, expected: false},
+		{role: "", required: models.BuildRoleViewer, expected: false},
+		{role: "admin", required: models.BuildRoleViewer, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+" as "+tt.required, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildRoleIncludes(tt.role, tt.required))
+		})
+	}
+}
+
+// TestValidateBuildRole tests that only the three build roles are accepted
+func TestValidateBuildRole(t *testing.T) {
+	assert.NoError(t, validateBuildRole(models.BuildRoleEditor))
+	assert.ErrorIs(t, validateBuildRole("admin"), ErrInvalidBuild)
+	assert.ErrorIs(t, validateBuildRole(""), ErrInvalidBuild)
+}
+
+// collaboratorMock swaps the database for a sqlmock for the length of a test
+func collaboratorMock(t *testing.T) sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	originalDB := utils.DB
+	utils.DB = db
+	t.Cleanup(func() {
+		utils.DB = originalDB
+		db.Close()
+	})
+	return mock
+}
+
+// expectCollaboratorBuild expects build 1, created by "owner", to be read
+func expectCollaboratorBuild(mock sqlmock.Sqlmock, public bool) {
+	mock.ExpectQuery("SELECT (.+) FROM user_builds WHERE id = \\$1").WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "description", "is_public", "is_complete", "total_price", "currency", "region", "parent_build_id", "fork_count", "use_case", "like_count", "comment_count", "rating_count", "rating_average", "created_at", "updated_at", "revision"}).
+			AddRow(1, "owner", "Gaming PC", nil, public, false, nil, "USD", "USA", nil, 0, nil, 0, 0, 0, nil, time.Now(), time.Now(), 3))
+}
+
+func collaboratorRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "build_id", "user_id", "role", "invited_by", "accepted_at", "created_at", "updated_at"})
+}
+
+func expectCollaborator(mock sqlmock.Sqlmock, userID, role string, accepted bool) {
+	rows := collaboratorRows()
+	if role != "" {
+		var acceptedAt interface{}
+		if accepted {
+			acceptedAt = time.Now()
+		}
+		rows.AddRow(4, 1, userID, role, "owner", acceptedAt, time.Now(), time.Now())
+	}
+	mock.ExpectQuery("SELECT (.+) FROM build_collaborators WHERE build_id = \\$1 AND user_id = \\$2").
+		WithArgs(int64(1), userID).WillReturnRows(rows)
+}
+
+// TestRequireBuildRole tests that users who cannot see a build are told it does not exist and users who can see it
+// without the required role are forbidden
+func TestRequireBuildRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		public   bool
+		userID   string
+		role     string
+		accepted bool
+		required string
+		expected error
+	}{
+		{name: "Owner", userID: "owner", required: models.BuildRoleOwner},
+		{name: "Editor editing", userID: "friend", role: models.BuildRoleEditor, accepted: true, required: models.BuildRoleEditor},
+		{name: "Viewer editing", userID: "friend", role: models.BuildRoleViewer, accepted: true, required: models.BuildRoleEditor, expected: ErrBuildForbidden},
+		{name: "Editor managing collaborators", userID: "friend", role: models.BuildRoleEditor, accepted: true, required: models.BuildRoleOwner, expected: ErrBuildForbidden},
+		{name: "Pending invitation", userID: "friend", role: models.BuildRoleEditor, required: models.BuildRoleViewer, expected: sql.ErrNoRows},
+		{name: "Revoked collaborator on a private build", userID: "friend", required: models.BuildRoleEditor, expected: sql.ErrNoRows},
+		{name: "Revoked collaborator on a public build", public: true, userID: "friend", required: models.BuildRoleEditor, expected: ErrBuildForbidden},
+		{name: "Anonymous on a private build", required: models.BuildRoleViewer, expected: sql.ErrNoRows},
+		{name: "Anonymous on a public build", public: true, required: models.BuildRoleViewer, expected: ErrBuildForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := collaboratorMock(t)
+			expectCollaboratorBuild(mock, tt.public)
+			if tt.userID != "" && tt.userID != "owner" {
+				expectCollaborator(mock, tt.userID, tt.role, tt.accepted)
+			}
+
+			_, _, err := requireBuildRole(1, tt.userID, tt.required)
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestBuildCollaboratorEscalation tests that collaborators cannot invite, change roles or remove others, whatever role
+// they ask for
+func TestBuildCollaboratorEscalation(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		accepted bool
+		call     func() error
+		expected error
+	}{
+		{name: "Editor invites a collaborator", role: models.BuildRoleEditor, accepted: true, expected: ErrBuildForbidden, call: func() error {
+			_, err := InviteBuildCollaborator(1, "friend", models.BuildCollaboratorInvite{UserID: "other", Role: models.BuildRoleViewer})
+			return err
+		}},
+		{name: "Editor invites an owner", role: models.BuildRoleEditor, accepted: true, expected: ErrBuildForbidden, call: func() error {
+			_, err := InviteBuildCollaborator(1, "friend", models.BuildCollaboratorInvite{UserID: "other", Role: models.BuildRoleOwner})
+			return err
+		}},
+		{name: "Editor promotes themselves", role: models.BuildRoleEditor, accepted: true, expected: ErrBuildForbidden, call: func() error {
+			_, err := UpdateBuildCollaborator(1, "friend", "friend", models.BuildCollaboratorUpdate{Role: models.BuildRoleOwner})
+			return err
+		}},
+		{name: "Viewer promotes themselves", role: models.BuildRoleViewer, accepted: true, expected: ErrBuildForbidden, call: func() error {
+			_, err := UpdateBuildCollaborator(1, "friend", "friend", models.BuildCollaboratorUpdate{Role: models.BuildRoleEditor})
+			return err
+		}},
+		{name: "Editor removes another collaborator", role: models.BuildRoleEditor, accepted: true, expected: ErrBuildForbidden, call: func() error {
+			return RemoveBuildCollaborator(1, "other", "friend")
+		}},
+		{name: "Invitee promotes themselves before accepting", role: models.BuildRoleViewer, expected: sql.ErrNoRows, call: func() error {
+			_, err := UpdateBuildCollaborator(1, "friend", "friend", models.BuildCollaboratorUpdate{Role: models.BuildRoleOwner})
+			return err
+		}},
+		{name: "Revoked collaborator invites", expected: sql.ErrNoRows, call: func() error {
+			_, err := InviteBuildCollaborator(1, "friend", models.BuildCollaboratorInvite{UserID: "other", Role: models.BuildRoleViewer})
+			return err
+		}},
+		{name: "Revoked collaborator lists collaborators", expected: sql.ErrNoRows, call: func() error {
+			_, err := ListBuildCollaborators(1, "friend")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := collaboratorMock(t)
+			expectCollaboratorBuild(mock, false)
+			expectCollaborator(mock, "friend", tt.role, tt.accepted)
+
+			assert.ErrorIs(t, tt.call(), tt.expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestBuildCollaboratorLifecycle tests inviting, accepting, declining, changing the role of and removing collaborators
+func TestBuildCollaboratorLifecycle(t *testing.T) {
+	invitation := func(accepted bool) *sqlmock.Rows {
+		var acceptedAt interface{}
+		if accepted {
+			acceptedAt = time.Now()
+		}
+		return collaboratorRows().AddRow(4, 1, "friend", models.BuildRoleEditor, "owner", acceptedAt, time.Now(), time.Now())
+	}
+	listCollaborators := "SELECT (.+) FROM build_collaborators WHERE build_id = \\$1 ORDER BY"
+	deleteCollaborator := "DELETE FROM build_collaborators WHERE build_id = \\$1 AND user_id = \\$2"
+
+	t.Run("Invite", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+		mock.ExpectQuery(listCollaborators).WithArgs(int64(1)).WillReturnRows(collaboratorRows())
+		mock.ExpectQuery("INSERT INTO build_collaborators").WithArgs(int64(1), "friend", models.BuildRoleEditor, "owner").
+			WillReturnRows(invitation(false))
+
+		collaborator, err := InviteBuildCollaborator(1, "owner", models.BuildCollaboratorInvite{UserID: " friend ", Role: "Editor"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "friend", collaborator.UserID)
+		assert.Nil(t, collaborator.AcceptedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Invite a user twice", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+		mock.ExpectQuery(listCollaborators).WithArgs(int64(1)).WillReturnRows(invitation(false))
+
+		_, err := InviteBuildCollaborator(1, "owner", models.BuildCollaboratorInvite{UserID: "friend"})
+
+		assert.ErrorIs(t, err, ErrCollaboratorExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Invite the creator", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+
+		_, err := InviteBuildCollaborator(1, "owner", models.BuildCollaboratorInvite{UserID: "owner"})
+
+		assert.ErrorIs(t, err, ErrCollaboratorExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Invite with an unknown role", func(t *testing.T) {
+		_, err := InviteBuildCollaborator(1, "owner", models.BuildCollaboratorInvite{UserID: "friend", Role: "admin"})
+
+		assert.ErrorIs(t, err, ErrInvalidBuild)
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		mock.ExpectQuery("UPDATE build_collaborators SET accepted_at = now\\(\\)").WithArgs(int64(1), "friend").WillReturnRows(invitation(true))
+
+		collaborator, err := AcceptBuildInvitation(1, "friend")
+
+		require.NoError(t, err)
+		assert.NotNil(t, collaborator.AcceptedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Accept without an invitation", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		mock.ExpectQuery("UPDATE build_collaborators SET accepted_at = now\\(\\)").WithArgs(int64(1), "friend").WillReturnRows(collaboratorRows())
+
+		_, err := AcceptBuildInvitation(1, "friend")
+
+		assert.ErrorIs(t, err, ErrInvitationNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Decline", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		mock.ExpectExec(deleteCollaborator+" AND accepted_at IS NULL").WithArgs(int64(1), "friend").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, DeclineBuildInvitation(1, "friend"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Decline an accepted invitation", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		mock.ExpectExec(deleteCollaborator+" AND accepted_at IS NULL").WithArgs(int64(1), "friend").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, DeclineBuildInvitation(1, "friend"), ErrInvitationNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Change a role", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+		mock.ExpectQuery("UPDATE build_collaborators SET role = \\$1").WithArgs(models.BuildRoleEditor, int64(1), "friend").
+			WillReturnRows(invitation(true))
+
+		collaborator, err := UpdateBuildCollaborator(1, "friend", "owner", models.BuildCollaboratorUpdate{Role: " editor "})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.BuildRoleEditor, collaborator.Role)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Change the role of the creator", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+
+		_, err := UpdateBuildCollaborator(1, "owner", "owner", models.BuildCollaboratorUpdate{Role: models.BuildRoleViewer})
+
+		assert.ErrorIs(t, err, ErrInvalidBuild)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Change the role of a user who is not a collaborator", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+		mock.ExpectQuery("UPDATE build_collaborators SET role = \\$1").WithArgs(models.BuildRoleViewer, int64(1), "stranger").
+			WillReturnRows(collaboratorRows())
+
+		_, err := UpdateBuildCollaborator(1, "stranger", "owner", models.BuildCollaboratorUpdate{Role: models.BuildRoleViewer})
+
+		assert.ErrorIs(t, err, ErrCollaboratorNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Remove a collaborator", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+		mock.ExpectExec(deleteCollaborator).WithArgs(int64(1), "friend").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, RemoveBuildCollaborator(1, "friend", "owner"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Leave a build", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		mock.ExpectExec(deleteCollaborator).WithArgs(int64(1), "friend").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, RemoveBuildCollaborator(1, "friend", "friend"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Remove a user who is not a collaborator", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+		mock.ExpectExec(deleteCollaborator).WithArgs(int64(1), "stranger").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, RemoveBuildCollaborator(1, "stranger", "owner"), ErrCollaboratorNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
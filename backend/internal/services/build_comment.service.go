@@ -0,0 +1,218 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+var (
+	// ErrCommentNotFound is returned when a comment does not exist on the build or has been deleted
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrCommentForbidden is returned when a user changes a comment they may not
+	ErrCommentForbidden = errors.New("comment belongs to another user")
+)
+
+// ListBuildComments returns the comments of a build visible to viewerID as threads, oldest first
+func ListBuildComments(buildID int64, viewerID string) ([]models.BuildComment, error) {
+	utils.Log(constants.SERVICE_LIST_BUILD_COMMENTS_START, nil, buildID, viewerID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_LIST_BUILD_COMMENTS_ERROR, err, buildID, viewerID)
+		return nil, err
+	}
+
+	comments, err := repository.ListBuildComments(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_LIST_BUILD_COMMENTS_ERROR, err, buildID, viewerID)
+		return nil, err
+	}
+
+	utils.Log(constants.SERVICE_LIST_BUILD_COMMENTS_SUCCESS, nil, len(comments), buildID)
+	return ThreadBuildComments(comments), nil
+}
+
+// ThreadBuildComments nests replies below their parent comment. Deleted comments lose their body and are
+// dropped unless they still have replies.
+func ThreadBuildComments(comments []models.BuildComment) []models.BuildComment {
+	children := map[int64][]models.BuildComment{}
+	roots := []models.BuildComment{}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		}
+	}
+
+	var thread func([]models.BuildComment) []models.BuildComment
+	thread = func(level []models.BuildComment) []models.BuildComment {
+		threaded := []models.BuildComment{}
+		for _, comment := range level {
+			comment.Replies = thread(children[comment.ID])
+			if comment.DeletedAt != nil {
+				if len(comment.Replies) == 0 {
+					continue
+				}
+				comment.Body = ""
+			}
+			threaded = append(threaded, comment)
+		}
+		return threaded
+	}
+	return thread(roots)
+}
+
+// CreateBuildComment comments on a build visible to userID, or replies to one of its comments
+func CreateBuildComment(buildID int64, userID string, input models.BuildCommentCreate) (models.BuildComment, error) {
+	utils.Log(constants.SERVICE_CREATE_BUILD_COMMENT_START, nil, buildID, userID)
+
+	body, err := normalizeCommentBody(input.Body)
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_COMMENT_ERROR, err, buildID, userID)
+		return models.BuildComment{}, err
+	}
+	input.Body = body
+
+	if _, err := getVisibleBuild(buildID, userID); err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_COMMENT_ERROR, err, buildID, userID)
+		return models.BuildComment{}, err
+	}
+	if input.ParentID != nil {
+		if err := checkCommentParent(buildID, *input.ParentID); err != nil {
+			utils.Log(constants.SERVICE_CREATE_BUILD_COMMENT_ERROR, err, buildID, userID)
+			return models.BuildComment{}, err
+		}
+	}
+
+	comment, err := repository.CreateBuildComment(buildID, userID, input)
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_COMMENT_ERROR, err, buildID, userID)
+		return models.BuildComment{}, err
+	}
+	comment.Replies = []models.BuildComment{}
+
+	utils.Log(constants.SERVICE_CREATE_BUILD_COMMENT_SUCCESS, nil, comment.ID, buildID)
+	return comment, nil
+}
+
+// UpdateBuildComment edits a comment written by userID
+func UpdateBuildComment(buildID, commentID int64, userID string, input models.BuildCommentUpdate) (models.BuildComment, error) {
+	utils.Log(constants.SERVICE_UPDATE_BUILD_COMMENT_START, nil, commentID, userID)
+
+	body, err := normalizeCommentBody(input.Body)
+	if err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COMMENT_ERROR, err, commentID, userID)
+		return models.BuildComment{}, err
+	}
+
+	comment, _, err := getVisibleComment(buildID, commentID, userID)
+	if err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COMMENT_ERROR, err, commentID, userID)
+		return models.BuildComment{}, err
+	}
+	if comment.UserID != userID {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COMMENT_ERROR, ErrCommentForbidden, commentID, userID)
+		return models.BuildComment{}, ErrCommentForbidden
+	}
+
+	updated, err := repository.UpdateBuildCommentBody(commentID, body)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = ErrCommentNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_COMMENT_ERROR, err, commentID, userID)
+		return models.BuildComment{}, err
+	}
+	updated.Replies = []models.BuildComment{}
+
+	utils.Log(constants.SERVICE_UPDATE_BUILD_COMMENT_SUCCESS, nil, commentID, userID)
+	return updated, nil
+}
+
+// DeleteBuildComment soft deletes a comment. Authors can delete their comments, build owners can delete any
+// comment on their build and admins listed in ADMIN_USER_IDS can delete any comment on a build they can see.
+func DeleteBuildComment(buildID, commentID int64, userID string) error {
+	utils.Log(constants.SERVICE_DELETE_BUILD_COMMENT_START, nil, commentID, userID)
+
+	comment, build, err := getVisibleComment(buildID, commentID, userID)
+	if err != nil {
+		utils.Log(constants.SERVICE_DELETE_BUILD_COMMENT_ERROR, err, commentID, userID)
+		return err
+	}
+	if comment.UserID != userID && build.UserID != userID && !IsAdmin(userID) {
+		utils.Log(constants.SERVICE_DELETE_BUILD_COMMENT_ERROR, ErrCommentForbidden, commentID, userID)
+		return ErrCommentForbidden
+	}
+
+	err = repository.SoftDeleteBuildComment(buildID, commentID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = ErrCommentNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_DELETE_BUILD_COMMENT_ERROR, err, commentID, userID)
+		return err
+	}
+
+	utils.Log(constants.SERVICE_DELETE_BUILD_COMMENT_SUCCESS, nil, commentID, userID)
+	return nil
+}
+
+// getVisibleComment returns a comment that is not deleted on a build visible to viewerID, and the build
+func getVisibleComment(buildID, commentID int64, viewerID string) (models.BuildComment, models.UserBuild, error) {
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		return models.BuildComment{}, models.UserBuild{}, err
+	}
+
+	comment, err := repository.GetBuildComment(buildID, commentID)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && comment.DeletedAt != nil {
+		return models.BuildComment{}, models.UserBuild{}, ErrCommentNotFound
+	}
+	if err != nil {
+		return models.BuildComment{}, models.UserBuild{}, err
+	}
+	return comment, build, nil
+}
+
+// checkCommentParent checks that a reply goes to a comment of the same build that is not deleted and
+// not nested too deep
+func checkCommentParent(buildID, parentID int64) error {
+	depth := 0
+	for id := &parentID; id != nil; depth++ {
+		if depth == constants.COMMENT_MAX_DEPTH {
+			return fmt.Errorf("%w: replies can be nested at most %d levels deep", ErrInvalidBuild, constants.COMMENT_MAX_DEPTH)
+		}
+
+		comment, err := repository.GetBuildComment(buildID, *id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if depth == 0 && comment.DeletedAt != nil {
+			return fmt.Errorf("%w: cannot reply to a deleted comment", ErrInvalidBuild)
+		}
+		id = comment.ParentID
+	}
+	return nil
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: comment body is required", ErrInvalidBuild)
+	}
+	if utf8.RuneCountInString(body) > constants.COMMENT_MAX_LENGTH {
+		return "", fmt.Errorf("%w: comments are limited to %d characters", ErrInvalidBuild, constants.COMMENT_MAX_LENGTH)
+	}
+	return body, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestThreadBuildComments tests nesting of replies and hiding of deleted comments
+func TestThreadBuildComments(t *testing.T) {
+	deletedAt := time.Now()
+	parent := func(id int64) *int64 { return &id }
+	comments := []models.BuildComment{
+		{ID: 1, Body: "Great build"},
+		{ID: 2, Body: "Removed", DeletedAt: &deletedAt},
+		{ID: 3, ParentID: parent(1), Body: "Thanks"},
+		{ID: 4, ParentID: parent(2), Body: "Reply to removed"},
+		{ID: 5, Body: "Removed without replies", DeletedAt: &deletedAt},
+		{ID: 6, ParentID: parent(3), Body: "Removed leaf", DeletedAt: &deletedAt},
+	}
+
+	threads := ThreadBuildComments(comments)
+
+	require.Len(t, threads, 2)
+	assert.Equal(t, int64(1), threads[0].ID)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, int64(3), threads[0].Replies[0].ID)
+	assert.Empty(t, threads[0].Replies[0].Replies)
+
+	assert.Equal(t, int64(2), threads[1].ID)
+	assert.Empty(t, threads[1].Body)
+	require.Len(t, threads[1].Replies, 1)
+	assert.Equal(t, "Reply to removed", threads[1].Replies[0].Body)
+}
+
+// TestNormalizeCommentBody tests trimming and length limits of comment bodies
+func TestNormalizeCommentBody(t *testing.T) {
+	body, err := normalizeCommentBody("  Nice cable management \n")
+	require.NoError(t, err)
+	assert.Equal(t, "Nice cable management", body)
+
+	_, err = normalizeCommentBody(" \t ")
+	assert.True(t, errors.Is(err, ErrInvalidBuild))
+
+	_, err = normalizeCommentBody(strings.Repeat("é", constants.COMMENT_MAX_LENGTH))
+	assert.NoError(t, err)
+	_, err = normalizeCommentBody(strings.Repeat("a", constants.COMMENT_MAX_LENGTH+1))
+	assert.True(t, errors.Is(err, ErrInvalidBuild))
+}
+
+// TestDeleteBuildComment tests who may moderate comments on a build
+func TestDeleteBuildComment(t *testing.T) {
+	t.Setenv("ADMIN_USER_IDS", "moderator")
+	expectComment := func(mock sqlmock.Sqlmock) {
+		expectCollaboratorBuild(mock, true)
+		mock.ExpectQuery("SELECT (.+) FROM build_comments WHERE id = \\$1 AND build_id = \\$2").WithArgs(int64(7), int64(1)).
+			WillReturnRows(sqlmock.NewRows(constants.BUILD_COMMENTS_SELECT_COLUMNS).
+				AddRow(7, 1, nil, "author", "Nice build", time.Now(), nil, nil, nil))
+	}
+
+	for _, userID := range []string{"author", "owner", "moderator"} {
+		t.Run(userID, func(t *testing.T) {
+			mock := collaboratorMock(t)
+			expectComment(mock)
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT id FROM user_builds WHERE id = \\$1 FOR UPDATE").WithArgs(int64(1)).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			mock.ExpectExec("UPDATE build_comments SET deleted_at = now\\(\\), deleted_by = \\$1").
+				WithArgs(userID, int64(7), int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("UPDATE user_builds SET comment_count").WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			require.NoError(t, DeleteBuildComment(1, 7, userID))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("Other users", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectComment(mock)
+
+		assert.True(t, errors.Is(DeleteBuildComment(1, 7, "stranger"), ErrCommentForbidden))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
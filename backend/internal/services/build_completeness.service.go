@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetBuildCompleteness returns the completeness checklist of a build visible to viewerID
+func GetBuildCompleteness(buildID int64, viewerID string) (models.BuildCompleteness, error) {
+	utils.Log(constants.SERVICE_GET_BUILD_COMPLETENESS_START, nil, buildID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_COMPLETENESS_ERROR, err, buildID)
+		return models.BuildCompleteness{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_COMPLETENESS_ERROR, err, buildID)
+		return models.BuildCompleteness{}, err
+	}
+
+	completeness := EvaluateCompleteness(parts)
+	completeness.BuildID = buildID
+
+	utils.Log(constants.SERVICE_GET_BUILD_COMPLETENESS_SUCCESS, nil, buildID, len(completeness.Missing))
+	return completeness, nil
+}
+
+// EvaluateCompleteness checks that a build has a CPU, motherboard, memory, storage, power supply and case,
+// a video card unless the CPU has integrated graphics, and a cooler unless the CPU ships with one.
+// An operating system is listed but never required.
+func EvaluateCompleteness(parts []models.BuildComponentWithDetails) models.BuildCompleteness {
+	completeness := models.BuildCompleteness{Missing: []models.Category{}, Checklist: []models.CompletenessItem{}}
+
+	check := func(category models.Category, required bool, reason string, present bool) {
+		completeness.Checklist = append(completeness.Checklist, models.CompletenessItem{
+			Category: category,
+			Required: required,
+			Present:  present,
+			Reason:   reason,
+		})
+		if required && !present {
+			completeness.Missing = append(completeness.Missing, category)
+		}
+	}
+	has := func(categories ...models.Category) bool {
+		for _, category := range categories {
+			if findPart(parts, category) != nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	cpu := findPart(parts, models.CategoryCPU)
+	check(models.CategoryCPU, true, "", cpu != nil)
+	check(models.CategoryMotherboard, true, "", has(models.CategoryMotherboard))
+	check(models.CategoryMemory, true, "", has(models.CategoryMemory))
+	check(models.CategoryInternalHDD, true, "", has(models.CategoryInternalHDD))
+
+	gpuRequired, gpuReason := true, ""
+	if cpu != nil && hasIntegratedGraphics(*cpu) {
+		gpuRequired, gpuReason = false, fmt.Sprintf("%s has integrated graphics", cpu.Model)
+	}
+	check(models.CategoryVideoCard, gpuRequired, gpuReason, has(models.CategoryVideoCard))
+
+	coolerRequired, coolerReason := true, ""
+	if cpu != nil {
+		if included, _ := utils.GetSpecBool(utils.ParseSpecs(cpu.Specs), constants.SPEC_INCLUDES_COOLER); included {
+			coolerRequired, coolerReason = false, fmt.Sprintf("%s ships with a cooler", cpu.Model)
+		}
+	}
+	if has(models.CategoryWaterCooling) && !has(models.CategoryCPUCooler) {
+		coolerReason = "Cooled by a liquid cooler"
+	}
+	check(models.CategoryCPUCooler, coolerRequired, coolerReason, has(models.CategoryCPUCooler, models.CategoryWaterCooling))
+
+	check(models.CategoryPowerSupply, true, "", has(models.CategoryPowerSupply))
+	check(models.CategoryCase, true, "", has(models.CategoryCase))
+	check(models.CategoryOS, false, "Optional", has(models.CategoryOS))
+
+	completeness.Complete = len(completeness.Missing) == 0
+	return completeness
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEvaluateCompleteness tests the required part checklist of a build
+func TestEvaluateCompleteness(t *testing.T) {
+	core := []models.BuildComponentWithDetails{
+		storagePart(models.CategoryMotherboard, "2", "B650", `{}`, 1),
+		storagePart(models.CategoryMemory, "3", "32GB DDR5", `{}`, 1),
+		storagePart(models.CategoryInternalHDD, "4", "990 Pro", `{}`, 1),
+		storagePart(models.CategoryPowerSupply, "5", "RM750e", `{}`, 1),
+		storagePart(models.CategoryCase, "6", "North", `{}`, 1),
+	}
+	with := func(parts ...models.BuildComponentWithDetails) []models.BuildComponentWithDetails {
+		return append(append([]models.BuildComponentWithDetails{}, core...), parts...)
+	}
+	item := func(completeness models.BuildCompleteness, category models.Category) models.CompletenessItem {
+		for _, item := range completeness.Checklist {
+			if item.Category == category {
+				return item
+			}
+		}
+		t.Fatalf("no checklist item for %s", category)
+		return models.CompletenessItem{}
+	}
+
+	t.Run("Empty build", func(t *testing.T) {
+		completeness := EvaluateCompleteness(nil)
+
+		assert.False(t, completeness.Complete)
+		assert.Len(t, completeness.Missing, 8)
+		assert.NotContains(t, completeness.Missing, models.CategoryOS)
+	})
+
+	t.Run("Integrated graphics and stock cooler", func(t *testing.T) {
+		apu := storagePart(models.CategoryCPU, "1", "Ryzen 5 8600G", `{"integrated_graphics": true, "includes_cooler": true}`, 1)
+		completeness := EvaluateCompleteness(with(apu))
+
+		assert.True(t, completeness.Complete)
+		assert.Empty(t, completeness.Missing)
+		assert.False(t, item(completeness, models.CategoryVideoCard).Required)
+		assert.Contains(t, item(completeness, models.CategoryVideoCard).Reason, "integrated graphics")
+		assert.False(t, item(completeness, models.CategoryCPUCooler).Required)
+		assert.False(t, item(completeness, models.CategoryOS).Present)
+	})
+
+	t.Run("CPU without graphics or cooler", func(t *testing.T) {
+		cpu := storagePart(models.CategoryCPU, "1", "Ryzen 7 7800X3D", `{"integrated_graphics": false}`, 1)
+		completeness := EvaluateCompleteness(with(cpu))
+
+		assert.False(t, completeness.Complete)
+		assert.Equal(t, []models.Category{models.CategoryVideoCard, models.CategoryCPUCooler}, completeness.Missing)
+	})
+
+	t.Run("Liquid cooler counts as the cooler", func(t *testing.T) {
+		cpu := storagePart(models.CategoryCPU, "1", "Ryzen 7 7800X3D", `{}`, 1)
+		gpu := storagePart(models.CategoryVideoCard, "7", "RTX 4070", `{}`, 1)
+		aio := storagePart(models.CategoryWaterCooling, "8", "H150i", `{}`, 1)
+		completeness := EvaluateCompleteness(with(cpu, gpu, aio))
+
+		assert.True(t, completeness.Complete)
+		assert.True(t, item(completeness, models.CategoryCPUCooler).Present)
+	})
+}
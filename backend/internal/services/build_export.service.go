@@ -0,0 +1,276 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// BuildExportFormat renders exported builds with a text template
+type BuildExportFormat struct {
+	Name        string
+	ContentType string
+	Extension   string
+	template    *template.Template
+}
+
+const markdownExportTemplate = `# {{ md .Name }}
+{{ if .Description }}
+{{ md .Description }}
+{{ end }}
+| Category | Part | Qty | Retailer | Price | Total |
+| --- | --- | ---: | --- | ---: | ---: |
+{{ range .Lines -}}
+| {{ md .Category }} | {{ if .ProductURL }}[{{ md (part .) }}](<{{ .ProductURL }}>){{ else }}{{ md (part .) }}{{ end }} | {{ .Quantity }} | {{ md .Retailer }} | {{ price .UnitPrice }} | {{ price .LineTotal }} |
+{{ end }}
+**Total: {{ if .Total }}{{ price .Total }} {{ .Currency }}{{ else }}no price{{ end }}**{{ if not .Complete }} (some parts have no price in {{ md .Region }}){{ end }}
+`
+
+const bbcodeExportTemplate = `[b]{{ bb .Name }}[/b]
+{{ if .Description }}{{ bb .Description }}
+{{ end }}
+[list]
+{{ range .Lines -}}
+[*][b]{{ bb .Category }}:[/b] {{ if .ProductURL }}[url={{ bbURL .ProductURL }}]{{ bb (part .) }}[/url]{{ else }}{{ bb (part .) }}{{ end }} x{{ .Quantity }}{{ if .Retailer }} @ {{ bb .Retailer }}{{ end }} - {{ if .LineTotal }}{{ price .LineTotal }} {{ $.Currency }}{{ else }}no price{{ end }}
+{{ end -}}
+[/list]
+[b]Total: {{ if .Total }}{{ price .Total }} {{ .Currency }}{{ else }}no price{{ end }}[/b]{{ if not .Complete }} (some parts have no price in {{ bb .Region }}){{ end }}
+`
+
+const textExportTemplate = `{{ .Name }}
+{{ if .Description }}{{ .Description }}
+{{ end }}
+{{ range .Lines -}}
+{{ .Category }}: {{ part . }} x{{ .Quantity }} - {{ if .LineTotal }}{{ price .LineTotal }} {{ $.Currency }}{{ else }}no price{{ end }}{{ if .Retailer }} at {{ .Retailer }}{{ end }}
+{{ if .ProductURL }}    {{ .ProductURL }}
+{{ end }}
+{{- end }}
+Total: {{ if .Total }}{{ price .Total }} {{ .Currency }}{{ else }}no price{{ end }}{{ if not .Complete }} (some parts have no price in {{ .Region }}){{ end }}
+`
+
+const csvExportTemplate = `category,brand,model,quantity,retailer,unit_price,line_total,currency,product_url
+{{ range .Lines -}}
+{{ csv .Category }},{{ csv .Brand }},{{ csv .Model }},{{ .Quantity }},{{ csv .Retailer }},{{ price .UnitPrice }},{{ price .LineTotal }},{{ csv $.Currency }},{{ csv .ProductURL }}
+{{ end -}}
+total,,,,,,{{ price .Total }},{{ csv .Currency }},
+`
+
+var (
+	buildExportFormatsMu sync.RWMutex
+	buildExportFormats   = map[string]BuildExportFormat{
+		"markdown": mustBuildExportFormat("markdown", "text/markdown; charset=utf-8", "md", markdownExportTemplate),
+		"bbcode":   mustBuildExportFormat("bbcode", "text/plain; charset=utf-8", "txt", bbcodeExportTemplate),
+		"text":     mustBuildExportFormat("text", "text/plain; charset=utf-8", "txt", textExportTemplate),
+		"csv":      mustBuildExportFormat("csv", "text/csv; charset=utf-8", "csv", csvExportTemplate),
+	}
+)
+
+var buildExportFuncs = template.FuncMap{
+	"part":  exportPartName,
+	"price": formatExportPrice,
+	"md":    escapeMarkdown,
+	"bb":    escapeBBCode,
+	"bbURL": escapeBBCodeURL,
+	"csv":   escapeCSV,
+}
+
+// NewBuildExportFormat parses a template into an export format. The template is executed with a
+// models.BuildExport and can use the part, price, md, bb, bbURL and csv functions.
+func NewBuildExportFormat(name, contentType, extension, text string) (BuildExportFormat, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return BuildExportFormat{}, fmt.Errorf("export format name is required")
+	}
+
+	tmpl, err := template.New(name).Funcs(buildExportFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return BuildExportFormat{}, fmt.Errorf("invalid template for export format %s: %w", name, err)
+	}
+	return BuildExportFormat{Name: name, ContentType: contentType, Extension: extension, template: tmpl}, nil
+}
+
+func mustBuildExportFormat(name, contentType, extension, text string) BuildExportFormat {
+	format, err := NewBuildExportFormat(name, contentType, extension, text)
+	if err != nil {
+		panic(err)
+	}
+	return format
+}
+
+// RegisterBuildExportFormat adds an export format, replacing any format with the same name
+func RegisterBuildExportFormat(format BuildExportFormat) {
+	buildExportFormatsMu.Lock()
+	defer buildExportFormatsMu.Unlock()
+	buildExportFormats[format.Name] = format
+}
+
+// GetBuildExportFormat looks up an export format by its case-insensitive name
+func GetBuildExportFormat(name string) (BuildExportFormat, bool) {
+	buildExportFormatsMu.RLock()
+	defer buildExportFormatsMu.RUnlock()
+	format, ok := buildExportFormats[strings.ToLower(strings.TrimSpace(name))]
+	return format, ok
+}
+
+// BuildExportFormatNames returns the names of the registered export formats in alphabetical order
+func BuildExportFormatNames() []string {
+	buildExportFormatsMu.RLock()
+	defer buildExportFormatsMu.RUnlock()
+
+	names := make([]string, 0, len(buildExportFormats))
+	for name := range buildExportFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExportBuild renders a build visible to viewerID with its parts and current prices
+func ExportBuild(buildID int64, viewerID string, format BuildExportFormat) ([]byte, error) {
+	utils.Log(constants.SERVICE_EXPORT_BUILD_START, nil, buildID, format.Name)
+
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_EXPORT_BUILD_ERROR, err, buildID)
+		return nil, err
+	}
+
+	detailed, err := withBuildDetails(build)
+	if err != nil {
+		utils.Log(constants.SERVICE_EXPORT_BUILD_ERROR, err, buildID)
+		return nil, err
+	}
+
+	retailerIDs := []int64{}
+	for _, line := range detailed.Pricing.Lines {
+		if line.RetailerID != nil {
+			retailerIDs = append(retailerIDs, *line.RetailerID)
+		}
+	}
+	retailers, err := repository.GetRetailerNames(retailerIDs)
+	if err != nil {
+		utils.Log(constants.SERVICE_EXPORT_BUILD_ERROR, err, buildID)
+		return nil, err
+	}
+
+	output, err := RenderBuildExport(format, NewBuildExport(detailed, retailers))
+	if err != nil {
+		utils.Log(constants.SERVICE_EXPORT_BUILD_ERROR, err, buildID)
+		return nil, err
+	}
+
+	utils.Log(constants.SERVICE_EXPORT_BUILD_SUCCESS, nil, buildID, format.Name)
+	return output, nil
+}
+
+// RenderBuildExport executes the template of an export format
+func RenderBuildExport(format BuildExportFormat, export models.BuildExport) ([]byte, error) {
+	if format.template == nil {
+		return nil, fmt.Errorf("export format %s has no template", format.Name)
+	}
+
+	var buf bytes.Buffer
+	if err := format.template.Execute(&buf, export); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewBuildExport pairs each part of a build with its priced line. retailers maps retailer ids to names.
+func NewBuildExport(build models.UserBuildWithComponents, retailers map[int64]string) models.BuildExport {
+	export := models.BuildExport{
+		Name:     build.Name,
+		Currency: strings.ToUpper(build.Currency),
+		Region:   build.Region,
+		Total:    build.TotalPrice,
+		Complete: true,
+		Lines:    []models.BuildExportLine{},
+	}
+	if build.Description != nil {
+		export.Description = *build.Description
+	}
+
+	lines := map[int64]models.BuildPriceLine{}
+	if build.Pricing != nil {
+		export.Currency = build.Pricing.Currency
+		export.Total = build.Pricing.Total
+		export.Complete = build.Pricing.Complete
+		for _, line := range build.Pricing.Lines {
+			lines[line.ComponentID] = line
+		}
+	}
+
+	for _, part := range build.Components {
+		exported := models.BuildExportLine{Quantity: part.Quantity}
+		if exported.Quantity < 1 {
+			exported.Quantity = 1
+		}
+		if part.Component != nil {
+			exported.Category = string(part.Component.Category)
+			exported.Brand = part.Component.Brand
+			exported.Model = part.Component.Model
+		}
+		if line, ok := lines[part.ComponentID]; ok {
+			exported.UnitPrice = line.UnitPrice
+			exported.LineTotal = line.LineTotal
+			if line.RetailerID != nil {
+				exported.Retailer = retailers[*line.RetailerID]
+			}
+			if line.ProductURL != nil {
+				exported.ProductURL = *line.ProductURL
+			}
+		}
+		export.Lines = append(export.Lines, exported)
+	}
+	return export
+}
+
+func exportPartName(line models.BuildExportLine) string {
+	return strings.TrimSpace(line.Brand + " " + line.Model)
+}
+
+func formatExportPrice(price *float64) string {
+	if price == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *price)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`,
+	"\r\n", " ", "\n", " ",
+)
+
+// escapeMarkdown keeps user text from breaking out of a table cell or adding formatting
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+// escapeBBCode swaps square brackets for parentheses since BBCode has no escape sequence
+func escapeBBCode(text string) string {
+	return strings.NewReplacer("[", "(", "]", ")").Replace(text)
+}
+
+func escapeBBCodeURL(url string) string {
+	return strings.NewReplacer("[", "%5B", "]", "%5D").Replace(url)
+}
+
+// escapeCSV quotes a field when it holds a separator, quote or line break, and neutralizes
+// leading characters that spreadsheets would evaluate as a formula
+func escapeCSV(field string) string {
+	if field != "" && strings.ContainsRune("=+-@\t\r", rune(field[0])) {
+		field = "'" + field
+	}
+	if strings.ContainsAny(field, ",\"\r\n") {
+		return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+	}
+	return field
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportFixture() models.BuildExport {
+	price := func(value float64) *float64 { return &value }
+	return models.BuildExport{
+		Name:     "Quiet | Cool",
+		Currency: "USD",
+		Region:   "USA",
+		Total:    price(1048),
+		Complete: false,
+		Lines: []models.BuildExportLine{
+			{Category: "cpu", Brand: "AMD", Model: "Ryzen 7 7800X3D", Quantity: 1, Retailer: "Newegg", UnitPrice: price(449), LineTotal: price(449), ProductURL: "https://example.com/cpu"},
+			{Category: "gpu", Brand: "NVIDIA", Model: "RTX 4070, 12GB", Quantity: 1, Retailer: "Best Buy", UnitPrice: price(599), LineTotal: price(599)},
+			{Category: "case_fan", Brand: "Arctic", Model: "=P12 [PWM]", Quantity: 3},
+		},
+	}
+}
+
+// TestRenderBuildExport tests each built-in export format
+func TestRenderBuildExport(t *testing.T) {
+	render := func(name string) string {
+		format, ok := GetBuildExportFormat(name)
+		require.True(t, ok)
+		output, err := RenderBuildExport(format, exportFixture())
+		require.NoError(t, err)
+		return string(output)
+	}
+
+	t.Run("Markdown", func(t *testing.T) {
+		output := render("markdown")
+
+		assert.Contains(t, output, `# Quiet \| Cool`)
+		assert.Contains(t, output, "| cpu | [AMD Ryzen 7 7800X3D](<https://example.com/cpu>) | 1 | Newegg | 449.00 | 449.00 |")
+		assert.Contains(t, output, `| case\_fan | Arctic =P12 \[PWM\] | 3 |  |  |  |`)
+		assert.Contains(t, output, "**Total: 1048.00 USD** (some parts have no price in USA)")
+	})
+
+	t.Run("BBCode", func(t *testing.T) {
+		output := render("BBCode")
+
+		assert.Contains(t, output, "[*][b]cpu:[/b] [url=https://example.com/cpu]AMD Ryzen 7 7800X3D[/url] x1 @ Newegg - 449.00 USD")
+		assert.Contains(t, output, "Arctic =P12 (PWM) x3 - no price\n")
+		assert.Contains(t, output, "[b]Total: 1048.00 USD[/b]")
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		output := render("text")
+
+		assert.Contains(t, output, "cpu: AMD Ryzen 7 7800X3D x1 - 449.00 USD at Newegg\n    https://example.com/cpu\n")
+		assert.Contains(t, output, "Total: 1048.00 USD")
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		records, err := csv.NewReader(strings.NewReader(render("csv"))).ReadAll()
+		require.NoError(t, err)
+
+		require.Len(t, records, 5)
+		assert.Equal(t, []string{"category", "brand", "model", "quantity", "retailer", "unit_price", "line_total", "currency", "product_url"}, records[0])
+		assert.Equal(t, "RTX 4070, 12GB", records[2][2])
+		assert.Equal(t, "'=P12 [PWM]", records[3][2])
+		assert.Equal(t, []string{"total", "", "", "", "", "", "1048.00", "USD", ""}, records[4])
+	})
+}
+
+// TestNewBuildExportFormat tests registering a custom export format
+func TestNewBuildExportFormat(t *testing.T) {
+	_, err := NewBuildExportFormat("broken", "text/plain", "txt", "{{ .Name")
+	assert.Error(t, err)
+
+	format, err := NewBuildExportFormat("Names", "text/plain", "txt", "{{ range .Lines }}{{ part . }};{{ end }}")
+	require.NoError(t, err)
+	RegisterBuildExportFormat(format)
+	defer func() {
+		buildExportFormatsMu.Lock()
+		delete(buildExportFormats, "names")
+		buildExportFormatsMu.Unlock()
+	}()
+
+	registered, ok := GetBuildExportFormat("names")
+	require.True(t, ok)
+	assert.Contains(t, BuildExportFormatNames(), "names")
+	output, err := RenderBuildExport(registered, exportFixture())
+	require.NoError(t, err)
+	assert.Equal(t, "AMD Ryzen 7 7800X3D;NVIDIA RTX 4070, 12GB;Arctic =P12 [PWM];", string(output))
+}
+
+// TestNewBuildExport tests pairing the parts of a build with their priced lines
+func TestNewBuildExport(t *testing.T) {
+	unitPrice, total, retailerID := 25.0, 75.0, int64(4)
+	url := "https://example.com/fan"
+	build := models.UserBuildWithComponents{
+		UserBuild:  models.UserBuild{Name: "Fans", Currency: "usd", Region: "USA"},
+		Components: []models.BuildComponentWithDetails{storagePart(models.CategoryCaseFan, "5", "P12", `{}`, 3)},
+		Pricing: &models.BuildPriceBreakdown{
+			Currency: "USD",
+			Total:    &total,
+			Complete: true,
+			Lines:    []models.BuildPriceLine{{ComponentID: 5, Quantity: 3, RetailerID: &retailerID, UnitPrice: &unitPrice, LineTotal: &total, ProductURL: &url}},
+		},
+	}
+	build.Components[0].ComponentID = 5
+
+	export := NewBuildExport(build, map[int64]string{4: "Micro Center"})
+
+	assert.Equal(t, "USD", export.Currency)
+	assert.Equal(t, 75.0, *export.Total)
+	require.Len(t, export.Lines, 1)
+	assert.Equal(t, "case_fan", export.Lines[0].Category)
+	assert.Equal(t, "Micro Center", export.Lines[0].Retailer)
+	assert.Equal(t, url, export.Lines[0].ProductURL)
+	assert.Equal(t, 3, export.Lines[0].Quantity)
+}
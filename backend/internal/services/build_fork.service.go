@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ForkUserBuild copies a build into a new private build owned by userID. Anyone may fork a public build, while a
+// private build can only be forked by its creator. Collaborators on a private build are forbidden from forking it,
+// and private builds userID does not collaborate on are reported as sql.ErrNoRows.
+func ForkUserBuild(buildID int64, userID string) (models.UserBuildWithComponents, error) {
+	utils.Log(constants.SERVICE_FORK_BUILD_START, nil, buildID, userID)
+
+	build, err := getVisibleBuild(buildID, userID)
+	if err != nil {
+		utils.Log(constants.SERVICE_FORK_BUILD_ERROR, err, buildID, userID)
+		return models.UserBuildWithComponents{}, err
+	}
+	if !build.IsPublic && build.UserID != userID {
+		utils.Log(constants.SERVICE_FORK_BUILD_ERROR, ErrBuildForbidden, buildID, userID)
+		return models.UserBuildWithComponents{}, ErrBuildForbidden
+	}
+
+	fork, err := repository.ForkUserBuild(buildID, userID, buildSnapshot(userID, fmt.Sprintf("Forked from build %d", buildID)))
+	if err != nil {
+		utils.Log(constants.SERVICE_FORK_BUILD_ERROR, err, buildID, userID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	utils.Log(constants.SERVICE_FORK_BUILD_SUCCESS, nil, buildID, fork.ID, userID)
+	return GetUserBuild(fork.ID, userID)
+}
+
+// GetBuildLineage returns the fork count of a build with the ancestors and forks the viewer may see
+func GetBuildLineage(buildID int64, viewerID string) (models.BuildLineage, error) {
+	utils.Log(constants.SERVICE_GET_BUILD_LINEAGE_START, nil, buildID, viewerID)
+
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_LINEAGE_ERROR, err, buildID, viewerID)
+		return models.BuildLineage{}, err
+	}
+
+	ancestors, err := repository.GetBuildAncestors(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_LINEAGE_ERROR, err, buildID, viewerID)
+		return models.BuildLineage{}, err
+	}
+
+	forks, err := repository.GetBuildForks(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_LINEAGE_ERROR, err, buildID, viewerID)
+		return models.BuildLineage{}, err
+	}
+
+	visibleAncestors, err := visibleBuilds(ancestors, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_LINEAGE_ERROR, err, buildID, viewerID)
+		return models.BuildLineage{}, err
+	}
+
+	lineage := models.BuildLineage{
+		BuildID:   buildID,
+		ForkCount: build.ForkCount,
+		Ancestors: visibleAncestors,
+		Forks:     forks,
+	}
+
+	utils.Log(constants.SERVICE_GET_BUILD_LINEAGE_SUCCESS, nil, buildID, viewerID)
+	return lineage, nil
+}
+
+// visibleBuilds keeps the builds the viewer may see, as getVisibleBuild decides
+func visibleBuilds(builds []models.UserBuild, viewerID string) ([]models.UserBuild, error) {
+	visible := []models.UserBuild{}
+	for _, build := range builds {
+		ok, err := canViewBuild(build, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible = append(visible, build)
+		}
+	}
+	return visible, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestForkUserBuildAccess tests that only the creator may fork a private build
+func TestForkUserBuildAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		role     string
+		expected error
+	}{
+		{name: "Editor", userID: "editor", role: "editor", expected: ErrBuildForbidden},
+		{name: "Viewer", userID: "viewer", role: "viewer", expected: ErrBuildForbidden},
+		{name: "Stranger", userID: "stranger", expected: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := collaboratorMock(t)
+			expectCollaboratorBuild(mock, false)
+			expectCollaborator(mock, tt.userID, tt.role, true)
+
+			_, err := ForkUserBuild(1, tt.userID)
+			assert.True(t, errors.Is(err, tt.expected), err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ListPublicBuilds returns a page of the public build gallery and the cursor of the next page, if any
+func ListPublicBuilds(filter models.BuildGalleryFilter) (models.BuildGalleryPage, error) {
+	if err := normalizeGalleryFilter(&filter); err != nil {
+		utils.Log(constants.SERVICE_LIST_PUBLIC_BUILDS_ERROR, err)
+		return models.BuildGalleryPage{}, err
+	}
+	utils.Log(constants.SERVICE_LIST_PUBLIC_BUILDS_START, nil, filter.Sort)
+
+	if err := convertGalleryPriceRange(&filter); err != nil {
+		utils.Log(constants.SERVICE_LIST_PUBLIC_BUILDS_ERROR, err)
+		return models.BuildGalleryPage{}, err
+	}
+
+	// Fetch one extra card to learn whether another page follows
+	limit := filter.Limit
+	filter.Limit++
+	cards, err := repository.ListPublicBuildCards(filter)
+	if err != nil {
+		utils.Log(constants.SERVICE_LIST_PUBLIC_BUILDS_ERROR, err)
+		return models.BuildGalleryPage{}, err
+	}
+
+	page := models.BuildGalleryPage{Builds: cards}
+	if len(cards) > limit {
+		page.Builds = cards[:limit]
+		last := page.Builds[limit-1]
+		page.NextCursor = EncodeGalleryCursor(models.GalleryCursor{
+			Sort:  filter.Sort,
+			Value: repository.GalleryCursorValue(last, filter.Sort),
+			ID:    last.ID,
+		})
+	}
+
+	utils.Log(constants.SERVICE_LIST_PUBLIC_BUILDS_SUCCESS, nil, len(page.Builds))
+	return page, nil
+}
+
+func normalizeGalleryFilter(filter *models.BuildGalleryFilter) error {
+	filter.Sort = strings.ToLower(strings.TrimSpace(filter.Sort))
+	if filter.Sort == "" {
+		filter.Sort = models.GallerySortNewest
+	}
+	if !repository.IsValidGallerySort(filter.Sort) {
+		return fmt.Errorf("%w: sort must be one of newest, most_forked, most_liked, price_low or price_high", ErrInvalidBuild)
+	}
+	if filter.Cursor != nil {
+		if filter.Cursor.Sort != filter.Sort {
+			return fmt.Errorf("%w: cursor belongs to a page sorted by %s", ErrInvalidBuild, filter.Cursor.Sort)
+		}
+		if !validGalleryCursorValue(filter.Cursor.Value, filter.Sort) {
+			return fmt.Errorf("%w: cursor is malformed", ErrInvalidBuild)
+		}
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = constants.GALLERY_PAGE_SIZE
+	}
+	if filter.Limit < 1 || filter.Limit > constants.GALLERY_MAX_PAGE_SIZE {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidBuild, constants.GALLERY_MAX_PAGE_SIZE)
+	}
+
+	if filter.Currency == "" {
+		filter.Currency = baseCurrency
+	}
+	if err := normalizeBuildLocale(&filter.Currency, filter.Region); err != nil {
+		return err
+	}
+	if err := normalizeBuildUseCase(filter.UseCase); err != nil {
+		return err
+	}
+	if filter.UseCase != nil && *filter.UseCase == "" {
+		filter.UseCase = nil
+	}
+
+	if filter.MinPrice != nil && *filter.MinPrice < 0 || filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		return fmt.Errorf("%w: prices cannot be negative", ErrInvalidBuild)
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return fmt.Errorf("%w: min_price cannot be above max_price", ErrInvalidBuild)
+	}
+	if filter.ComponentID != nil && *filter.ComponentID <= 0 {
+		return fmt.Errorf("%w: component_id must be positive", ErrInvalidBuild)
+	}
+	return nil
+}
+
+// convertGalleryPriceRange converts the price bounds of a filter to US dollars, the currency the gallery compares totals in
+func convertGalleryPriceRange(filter *models.BuildGalleryFilter) error {
+	if filter.MinPrice == nil && filter.MaxPrice == nil || filter.Currency == baseCurrency {
+		return nil
+	}
+
+	rates, err := repository.GetExchangeRates()
+	if err != nil {
+		return err
+	}
+	for _, bound := range []**float64{&filter.MinPrice, &filter.MaxPrice} {
+		if *bound == nil {
+			continue
+		}
+		converted, ok := ConvertCurrency(**bound, filter.Currency, baseCurrency, rates)
+		if !ok {
+			return fmt.Errorf("%w: no exchange rate for %s", ErrInvalidBuild, filter.Currency)
+		}
+		converted = roundTo(converted, 4)
+		*bound = &converted
+	}
+	filter.Currency = baseCurrency
+	return nil
+}
+
+// validGalleryCursorValue checks that a cursor value parses as the sort key of the gallery sort
+func validGalleryCursorValue(value, sort string) bool {
+	var err error
+	switch sort {
+	case models.GallerySortMostForked, models.GallerySortMostLiked:
+		_, err = strconv.Atoi(value)
+	case models.GallerySortPriceLow, models.GallerySortPriceHigh:
+		_, err = strconv.ParseFloat(value, 64)
+	default:
+		_, err = time.Parse(time.RFC3339Nano, value)
+	}
+	return err == nil
+}
+
+// EncodeGalleryCursor turns a cursor into the opaque text clients pass back for the next page
+func EncodeGalleryCursor(cursor models.GalleryCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeGalleryCursor parses a cursor returned by EncodeGalleryCursor
+func DecodeGalleryCursor(text string) (models.GalleryCursor, error) {
+	var cursor models.GalleryCursor
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return cursor, fmt.Errorf("cursor is malformed")
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Value == "" || cursor.ID <= 0 {
+		return models.GalleryCursor{}, fmt.Errorf("cursor is malformed")
+	}
+	return cursor, nil
+}
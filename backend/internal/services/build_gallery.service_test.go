@@ -0,0 +1,91 @@
+package services
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListPublicBuilds tests page trimming, next cursors and price bounds in other currencies
+func TestListPublicBuilds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	columns := []string{"id", "user_id", "name", "use_case", "total_price", "currency", "region", "is_complete", "fork_count", "like_count",
+		"created_at", "total_usd", "cpu_id", "cpu_brand", "cpu_model", "gpu_id", "gpu_brand", "gpu_model", "part_count"}
+	card := func(id int64, forks int) []driver.Value {
+		return []driver.Value{id, "user-1", "Build", nil, nil, "USD", "USA", false, forks, 0, time.Now(), nil, nil, nil, nil, nil, nil, nil, 0}
+	}
+
+	minPrice := 1350.0
+	mock.ExpectQuery("SELECT (.+) FROM exchange_rates").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "usd_rate"}).AddRow("CAD", 1.35))
+	mock.ExpectQuery(`LIMIT 3`).
+		WithArgs(models.CategoryCPU, models.CategoryVideoCard, 1000.0).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(card(9, 12)...).AddRow(card(4, 7)...).AddRow(card(3, 7)...))
+
+	page, err := ListPublicBuilds(models.BuildGalleryFilter{MinPrice: &minPrice, Currency: "cad", Sort: "most_forked", Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, page.Builds, 2)
+	require.NotEmpty(t, page.NextCursor)
+	cursor, err := DecodeGalleryCursor(page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, models.GalleryCursor{Sort: models.GallerySortMostForked, Value: "7", ID: 4}, cursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestNormalizeGalleryFilter tests gallery defaults and validation
+func TestNormalizeGalleryFilter(t *testing.T) {
+	filter := models.BuildGalleryFilter{}
+	require.NoError(t, normalizeGalleryFilter(&filter))
+	assert.Equal(t, models.GallerySortNewest, filter.Sort)
+	assert.Equal(t, constants.GALLERY_PAGE_SIZE, filter.Limit)
+	assert.Equal(t, "USD", filter.Currency)
+
+	low, high, componentID, useCase := 2000.0, 1000.0, int64(0), "mining"
+	invalid := map[string]models.BuildGalleryFilter{
+		"Unknown sort":           {Sort: "random"},
+		"Limit too large":        {Limit: constants.GALLERY_MAX_PAGE_SIZE + 1},
+		"Inverted price range":   {MinPrice: &low, MaxPrice: &high},
+		"Unknown use case":       {UseCase: &useCase},
+		"Invalid component":      {ComponentID: &componentID},
+		"Cursor of another sort": {Cursor: &models.GalleryCursor{Sort: models.GallerySortMostLiked, Value: "3", ID: 1}},
+		"Cursor value of another type": {
+			Sort:   models.GallerySortNewest,
+			Cursor: &models.GalleryCursor{Sort: models.GallerySortNewest, Value: "3", ID: 1},
+		},
+	}
+	for name, filter := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, errors.Is(normalizeGalleryFilter(&filter), ErrInvalidBuild))
+		})
+	}
+}
+
+// TestGalleryCursor tests that cursors survive encoding and malformed cursors are rejected
+func TestGalleryCursor(t *testing.T) {
+	cursor := models.GalleryCursor{Sort: models.GallerySortNewest, Value: "2026-01-02T03:04:05.123456Z", ID: 42}
+
+	decoded, err := DecodeGalleryCursor(EncodeGalleryCursor(cursor))
+
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	for _, text := range []string{"not base64!", "bm90IGpzb24", EncodeGalleryCursor(models.GalleryCursor{Sort: "newest"})} {
+		_, err := DecodeGalleryCursor(text)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,650 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ErrBudgetTooLow is returned when no compatible combination of in-stock parts fits the budget
+var ErrBudgetTooLow = errors.New("no compatible build fits the budget")
+
+// generatorProfile describes how the generator scores builds for a use case.
+// cpuWeight is the share of the CPU score in the build score; the GPU gets the rest. Use cases that do not
+// need a video card score the CPU alone and only add the cheapest card when the CPU has no integrated graphics.
+type generatorProfile struct {
+	useCase         string
+	cpuWeight       float64
+	needsGPU        bool
+	boardFormFactor string
+}
+
+var generatorProfiles = map[string]generatorProfile{
+	constants.USE_CASE_GAMING:      {useCase: constants.USE_CASE_GAMING, cpuWeight: 0.35, needsGPU: true},
+	constants.USE_CASE_WORKSTATION: {useCase: constants.USE_CASE_WORKSTATION, cpuWeight: 0.7, needsGPU: true},
+	constants.USE_CASE_OFFICE:      {useCase: constants.USE_CASE_OFFICE, cpuWeight: 1, needsGPU: false},
+	constants.USE_CASE_SFF:         {useCase: constants.USE_CASE_GAMING, cpuWeight: 0.35, needsGPU: true, boardFormFactor: constants.FORM_FACTOR_MINI_ITX},
+}
+
+// generatorCategories are the categories every generated build gets a part from, in the order they are chosen.
+// A CPU cooler is skipped when the CPU includes one and a video card when the use case does not need it.
+var generatorCategories = []models.Category{
+	models.CategoryCPU,
+	models.CategoryVideoCard,
+	models.CategoryMotherboard,
+	models.CategoryMemory,
+	models.CategoryCPUCooler,
+	models.CategoryInternalHDD,
+	models.CategoryCase,
+	models.CategoryPowerSupply,
+}
+
+// generatorCatalog holds the parts the generator can choose from, cheapest first within each category
+type generatorCatalog struct {
+	byCategory map[models.Category][]models.GeneratedBuildPart
+	extras     []models.GeneratedBuildPart
+	rules      []models.CompatibilityRule
+}
+
+// generatorPlatform is the part of a build chosen for a CPU before the video card is known
+type generatorPlatform struct {
+	parts []models.GeneratedBuildPart
+	cost  float64
+	ok    bool
+}
+
+// IsValidGeneratorUseCase returns true if the build generator supports the use case
+func IsValidGeneratorUseCase(useCase string) bool {
+	_, ok := generatorProfiles[useCase]
+	return ok
+}
+
+// GenerateBuild searches the catalog and current prices of a region for the best scoring compatible build
+// within budget
+func GenerateBuild(request models.BuildGeneratorRequest) (models.BuildGeneratorResult, error) {
+	utils.Log(constants.SERVICE_GENERATE_BUILD_START, nil, request.UseCase, request.Budget, request.Currency, request.Region)
+
+	if err := normalizeGeneratorRequest(&request); err != nil {
+		utils.Log(constants.SERVICE_GENERATE_BUILD_ERROR, err, request.UseCase)
+		return models.BuildGeneratorResult{}, err
+	}
+
+	catalog, err := loadGeneratorCatalog(request)
+	if err != nil {
+		utils.Log(constants.SERVICE_GENERATE_BUILD_ERROR, err, request.UseCase)
+		return models.BuildGeneratorResult{}, err
+	}
+
+	result, err := GenerateBuildFromCatalog(request, catalog, GetCompatibilityRules())
+	if err != nil {
+		utils.Log(constants.SERVICE_GENERATE_BUILD_ERROR, err, request.UseCase)
+		return models.BuildGeneratorResult{}, err
+	}
+
+	utils.Log(constants.SERVICE_GENERATE_BUILD_SUCCESS, nil, request.UseCase, result.Build.Score, result.Build.Total, result.Currency)
+	return result, nil
+}
+
+func normalizeGeneratorRequest(request *models.BuildGeneratorRequest) error {
+	if request.Budget <= 0 {
+		return fmt.Errorf("%w: budget must be positive", ErrInvalidBuild)
+	}
+
+	if request.Currency == "" {
+		request.Currency = baseCurrency
+	}
+	if request.Region == "" {
+		request.Region = constants.DEFAULT_BUILD_REGION
+	}
+	if err := normalizeBuildLocale(&request.Currency, &request.Region); err != nil {
+		return err
+	}
+
+	request.UseCase = strings.ToLower(strings.TrimSpace(request.UseCase))
+	if request.UseCase == "" {
+		request.UseCase = constants.DEFAULT_USE_CASE
+	}
+	if !IsValidGeneratorUseCase(request.UseCase) {
+		return fmt.Errorf("%w: use_case must be one of gaming, workstation, office or sff", ErrInvalidBuild)
+	}
+
+	request.Resolution = strings.ToLower(strings.TrimSpace(request.Resolution))
+	if generatorProfiles[request.UseCase].useCase != constants.USE_CASE_GAMING {
+		request.Resolution = ""
+	} else if request.Resolution == "" {
+		request.Resolution = constants.DEFAULT_RESOLUTION
+	} else if !IsValidResolution(request.Resolution) {
+		return fmt.Errorf("%w: resolution must be 1080p, 1440p or 4k", ErrInvalidBuild)
+	}
+
+	if len(request.PinnedComponentIDs) > constants.GENERATOR_MAX_PINNED {
+		return fmt.Errorf("%w: at most %d parts can be pinned", ErrInvalidBuild, constants.GENERATOR_MAX_PINNED)
+	}
+	seen := map[int64]bool{}
+	pinned := []int64{}
+	for _, id := range request.PinnedComponentIDs {
+		if id <= 0 {
+			return fmt.Errorf("%w: pinned component ids must be positive", ErrInvalidBuild)
+		}
+		if !seen[id] {
+			seen[id] = true
+			pinned = append(pinned, id)
+		}
+	}
+	request.PinnedComponentIDs = pinned
+	return nil
+}
+
+// loadGeneratorCatalog prices the in-stock parts of the generator categories and the pinned parts
+func loadGeneratorCatalog(request models.BuildGeneratorRequest) ([]models.GeneratedBuildPart, error) {
+	components, prices, err := repository.ListInStockComponents(generatorCategories, request.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, err := repository.GetComponentsByIDs(request.PinnedComponentIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(pinned) != len(request.PinnedComponentIDs) {
+		found := map[string]bool{}
+		for _, component := range pinned {
+			found[component.ID] = true
+		}
+		for _, id := range request.PinnedComponentIDs {
+			if !found[strconv.FormatInt(id, 10)] {
+				return nil, fmt.Errorf("%w: pinned component %d does not exist", ErrInvalidBuild, id)
+			}
+		}
+	}
+	pinnedPrices, err := repository.GetInStockPrices(request.PinnedComponentIDs, request.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := repository.GetExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+
+	byComponent := map[int64][]models.Price{}
+	for _, price := range append(prices, pinnedPrices...) {
+		byComponent[price.ComponentID] = append(byComponent[price.ComponentID], price)
+	}
+
+	catalog := []models.GeneratedBuildPart{}
+	for _, component := range pinned {
+		id, _ := strconv.ParseInt(component.ID, 10, 64)
+		price, unitPrice := cheapestPrice(byComponent[id], request.Currency, rates)
+		if price == nil {
+			return nil, fmt.Errorf("%w: pinned component %d has no in-stock price in %s", ErrInvalidBuild, id, request.Region)
+		}
+		catalog = append(catalog, models.GeneratedBuildPart{Component: component, Price: *price, UnitPrice: roundTo(unitPrice, 2), Pinned: true})
+	}
+	return append(catalog, priceCatalog(components, byComponent, request.Currency, rates)...), nil
+}
+
+// priceCatalog pairs components with their cheapest price in currency, leaving out those without one
+func priceCatalog(components []models.Component, byComponent map[int64][]models.Price, currency string, rates map[string]float64) []models.GeneratedBuildPart {
+	catalog := []models.GeneratedBuildPart{}
+	for _, component := range components {
+		id, _ := strconv.ParseInt(component.ID, 10, 64)
+		price, unitPrice := cheapestPrice(byComponent[id], currency, rates)
+		if price == nil {
+			continue
+		}
+		catalog = append(catalog, models.GeneratedBuildPart{Component: component, Price: *price, UnitPrice: roundTo(unitPrice, 2)})
+	}
+	return catalog
+}
+
+// GenerateBuildFromCatalog finds the combination of priced parts with the highest use case score that fits the
+// budget and passes the compatibility rules. CPUs and video cards are paired from those that score higher than
+// every cheaper option; the remaining parts are the cheapest that are compatible with them, so the budget goes
+// to performance. Pinned parts replace the choice for their category and other pinned parts are added as is.
+func GenerateBuildFromCatalog(request models.BuildGeneratorRequest, parts []models.GeneratedBuildPart, rules []models.CompatibilityRule) (models.BuildGeneratorResult, error) {
+	profile := generatorProfiles[request.UseCase]
+	catalog, err := newGeneratorCatalog(parts, rules)
+	if err != nil {
+		return models.BuildGeneratorResult{}, err
+	}
+
+	extrasCost := 0.0
+	for _, extra := range catalog.extras {
+		extrasCost += extra.UnitPrice
+	}
+
+	cpus := catalog.scoreFrontier(models.CategoryCPU, profile.useCase)
+	gpus := catalog.scoreFrontier(models.CategoryVideoCard, profile.useCase)
+	if !profile.needsGPU {
+		// Only a display output is needed, so the cheapest card that fits will do
+		gpus = catalog.byCategory[models.CategoryVideoCard]
+	}
+
+	builds := []models.GeneratedBuild{}
+	for _, cpu := range cpus {
+		platform := catalog.choosePlatform(cpu, profile)
+		if !platform.ok || cpu.UnitPrice+platform.cost+extrasCost > request.Budget {
+			continue
+		}
+
+		options := []*models.GeneratedBuildPart{}
+		_, gpuPinned := pinnedPart(catalog, models.CategoryVideoCard)
+		if profile.needsGPU || gpuPinned || !hasIntegratedGraphics(cpu.Component) {
+			for i := range gpus {
+				options = append(options, &gpus[i])
+			}
+		} else {
+			options = append(options, nil)
+		}
+
+		for _, gpu := range options {
+			selected := append([]models.GeneratedBuildPart{cpu}, platform.parts...)
+			if gpu != nil {
+				if cpu.UnitPrice+gpu.UnitPrice+platform.cost+extrasCost > request.Budget {
+					continue
+				}
+				selected = append(selected, *gpu)
+			}
+
+			selected, ok := catalog.chooseEnclosure(selected, profile)
+			if !ok {
+				continue
+			}
+			selected = append(selected, catalog.extras...)
+
+			build := newGeneratedBuild(selected, request.Currency)
+			if build.Total > request.Budget {
+				continue
+			}
+			build.Score = generatedBuildScore(profile, request.Resolution, cpu, gpu)
+			builds = append(builds, build)
+
+			if !profile.needsGPU {
+				break
+			}
+		}
+	}
+
+	if len(builds) == 0 {
+		return models.BuildGeneratorResult{}, fmt.Errorf("%w: no compatible %s build with in-stock parts costs %.2f %s or less",
+			ErrBudgetTooLow, request.UseCase, request.Budget, request.Currency)
+	}
+
+	sort.SliceStable(builds, func(i, j int) bool {
+		if builds[i].Score != builds[j].Score {
+			return builds[i].Score > builds[j].Score
+		}
+		return builds[i].Total < builds[j].Total
+	})
+
+	result := models.BuildGeneratorResult{
+		UseCase:      request.UseCase,
+		Resolution:   request.Resolution,
+		Budget:       request.Budget,
+		Currency:     request.Currency,
+		Region:       request.Region,
+		Alternatives: []models.GeneratedBuild{},
+	}
+	result.Build = builds[0]
+	result.Build.Reasons = explainGeneratedBuild(builds[0], request, profile)
+	for _, alternative := range builds[1:] {
+		if len(result.Alternatives) == constants.GENERATOR_MAX_ALTERNATIVES {
+			break
+		}
+		alternative.Reasons = explainAlternative(alternative, result.Build, request)
+		result.Alternatives = append(result.Alternatives, alternative)
+	}
+	return result, nil
+}
+
+func newGeneratorCatalog(parts []models.GeneratedBuildPart, rules []models.CompatibilityRule) (generatorCatalog, error) {
+	catalog := generatorCatalog{byCategory: map[models.Category][]models.GeneratedBuildPart{}, rules: rules}
+
+	generated := map[models.Category]bool{}
+	for _, category := range generatorCategories {
+		generated[category] = true
+	}
+
+	pinned := map[models.Category]bool{}
+	for _, part := range parts {
+		if !part.Pinned {
+			continue
+		}
+		category := part.Component.Category
+		if !generated[category] {
+			catalog.extras = append(catalog.extras, part)
+			continue
+		}
+		if pinned[category] {
+			return generatorCatalog{}, fmt.Errorf("%w: only one %s can be pinned", ErrInvalidBuild, category)
+		}
+		pinned[category] = true
+		catalog.byCategory[category] = []models.GeneratedBuildPart{part}
+	}
+
+	for _, part := range parts {
+		if !part.Pinned && generated[part.Component.Category] && !pinned[part.Component.Category] {
+			catalog.byCategory[part.Component.Category] = append(catalog.byCategory[part.Component.Category], part)
+		}
+	}
+	for category := range catalog.byCategory {
+		list := catalog.byCategory[category]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].UnitPrice < list[j].UnitPrice })
+	}
+	return catalog, nil
+}
+
+// scoreFrontier returns the parts of a category that score higher than every cheaper part. Parts without a
+// score are left out unless they are pinned.
+func (c generatorCatalog) scoreFrontier(category models.Category, useCase string) []models.GeneratedBuildPart {
+	frontier := []models.GeneratedBuildPart{}
+	best := -1.0
+	for _, part := range c.byCategory[category] {
+		score, ok := PerformanceScore(part.Component, useCase)
+		if part.Pinned {
+			return []models.GeneratedBuildPart{part}
+		}
+		if ok && score > best {
+			frontier = append(frontier, part)
+			best = score
+		}
+	}
+	return frontier
+}
+
+// choosePlatform picks the cheapest motherboard, memory, cooler and storage compatible with a CPU
+func (c generatorCatalog) choosePlatform(cpu models.GeneratedBuildPart, profile generatorProfile) generatorPlatform {
+	selected := []models.GeneratedBuildPart{cpu}
+	platform := generatorPlatform{}
+
+	categories := []models.Category{models.CategoryMotherboard, models.CategoryMemory, models.CategoryCPUCooler, models.CategoryInternalHDD}
+	for _, category := range categories {
+		if category == models.CategoryCPUCooler && !needsCooler(cpu, c) {
+			continue
+		}
+
+		part, ok := c.cheapestFitting(category, selected, func(candidate models.GeneratedBuildPart) bool {
+			switch category {
+			case models.CategoryMotherboard:
+				return fitsBoardFormFactor(candidate.Component, profile)
+			case models.CategoryCPUCooler:
+				return coolsCPU(candidate.Component, cpu.Component)
+			}
+			return true
+		})
+		if !ok {
+			return platform
+		}
+		selected = append(selected, part)
+		platform.parts = append(platform.parts, part)
+		platform.cost += part.UnitPrice
+	}
+
+	platform.ok = true
+	return platform
+}
+
+// chooseEnclosure adds the cheapest case and power supply that fit the selected parts
+func (c generatorCatalog) chooseEnclosure(selected []models.GeneratedBuildPart, profile generatorProfile) ([]models.GeneratedBuildPart, bool) {
+	pcCase, ok := c.cheapestFitting(models.CategoryCase, selected, func(candidate models.GeneratedBuildPart) bool {
+		return fitsCaseFormFactor(candidate.Component, profile)
+	})
+	if !ok {
+		return nil, false
+	}
+	selected = append(selected, pcCase)
+
+	draw, drawKnown := estimatePowerDraw(selected)
+	psu, ok := c.cheapestFitting(models.CategoryPowerSupply, selected, func(candidate models.GeneratedBuildPart) bool {
+		if !drawKnown {
+			return true
+		}
+		wattage, ok := utils.GetSpecNumber(utils.ParseSpecs(candidate.Component.Specs), constants.SPEC_WATTAGE)
+		return ok && wattage >= draw*constants.GENERATOR_PSU_HEADROOM
+	})
+	if !ok {
+		return nil, false
+	}
+	return append(selected, psu), true
+}
+
+// cheapestFitting returns the cheapest part of a category that passes accept and adds no compatibility error
+// to the selected parts
+func (c generatorCatalog) cheapestFitting(category models.Category, selected []models.GeneratedBuildPart, accept func(models.GeneratedBuildPart) bool) (models.GeneratedBuildPart, bool) {
+	for _, candidate := range c.byCategory[category] {
+		if !accept(candidate) {
+			continue
+		}
+		if fitsGeneratedBuild(append(selected[:len(selected):len(selected)], candidate), candidate.Component.ID, c.rules) {
+			return candidate, true
+		}
+	}
+	return models.GeneratedBuildPart{}, false
+}
+
+// fitsGeneratedBuild checks that no rule, storage or power connector error involves the component
+func fitsGeneratedBuild(selected []models.GeneratedBuildPart, componentID string, rules []models.CompatibilityRule) bool {
+	for _, issue := range buildErrors(generatedBuildParts(selected), rules) {
+		if issue.SourceComponentID == componentID || issue.TargetComponentID == componentID || issue.SourceComponentID == "" && issue.TargetComponentID == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// buildErrors returns the rule, storage and power connector issues of a build that are errors
+func buildErrors(parts []models.BuildComponentWithDetails, rules []models.CompatibilityRule) []models.CompatibilityIssue {
+	issues := EvaluateCompatibilityRules(parts, rules)
+	issues = append(issues, AllocateStorage(parts).Issues...)
+	if findPart(parts, models.CategoryPowerSupply) != nil {
+		issues = append(issues, AnalyzePowerConnectors(parts).Issues...)
+	}
+
+	errors := []models.CompatibilityIssue{}
+	for _, issue := range issues {
+		if issue.Severity == models.RuleSeverityError {
+			errors = append(errors, issue)
+		}
+	}
+	return errors
+}
+
+func generatedBuildParts(selected []models.GeneratedBuildPart) []models.BuildComponentWithDetails {
+	parts := make([]models.BuildComponentWithDetails, 0, len(selected))
+	for i := range selected {
+		id, _ := strconv.ParseInt(selected[i].Component.ID, 10, 64)
+		parts = append(parts, models.BuildComponentWithDetails{
+			BuildComponent: models.BuildComponent{ComponentID: id, Quantity: 1},
+			Component:      &selected[i].Component,
+		})
+	}
+	return parts
+}
+
+// needsCooler is false when the CPU ships with a cooler or a pinned liquid cooler takes its place
+func needsCooler(cpu models.GeneratedBuildPart, catalog generatorCatalog) bool {
+	for _, extra := range catalog.extras {
+		if extra.Component.Category == models.CategoryWaterCooling {
+			return false
+		}
+	}
+	if _, pinned := pinnedPart(catalog, models.CategoryCPUCooler); pinned {
+		return true
+	}
+	included, _ := utils.GetSpecBool(utils.ParseSpecs(cpu.Component.Specs), constants.SPEC_INCLUDES_COOLER)
+	return !included
+}
+
+func pinnedPart(catalog generatorCatalog, category models.Category) (models.GeneratedBuildPart, bool) {
+	list := catalog.byCategory[category]
+	if len(list) == 1 && list[0].Pinned {
+		return list[0], true
+	}
+	return models.GeneratedBuildPart{}, false
+}
+
+// coolsCPU accepts a cooler rated for at least the power of the CPU; coolers or CPUs without a rating are accepted
+func coolsCPU(cooler, cpu models.Component) bool {
+	rating, ok := utils.GetSpecNumber(utils.ParseSpecs(cooler.Specs), constants.SPEC_RATED_TDP, constants.SPEC_TDP)
+	if !ok {
+		return true
+	}
+	power, ok := utils.GetSpecNumber(utils.ParseSpecs(cpu.Specs), constants.SPEC_SUSTAINED_POWER, constants.SPEC_TDP)
+	return !ok || rating >= power
+}
+
+func fitsBoardFormFactor(board models.Component, profile generatorProfile) bool {
+	if profile.boardFormFactor == "" {
+		return true
+	}
+	formFactor, ok := utils.GetSpecString(utils.ParseSpecs(board.Specs), constants.SPEC_FORM_FACTOR)
+	return ok && strings.EqualFold(formFactor, profile.boardFormFactor)
+}
+
+// fitsCaseFormFactor limits small form factor builds to cases that take nothing larger than their board
+func fitsCaseFormFactor(pcCase models.Component, profile generatorProfile) bool {
+	if profile.boardFormFactor == "" {
+		return true
+	}
+	supported, ok := utils.GetSpecStringList(utils.ParseSpecs(pcCase.Specs), constants.SPEC_SUPPORTED_FORM_FACTORS)
+	if !ok || len(supported) == 0 {
+		return false
+	}
+	for _, formFactor := range supported {
+		if !strings.EqualFold(formFactor, profile.boardFormFactor) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasIntegratedGraphics(cpu models.Component) bool {
+	integrated, _ := utils.GetSpecBool(utils.ParseSpecs(cpu.Specs), constants.SPEC_INTEGRATED_GRAPHICS)
+	return integrated
+}
+
+// estimatePowerDraw adds the power of the CPU and video card to an allowance for the rest of the build.
+// known is false when the CPU does not list its power.
+func estimatePowerDraw(selected []models.GeneratedBuildPart) (draw float64, known bool) {
+	draw = constants.GENERATOR_PLATFORM_POWER_W
+	for _, part := range selected {
+		power, ok := componentPowerDraw(part.Component)
+		if !ok {
+			return 0, false
+		}
+		draw += power
+	}
+	return draw, true
+}
+
+// componentPowerDraw is the power one unit of a CPU or video card adds to estimatePowerDraw; other parts are
+// covered by the platform allowance. ok is false for a CPU that does not list its power.
+func componentPowerDraw(component models.Component) (power float64, ok bool) {
+	specs := utils.ParseSpecs(component.Specs)
+	switch component.Category {
+	case models.CategoryCPU:
+		return utils.GetSpecNumber(specs, constants.SPEC_SUSTAINED_POWER, constants.SPEC_TDP)
+	case models.CategoryVideoCard:
+		if power, ok := utils.GetSpecNumber(specs, constants.SPEC_TDP); ok {
+			return power, true
+		}
+	}
+	return 0, true
+}
+
+// generatedBuildScore weighs the CPU score and the GPU score on the CPU's scale for the use case
+func generatedBuildScore(profile generatorProfile, resolution string, cpu models.GeneratedBuildPart, gpu *models.GeneratedBuildPart) float64 {
+	cpuScore, _ := PerformanceScore(cpu.Component, profile.useCase)
+	if !profile.needsGPU || gpu == nil {
+		return roundTo(cpuScore, 1)
+	}
+	gpuScore, _ := PerformanceScore(gpu.Component, profile.useCase)
+	gpuEffective := effectiveGPUScore(gpuScore, profile.useCase, resolution)
+	return roundTo(profile.cpuWeight*cpuScore+(1-profile.cpuWeight)*gpuEffective, 1)
+}
+
+func newGeneratedBuild(selected []models.GeneratedBuildPart, currency string) models.GeneratedBuild {
+	build := models.GeneratedBuild{Parts: selected, Currency: currency, Reasons: []string{}}
+	for _, part := range selected {
+		build.Total += part.UnitPrice
+	}
+	build.Total = roundTo(build.Total, 2)
+	return build
+}
+
+// explainGeneratedBuild lists why the generator settled on a build
+func explainGeneratedBuild(build models.GeneratedBuild, request models.BuildGeneratorRequest, profile generatorProfile) []string {
+	reasons := []string{
+		fmt.Sprintf("Costs %.2f of the %.2f %s budget, leaving %.2f", build.Total, request.Budget, request.Currency, roundTo(request.Budget-build.Total, 2)),
+	}
+
+	parts := generatedBuildParts(build.Parts)
+	cpu, gpu := findPart(parts, models.CategoryCPU), findPart(parts, models.CategoryVideoCard)
+	workload := describeWorkload(profile.useCase, request.Resolution)
+	if cpu != nil && gpu != nil && profile.needsGPU {
+		report := AnalyzeBottleneck(parts, profile.useCase, request.Resolution)
+		reasons = append(reasons, fmt.Sprintf("%s %s with %s %s scores %.1f for %s, the highest of the combinations within budget",
+			cpu.Brand, cpu.Model, gpu.Brand, gpu.Model, build.Score, workload))
+		if report.Summary != "" {
+			reasons = append(reasons, report.Summary)
+		}
+	} else if cpu != nil {
+		reasons = append(reasons, fmt.Sprintf("%s %s scores %.1f for %s, the highest of the CPUs within budget", cpu.Brand, cpu.Model, build.Score, workload))
+		if gpu == nil {
+			reasons = append(reasons, fmt.Sprintf("%s has integrated graphics, so no video card is needed", cpu.Model))
+		} else if !profile.needsGPU {
+			reasons = append(reasons, fmt.Sprintf("%s is the cheapest video card that fits, since %s has no integrated graphics", gpu.Model, cpu.Model))
+		}
+	}
+
+	if profile.boardFormFactor != "" {
+		reasons = append(reasons, fmt.Sprintf("Limited to %s motherboards and cases for a small form factor build", profile.boardFormFactor))
+	}
+	if cpu != nil && findPart(parts, models.CategoryCPUCooler) == nil && findPart(parts, models.CategoryWaterCooling) == nil {
+		reasons = append(reasons, fmt.Sprintf("%s includes a cooler, so none was added", cpu.Model))
+	}
+	if draw, ok := estimatePowerDraw(build.Parts); ok {
+		if psu := findPart(parts, models.CategoryPowerSupply); psu != nil {
+			if wattage, ok := utils.GetSpecNumber(utils.ParseSpecs(psu.Specs), constants.SPEC_WATTAGE); ok {
+				reasons = append(reasons, fmt.Sprintf("%s provides %.0f W for an estimated draw of %.0f W", psu.Model, wattage, draw))
+			}
+		}
+	}
+
+	pinned := []string{}
+	for _, part := range build.Parts {
+		if part.Pinned {
+			pinned = append(pinned, part.Component.Brand+" "+part.Component.Model)
+		}
+	}
+	if len(pinned) > 0 {
+		reasons = append(reasons, "Kept the pinned parts: "+strings.Join(pinned, ", "))
+	}
+	reasons = append(reasons, "The other parts are the cheapest in-stock options compatible with the rest of the build")
+	return reasons
+}
+
+// explainAlternative compares a runner-up with the chosen build
+func explainAlternative(alternative, best models.GeneratedBuild, request models.BuildGeneratorRequest) []string {
+	parts := generatedBuildParts(alternative.Parts)
+	names := []string{}
+	for _, category := range []models.Category{models.CategoryCPU, models.CategoryVideoCard} {
+		if part := findPart(parts, category); part != nil {
+			names = append(names, part.Brand+" "+part.Model)
+		}
+	}
+
+	reason := fmt.Sprintf("%s scores %.1f for %.2f %s", strings.Join(names, " with "), alternative.Score, alternative.Total, request.Currency)
+	if saving := roundTo(best.Total-alternative.Total, 2); saving > 0 {
+		reason += fmt.Sprintf(", %.2f less than the best build", saving)
+	}
+	return []string{reason}
+}
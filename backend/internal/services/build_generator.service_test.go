@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func catalogPart(category models.Category, id, model, specs string, price float64) models.GeneratedBuildPart {
+	return models.GeneratedBuildPart{
+		Component: models.Component{ID: id, Category: category, Brand: "Test", Model: model, Specs: []byte(specs)},
+		UnitPrice: price,
+	}
+}
+
+func generatedPartIDs(build models.GeneratedBuild) map[models.Category]string {
+	ids := map[models.Category]string{}
+	for _, part := range build.Parts {
+		ids[part.Component.Category] = part.Component.ID
+	}
+	return ids
+}
+
+// TestGenerateBuildFromCatalog tests the search for the best compatible build within budget
+func TestGenerateBuildFromCatalog(t *testing.T) {
+	socketRule := models.CompatibilityRule{
+		ID:             "cpu-motherboard-socket",
+		SourceCategory: models.CategoryCPU,
+		TargetCategory: models.CategoryMotherboard,
+		Comparisons:    []models.RuleComparison{{SourceKey: "socket", Operator: models.SpecOperatorEquals, TargetKey: "socket"}},
+		Severity:       models.RuleSeverityError,
+		Message:        "socket mismatch",
+	}
+	rules := []models.CompatibilityRule{socketRule}
+
+	catalog := []models.GeneratedBuildPart{
+		catalogPart(models.CategoryCPU, "1", "Budget CPU", `{"socket": "AM4", "tdp": 65, "includes_cooler": true, "integrated_graphics": true, "performance_gaming": 40, "performance_multi_thread": 35}`, 100),
+		catalogPart(models.CategoryCPU, "2", "Fast CPU", `{"socket": "AM5", "tdp": 120, "performance_gaming": 90, "performance_multi_thread": 90}`, 400),
+		catalogPart(models.CategoryCPU, "3", "Slow expensive CPU", `{"socket": "AM5", "tdp": 120, "performance_gaming": 30}`, 500),
+		catalogPart(models.CategoryVideoCard, "10", "Entry GPU", `{"tdp": 100, "performance_gaming": 40}`, 200),
+		catalogPart(models.CategoryVideoCard, "11", "High end GPU", `{"tdp": 300, "performance_gaming": 90}`, 800),
+		catalogPart(models.CategoryMotherboard, "20", "AM4 ATX", `{"socket": "AM4", "form_factor": "ATX", "m2_slots": [{"name": "M2_1", "interfaces": ["nvme"]}]}`, 80),
+		catalogPart(models.CategoryMotherboard, "21", "AM5 ATX", `{"socket": "AM5", "form_factor": "ATX", "m2_slots": [{"name": "M2_1", "interfaces": ["nvme"]}]}`, 150),
+		catalogPart(models.CategoryMotherboard, "22", "AM5 ITX", `{"socket": "AM5", "form_factor": "Mini-ITX", "m2_slots": [{"name": "M2_1", "interfaces": ["nvme"]}]}`, 220),
+		catalogPart(models.CategoryMemory, "30", "32GB DDR5", `{}`, 90),
+		catalogPart(models.CategoryCPUCooler, "40", "Small cooler", `{"rated_tdp": 95}`, 20),
+		catalogPart(models.CategoryCPUCooler, "41", "Tower cooler", `{"rated_tdp": 220}`, 45),
+		catalogPart(models.CategoryInternalHDD, "50", "1TB NVMe", `{"interface": "PCIe 4.0 x4 NVMe", "form_factor": "M.2-2280"}`, 60),
+		catalogPart(models.CategoryCase, "60", "ATX tower", `{"supported_form_factors": ["ATX", "Micro-ATX", "Mini-ITX"]}`, 70),
+		catalogPart(models.CategoryCase, "61", "ITX box", `{"supported_form_factors": ["Mini-ITX"]}`, 110),
+		catalogPart(models.CategoryPowerSupply, "70", "450W", `{"wattage": 450}`, 50),
+		catalogPart(models.CategoryPowerSupply, "71", "850W", `{"wattage": 850}`, 110),
+	}
+
+	t.Run("Spends the budget on the best CPU and video card", func(t *testing.T) {
+		request := models.BuildGeneratorRequest{Budget: 2000, Currency: "USD", Region: "USA", UseCase: constants.USE_CASE_GAMING, Resolution: constants.RESOLUTION_1440P}
+
+		result, err := GenerateBuildFromCatalog(request, catalog, rules)
+
+		require.NoError(t, err)
+		ids := generatedPartIDs(result.Build)
+		assert.Equal(t, "2", ids[models.CategoryCPU])
+		assert.Equal(t, "11", ids[models.CategoryVideoCard])
+		assert.Equal(t, "21", ids[models.CategoryMotherboard])
+		assert.Equal(t, "41", ids[models.CategoryCPUCooler])
+		assert.Equal(t, "71", ids[models.CategoryPowerSupply])
+		assert.LessOrEqual(t, result.Build.Total, request.Budget)
+		assert.NotEmpty(t, result.Build.Reasons)
+		assert.NotEmpty(t, result.Alternatives)
+		assert.LessOrEqual(t, len(result.Alternatives), constants.GENERATOR_MAX_ALTERNATIVES)
+		for _, alternative := range result.Alternatives {
+			assert.LessOrEqual(t, alternative.Score, result.Build.Score)
+		}
+	})
+
+	t.Run("Smaller budget settles for cheaper parts", func(t *testing.T) {
+		request := models.BuildGeneratorRequest{Budget: 700, Currency: "USD", UseCase: constants.USE_CASE_GAMING, Resolution: constants.RESOLUTION_1440P}
+
+		result, err := GenerateBuildFromCatalog(request, catalog, rules)
+
+		require.NoError(t, err)
+		ids := generatedPartIDs(result.Build)
+		assert.Equal(t, "1", ids[models.CategoryCPU])
+		assert.Equal(t, "10", ids[models.CategoryVideoCard])
+		assert.NotContains(t, ids, models.CategoryCPUCooler)
+		assert.LessOrEqual(t, result.Build.Total, request.Budget)
+	})
+
+	t.Run("Office build uses integrated graphics", func(t *testing.T) {
+		request := models.BuildGeneratorRequest{Budget: 600, Currency: "USD", UseCase: constants.USE_CASE_OFFICE}
+
+		result, err := GenerateBuildFromCatalog(request, catalog, rules)
+
+		require.NoError(t, err)
+		ids := generatedPartIDs(result.Build)
+		assert.Equal(t, "1", ids[models.CategoryCPU])
+		assert.NotContains(t, ids, models.CategoryVideoCard)
+	})
+
+	t.Run("Small form factor build uses Mini-ITX parts", func(t *testing.T) {
+		request := models.BuildGeneratorRequest{Budget: 2000, Currency: "USD", UseCase: constants.USE_CASE_SFF, Resolution: constants.RESOLUTION_1440P}
+
+		result, err := GenerateBuildFromCatalog(request, catalog, rules)
+
+		require.NoError(t, err)
+		ids := generatedPartIDs(result.Build)
+		assert.Equal(t, "22", ids[models.CategoryMotherboard])
+		assert.Equal(t, "61", ids[models.CategoryCase])
+	})
+
+	t.Run("Keeps pinned parts", func(t *testing.T) {
+		pinned := catalogPart(models.CategoryVideoCard, "10", "Entry GPU", `{"tdp": 100, "performance_gaming": 40}`, 200)
+		pinned.Pinned = true
+		request := models.BuildGeneratorRequest{Budget: 2000, Currency: "USD", UseCase: constants.USE_CASE_GAMING, Resolution: constants.RESOLUTION_1440P}
+
+		result, err := GenerateBuildFromCatalog(request, append([]models.GeneratedBuildPart{pinned}, catalog...), rules)
+
+		require.NoError(t, err)
+		ids := generatedPartIDs(result.Build)
+		assert.Equal(t, "10", ids[models.CategoryVideoCard])
+		assert.Equal(t, "2", ids[models.CategoryCPU])
+		assert.Contains(t, result.Build.Reasons[len(result.Build.Reasons)-2], "Entry GPU")
+	})
+
+	t.Run("Two pinned parts of one category", func(t *testing.T) {
+		first := catalogPart(models.CategoryCPU, "1", "Budget CPU", `{}`, 100)
+		second := catalogPart(models.CategoryCPU, "2", "Fast CPU", `{}`, 400)
+		first.Pinned, second.Pinned = true, true
+
+		_, err := GenerateBuildFromCatalog(models.BuildGeneratorRequest{Budget: 2000, UseCase: constants.USE_CASE_GAMING}, []models.GeneratedBuildPart{first, second}, rules)
+
+		assert.True(t, errors.Is(err, ErrInvalidBuild))
+	})
+
+	t.Run("Budget too low", func(t *testing.T) {
+		request := models.BuildGeneratorRequest{Budget: 300, Currency: "USD", UseCase: constants.USE_CASE_GAMING, Resolution: constants.RESOLUTION_1080P}
+
+		_, err := GenerateBuildFromCatalog(request, catalog, rules)
+
+		assert.True(t, errors.Is(err, ErrBudgetTooLow))
+	})
+}
+
+// TestNormalizeGeneratorRequest tests defaults and validation of generator requests
+func TestNormalizeGeneratorRequest(t *testing.T) {
+	request := models.BuildGeneratorRequest{Budget: 1200, Currency: "eur", UseCase: " SFF ", PinnedComponentIDs: []int64{5, 5, 7}}
+
+	require.NoError(t, normalizeGeneratorRequest(&request))
+	assert.Equal(t, "EUR", request.Currency)
+	assert.Equal(t, constants.DEFAULT_BUILD_REGION, request.Region)
+	assert.Equal(t, constants.USE_CASE_SFF, request.UseCase)
+	assert.Equal(t, constants.DEFAULT_RESOLUTION, request.Resolution)
+	assert.Equal(t, []int64{5, 7}, request.PinnedComponentIDs)
+
+	office := models.BuildGeneratorRequest{Budget: 500, UseCase: constants.USE_CASE_OFFICE, Resolution: "4k"}
+	require.NoError(t, normalizeGeneratorRequest(&office))
+	assert.Empty(t, office.Resolution)
+
+	invalid := []models.BuildGeneratorRequest{
+		{Budget: 0},
+		{Budget: 1000, UseCase: "mining"},
+		{Budget: 1000, Resolution: "8k"},
+		{Budget: 1000, PinnedComponentIDs: []int64{-1}},
+	}
+	for _, request := range invalid {
+		assert.True(t, errors.Is(normalizeGeneratorRequest(&request), ErrInvalidBuild))
+	}
+}
@@ -0,0 +1,493 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const defaultImportedBuildName = "Imported build"
+
+// partsListCategories maps the labels used by parts lists to categories. Labels are lower case with
+// underscores replaced by spaces, so the category values themselves are recognized too.
+var partsListCategories = map[string]models.Category{
+	"cpu":                      models.CategoryCPU,
+	"processor":                models.CategoryCPU,
+	"cpu cooler":               models.CategoryCPUCooler,
+	"cooler":                   models.CategoryCPUCooler,
+	"motherboard":              models.CategoryMotherboard,
+	"mobo":                     models.CategoryMotherboard,
+	"mainboard":                models.CategoryMotherboard,
+	"memory":                   models.CategoryMemory,
+	"ram":                      models.CategoryMemory,
+	"storage":                  models.CategoryInternalHDD,
+	"internal hdd":             models.CategoryInternalHDD,
+	"hard drive":               models.CategoryInternalHDD,
+	"ssd":                      models.CategoryInternalHDD,
+	"hdd":                      models.CategoryInternalHDD,
+	"video card":               models.CategoryVideoCard,
+	"graphics card":            models.CategoryVideoCard,
+	"gpu":                      models.CategoryVideoCard,
+	"case":                     models.CategoryCase,
+	"chassis":                  models.CategoryCase,
+	"power supply":             models.CategoryPowerSupply,
+	"psu":                      models.CategoryPowerSupply,
+	"operating system":         models.CategoryOS,
+	"os":                       models.CategoryOS,
+	"monitor":                  models.CategoryMonitor,
+	"case fan":                 models.CategoryCaseFan,
+	"fan":                      models.CategoryCaseFan,
+	"case accessory":           models.CategoryCaseAccessory,
+	"fan controller":           models.CategoryFanController,
+	"thermal paste":            models.CategoryThermalPaste,
+	"thermal compound":         models.CategoryThermalPaste,
+	"water cooling":            models.CategoryWaterCooling,
+	"custom water cooling":     models.CategoryWaterCooling,
+	"sound card":               models.CategorySoundCard,
+	"wired network card":       models.CategoryWiredNetworkCard,
+	"wired network adapter":    models.CategoryWiredNetworkCard,
+	"wireless network card":    models.CategoryWirelessNetworkCard,
+	"wireless network adapter": models.CategoryWirelessNetworkCard,
+	"optical drive":            models.CategoryOpticalDrive,
+	"external hdd":             models.CategoryExternalHDD,
+	"external storage":         models.CategoryExternalHDD,
+	"keyboard":                 models.CategoryKeyboard,
+	"mouse":                    models.CategoryMouse,
+	"headphone":                models.CategoryHeadphone,
+	"speaker":                  models.CategorySpeaker,
+	"webcam":                   models.CategoryWebcam,
+	"ups":                      models.CategoryUPS,
+	"other":                    models.CategoryOther,
+	"custom":                   models.CategoryOther,
+}
+
+// partsListSkippedLabels are labels of lines that describe the list rather than a component
+var partsListSkippedLabels = map[string]bool{
+	"total":          true,
+	"base total":     true,
+	"promo discount": true,
+	"shipping":       true,
+	"tax":            true,
+	"price":          true,
+	"generated by":   true,
+}
+
+var (
+	markdownLinkPattern  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	bbcodeTagPattern     = regexp.MustCompile(`\[/?[a-zA-Z*]+(=[^\]]*)?\]`)
+	markdownEscape       = regexp.MustCompile(`\\([\\|*_\x60\[\]<#])`)
+	bulletPattern        = regexp.MustCompile(`^([-*•+]|\d+[.)])\s+`)
+	tableSeparatorCell   = regexp.MustCompile(`^:?-{3,}:?$`)
+	parenthesizedPrice   = regexp.MustCompile(`\s*\((\s*[$€£][^)]*|[^)]*@[^)]*)\)\s*$`)
+	trailingPrice        = regexp.MustCompile(`\s+[-–]\s+(no price|[$€£]\s*\d[\d.,]*|\d[\d.,]*\s+[A-Z]{3})(\s.*)?$`)
+	trailingRetailer     = regexp.MustCompile(`\s+@\s+.*$`)
+	leadingQuantity      = regexp.MustCompile(`^(\d+)\s*[x×]\s+`)
+	trailingQuantity     = regexp.MustCompile(`\s+[x×]\s?(\d+)$|\s+\((\d+)\s*[x×]?\)$`)
+	partsListLabelFormat = regexp.MustCompile(`^([A-Za-z][A-Za-z _/]{0,30}):\s*(.*)$`)
+)
+
+// ImportBuild creates a build owned by userID from a pasted parts list. Lines that match a component
+// confidently are added to the build; the others are returned with their closest candidates.
+func ImportBuild(userID string, input models.BuildImport) (models.BuildImportResult, error) {
+	utils.Log(constants.SERVICE_IMPORT_BUILD_START, nil, userID)
+
+	create := models.UserBuildCreate{
+		UserID:      userID,
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		IsPublic:    input.IsPublic,
+		Currency:    input.Currency,
+		Region:      input.Region,
+	}
+	if create.Name == "" {
+		create.Name = defaultImportedBuildName
+	}
+	if err := normalizeBuildCreate(&create); err != nil {
+		utils.Log(constants.SERVICE_IMPORT_BUILD_ERROR, err, userID)
+		return models.BuildImportResult{}, err
+	}
+
+	lines := ParsePartsList(input.Text)
+	if err := validatePartsList(lines); err != nil {
+		utils.Log(constants.SERVICE_IMPORT_BUILD_ERROR, err, userID)
+		return models.BuildImportResult{}, err
+	}
+
+	result := models.BuildImportResult{Matched: []models.ImportedPart{}, Unmatched: []models.UnmatchedPart{}}
+	parts := []models.BuildVersionPart{}
+	partIndex := map[int64]int{}
+	for _, line := range lines {
+		candidates, err := findComponentMatches(line)
+		if err != nil {
+			utils.Log(constants.SERVICE_IMPORT_BUILD_ERROR, err, userID)
+			return models.BuildImportResult{}, err
+		}
+
+		match, ok := pickComponentMatch(candidates)
+		if !ok {
+			if len(candidates) > constants.IMPORT_MAX_CANDIDATES {
+				candidates = candidates[:constants.IMPORT_MAX_CANDIDATES]
+			}
+			result.Unmatched = append(result.Unmatched, models.UnmatchedPart{PartsListLine: line, Candidates: candidates})
+			continue
+		}
+		componentID, err := strconv.ParseInt(match.Component.ID, 10, 64)
+		if err != nil {
+			utils.Log(constants.SERVICE_IMPORT_BUILD_ERROR, err, userID)
+			return models.BuildImportResult{}, err
+		}
+
+		result.Matched = append(result.Matched, models.ImportedPart{PartsListLine: line, Match: match})
+		// A component listed on several lines is added once with the quantities summed
+		if i, ok := partIndex[componentID]; ok {
+			parts[i].Quantity += line.Quantity
+			continue
+		}
+		partIndex[componentID] = len(parts)
+		parts = append(parts, models.BuildVersionPart{ComponentID: componentID, Quantity: line.Quantity})
+	}
+	if err := validateImportedQuantities(parts); err != nil {
+		utils.Log(constants.SERVICE_IMPORT_BUILD_ERROR, err, userID)
+		return models.BuildImportResult{}, err
+	}
+
+	build, err := repository.CreateUserBuildWithParts(create, parts, buildSnapshot(userID, fmt.Sprintf("Imported %d components from a parts list", len(parts))))
+	if err != nil {
+		utils.Log(constants.SERVICE_IMPORT_BUILD_ERROR, err, userID)
+		return models.BuildImportResult{}, err
+	}
+
+	if result.Build, err = withBuildDetails(build); err != nil {
+		utils.Log(constants.SERVICE_IMPORT_BUILD_ERROR, err, userID)
+		return models.BuildImportResult{}, err
+	}
+
+	utils.Log(constants.SERVICE_IMPORT_BUILD_SUCCESS, nil, build.ID, len(result.Matched), len(result.Unmatched))
+	return result, nil
+}
+
+func validatePartsList(lines []models.PartsListLine) error {
+	if len(lines) == 0 {
+		return fmt.Errorf("%w: text does not list any parts", ErrInvalidBuild)
+	}
+	if len(lines) > constants.IMPORT_MAX_LINES {
+		return fmt.Errorf("%w: text lists %d parts, at most %d can be imported at once", ErrInvalidBuild, len(lines), constants.IMPORT_MAX_LINES)
+	}
+	for _, line := range lines {
+		if line.Quantity > constants.MAX_PART_QUANTITY {
+			return fmt.Errorf("%w: line %d asks for %d units, at most %d are allowed", ErrInvalidBuild, line.Line, line.Quantity, constants.MAX_PART_QUANTITY)
+		}
+	}
+	return nil
+}
+
+// validateImportedQuantities checks the quantities of components listed on several lines once they are summed
+func validateImportedQuantities(parts []models.BuildVersionPart) error {
+	for _, part := range parts {
+		if part.Quantity > constants.MAX_PART_QUANTITY {
+			return fmt.Errorf("%w: component %d is listed %d times, at most %d are allowed", ErrInvalidBuild, part.ComponentID, part.Quantity, constants.MAX_PART_QUANTITY)
+		}
+	}
+	return nil
+}
+
+// findComponentMatches scores the components sharing a term with the line, best first
+func findComponentMatches(line models.PartsListLine) ([]models.ComponentMatch, error) {
+	category := ""
+	if line.Category != nil {
+		category = string(*line.Category)
+	}
+
+	components, err := repository.FindComponentCandidates(category, partsListTerms(line.Query), constants.IMPORT_CANDIDATE_POOL)
+	if err != nil {
+		return nil, err
+	}
+	return RankComponentMatches(line.Query, components), nil
+}
+
+// RankComponentMatches scores each component against a query, best first
+func RankComponentMatches(query string, components []models.Component) []models.ComponentMatch {
+	matches := make([]models.ComponentMatch, 0, len(components))
+	for _, component := range components {
+		matches = append(matches, models.ComponentMatch{Component: component, Score: ScoreComponentMatch(query, component)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// pickComponentMatch returns the best candidate when it scores high enough and clearly above the next one.
+// A runner-up whose model is part of the best one's, such as "RTX 4070" behind "RTX 4070 Ti", does not make
+// the match ambiguous: the query named the longer model.
+func pickComponentMatch(candidates []models.ComponentMatch) (models.ComponentMatch, bool) {
+	if len(candidates) == 0 || candidates[0].Score < constants.IMPORT_MATCH_THRESHOLD {
+		return models.ComponentMatch{}, false
+	}
+	best := candidates[0]
+	for _, other := range candidates[1:] {
+		if best.Score-other.Score >= constants.IMPORT_MATCH_MARGIN {
+			break
+		}
+		if !strings.Contains(compactName(best.Component.Model), compactName(other.Component.Model)) {
+			return models.ComponentMatch{}, false
+		}
+	}
+	return best, true
+}
+
+// ScoreComponentMatch rates from 0 to 1 how well a parts list entry names a component. Names are compared
+// as trigrams of their letters and digits, so spacing, punctuation and case do not matter. Most of the
+// score is how much of the model appears in the query, since lists often add clock speeds or core counts;
+// the rest is the similarity of the whole name, which prefers "RTX 4070 Ti" over "RTX 4070" for a query
+// naming the former and lets the brand count without being required.
+func ScoreComponentMatch(query string, component models.Component) float64 {
+	queryGrams := trigrams(compactName(query))
+	modelGrams := trigrams(compactName(component.Model))
+	nameGrams := trigrams(compactName(component.Brand + component.Model))
+	if len(queryGrams) == 0 || len(modelGrams) == 0 {
+		return 0
+	}
+
+	recall := float64(sharedGrams(modelGrams, queryGrams)) / float64(len(modelGrams))
+	similarity := 2 * float64(sharedGrams(nameGrams, queryGrams)) / float64(len(nameGrams)+len(queryGrams))
+	return math.Round((0.8*recall+0.2*similarity)*1000) / 1000
+}
+
+// ParsePartsList finds the lines naming components in plain text, Markdown or BBCode parts lists,
+// including Markdown tables such as PCPartPicker's and the ones produced by the export endpoint
+func ParsePartsList(text string) []models.PartsListLine {
+	lines := []models.PartsListLine{}
+	var columns map[string]int
+
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			columns = nil
+			continue
+		}
+
+		var label, item, quantity string
+		if cells := splitTableRow(trimmed); cells != nil {
+			if isTableSeparator(cells) {
+				continue
+			}
+			if header := tableHeaderColumns(cells); header != nil {
+				columns = header
+				continue
+			}
+			label, item, quantity = tableCell(cells, columns, "category", 0), tableCell(cells, columns, "item", 1), tableCell(cells, columns, "quantity", -1)
+			if len(cells) == 1 {
+				label, item = "", cells[0]
+			}
+		} else {
+			item = trimmed
+		}
+
+		item = cleanPartsListText(bulletPattern.ReplaceAllString(item, ""))
+		label = normalizePartsListLabel(cleanPartsListText(label))
+		if label == "" {
+			if m := partsListLabelFormat.FindStringSubmatch(item); m != nil {
+				if _, ok := lookupPartsListCategory(normalizePartsListLabel(m[1])); ok || partsListSkippedLabels[normalizePartsListLabel(m[1])] {
+					label, item = normalizePartsListLabel(m[1]), m[2]
+				}
+			}
+		}
+		if partsListSkippedLabels[label] || isPartsListNote(item) {
+			continue
+		}
+
+		line := models.PartsListLine{Line: i + 1, Text: trimmed, Quantity: 1}
+		if category, ok := lookupPartsListCategory(label); ok {
+			line.Category = &category
+		}
+		line.Query, line.Quantity = splitPartsListQuantity(item)
+		if n, err := strconv.Atoi(strings.TrimSpace(quantity)); err == nil && n > 0 {
+			line.Quantity = n
+		}
+		if !strings.ContainsFunc(line.Query, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// isPartsListNote reports whether an entry describes the list, such as its title, total or a link to it
+func isPartsListNote(item string) bool {
+	lower := strings.ToLower(item)
+	for _, prefix := range []string{"generated by", "prices include", "http://", "https://"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return partsListSkippedLabels[normalizePartsListLabel(item)] || strings.Contains(lower, "part list")
+}
+
+// splitTableRow returns the cells of a Markdown table row or a tab separated line, nil for other lines
+func splitTableRow(line string) []string {
+	separator := "|"
+	if !strings.Contains(line, "|") {
+		if !strings.Contains(line, "\t") {
+			return nil
+		}
+		separator = "\t"
+	}
+
+	// Escaped pipes belong to the cell text
+	line = strings.ReplaceAll(line, `\|`, "\x00")
+	line = strings.TrimSuffix(strings.TrimPrefix(line, separator), separator)
+	cells := strings.Split(line, separator)
+	for i := range cells {
+		cells[i] = strings.TrimSpace(strings.ReplaceAll(cells[i], "\x00", `\|`))
+	}
+	return cells
+}
+
+func isTableSeparator(cells []string) bool {
+	for _, cell := range cells {
+		if !tableSeparatorCell.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// tableHeaderColumns returns the position of the category, item and quantity columns when the cells are a
+// table header, nil otherwise
+func tableHeaderColumns(cells []string) map[string]int {
+	columns := map[string]int{}
+	for i, cell := range cells {
+		switch strings.ToLower(strings.Trim(cell, "*_ ")) {
+		case "type", "category":
+			columns["category"] = i
+		case "item", "part", "component", "product":
+			columns["item"] = i
+		case "qty", "quantity":
+			columns["quantity"] = i
+		}
+	}
+	if _, ok := columns["item"]; !ok {
+		return nil
+	}
+	return columns
+}
+
+func tableCell(cells []string, columns map[string]int, name string, fallback int) string {
+	i, ok := columns[name]
+	if !ok {
+		if columns != nil {
+			return ""
+		}
+		i = fallback
+	}
+	if i < 0 || i >= len(cells) {
+		return ""
+	}
+	return cells[i]
+}
+
+// cleanPartsListText strips links, markup, prices and retailers from a parts list entry
+func cleanPartsListText(text string) string {
+	text = markdownLinkPattern.ReplaceAllString(text, "$1")
+	text = bbcodeTagPattern.ReplaceAllString(text, "")
+	text = strings.NewReplacer("**", "", "__", "", "*", "", "`", "").Replace(text)
+	text = markdownEscape.ReplaceAllString(text, "$1")
+	text = strings.TrimSpace(text)
+	text = parenthesizedPrice.ReplaceAllString(text, "")
+	text = trailingPrice.ReplaceAllString(text, "")
+	text = trailingRetailer.ReplaceAllString(text, "")
+	return strings.TrimSpace(text)
+}
+
+func normalizePartsListLabel(label string) string {
+	label = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(label), ":")))
+	return strings.Join(strings.Fields(strings.ReplaceAll(label, "_", " ")), " ")
+}
+
+// lookupPartsListCategory resolves a label, accepting plurals such as "Case Fans"
+func lookupPartsListCategory(label string) (models.Category, bool) {
+	if category, ok := partsListCategories[label]; ok {
+		return category, true
+	}
+	category, ok := partsListCategories[strings.TrimSuffix(label, "s")]
+	return category, ok
+}
+
+// splitPartsListQuantity reads a quantity written as "2x Fan", "2 x Fan", "Fan x2" or "Fan (2)"
+func splitPartsListQuantity(item string) (string, int) {
+	item = strings.TrimSpace(item)
+	if m := leadingQuantity.FindStringSubmatch(item); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			return strings.TrimSpace(item[len(m[0]):]), n
+		}
+	}
+	if m := trailingQuantity.FindStringSubmatchIndex(item); m != nil {
+		digits := ""
+		for _, group := range []int{2, 4} {
+			if m[group] >= 0 {
+				digits = item[m[group]:m[group+1]]
+			}
+		}
+		if n, err := strconv.Atoi(digits); err == nil && n > 0 {
+			return strings.TrimSpace(item[:m[0]]), n
+		}
+	}
+	return item, 1
+}
+
+// partsListTerms splits a query into the lower case words used to look up candidates
+func partsListTerms(query string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, word := range strings.FieldsFunc(query, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		term := compactName(word)
+		if len(term) < 2 || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// compactName lower cases a name and drops everything but letters and digits
+func compactName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func trigrams(s string) map[string]bool {
+	grams := map[string]bool{}
+	runes := []rune(s)
+	if len(runes) > 0 && len(runes) < 3 {
+		grams[s] = true
+		return grams
+	}
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])] = true
+	}
+	return grams
+}
+
+func sharedGrams(a, b map[string]bool) int {
+	shared := 0
+	for gram := range a {
+		if b[gram] {
+			shared++
+		}
+	}
+	return shared
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParsePartsList tests reading the parts of common parts list formats
+func TestParsePartsList(t *testing.T) {
+	type part struct {
+		category models.Category
+		query    string
+		quantity int
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected []part
+	}{
+		{
+			name: "PCPartPicker text",
+			text: `[PCPartPicker Part List](https://pcpartpicker.com/list/abc123)
+
+CPU: AMD Ryzen 7 7800X3D 4.2 GHz 8-Core Processor  ($449.00 @ Amazon)
+Memory: Corsair Vengeance 32 GB (2 x 16 GB) DDR5-6000 CL30 Memory  ($104.99 @ Newegg)
+Case Fan: ARCTIC P12 PST 56.3 CFM 120 mm Fan  ($6.99 @ Amazon)
+Case Fan: ARCTIC P12 PST 56.3 CFM 120 mm Fan  ($6.99 @ Amazon)
+Total: $568.97
+Prices include shipping, taxes, and discounts when available
+Generated by PCPartPicker 2024-05-01 10:00 EDT-0400`,
+			expected: []part{
+				{models.CategoryCPU, "AMD Ryzen 7 7800X3D 4.2 GHz 8-Core Processor", 1},
+				{models.CategoryMemory, "Corsair Vengeance 32 GB (2 x 16 GB) DDR5-6000 CL30 Memory", 1},
+				{models.CategoryCaseFan, "ARCTIC P12 PST 56.3 CFM 120 mm Fan", 1},
+				{models.CategoryCaseFan, "ARCTIC P12 PST 56.3 CFM 120 mm Fan", 1},
+			},
+		},
+		{
+			name: "PCPartPicker Markdown table",
+			text: `Type|Item|Price
+:----|:----|:----
+**CPU** | [AMD Ryzen 7 7800X3D 4.2 GHz 8-Core Processor](https://pcpartpicker.com/product/abc) | $449.00 @ Amazon
+**Video Card** | [Gigabyte WINDFORCE GeForce RTX 4070 SUPER 12 GB Video Card](https://pcpartpicker.com/product/def) | $599.99 @ Newegg
+| *Prices include shipping, taxes, rebates, and discounts* |
+| **Total** | **$1048.99**
+| Generated by [PCPartPicker](https://pcpartpicker.com) 2024-05-01 10:00 EDT-0400 |`,
+			expected: []part{
+				{models.CategoryCPU, "AMD Ryzen 7 7800X3D 4.2 GHz 8-Core Processor", 1},
+				{models.CategoryVideoCard, "Gigabyte WINDFORCE GeForce RTX 4070 SUPER 12 GB Video Card", 1},
+			},
+		},
+		{
+			name: "Exported Markdown table",
+			text: `# My build
+
+| Category | Part | Qty | Retailer | Price | Total |
+| --- | --- | ---: | --- | ---: | ---: |
+| cpu | [AMD Ryzen 7 7800X3D](<https://example.com/cpu>) | 1 | Newegg | 449.00 | 449.00 |
+| case\_fan | Arctic P12 | 3 |  |  |  |
+
+**Total: 1048.00 USD**`,
+			expected: []part{
+				{models.CategoryCPU, "AMD Ryzen 7 7800X3D", 1},
+				{models.CategoryCaseFan, "Arctic P12", 3},
+			},
+		},
+		{
+			name: "Bullets, BBCode and quantities",
+			text: `- CPU: AMD Ryzen 5 7600
+* RAM: 2x Kingston Fury Beast 16GB
+1. GPU: Sapphire Pulse RX 7800 XT - $499.99
+[*][b]case_fan:[/b] [url=https://example.com/fan]Arctic P12[/url] x3 @ Amazon - 20.97 USD
+Noctua NH-D15 (2)`,
+			expected: []part{
+				{models.CategoryCPU, "AMD Ryzen 5 7600", 1},
+				{models.CategoryMemory, "Kingston Fury Beast 16GB", 2},
+				{models.CategoryVideoCard, "Sapphire Pulse RX 7800 XT", 1},
+				{models.CategoryCaseFan, "Arctic P12", 3},
+				{"", "Noctua NH-D15", 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := ParsePartsList(tt.text)
+
+			parts := []part{}
+			for _, line := range lines {
+				p := part{query: line.Query, quantity: line.Quantity}
+				if line.Category != nil {
+					p.category = *line.Category
+				}
+				parts = append(parts, p)
+			}
+			assert.Equal(t, tt.expected, parts)
+		})
+	}
+}
+
+// TestRankComponentMatches tests that the closest component ranks first and is only picked when it is clear
+func TestRankComponentMatches(t *testing.T) {
+	component := func(id, brand, model string) models.Component {
+		return models.Component{ID: id, Category: models.CategoryVideoCard, Brand: brand, Model: model}
+	}
+	components := []models.Component{
+		component("1", "NVIDIA", "GeForce RTX 4070"),
+		component("2", "NVIDIA", "GeForce RTX 4070 Ti"),
+		component("3", "NVIDIA", "GeForce RTX 4060"),
+		component("4", "AMD", "Radeon RX 7800 XT"),
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		expectedID string
+		picked     bool
+	}{
+		{name: "Extra words", query: "NVIDIA GeForce RTX 4070 Ti 12 GB Video Card", expectedID: "2", picked: true},
+		{name: "Spacing and case", query: "geforce rtx4060", expectedID: "3", picked: true},
+		{name: "Without brand", query: "Radeon RX 7800XT", expectedID: "4", picked: true},
+		{name: "Unknown model", query: "GeForce RTX 3080", expectedID: "", picked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := RankComponentMatches(tt.query, components)
+			require.Len(t, matches, len(components))
+			for i := 1; i < len(matches); i++ {
+				assert.GreaterOrEqual(t, matches[i-1].Score, matches[i].Score)
+			}
+
+			match, ok := pickComponentMatch(matches)
+			assert.Equal(t, tt.picked, ok)
+			assert.Equal(t, tt.expectedID, match.Component.ID)
+		})
+	}
+}
+
+// TestPartsListTerms tests the terms used to look up candidates
+func TestPartsListTerms(t *testing.T) {
+	assert.Equal(t, []string{"amd", "ryzen", "7800x3d"}, partsListTerms("AMD Ryzen 7 7800X3D (AMD)"))
+	assert.Empty(t, partsListTerms("- 1 -"))
+}
+
+// TestImportedQuantityLimits tests that a line or the sum of duplicate lines cannot exceed MAX_PART_QUANTITY
+func TestImportedQuantityLimits(t *testing.T) {
+	lines := ParsePartsList("Case Fan: ARCTIC P12 x2000000000")
+	require.Len(t, lines, 1)
+	assert.ErrorIs(t, validatePartsList(lines), ErrInvalidBuild)
+	assert.NoError(t, validatePartsList(ParsePartsList("Case Fan: ARCTIC P12 x32")))
+
+	assert.NoError(t, validateImportedQuantities([]models.BuildVersionPart{{ComponentID: 5, Quantity: 32}}))
+	assert.ErrorIs(t, validateImportedQuantities([]models.BuildVersionPart{{ComponentID: 5, Quantity: 33}}), ErrInvalidBuild)
+}
@@ -0,0 +1,399 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// purchaseOffer is the cheapest in-stock price of a part at one retailer, converted to the build's currency
+type purchaseOffer struct {
+	price     models.Price
+	unitPrice float64
+	lineTotal float64
+}
+
+// OptimizeBuildPurchase finds the cheapest way to buy the parts of a build visible to viewerID from the in-stock
+// prices of its region, shipping included. maxRetailers limits the number of retailers the plan orders from.
+func OptimizeBuildPurchase(buildID int64, viewerID string, maxRetailers *int) (models.BuildPurchasePlan, error) {
+	utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_START, nil, buildID)
+
+	if maxRetailers != nil && *maxRetailers < 1 {
+		err := fmt.Errorf("%w: max_retailers must be at least 1", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR, err, buildID)
+		return models.BuildPurchasePlan{}, err
+	}
+
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR, err, buildID)
+		return models.BuildPurchasePlan{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR, err, buildID)
+		return models.BuildPurchasePlan{}, err
+	}
+	componentIDs := make([]int64, len(parts))
+	for i, part := range parts {
+		componentIDs[i] = part.ComponentID
+	}
+
+	candidates, err := repository.GetInStockPrices(componentIDs, build.Region)
+	if err != nil {
+		utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR, err, buildID)
+		return models.BuildPurchasePlan{}, err
+	}
+
+	retailerIDs := []int64{}
+	seen := map[int64]bool{}
+	for _, price := range candidates {
+		if !seen[price.RetailerID] {
+			seen[price.RetailerID] = true
+			retailerIDs = append(retailerIDs, price.RetailerID)
+		}
+	}
+	for _, part := range parts {
+		if part.SelectedPrice != nil && !seen[part.SelectedPrice.RetailerID] {
+			seen[part.SelectedPrice.RetailerID] = true
+			retailerIDs = append(retailerIDs, part.SelectedPrice.RetailerID)
+		}
+	}
+	retailers, err := repository.GetRetailerShipping(retailerIDs)
+	if err != nil {
+		utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR, err, buildID)
+		return models.BuildPurchasePlan{}, err
+	}
+
+	rates, err := repository.GetExchangeRates()
+	if err != nil {
+		utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR, err, buildID)
+		return models.BuildPurchasePlan{}, err
+	}
+
+	plan, err := OptimizePurchase(build, parts, candidates, retailers, rates, maxRetailers)
+	if err != nil {
+		utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_ERROR, err, buildID)
+		return models.BuildPurchasePlan{}, err
+	}
+
+	utils.Log(constants.SERVICE_OPTIMIZE_BUILD_PURCHASE_SUCCESS, nil, buildID, plan.Total, plan.Currency, len(plan.Orders))
+	return plan, nil
+}
+
+// OptimizePurchase picks a retailer for every part of a build from the in-stock candidates of active retailers so
+// that parts and shipping cost the least. Every combination of up to maxRetailers retailers is tried, and within
+// one, parts move between retailers while that lowers the total, so paying a little more for a part to reach a
+// free shipping threshold is found. Retailers that are the only seller of a part are always kept; past
+// OPTIMIZER_MAX_RETAILERS, the other retailers carrying the fewest parts are dropped unless one is the cheapest for a
+// part.
+func OptimizePurchase(build models.UserBuild, parts []models.BuildComponentWithDetails, candidates []models.Price,
+	retailers map[int64]models.Retailer, rates map[string]float64, maxRetailers *int) (models.BuildPurchasePlan, error) {
+	currency := strings.ToUpper(build.Currency)
+	plan := models.BuildPurchasePlan{
+		BuildID:      build.ID,
+		Currency:     currency,
+		Region:       build.Region,
+		MaxRetailers: maxRetailers,
+		Lines:        []models.PurchasePlanLine{},
+		Orders:       []models.PurchasePlanOrder{},
+		Unavailable:  []int64{},
+	}
+
+	shipping := map[int64]models.RetailerShipping{}
+	for id, retailer := range retailers {
+		if rule, ok := parseRetailerShipping(retailer.ShippingInfo, currency, rates); ok {
+			shipping[id] = rule
+		}
+	}
+
+	// offers[i] holds the cheapest offer of each retailer for parts[i]
+	offers := make([]map[int64]purchaseOffer, len(parts))
+	available := []int{}
+	for i, part := range parts {
+		offers[i] = map[int64]purchaseOffer{}
+		quantity := max(part.Quantity, 1)
+		for _, price := range candidates {
+			if price.ComponentID != part.ComponentID {
+				continue
+			}
+			if _, ok := shipping[price.RetailerID]; !ok {
+				continue
+			}
+			converted, ok := ConvertCurrency(price.Price, price.Currency, currency, rates)
+			if !ok {
+				continue
+			}
+			unitPrice := roundTo(converted, 2)
+			if offer, ok := offers[i][price.RetailerID]; !ok || unitPrice < offer.unitPrice {
+				offers[i][price.RetailerID] = purchaseOffer{price: price, unitPrice: unitPrice, lineTotal: roundTo(unitPrice*float64(quantity), 2)}
+			}
+		}
+		if len(offers[i]) == 0 {
+			plan.Unavailable = append(plan.Unavailable, part.ComponentID)
+		} else {
+			available = append(available, i)
+		}
+	}
+
+	current := PriceBuild(build, parts, candidates, rates)
+	if current.Complete && current.Total != nil {
+		currentTotal := *current.Total
+		subtotals := map[int64]float64{}
+		for _, line := range current.Lines {
+			if line.RetailerID != nil {
+				subtotals[*line.RetailerID] += *line.LineTotal
+			}
+		}
+		for id, subtotal := range subtotals {
+			rule, ok := shipping[id]
+			if !ok {
+				plan.UnknownShipping = append(plan.UnknownShipping, id)
+				continue
+			}
+			currentTotal += shippingCost(rule, subtotal)
+		}
+		if len(plan.UnknownShipping) == 0 {
+			currentTotal = roundTo(currentTotal, 2)
+			plan.CurrentTotal = &currentTotal
+		} else {
+			sort.Slice(plan.UnknownShipping, func(a, b int) bool { return plan.UnknownShipping[a] < plan.UnknownShipping[b] })
+		}
+	}
+	if len(available) == 0 {
+		return plan, nil
+	}
+
+	required, optional := chooseOptimizerRetailers(offers, available)
+	limit := len(required) + len(optional)
+	if maxRetailers != nil && *maxRetailers < limit {
+		limit = *maxRetailers
+	}
+
+	// Every plan orders from the sole sellers of parts, so only the other retailers are combined
+	var best map[int]int64
+	bestTotal, bestUsed := 0.0, 0
+	for mask := 0; mask < 1<<len(optional); mask++ {
+		if len(required)+bits.OnesCount(uint(mask)) > limit {
+			continue
+		}
+		subset := append([]int64{}, required...)
+		for bit, id := range optional {
+			if mask&(1<<bit) != 0 {
+				subset = append(subset, id)
+			}
+		}
+		if len(subset) == 0 {
+			continue
+		}
+
+		assignment, ok := assignPurchase(offers, available, subset, shipping)
+		if !ok {
+			continue
+		}
+		total, used := purchaseTotal(offers, assignment, shipping)
+		if best == nil || total < bestTotal-0.005 || total < bestTotal+0.005 && used < bestUsed {
+			best, bestTotal, bestUsed = assignment, total, used
+		}
+	}
+	if best == nil {
+		return models.BuildPurchasePlan{}, fmt.Errorf("%w: no %d retailers have every part in stock", ErrInvalidBuild, limit)
+	}
+
+	currentPrices := map[int64]int64{}
+	for _, line := range current.Lines {
+		if line.PriceID != nil {
+			currentPrices[line.ComponentID] = *line.PriceID
+		}
+	}
+	orders := map[int64]*models.PurchasePlanOrder{}
+	for _, i := range available {
+		offer := offers[i][best[i]]
+		plan.Lines = append(plan.Lines, models.PurchasePlanLine{
+			ComponentID: parts[i].ComponentID,
+			Quantity:    max(parts[i].Quantity, 1),
+			PriceID:     offer.price.ID,
+			RetailerID:  offer.price.RetailerID,
+			UnitPrice:   offer.unitPrice,
+			LineTotal:   offer.lineTotal,
+			ProductURL:  offer.price.ProductURL,
+			Changed:     currentPrices[parts[i].ComponentID] != offer.price.ID,
+		})
+
+		order, ok := orders[offer.price.RetailerID]
+		if !ok {
+			order = &models.PurchasePlanOrder{RetailerID: offer.price.RetailerID, Name: retailers[offer.price.RetailerID].Name}
+			orders[offer.price.RetailerID] = order
+		}
+		order.Subtotal = roundTo(order.Subtotal+offer.lineTotal, 2)
+		plan.PartsTotal += offer.lineTotal
+	}
+	for id, order := range orders {
+		order.Shipping = roundTo(shippingCost(shipping[id], order.Subtotal), 2)
+		order.Total = roundTo(order.Subtotal+order.Shipping, 2)
+		plan.ShippingTotal += order.Shipping
+		plan.Orders = append(plan.Orders, *order)
+	}
+	sort.Slice(plan.Orders, func(a, b int) bool {
+		if plan.Orders[a].Total != plan.Orders[b].Total {
+			return plan.Orders[a].Total > plan.Orders[b].Total
+		}
+		return plan.Orders[a].RetailerID < plan.Orders[b].RetailerID
+	})
+
+	plan.PartsTotal = roundTo(plan.PartsTotal, 2)
+	plan.ShippingTotal = roundTo(plan.ShippingTotal, 2)
+	plan.Total = roundTo(plan.PartsTotal+plan.ShippingTotal, 2)
+	if plan.CurrentTotal != nil && len(plan.Unavailable) == 0 {
+		savings := roundTo(*plan.CurrentTotal-plan.Total, 2)
+		plan.Savings = &savings
+	}
+	return plan, nil
+}
+
+// chooseOptimizerRetailers returns the retailers worth trying. required holds the sole sellers of parts, which every
+// plan needs. optional holds the others up to OPTIMIZER_MAX_RETAILERS in all: the cheapest retailer of each part first,
+// then those carrying the most parts.
+func chooseOptimizerRetailers(offers []map[int64]purchaseOffer, available []int) (required, optional []int64) {
+	carried := map[int64]int{}
+	cheapest := map[int64]bool{}
+	sole := map[int64]bool{}
+	for _, i := range available {
+		var cheapestID int64
+		for id, offer := range offers[i] {
+			carried[id]++
+			if cheapestID == 0 || offer.lineTotal < offers[i][cheapestID].lineTotal || offer.lineTotal == offers[i][cheapestID].lineTotal && id < cheapestID {
+				cheapestID = id
+			}
+		}
+		cheapest[cheapestID] = true
+		if len(offers[i]) == 1 {
+			sole[cheapestID] = true
+		}
+	}
+
+	required, optional = []int64{}, []int64{}
+	for id := range carried {
+		if sole[id] {
+			required = append(required, id)
+		} else {
+			optional = append(optional, id)
+		}
+	}
+	sort.Slice(required, func(a, b int) bool { return required[a] < required[b] })
+	sort.Slice(optional, func(a, b int) bool {
+		if cheapest[optional[a]] != cheapest[optional[b]] {
+			return cheapest[optional[a]]
+		}
+		if carried[optional[a]] != carried[optional[b]] {
+			return carried[optional[a]] > carried[optional[b]]
+		}
+		return optional[a] < optional[b]
+	})
+	if room := max(constants.OPTIMIZER_MAX_RETAILERS-len(required), 0); len(optional) > room {
+		optional = optional[:room]
+	}
+	return required, optional
+}
+
+// assignPurchase buys every available part from the subset of retailers, starting with the cheapest offer of each
+// and moving parts while that lowers parts and shipping together. ok is false when the subset misses a part.
+func assignPurchase(offers []map[int64]purchaseOffer, available []int, subset []int64, shipping map[int64]models.RetailerShipping) (map[int]int64, bool) {
+	assignment := map[int]int64{}
+	for _, i := range available {
+		for _, id := range subset {
+			offer, ok := offers[i][id]
+			if !ok {
+				continue
+			}
+			if current, assigned := assignment[i]; !assigned || offer.lineTotal < offers[i][current].lineTotal {
+				assignment[i] = id
+			}
+		}
+		if _, ok := assignment[i]; !ok {
+			return nil, false
+		}
+	}
+
+	total, _ := purchaseTotal(offers, assignment, shipping)
+	for improved := true; improved; {
+		improved = false
+		for _, i := range available {
+			original := assignment[i]
+			for _, id := range subset {
+				if _, ok := offers[i][id]; !ok || id == assignment[i] {
+					continue
+				}
+				previous := assignment[i]
+				assignment[i] = id
+				if moved, _ := purchaseTotal(offers, assignment, shipping); moved < total-0.005 {
+					total = moved
+				} else {
+					assignment[i] = previous
+				}
+			}
+			if assignment[i] != original {
+				improved = true
+			}
+		}
+	}
+	return assignment, true
+}
+
+// purchaseTotal returns the cost of an assignment with shipping and the number of retailers it orders from
+func purchaseTotal(offers []map[int64]purchaseOffer, assignment map[int]int64, shipping map[int64]models.RetailerShipping) (float64, int) {
+	subtotals := map[int64]float64{}
+	for i, id := range assignment {
+		subtotals[id] += offers[i][id].lineTotal
+	}
+
+	total := 0.0
+	for id, subtotal := range subtotals {
+		total += subtotal + shippingCost(shipping[id], subtotal)
+	}
+	return roundTo(total, 2), len(subtotals)
+}
+
+// shippingCost returns the shipping of an order, free once the subtotal reaches the retailer's threshold
+func shippingCost(rule models.RetailerShipping, subtotal float64) float64 {
+	if rule.FreeShippingThreshold != nil && subtotal >= *rule.FreeShippingThreshold-0.005 {
+		return 0
+	}
+	return rule.FlatRate
+}
+
+// parseRetailerShipping reads a retailer's shipping_info and converts it to currency. Retailers without shipping
+// info ship for free. ok is false when the info is malformed or its currency cannot be converted.
+func parseRetailerShipping(info json.RawMessage, currency string, rates map[string]float64) (models.RetailerShipping, bool) {
+	var rule models.RetailerShipping
+	if len(info) == 0 || string(info) == "null" {
+		return rule, true
+	}
+	if err := json.Unmarshal(info, &rule); err != nil || rule.FlatRate < 0 {
+		return models.RetailerShipping{}, false
+	}
+	if rule.Currency == "" {
+		rule.Currency = currency
+	}
+
+	flatRate, ok := ConvertCurrency(rule.FlatRate, rule.Currency, currency, rates)
+	if !ok {
+		return models.RetailerShipping{}, false
+	}
+	rule.FlatRate = roundTo(flatRate, 2)
+	if rule.FreeShippingThreshold != nil {
+		threshold, _ := ConvertCurrency(*rule.FreeShippingThreshold, rule.Currency, currency, rates)
+		threshold = roundTo(threshold, 2)
+		rule.FreeShippingThreshold = &threshold
+	}
+	rule.Currency = currency
+	return rule, true
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOptimizePurchase tests the choice of retailers with shipping and free shipping thresholds
+func TestOptimizePurchase(t *testing.T) {
+	build := models.UserBuild{ID: 1, Currency: "USD", Region: "USA"}
+	part := func(componentID int64, selected *models.Price) models.BuildComponentWithDetails {
+		return models.BuildComponentWithDetails{
+			BuildComponent: models.BuildComponent{BuildID: 1, ComponentID: componentID, Quantity: 1},
+			SelectedPrice:  selected,
+		}
+	}
+	price := func(id, componentID, retailerID int64, amount float64) models.Price {
+		return models.Price{ID: id, ComponentID: componentID, RetailerID: retailerID, Currency: "USD", Price: amount, InStock: true}
+	}
+	retailers := map[int64]models.Retailer{
+		1: {ID: 1, Name: "Free over 150", ShippingInfo: json.RawMessage(`{"flat_rate": 10, "free_shipping_threshold": 150}`)},
+		2: {ID: 2, Name: "Cheap parts", ShippingInfo: json.RawMessage(`{"flat_rate": 14}`)},
+		3: {ID: 3, Name: "Only one with part 3", ShippingInfo: json.RawMessage(`{"flat_rate": 25}`)},
+	}
+	candidates := []models.Price{
+		price(11, 1, 1, 100), price(12, 2, 1, 55),
+		price(21, 1, 2, 95), price(22, 2, 2, 50),
+		price(32, 2, 3, 48), price(33, 3, 3, 30),
+	}
+	selected := candidates[0]
+	parts := []models.BuildComponentWithDetails{part(1, &selected), part(2, nil), part(3, nil)}
+
+	t.Run("Reaches the free shipping threshold", func(t *testing.T) {
+		plan, err := OptimizePurchase(build, parts, candidates, retailers, nil, nil)
+
+		require.NoError(t, err)
+		require.Len(t, plan.Lines, 3)
+		assert.Equal(t, int64(11), plan.Lines[0].PriceID)
+		assert.False(t, plan.Lines[0].Changed)
+		assert.Equal(t, int64(12), plan.Lines[1].PriceID)
+		assert.True(t, plan.Lines[1].Changed)
+		assert.Equal(t, int64(33), plan.Lines[2].PriceID)
+		assert.Equal(t, 185.0, plan.PartsTotal)
+		assert.Equal(t, 25.0, plan.ShippingTotal)
+		assert.Equal(t, 210.0, plan.Total)
+		require.Len(t, plan.Orders, 2)
+		assert.Equal(t, models.PurchasePlanOrder{RetailerID: 1, Name: "Free over 150", Subtotal: 155, Shipping: 0, Total: 155}, plan.Orders[0])
+
+		require.NotNil(t, plan.CurrentTotal)
+		assert.Equal(t, 213.0, *plan.CurrentTotal)
+		require.NotNil(t, plan.Savings)
+		assert.Equal(t, 3.0, *plan.Savings)
+		assert.Empty(t, plan.Unavailable)
+	})
+
+	t.Run("Too few retailers allowed", func(t *testing.T) {
+		one := 1
+
+		_, err := OptimizePurchase(build, parts, candidates, retailers, nil, &one)
+
+		assert.True(t, errors.Is(err, ErrInvalidBuild))
+	})
+
+	t.Run("Inactive retailers and parts out of stock", func(t *testing.T) {
+		active := map[int64]models.Retailer{1: retailers[1], 3: retailers[3]}
+		two := 2
+
+		plan, err := OptimizePurchase(build, []models.BuildComponentWithDetails{part(2, nil), part(4, nil)}, candidates, active, nil, &two)
+
+		require.NoError(t, err)
+		require.Len(t, plan.Lines, 1)
+		assert.Equal(t, int64(12), plan.Lines[0].PriceID)
+		assert.Equal(t, 65.0, plan.Total)
+		assert.Equal(t, []int64{4}, plan.Unavailable)
+		assert.Nil(t, plan.CurrentTotal)
+		assert.Nil(t, plan.Savings)
+	})
+
+	t.Run("Current price from a retailer with unknown shipping", func(t *testing.T) {
+		withUnknown := map[int64]models.Retailer{
+			1: retailers[1], 2: retailers[2], 3: retailers[3],
+			4: {ID: 4, Name: "Malformed shipping", ShippingInfo: json.RawMessage(`"free"`)},
+		}
+		unknown := price(41, 1, 4, 90)
+
+		plan, err := OptimizePurchase(build, []models.BuildComponentWithDetails{part(1, &unknown), part(2, nil), part(3, nil)},
+			append(candidates, unknown), withUnknown, nil, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []int64{4}, plan.UnknownShipping)
+		assert.Nil(t, plan.CurrentTotal)
+		assert.Nil(t, plan.Savings)
+		assert.Equal(t, 210.0, plan.Total)
+	})
+
+	t.Run("Sole sellers are kept past the retailer limit", func(t *testing.T) {
+		many := map[int64]models.Retailer{}
+		soldAlone := []models.Price{}
+		onlyParts := []models.BuildComponentWithDetails{}
+		for id := int64(1); id <= constants.OPTIMIZER_MAX_RETAILERS+1; id++ {
+			many[id] = models.Retailer{ID: id}
+			soldAlone = append(soldAlone, price(100+id, id, id, 10))
+			onlyParts = append(onlyParts, part(id, nil))
+		}
+
+		plan, err := OptimizePurchase(build, onlyParts, soldAlone, many, nil, nil)
+
+		require.NoError(t, err)
+		assert.Len(t, plan.Orders, constants.OPTIMIZER_MAX_RETAILERS+1)
+		assert.Empty(t, plan.Unavailable)
+		assert.Equal(t, float64(10*(constants.OPTIMIZER_MAX_RETAILERS+1)), plan.Total)
+	})
+}
+
+// TestChooseOptimizerRetailers tests that sole sellers are required and the other retailers are capped
+func TestChooseOptimizerRetailers(t *testing.T) {
+	offer := func(lineTotal float64) purchaseOffer { return purchaseOffer{lineTotal: lineTotal} }
+	offers := []map[int64]purchaseOffer{{7: offer(10)}, {}}
+	for id := int64(1); id <= constants.OPTIMIZER_MAX_RETAILERS+2; id++ {
+		offers[1][100+id] = offer(float64(id))
+	}
+
+	required, optional := chooseOptimizerRetailers(offers, []int{0, 1})
+
+	assert.Equal(t, []int64{7}, required)
+	require.Len(t, optional, constants.OPTIMIZER_MAX_RETAILERS-1)
+	assert.Equal(t, int64(101), optional[0])
+}
+
+// TestParseRetailerShipping tests reading and converting retailer shipping info
+func TestParseRetailerShipping(t *testing.T) {
+	rates := map[string]float64{"CAD": 1.25}
+
+	rule, ok := parseRetailerShipping(json.RawMessage(`{"flat_rate": 8, "free_shipping_threshold": 100, "currency": "USD"}`), "CAD", rates)
+	require.True(t, ok)
+	assert.Equal(t, 10.0, rule.FlatRate)
+	assert.Equal(t, 125.0, *rule.FreeShippingThreshold)
+
+	rule, ok = parseRetailerShipping(nil, "CAD", rates)
+	require.True(t, ok)
+	assert.Zero(t, rule.FlatRate)
+
+	_, ok = parseRetailerShipping(json.RawMessage(`{"flat_rate": 8, "currency": "GBP"}`), "CAD", rates)
+	assert.False(t, ok)
+	_, ok = parseRetailerShipping(json.RawMessage(`"free"`), "CAD", rates)
+	assert.False(t, ok)
+}
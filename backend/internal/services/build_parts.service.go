@@ -0,0 +1,177 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// AddBuildPart adds a component to a build userID can edit and returns the updated build.
+// Adding a component already in the build increases its quantity. A set revision must match the build's current revision.
+func AddBuildPart(buildID int64, userID string, revision *int, input models.BuildComponentCreate) (models.UserBuildWithComponents, error) {
+	utils.Log(constants.SERVICE_ADD_BUILD_PART_START, nil, input.ComponentID, buildID)
+
+	if err := prepareBuildPart(buildID, &input); err != nil {
+		utils.Log(constants.SERVICE_ADD_BUILD_PART_ERROR, err, input.ComponentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleEditor); err != nil {
+		utils.Log(constants.SERVICE_ADD_BUILD_PART_ERROR, err, input.ComponentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	if err := addBuildPart(input, userID, revision); err != nil {
+		utils.Log(constants.SERVICE_ADD_BUILD_PART_ERROR, err, input.ComponentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	utils.Log(constants.SERVICE_ADD_BUILD_PART_SUCCESS, nil, input.ComponentID, buildID)
+	return GetUserBuild(buildID, userID)
+}
+
+// UpdateBuildPart changes the quantity, selected price or notes of a part of a build userID can edit
+func UpdateBuildPart(buildID, componentID int64, userID string, revision *int, update models.BuildComponentUpdate) (models.UserBuildWithComponents, error) {
+	utils.Log(constants.SERVICE_UPDATE_BUILD_PART_START, nil, componentID, buildID)
+
+	if err := validateBuildPartUpdate(componentID, update); err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_PART_ERROR, err, componentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleEditor); err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_PART_ERROR, err, componentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	if err := updateBuildPart(buildID, componentID, update, userID, revision); err != nil {
+		utils.Log(constants.SERVICE_UPDATE_BUILD_PART_ERROR, err, componentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	utils.Log(constants.SERVICE_UPDATE_BUILD_PART_SUCCESS, nil, componentID, buildID)
+	return GetUserBuild(buildID, userID)
+}
+
+// RemoveBuildPart removes a component from a build userID can edit
+func RemoveBuildPart(buildID, componentID int64, userID string, revision *int) (models.UserBuildWithComponents, error) {
+	utils.Log(constants.SERVICE_REMOVE_BUILD_PART_START, nil, componentID, buildID)
+
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleEditor); err != nil {
+		utils.Log(constants.SERVICE_REMOVE_BUILD_PART_ERROR, err, componentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	if err := removeBuildPart(buildID, componentID, userID, revision); err != nil {
+		utils.Log(constants.SERVICE_REMOVE_BUILD_PART_ERROR, err, componentID, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	utils.Log(constants.SERVICE_REMOVE_BUILD_PART_SUCCESS, nil, componentID, buildID)
+	return GetUserBuild(buildID, userID)
+}
+
+// prepareBuildPart points input at the build and validates it
+func prepareBuildPart(buildID int64, input *models.BuildComponentCreate) error {
+	if input.BuildID != 0 && input.BuildID != buildID {
+		return fmt.Errorf("%w: build_id does not match the build in the path", ErrInvalidBuild)
+	}
+	input.BuildID = buildID
+	return validateBuildPart(input.ComponentID, input.Quantity, input.SelectedPriceID)
+}
+
+// addBuildPart stores a validated part and records the change as a new version in the same transaction
+func addBuildPart(input models.BuildComponentCreate, actor string, revision *int) error {
+	if _, err := repository.UpsertBuildPart(input, revision, buildSnapshot(actor, fmt.Sprintf("Added component %d", input.ComponentID))); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: the build would hold more than %d of component %d", ErrInvalidBuild, constants.MAX_PART_QUANTITY, input.ComponentID)
+		}
+		return err
+	}
+	return nil
+}
+
+// updateBuildPart applies a validated update and records the change as a new version in the same transaction
+func updateBuildPart(buildID, componentID int64, update models.BuildComponentUpdate, actor string, revision *int) error {
+	if _, err := repository.UpdateBuildPart(buildID, componentID, update, revision, buildSnapshot(actor, fmt.Sprintf("Changed component %d", componentID))); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrBuildPartNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// removeBuildPart deletes a part and records the change as a new version in the same transaction
+func removeBuildPart(buildID, componentID int64, actor string, revision *int) error {
+	if err := repository.DeleteBuildPart(buildID, componentID, revision, buildSnapshot(actor, fmt.Sprintf("Removed component %d", componentID))); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrBuildPartNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// validateBuildPart checks that the component exists, the quantity is between 1 and MAX_PART_QUANTITY
+// and the selected price is a price of that component
+func validateBuildPart(componentID int64, quantity *int, selectedPriceID *int64) error {
+	if componentID <= 0 {
+		return fmt.Errorf("%w: component_id is required", ErrInvalidBuild)
+	}
+	if quantity != nil {
+		if err := validateBuildPartQuantity(*quantity); err != nil {
+			return err
+		}
+	}
+
+	_, err := repository.GetComponentById(models.GetComponentByIdInput{ID: strconv.FormatInt(componentID, 10)})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: component %d does not exist", ErrInvalidBuild, componentID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return validateSelectedPrice(componentID, selectedPriceID)
+}
+
+func validateBuildPartUpdate(componentID int64, update models.BuildComponentUpdate) error {
+	if update.Quantity != nil {
+		if err := validateBuildPartQuantity(*update.Quantity); err != nil {
+			return err
+		}
+	}
+	return validateSelectedPrice(componentID, update.SelectedPriceID)
+}
+
+func validateBuildPartQuantity(quantity int) error {
+	if quantity < 1 || quantity > constants.MAX_PART_QUANTITY {
+		return fmt.Errorf("%w: quantity must be between 1 and %d", ErrInvalidBuild, constants.MAX_PART_QUANTITY)
+	}
+	return nil
+}
+
+func validateSelectedPrice(componentID int64, selectedPriceID *int64) error {
+	if selectedPriceID == nil {
+		return nil
+	}
+
+	price, err := repository.GetPriceById(*selectedPriceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: price %d does not exist", ErrInvalidBuild, *selectedPriceID)
+	}
+	if err != nil {
+		return err
+	}
+	if price.ComponentID != componentID {
+		return fmt.Errorf("%w: price %d is not a price of component %d", ErrInvalidBuild, *selectedPriceID, componentID)
+	}
+	return nil
+}
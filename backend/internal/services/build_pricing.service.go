@@ -0,0 +1,215 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const (
+	PriceSourceSelected        = "selected"
+	PriceSourceCheapestInStock = "cheapest_in_stock"
+	PriceSourceUnavailable     = "unavailable"
+
+	baseCurrency = "USD"
+)
+
+// CalculateBuildPrice prices every part of a build at current prices without storing the total
+func CalculateBuildPrice(build models.UserBuild, parts []models.BuildComponentWithDetails) (models.BuildPriceBreakdown, error) {
+	utils.Log(constants.SERVICE_CALCULATE_BUILD_PRICE_START, nil, build.ID)
+
+	componentIDs := []int64{}
+	for _, part := range parts {
+		if part.SelectedPrice == nil {
+			componentIDs = append(componentIDs, part.ComponentID)
+		}
+	}
+
+	candidates, err := repository.GetInStockPrices(componentIDs, build.Region)
+	if err != nil {
+		utils.Log(constants.SERVICE_CALCULATE_BUILD_PRICE_ERROR, err, build.ID)
+		return models.BuildPriceBreakdown{}, err
+	}
+
+	rates, err := repository.GetExchangeRates()
+	if err != nil {
+		utils.Log(constants.SERVICE_CALCULATE_BUILD_PRICE_ERROR, err, build.ID)
+		return models.BuildPriceBreakdown{}, err
+	}
+
+	breakdown := PriceBuild(build, parts, candidates, rates)
+
+	total := 0.0
+	if breakdown.Total != nil {
+		total = *breakdown.Total
+	}
+	utils.Log(constants.SERVICE_CALCULATE_BUILD_PRICE_SUCCESS, nil, build.ID, total, breakdown.Currency)
+	return breakdown, nil
+}
+
+// RecalculateBuildTotal prices a build and stores its total. Completeness follows from the parts, so the stored flag
+// is refreshed with it.
+func RecalculateBuildTotal(buildID int64) (models.BuildPriceBreakdown, error) {
+	build, err := repository.GetUserBuildById(buildID)
+	if err != nil {
+		return models.BuildPriceBreakdown{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		return models.BuildPriceBreakdown{}, err
+	}
+
+	breakdown, err := CalculateBuildPrice(build, parts)
+	if err != nil {
+		return models.BuildPriceBreakdown{}, err
+	}
+
+	if err := repository.SetUserBuildTotals(buildID, breakdown.Total, EvaluateCompleteness(parts).Complete); err != nil {
+		return models.BuildPriceBreakdown{}, err
+	}
+	return breakdown, nil
+}
+
+// RecalculateBuildTotals refreshes the stored totals and completeness of the builds containing any of the components,
+// or of every build when no component is given. Run it after prices or exchange rates change.
+func RecalculateBuildTotals(componentIDs ...int64) (int, error) {
+	utils.Log(constants.SERVICE_RECALCULATE_BUILD_TOTALS_START, nil)
+
+	buildIDs, err := repository.ListBuildIDsWithComponents(componentIDs)
+	if err != nil {
+		utils.Log(constants.SERVICE_RECALCULATE_BUILD_TOTALS_ERROR, err)
+		return 0, err
+	}
+
+	for i, buildID := range buildIDs {
+		if _, err := RecalculateBuildTotal(buildID); err != nil {
+			utils.Log(constants.SERVICE_RECALCULATE_BUILD_TOTALS_ERROR, err)
+			return i, err
+		}
+	}
+
+	utils.Log(constants.SERVICE_RECALCULATE_BUILD_TOTALS_SUCCESS, nil, len(buildIDs))
+	return len(buildIDs), nil
+}
+
+// PriceBuild prices each part with its selected price, or the cheapest of the in-stock candidates for the component
+// when none is selected, converted to the build's currency. Parts without a usable price are listed but left out of
+// the total, and the breakdown is marked incomplete.
+func PriceBuild(build models.UserBuild, parts []models.BuildComponentWithDetails, candidates []models.Price, rates map[string]float64) models.BuildPriceBreakdown {
+	breakdown := models.BuildPriceBreakdown{
+		BuildID:  build.ID,
+		Currency: strings.ToUpper(build.Currency),
+		Region:   build.Region,
+		Complete: true,
+		Lines:    []models.BuildPriceLine{},
+	}
+
+	byComponent := map[int64][]models.Price{}
+	for _, candidate := range candidates {
+		byComponent[candidate.ComponentID] = append(byComponent[candidate.ComponentID], candidate)
+	}
+
+	total := 0.0
+	priced := 0
+	for _, part := range parts {
+		quantity := part.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+		line := models.BuildPriceLine{ComponentID: part.ComponentID, Quantity: quantity}
+
+		var price *models.Price
+		var unitPrice float64
+		switch {
+		case part.SelectedPrice != nil:
+			converted, ok := ConvertCurrency(part.SelectedPrice.Price, part.SelectedPrice.Currency, breakdown.Currency, rates)
+			if !ok {
+				line.Source = PriceSourceUnavailable
+				line.Message = fmt.Sprintf("No exchange rate from %s to %s for the selected price", part.SelectedPrice.Currency, breakdown.Currency)
+				break
+			}
+			price, unitPrice = part.SelectedPrice, converted
+			line.Source = PriceSourceSelected
+		default:
+			price, unitPrice = cheapestPrice(byComponent[part.ComponentID], breakdown.Currency, rates)
+			if price == nil {
+				line.Source = PriceSourceUnavailable
+				line.Message = fmt.Sprintf("No in-stock price in %s", build.Region)
+				break
+			}
+			line.Source = PriceSourceCheapestInStock
+		}
+
+		if price != nil {
+			unitPrice = roundTo(unitPrice, 2)
+			lineTotal := roundTo(unitPrice*float64(quantity), 2)
+			original := price.Price
+			line.PriceID = &price.ID
+			line.RetailerID = &price.RetailerID
+			line.OriginalPrice = &original
+			line.OriginalCurrency = strings.ToUpper(price.Currency)
+			line.UnitPrice = &unitPrice
+			line.LineTotal = &lineTotal
+			line.ProductURL = price.ProductURL
+			total += lineTotal
+			priced++
+		} else {
+			breakdown.Complete = false
+		}
+		breakdown.Lines = append(breakdown.Lines, line)
+	}
+
+	if priced > 0 || len(parts) == 0 {
+		total = roundTo(total, 2)
+		breakdown.Total = &total
+	}
+	return breakdown
+}
+
+// ConvertCurrency converts an amount between currencies through USD. rates holds the amount of each currency one
+// US dollar buys. ok is false when a rate is missing.
+func ConvertCurrency(amount float64, from, to string, rates map[string]float64) (float64, bool) {
+	from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+	if from == to {
+		return amount, true
+	}
+
+	rate := func(currency string) (float64, bool) {
+		if currency == baseCurrency {
+			return 1, true
+		}
+		value, ok := rates[currency]
+		return value, ok && value > 0
+	}
+
+	fromRate, ok := rate(from)
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := rate(to)
+	if !ok {
+		return 0, false
+	}
+	return amount / fromRate * toRate, true
+}
+
+// cheapestPrice returns the lowest of the prices after conversion, skipping prices that cannot be converted
+func cheapestPrice(prices []models.Price, currency string, rates map[string]float64) (*models.Price, float64) {
+	var cheapest *models.Price
+	var lowest float64
+	for i := range prices {
+		converted, ok := ConvertCurrency(prices[i].Price, prices[i].Currency, currency, rates)
+		if !ok {
+			continue
+		}
+		if cheapest == nil || converted < lowest {
+			cheapest, lowest = &prices[i], converted
+		}
+	}
+	return cheapest, lowest
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPriceBuild tests the per-line price breakdown and total of a build
+func TestPriceBuild(t *testing.T) {
+	build := models.UserBuild{ID: 1, Currency: "CAD", Region: "CAN"}
+	rates := map[string]float64{"CAD": 1.25, "EUR": 0.5}
+	part := func(componentID int64, quantity int, selected *models.Price) models.BuildComponentWithDetails {
+		return models.BuildComponentWithDetails{
+			BuildComponent: models.BuildComponent{BuildID: 1, ComponentID: componentID, Quantity: quantity},
+			SelectedPrice:  selected,
+		}
+	}
+
+	t.Run("Selected and cheapest prices", func(t *testing.T) {
+		selected := &models.Price{ID: 10, ComponentID: 1, RetailerID: 1, Currency: "USD", Price: 100}
+		candidates := []models.Price{
+			{ID: 20, ComponentID: 2, RetailerID: 1, Currency: "CAD", Price: 60},
+			{ID: 21, ComponentID: 2, RetailerID: 2, Currency: "EUR", Price: 20},
+		}
+
+		breakdown := PriceBuild(build, []models.BuildComponentWithDetails{part(1, 1, selected), part(2, 2, nil)}, candidates, rates)
+
+		require.Len(t, breakdown.Lines, 2)
+		assert.Equal(t, PriceSourceSelected, breakdown.Lines[0].Source)
+		assert.Equal(t, 125.0, *breakdown.Lines[0].UnitPrice)
+		assert.Equal(t, PriceSourceCheapestInStock, breakdown.Lines[1].Source)
+		assert.Equal(t, int64(21), *breakdown.Lines[1].PriceID)
+		assert.Equal(t, 50.0, *breakdown.Lines[1].UnitPrice)
+		assert.Equal(t, 100.0, *breakdown.Lines[1].LineTotal)
+		require.NotNil(t, breakdown.Total)
+		assert.Equal(t, 225.0, *breakdown.Total)
+		assert.True(t, breakdown.Complete)
+	})
+
+	t.Run("Part without a price", func(t *testing.T) {
+		unconvertible := []models.Price{{ID: 30, ComponentID: 3, Currency: "JPY", Price: 5000}}
+
+		breakdown := PriceBuild(build, []models.BuildComponentWithDetails{part(3, 1, nil)}, unconvertible, rates)
+
+		assert.Equal(t, PriceSourceUnavailable, breakdown.Lines[0].Source)
+		assert.Nil(t, breakdown.Lines[0].LineTotal)
+		assert.Nil(t, breakdown.Total)
+		assert.False(t, breakdown.Complete)
+	})
+
+	t.Run("Empty build", func(t *testing.T) {
+		breakdown := PriceBuild(build, nil, nil, rates)
+
+		require.NotNil(t, breakdown.Total)
+		assert.Equal(t, 0.0, *breakdown.Total)
+	})
+}
+
+// TestConvertCurrency tests conversion between currencies through USD
+func TestConvertCurrency(t *testing.T) {
+	rates := map[string]float64{"CAD": 1.25, "EUR": 0.5}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected float64
+		ok       bool
+	}{
+		{name: "Same currency", from: "JPY", to: "jpy", expected: 100, ok: true},
+		{name: "From USD", from: "USD", to: "CAD", expected: 125, ok: true},
+		{name: "Through USD", from: "EUR", to: "CAD", expected: 250, ok: true},
+		{name: "Missing rate", from: "GBP", to: "CAD", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, ok := ConvertCurrency(100, tt.from, tt.to, rates)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.expected, converted, 0.0001)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// GetBuildLikeStatus returns whether userID likes a build visible to them
+func GetBuildLikeStatus(buildID int64, userID string) (models.BuildLikeStatus, error) {
+	build, err := getVisibleBuild(buildID, userID)
+	if err != nil {
+		return models.BuildLikeStatus{}, err
+	}
+
+	liked, err := repository.HasBuildLike(buildID, userID)
+	if err != nil {
+		return models.BuildLikeStatus{}, err
+	}
+	return models.BuildLikeStatus{BuildID: buildID, Liked: liked, LikeCount: build.LikeCount}, nil
+}
+
+// SetBuildLike likes or unlikes a build visible to userID. A user likes a build at most once.
+func SetBuildLike(buildID int64, userID string, liked bool) (models.BuildLikeStatus, error) {
+	utils.Log(constants.SERVICE_SET_BUILD_LIKE_START, nil, buildID, userID, liked)
+
+	if _, err := getVisibleBuild(buildID, userID); err != nil {
+		utils.Log(constants.SERVICE_SET_BUILD_LIKE_ERROR, err, buildID, userID)
+		return models.BuildLikeStatus{}, err
+	}
+
+	likeCount, err := repository.SetBuildLike(buildID, userID, liked)
+	if err != nil {
+		utils.Log(constants.SERVICE_SET_BUILD_LIKE_ERROR, err, buildID, userID)
+		return models.BuildLikeStatus{}, err
+	}
+
+	utils.Log(constants.SERVICE_SET_BUILD_LIKE_SUCCESS, nil, buildID, likeCount)
+	return models.BuildLikeStatus{BuildID: buildID, Liked: liked, LikeCount: likeCount}, nil
+}
+
+// GetBuildRatingStatus returns the rating userID gave a build visible to them and the build's aggregate rating
+func GetBuildRatingStatus(buildID int64, userID string) (models.BuildRatingStatus, error) {
+	build, err := getVisibleBuild(buildID, userID)
+	if err != nil {
+		return models.BuildRatingStatus{}, err
+	}
+
+	rating, err := repository.GetBuildRating(buildID, userID)
+	if err != nil {
+		return models.BuildRatingStatus{}, err
+	}
+	return models.BuildRatingStatus{BuildID: buildID, Rating: rating, RatingCount: build.RatingCount, RatingAverage: build.RatingAverage}, nil
+}
+
+// SetBuildRating rates a build visible to userID from 1 to 5 stars, replacing their earlier rating, or removes
+// their rating when rating is nil. Owners cannot rate their own builds.
+func SetBuildRating(buildID int64, userID string, rating *int) (models.BuildRatingStatus, error) {
+	utils.Log(constants.SERVICE_SET_BUILD_RATING_START, nil, buildID, userID)
+
+	if rating != nil && (*rating < constants.RATING_MIN || *rating > constants.RATING_MAX) {
+		err := fmt.Errorf("%w: rating must be between %d and %d", ErrInvalidBuild, constants.RATING_MIN, constants.RATING_MAX)
+		utils.Log(constants.SERVICE_SET_BUILD_RATING_ERROR, err, buildID, userID)
+		return models.BuildRatingStatus{}, err
+	}
+
+	build, err := getVisibleBuild(buildID, userID)
+	if err != nil {
+		utils.Log(constants.SERVICE_SET_BUILD_RATING_ERROR, err, buildID, userID)
+		return models.BuildRatingStatus{}, err
+	}
+	if rating != nil && build.UserID == userID {
+		err := fmt.Errorf("%w: owners cannot rate their own builds", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_SET_BUILD_RATING_ERROR, err, buildID, userID)
+		return models.BuildRatingStatus{}, err
+	}
+
+	count, average, err := repository.SetBuildRating(buildID, userID, rating)
+	if err != nil {
+		utils.Log(constants.SERVICE_SET_BUILD_RATING_ERROR, err, buildID, userID)
+		return models.BuildRatingStatus{}, err
+	}
+
+	utils.Log(constants.SERVICE_SET_BUILD_RATING_SUCCESS, nil, buildID, count)
+	return models.BuildRatingStatus{BuildID: buildID, Rating: rating, RatingCount: count, RatingAverage: average}, nil
+}
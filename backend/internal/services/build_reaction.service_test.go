@@ -0,0 +1,115 @@
+package services
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSetBuildLike tests that liking twice keeps a single like and unliking a build that is not liked changes nothing
+func TestSetBuildLike(t *testing.T) {
+	expectLike := func(mock sqlmock.Sqlmock, change string, count int) {
+		expectCollaboratorBuild(mock, true)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM user_builds WHERE id = \\$1 FOR UPDATE").WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(change).WithArgs(int64(1), "fan").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("UPDATE user_builds SET like_count = (.+) RETURNING like_count").WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"like_count"}).AddRow(count))
+		mock.ExpectCommit()
+	}
+
+	t.Run("Liking twice counts once", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectLike(mock, "INSERT INTO build_likes (.+) ON CONFLICT DO NOTHING", 1)
+		expectLike(mock, "INSERT INTO build_likes (.+) ON CONFLICT DO NOTHING", 1)
+
+		for i := 0; i < 2; i++ {
+			status, err := SetBuildLike(1, "fan", true)
+			require.NoError(t, err)
+			assert.True(t, status.Liked)
+			assert.Equal(t, 1, status.LikeCount)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unliking without a like", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectLike(mock, "DELETE FROM build_likes WHERE build_id = \\$1 AND user_id = \\$2", 0)
+
+		status, err := SetBuildLike(1, "fan", false)
+		require.NoError(t, err)
+		assert.False(t, status.Liked)
+		assert.Equal(t, 0, status.LikeCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Private build of another user", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, false)
+		expectCollaborator(mock, "fan", "", false)
+
+		_, err := SetBuildLike(1, "fan", true)
+		assert.True(t, errors.Is(err, sql.ErrNoRows))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestSetBuildRating tests the rating range and that owners cannot rate their own builds
+func TestSetBuildRating(t *testing.T) {
+	stars := func(n int) *int { return &n }
+	expectRating := func(mock sqlmock.Sqlmock, change string, args ...driver.Value) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM user_builds WHERE id = \\$1 FOR UPDATE").WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(change).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("UPDATE user_builds SET (.+) RETURNING rating_count, rating_average").WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"rating_count", "rating_average"}).AddRow(1, 4.0))
+		mock.ExpectCommit()
+	}
+
+	for _, rating := range []int{0, 6} {
+		mock := collaboratorMock(t)
+		_, err := SetBuildRating(1, "fan", stars(rating))
+		assert.True(t, errors.Is(err, ErrInvalidBuild), "rating %d", rating)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+
+	t.Run("Rating another user's build", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, true)
+		expectRating(mock, "INSERT INTO build_ratings (.+) ON CONFLICT", int64(1), "fan", 4)
+
+		status, err := SetBuildRating(1, "fan", stars(4))
+		require.NoError(t, err)
+		assert.Equal(t, 4, *status.Rating)
+		assert.Equal(t, 1, status.RatingCount)
+		assert.Equal(t, 4.0, *status.RatingAverage)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Owner cannot rate", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, true)
+
+		_, err := SetBuildRating(1, "owner", stars(5))
+		assert.True(t, errors.Is(err, ErrInvalidBuild))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Owner can clear a rating", func(t *testing.T) {
+		mock := collaboratorMock(t)
+		expectCollaboratorBuild(mock, true)
+		expectRating(mock, "DELETE FROM build_ratings WHERE build_id = \\$1 AND user_id = \\$2", int64(1), "owner")
+
+		status, err := SetBuildRating(1, "owner", nil)
+		require.NoError(t, err)
+		assert.Nil(t, status.Rating)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const shareTokenBytes = 32
+
+var (
+	// ErrShareNotFound is returned for unknown, expired and revoked share tokens alike
+	ErrShareNotFound = errors.New("share link not found or expired")
+	// ErrShareReadOnly is returned when a read-only share link is used to change a build
+	ErrShareReadOnly = errors.New("share link is read-only")
+)
+
+// CreateBuildShare creates a share link for a build userID owns. The returned share is the only one that
+// carries the token.
+func CreateBuildShare(buildID int64, userID string, input models.BuildShareCreate) (models.BuildShare, error) {
+	utils.Log(constants.SERVICE_CREATE_BUILD_SHARE_START, nil, buildID, userID)
+
+	access := strings.ToLower(strings.TrimSpace(input.Access))
+	if access == "" {
+		access = models.BuildShareAccessRead
+	}
+	if access != models.BuildShareAccessRead && access != models.BuildShareAccessEdit {
+		err := fmt.Errorf("%w: access must be %s or %s", ErrInvalidBuild, models.BuildShareAccessRead, models.BuildShareAccessEdit)
+		utils.Log(constants.SERVICE_CREATE_BUILD_SHARE_ERROR, err, buildID, userID)
+		return models.BuildShare{}, err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		err := fmt.Errorf("%w: expires_at must be in the future", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_CREATE_BUILD_SHARE_ERROR, err, buildID, userID)
+		return models.BuildShare{}, err
+	}
+
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleOwner); err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_SHARE_ERROR, err, buildID, userID)
+		return models.BuildShare{}, err
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_SHARE_ERROR, err, buildID, userID)
+		return models.BuildShare{}, err
+	}
+
+	share, err := repository.CreateBuildShare(buildID, hashShareToken(token), access, userID, input.ExpiresAt)
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_SHARE_ERROR, err, buildID, userID)
+		return models.BuildShare{}, err
+	}
+	share.Token = token
+
+	utils.Log(constants.SERVICE_CREATE_BUILD_SHARE_SUCCESS, nil, share.ID, buildID)
+	return share, nil
+}
+
+// ListBuildShares returns the share links of a build owned by userID, without their tokens
+func ListBuildShares(buildID int64, userID string) ([]models.BuildShare, error) {
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleOwner); err != nil {
+		return nil, err
+	}
+	return repository.ListBuildShares(buildID)
+}
+
+// RevokeBuildShare revokes a share link of a build owned by userID. Revoking an unknown or revoked link returns ErrShareNotFound.
+func RevokeBuildShare(buildID, shareID int64, userID string) error {
+	utils.Log(constants.SERVICE_REVOKE_BUILD_SHARE_START, nil, shareID, buildID)
+
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleOwner); err != nil {
+		utils.Log(constants.SERVICE_REVOKE_BUILD_SHARE_ERROR, err, shareID, buildID)
+		return err
+	}
+
+	if err := repository.RevokeBuildShare(buildID, shareID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrShareNotFound
+		}
+		utils.Log(constants.SERVICE_REVOKE_BUILD_SHARE_ERROR, err, shareID, buildID)
+		return err
+	}
+
+	utils.Log(constants.SERVICE_REVOKE_BUILD_SHARE_SUCCESS, nil, shareID, buildID)
+	return nil
+}
+
+// GetSharedBuild returns the build a share token points at
+func GetSharedBuild(token string) (models.SharedBuild, error) {
+	share, err := resolveBuildShare(token, false)
+	if err != nil {
+		return models.SharedBuild{}, err
+	}
+	return getSharedBuild(share)
+}
+
+// UpdateSharedBuild applies a partial update through an editable share link. Visibility stays with the owner.
+// A set revision must match the build's current revision, as for every change made through a share link.
+func UpdateSharedBuild(token string, revision *int, update models.UserBuildUpdate) (models.SharedBuild, error) {
+	if update.IsPublic != nil {
+		return models.SharedBuild{}, fmt.Errorf("%w: is_public can only be changed by the owner", ErrInvalidBuild)
+	}
+	if err := normalizeBuildUpdate(&update); err != nil {
+		return models.SharedBuild{}, err
+	}
+
+	share, err := resolveBuildShare(token, true)
+	if err != nil {
+		return models.SharedBuild{}, err
+	}
+	if _, err := updateUserBuild(share.BuildID, update, shareActor(share), revision); err != nil {
+		return models.SharedBuild{}, err
+	}
+	return getSharedBuild(share)
+}
+
+// AddSharedBuildPart adds a component through an editable share link
+func AddSharedBuildPart(token string, revision *int, input models.BuildComponentCreate) (models.SharedBuild, error) {
+	if input.BuildID != 0 {
+		return models.SharedBuild{}, fmt.Errorf("%w: build_id cannot be set through a share link", ErrInvalidBuild)
+	}
+
+	share, err := resolveBuildShare(token, true)
+	if err != nil {
+		return models.SharedBuild{}, err
+	}
+	if err := prepareBuildPart(share.BuildID, &input); err != nil {
+		return models.SharedBuild{}, err
+	}
+	if err := addBuildPart(input, shareActor(share), revision); err != nil {
+		return models.SharedBuild{}, err
+	}
+	return getSharedBuild(share)
+}
+
+// UpdateSharedBuildPart changes a part through an editable share link
+func UpdateSharedBuildPart(token string, componentID int64, revision *int, update models.BuildComponentUpdate) (models.SharedBuild, error) {
+	if err := validateBuildPartUpdate(componentID, update); err != nil {
+		return models.SharedBuild{}, err
+	}
+
+	share, err := resolveBuildShare(token, true)
+	if err != nil {
+		return models.SharedBuild{}, err
+	}
+	if err := updateBuildPart(share.BuildID, componentID, update, shareActor(share), revision); err != nil {
+		return models.SharedBuild{}, err
+	}
+	return getSharedBuild(share)
+}
+
+// RemoveSharedBuildPart removes a part through an editable share link
+func RemoveSharedBuildPart(token string, componentID int64, revision *int) (models.SharedBuild, error) {
+	share, err := resolveBuildShare(token, true)
+	if err != nil {
+		return models.SharedBuild{}, err
+	}
+	if err := removeBuildPart(share.BuildID, componentID, shareActor(share), revision); err != nil {
+		return models.SharedBuild{}, err
+	}
+	return getSharedBuild(share)
+}
+
+// resolveBuildShare finds the active share link of a token, requiring edit access when edit is set
+func resolveBuildShare(token string, edit bool) (models.BuildShare, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return models.BuildShare{}, ErrShareNotFound
+	}
+
+	share, err := repository.GetActiveBuildShare(hashShareToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.BuildShare{}, ErrShareNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_RESOLVE_BUILD_SHARE_ERROR, err)
+		return models.BuildShare{}, err
+	}
+	if edit && share.Access != models.BuildShareAccessEdit {
+		return models.BuildShare{}, ErrShareReadOnly
+	}
+	return share, nil
+}
+
+// shareActor names a share link as the author of changes made through it
+func shareActor(share models.BuildShare) string {
+	return fmt.Sprintf("share:%d", share.ID)
+}
+
+func getSharedBuild(share models.BuildShare) (models.SharedBuild, error) {
+	build, err := repository.GetUserBuildById(share.BuildID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SharedBuild{}, ErrShareNotFound
+	}
+	if err != nil {
+		return models.SharedBuild{}, err
+	}
+
+	detailed, err := withBuildDetails(build)
+	if err != nil {
+		return models.SharedBuild{}, err
+	}
+	return toSharedBuild(share, detailed), nil
+}
+
+// toSharedBuild copies what a share link may reveal, leaving out the build, part and owner IDs
+func toSharedBuild(share models.BuildShare, build models.UserBuildWithComponents) models.SharedBuild {
+	shared := models.SharedBuild{
+		Access:      share.Access,
+		ExpiresAt:   share.ExpiresAt,
+		Name:        build.Name,
+		Description: build.Description,
+		IsComplete:  build.IsComplete,
+		TotalPrice:  build.TotalPrice,
+		Currency:    build.Currency,
+		Region:      build.Region,
+		UpdatedAt:   build.UpdatedAt,
+		Revision:    build.Revision,
+		Components:  []models.SharedBuildPart{},
+	}
+	for _, part := range build.Components {
+		shared.Components = append(shared.Components, models.SharedBuildPart{
+			ComponentID:     part.ComponentID,
+			Quantity:        part.Quantity,
+			SelectedPriceID: part.SelectedPriceID,
+			Notes:           part.Notes,
+			Component:       part.Component,
+			SelectedPrice:   part.SelectedPrice,
+		})
+	}
+	if build.Pricing != nil {
+		pricing := *build.Pricing
+		pricing.BuildID = 0
+		shared.Pricing = &pricing
+	}
+	return shared
+}
+
+func generateShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashShareToken returns the hex SHA-256 of a token; only hashes are stored so a database leak exposes no links
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShareTokens tests that share tokens are random and URL safe and that only their hash is stored
+func TestShareTokens(t *testing.T) {
+	first, err := generateShareToken()
+	require.NoError(t, err)
+	second, err := generateShareToken()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.Len(t, first, 43)
+	assert.NotContains(t, first, "/")
+	assert.NotContains(t, first, "+")
+	assert.Len(t, hashShareToken(first), 64)
+	assert.Equal(t, hashShareToken(first), hashShareToken(first))
+	assert.NotEqual(t, first, hashShareToken(first))
+}
+
+// TestToSharedBuild tests that a shared build does not reveal the build, part or owner IDs
+func TestToSharedBuild(t *testing.T) {
+	total := 100.0
+	build := models.UserBuildWithComponents{
+		UserBuild: models.UserBuild{ID: 42, UserID: "owner", Name: "Gaming PC", Currency: "USD", Region: "USA", TotalPrice: &total},
+		Components: []models.BuildComponentWithDetails{
+			{BuildComponent: models.BuildComponent{ID: 9, BuildID: 42, ComponentID: 5, Quantity: 2}},
+		},
+		Pricing: &models.BuildPriceBreakdown{BuildID: 42, Currency: "USD", Total: &total},
+	}
+
+	shared := toSharedBuild(models.BuildShare{ID: 1, BuildID: 42, Access: models.BuildShareAccessEdit}, build)
+	encoded, err := json.Marshal(shared)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gaming PC", shared.Name)
+	assert.Equal(t, int64(5), shared.Components[0].ComponentID)
+	assert.NotContains(t, string(encoded), "build_id")
+	assert.NotContains(t, string(encoded), "owner")
+	assert.NotContains(t, string(encoded), "42")
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// sheetSpec is a spec printed next to the parts of a category. Unit follows numeric values.
+type sheetSpec struct {
+	label string
+	keys  []string
+	unit  string
+}
+
+var buildSheetSpecs = map[models.Category][]sheetSpec{
+	models.CategoryCPU: {
+		{label: "Socket", keys: []string{constants.SPEC_SOCKET}},
+		{label: "Cores", keys: []string{constants.SPEC_CORES, "core_count"}},
+		{label: "Threads", keys: []string{constants.SPEC_THREADS, "thread_count"}},
+		{label: "Base clock", keys: []string{constants.SPEC_BASE_CLOCK}, unit: "GHz"},
+		{label: "Boost clock", keys: []string{constants.SPEC_BOOST_CLOCK}, unit: "GHz"},
+		{label: "TDP", keys: []string{constants.SPEC_TDP}, unit: "W"},
+	},
+	models.CategoryCPUCooler: {
+		{label: "Rated for", keys: []string{constants.SPEC_RATED_TDP}, unit: "W"},
+		{label: "Height", keys: []string{constants.SPEC_HEIGHT_MM}, unit: "mm"},
+	},
+	models.CategoryMotherboard: {
+		{label: "Socket", keys: []string{constants.SPEC_SOCKET}},
+		{label: "Chipset", keys: []string{constants.SPEC_CHIPSET}},
+		{label: "Form factor", keys: []string{constants.SPEC_FORM_FACTOR}},
+		{label: "Memory", keys: []string{constants.SPEC_MEMORY_TYPE}},
+	},
+	models.CategoryMemory: {
+		{label: "Capacity", keys: []string{constants.SPEC_CAPACITY}},
+		{label: "Type", keys: []string{constants.SPEC_TYPE, constants.SPEC_MEMORY_TYPE}},
+		{label: "Speed", keys: []string{constants.SPEC_SPEED}, unit: "MT/s"},
+	},
+	models.CategoryInternalHDD: {
+		{label: "Capacity", keys: []string{constants.SPEC_CAPACITY}},
+		{label: "Interface", keys: []string{constants.SPEC_INTERFACE}},
+		{label: "Form factor", keys: []string{constants.SPEC_FORM_FACTOR}},
+	},
+	models.CategoryVideoCard: {
+		{label: "Chipset", keys: []string{constants.SPEC_CHIPSET}},
+		{label: "Memory", keys: []string{constants.SPEC_VRAM}, unit: "GB"},
+		{label: "Length", keys: []string{constants.SPEC_LENGTH_MM}, unit: "mm"},
+		{label: "TDP", keys: []string{constants.SPEC_TDP}, unit: "W"},
+	},
+	models.CategoryCase: {
+		{label: "Form factor", keys: []string{constants.SPEC_FORM_FACTOR, constants.SPEC_TYPE}},
+		{label: "Fits boards", keys: []string{constants.SPEC_SUPPORTED_FORM_FACTORS}},
+		{label: "Max GPU length", keys: []string{constants.SPEC_MAX_GPU_LENGTH_MM}, unit: "mm"},
+	},
+	models.CategoryPowerSupply: {
+		{label: "Wattage", keys: []string{constants.SPEC_WATTAGE}, unit: "W"},
+		{label: "Efficiency", keys: []string{constants.SPEC_EFFICIENCY}},
+		{label: "Form factor", keys: []string{constants.SPEC_FORM_FACTOR}},
+	},
+	models.CategoryWaterCooling: {
+		{label: "Rated for", keys: []string{constants.SPEC_RATED_TDP}, unit: "W"},
+		{label: "Radiator fans", keys: []string{constants.SPEC_RADIATOR_FANS}},
+	},
+	models.CategoryCaseFan: {
+		{label: "Airflow", keys: []string{constants.SPEC_AIRFLOW_CFM}, unit: "CFM"},
+	},
+}
+
+const buildSheetTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Name }} - Spec Sheet</title>
+<style>
+@page { size: A4; margin: 15mm; }
+* { box-sizing: border-box; }
+body { font-family: "Helvetica Neue", Arial, sans-serif; color: #111; margin: 0 auto; max-width: 190mm; padding: 12mm 0; font-size: 11pt; line-height: 1.4; }
+header { display: flex; justify-content: space-between; align-items: flex-start; gap: 8mm; border-bottom: 2px solid #111; padding-bottom: 4mm; margin-bottom: 6mm; }
+h1 { font-size: 20pt; margin: 0 0 2mm; }
+h2 { font-size: 13pt; margin: 8mm 0 3mm; }
+.meta { color: #555; font-size: 9pt; margin: 0; }
+.description { margin: 2mm 0 0; }
+.qr { width: 32mm; flex-shrink: 0; text-align: center; font-size: 7pt; color: #555; word-break: break-all; }
+.qr svg { width: 32mm; height: 32mm; display: block; }
+table { width: 100%; border-collapse: collapse; }
+th, td { text-align: left; vertical-align: top; padding: 2mm; border-bottom: 1px solid #ccc; }
+th { font-size: 9pt; text-transform: uppercase; letter-spacing: 0.05em; color: #555; border-bottom: 1px solid #111; }
+td.number, th.number { text-align: right; white-space: nowrap; }
+.category { white-space: nowrap; font-weight: bold; }
+.retailer { color: #555; font-size: 9pt; }
+ul.specs { list-style: none; margin: 1mm 0 0; padding: 0; font-size: 9pt; color: #333; }
+ul.specs li { display: inline; margin-right: 3mm; }
+tfoot td { font-weight: bold; font-size: 12pt; border-bottom: none; border-top: 2px solid #111; }
+.note { color: #555; font-size: 9pt; }
+.warning { color: #a00; }
+dl.power { display: grid; grid-template-columns: max-content auto; gap: 1mm 6mm; margin: 0; }
+dl.power dt { color: #555; }
+dl.power dd { margin: 0; }
+tr { page-break-inside: avoid; break-inside: avoid; }
+@media print {
+	body { padding: 0; max-width: none; }
+	a { color: inherit; text-decoration: none; }
+}
+</style>
+</head>
+<body>
+<header>
+	<div>
+		<h1>{{ .Name }}</h1>
+		<p class="meta">Build #{{ .BuildID }} &middot; Prices in {{ .Currency }} for {{ .Region }}</p>
+		{{- if .Description }}
+		<p class="description">{{ .Description }}</p>
+		{{- end }}
+	</div>
+	<div class="qr">
+		{{ svg .QRCode }}
+		{{ .BuildURL }}
+	</div>
+</header>
+
+<h2>Parts</h2>
+<table>
+	<thead>
+		<tr><th>Category</th><th>Part</th><th class="number">Qty</th><th class="number">Price</th><th class="number">Total</th></tr>
+	</thead>
+	<tbody>
+	{{- range .Lines }}
+		<tr>
+			<td class="category">{{ category .Category }}</td>
+			<td>
+				{{ part .BuildExportLine }}
+				{{- if .Retailer }} <span class="retailer">at {{ .Retailer }}</span>{{ end }}
+				{{- if .Highlights }}
+				<ul class="specs">
+				{{- range .Highlights }}
+					<li>{{ .Label }}: {{ .Value }}</li>
+				{{- end }}
+				</ul>
+				{{- end }}
+			</td>
+			<td class="number">{{ .Quantity }}</td>
+			<td class="number">{{ price .UnitPrice }}</td>
+			<td class="number">{{ if .LineTotal }}{{ price .LineTotal }}{{ else }}&mdash;{{ end }}</td>
+		</tr>
+	{{- end }}
+	</tbody>
+	<tfoot>
+		<tr><td colspan="4">Total</td><td class="number">{{ if .Total }}{{ price .Total }} {{ .Currency }}{{ else }}&mdash;{{ end }}</td></tr>
+	</tfoot>
+</table>
+{{- if not .Complete }}
+<p class="note">Some parts have no price in {{ .Region }} and are not included in the total.</p>
+{{- end }}
+
+<h2>Power</h2>
+{{- if .Power.Known }}
+<dl class="power">
+	<dt>Estimated draw</dt><dd>{{ watts .Power.EstimatedDraw }}</dd>
+	<dt>Recommended supply</dt><dd>{{ watts .Power.RecommendedWattage }} or more</dd>
+	{{- if .Power.PSUWattage }}
+	<dt>Power supply</dt><dd>{{ watts .Power.PSUWattage }}{{ if not .Power.Sufficient }} <span class="warning">(below the recommended wattage)</span>{{ end }}</dd>
+	{{- end }}
+</dl>
+{{- else }}
+<p class="note">Power draw cannot be estimated without a CPU that lists its power.</p>
+{{- end }}
+</body>
+</html>
+`
+
+var buildSheetFuncs = template.FuncMap{
+	"part":     exportPartName,
+	"price":    formatExportPrice,
+	"category": sheetCategoryName,
+	"watts":    formatSheetWatts,
+	// The QR code is an SVG drawn by utils.EncodeQRCode, never user input
+	"svg": func(svg string) template.HTML { return template.HTML(svg) },
+}
+
+var buildSheetHTML = template.Must(template.New("sheet").Funcs(buildSheetFuncs).Parse(buildSheetTemplate))
+
+// RenderBuildSheet renders a printable HTML spec sheet of a build visible to viewerID. The QR code links to the
+// build under PUBLIC_BASE_URL.
+func RenderBuildSheet(buildID int64, viewerID string) ([]byte, error) {
+	utils.Log(constants.SERVICE_RENDER_BUILD_SHEET_START, nil, buildID)
+
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_RENDER_BUILD_SHEET_ERROR, err, buildID)
+		return nil, err
+	}
+
+	detailed, err := withBuildDetails(build)
+	if err != nil {
+		utils.Log(constants.SERVICE_RENDER_BUILD_SHEET_ERROR, err, buildID)
+		return nil, err
+	}
+
+	retailerIDs := []int64{}
+	if detailed.Pricing != nil {
+		for _, line := range detailed.Pricing.Lines {
+			if line.RetailerID != nil {
+				retailerIDs = append(retailerIDs, *line.RetailerID)
+			}
+		}
+	}
+	retailers, err := repository.GetRetailerNames(retailerIDs)
+	if err != nil {
+		utils.Log(constants.SERVICE_RENDER_BUILD_SHEET_ERROR, err, buildID)
+		return nil, err
+	}
+
+	sheet, err := NewBuildSheet(detailed, retailers, BuildSheetURL(buildID))
+	if err != nil {
+		utils.Log(constants.SERVICE_RENDER_BUILD_SHEET_ERROR, err, buildID)
+		return nil, err
+	}
+
+	output, err := RenderBuildSheetHTML(sheet)
+	if err != nil {
+		utils.Log(constants.SERVICE_RENDER_BUILD_SHEET_ERROR, err, buildID)
+		return nil, err
+	}
+
+	utils.Log(constants.SERVICE_RENDER_BUILD_SHEET_SUCCESS, nil, buildID)
+	return output, nil
+}
+
+// BuildSheetURL returns the link to a build printed on its spec sheet. It never comes from the request, so a forged
+// Host header cannot point printed sheets at another site.
+func BuildSheetURL(buildID int64) string {
+	baseURL := strings.TrimSpace(os.Getenv("PUBLIC_BASE_URL"))
+	if baseURL == "" {
+		baseURL = constants.DEFAULT_PUBLIC_BASE_URL
+	}
+	return fmt.Sprintf("%s/builds/%d", strings.TrimRight(baseURL, "/"), buildID)
+}
+
+// NewBuildSheet adds spec highlights, a power estimate and a QR code linking to buildURL to the export of a build
+func NewBuildSheet(build models.UserBuildWithComponents, retailers map[int64]string, buildURL string) (models.BuildSheet, error) {
+	qr, err := utils.EncodeQRCode(buildURL)
+	if err != nil {
+		return models.BuildSheet{}, err
+	}
+
+	export := NewBuildExport(build, retailers)
+	sheet := models.BuildSheet{
+		BuildID:     build.ID,
+		Name:        export.Name,
+		Description: export.Description,
+		Currency:    export.Currency,
+		Region:      export.Region,
+		Total:       export.Total,
+		Complete:    export.Complete,
+		Lines:       make([]models.BuildSheetLine, 0, len(export.Lines)),
+		Power:       estimateSheetPower(build.Components),
+		BuildURL:    buildURL,
+		QRCode:      qr.SVG(),
+	}
+
+	// NewBuildExport adds one line per part, in order
+	for i, line := range export.Lines {
+		sheetLine := models.BuildSheetLine{BuildExportLine: line, Highlights: []models.SpecHighlight{}}
+		if component := build.Components[i].Component; component != nil {
+			sheetLine.Highlights = sheetHighlights(*component)
+		}
+		sheet.Lines = append(sheet.Lines, sheetLine)
+	}
+	return sheet, nil
+}
+
+// RenderBuildSheetHTML executes the spec sheet template
+func RenderBuildSheetHTML(sheet models.BuildSheet) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := buildSheetHTML.Execute(&buf, sheet); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sheetHighlights formats the specs of a component printed on spec sheets, skipping the ones it does not list
+func sheetHighlights(component models.Component) []models.SpecHighlight {
+	specs := utils.ParseSpecs(component.Specs)
+	highlights := []models.SpecHighlight{}
+	for _, spec := range buildSheetSpecs[component.Category] {
+		value, ok := utils.GetSpecValue(specs, spec.keys...)
+		if !ok {
+			continue
+		}
+		if formatted := formatSheetSpec(value, spec.unit); formatted != "" {
+			highlights = append(highlights, models.SpecHighlight{Label: spec.label, Value: formatted})
+		}
+	}
+	return highlights
+}
+
+// formatSheetSpec formats a spec value for display. Units are only added to bare numbers since text values such
+// as "3.6 GHz" usually carry their own.
+func formatSheetSpec(value interface{}, unit string) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "Yes"
+		}
+		return "No"
+	case []interface{}:
+		list, _ := utils.SpecValueToStringList(v)
+		return strings.Join(list, ", ")
+	case string:
+		text := strings.TrimSpace(v)
+		if _, err := strconv.ParseFloat(text, 64); err == nil && unit != "" {
+			return text + " " + unit
+		}
+		return text
+	}
+	text, ok := utils.SpecValueToString(value)
+	if !ok {
+		return ""
+	}
+	if unit != "" {
+		return text + " " + unit
+	}
+	return text
+}
+
+// estimateSheetPower estimates the draw of a build the way the build generator does and recommends a power
+// supply with the same headroom, rounded up to a common wattage
+func estimateSheetPower(parts []models.BuildComponentWithDetails) models.BuildSheetPower {
+	power := models.BuildSheetPower{}
+	if findPart(parts, models.CategoryCPU) == nil {
+		return power
+	}
+
+	// Each unit draws power, so a part's draw is multiplied by its quantity
+	draw := constants.GENERATOR_PLATFORM_POWER_W
+	for _, part := range parts {
+		if part.Component == nil {
+			continue
+		}
+		unitDraw, known := componentPowerDraw(*part.Component)
+		if !known {
+			return power
+		}
+		draw += unitDraw * float64(max(part.Quantity, 1))
+	}
+
+	power.Known = true
+	power.EstimatedDraw = draw
+	power.RecommendedWattage = math.Ceil(draw*constants.GENERATOR_PSU_HEADROOM/constants.SHEET_PSU_WATTAGE_STEP) * constants.SHEET_PSU_WATTAGE_STEP
+	if psu := findPart(parts, models.CategoryPowerSupply); psu != nil {
+		if wattage, ok := utils.GetSpecNumber(utils.ParseSpecs(psu.Specs), constants.SPEC_WATTAGE); ok {
+			power.PSUWattage = &wattage
+			power.Sufficient = wattage >= power.RecommendedWattage
+		}
+	}
+	return power
+}
+
+// sheetCategoryName turns a category such as "cpu_cooler" into "CPU Cooler"
+func sheetCategoryName(category string) string {
+	words := strings.Split(category, "_")
+	for i, word := range words {
+		switch word {
+		case "cpu", "os", "hdd", "ups":
+			words[i] = strings.ToUpper(word)
+		default:
+			if word != "" {
+				words[i] = strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func formatSheetWatts(watts interface{}) string {
+	switch w := watts.(type) {
+	case float64:
+		return fmt.Sprintf("%.0f W", w)
+	case *float64:
+		if w != nil {
+			return fmt.Sprintf("%.0f W", *w)
+		}
+	}
+	return ""
+}
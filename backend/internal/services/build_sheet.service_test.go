@@ -0,0 +1,144 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/testutils"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sheetFixture() models.UserBuildWithComponents {
+	price := func(value float64) *float64 { return &value }
+	retailer := int64(7)
+	description := "Quiet <script>alert(1)</script> build"
+	part := func(id int64, category models.Category, model, specs string, quantity int) models.BuildComponentWithDetails {
+		return models.BuildComponentWithDetails{
+			BuildComponent: models.BuildComponent{ComponentID: id, Quantity: quantity},
+			Component:      &models.Component{Category: category, Brand: "Brand", Model: model, Specs: json.RawMessage(specs)},
+		}
+	}
+
+	return models.UserBuildWithComponents{
+		UserBuild: models.UserBuild{ID: 42, Name: "Shop build", Description: &description, Currency: "usd", Region: "USA"},
+		Components: []models.BuildComponentWithDetails{
+			part(1, models.CategoryCPU, "Eight Core", `{"socket": "AM5", "cores": 8, "base_clock": "4.2 GHz", "tdp": 120}`, 1),
+			part(2, models.CategoryVideoCard, "Fast GPU", `{"memory": 16, "tdp": 300}`, 1),
+			part(3, models.CategoryPowerSupply, "550W", `{"wattage": 550, "efficiency": "80+ Gold"}`, 1),
+			part(4, models.CategoryCaseFan, "120mm", `{}`, 3),
+		},
+		Pricing: &models.BuildPriceBreakdown{
+			Currency: "USD",
+			Total:    price(1100),
+			Complete: false,
+			Lines: []models.BuildPriceLine{
+				{ComponentID: 1, RetailerID: &retailer, UnitPrice: price(400), LineTotal: price(400)},
+				{ComponentID: 2, RetailerID: &retailer, UnitPrice: price(600), LineTotal: price(600)},
+				{ComponentID: 3, UnitPrice: price(100), LineTotal: price(100)},
+			},
+		},
+	}
+}
+
+// TestNewBuildSheet tests the spec highlights, power estimate and QR code of a spec sheet
+func TestNewBuildSheet(t *testing.T) {
+	sheet, err := NewBuildSheet(sheetFixture(), map[int64]string{7: "Newegg"}, "https://shop.example.com/builds/42")
+
+	require.NoError(t, err)
+	require.Len(t, sheet.Lines, 4)
+	assert.Equal(t, "Newegg", sheet.Lines[0].Retailer)
+	assert.Equal(t, []models.SpecHighlight{
+		{Label: "Socket", Value: "AM5"},
+		{Label: "Cores", Value: "8"},
+		{Label: "Base clock", Value: "4.2 GHz"},
+		{Label: "TDP", Value: "120 W"},
+	}, sheet.Lines[0].Highlights)
+	assert.Equal(t, []models.SpecHighlight{{Label: "Memory", Value: "16 GB"}, {Label: "TDP", Value: "300 W"}}, sheet.Lines[1].Highlights)
+	assert.Empty(t, sheet.Lines[3].Highlights)
+	assert.Equal(t, 3, sheet.Lines[3].Quantity)
+
+	assert.True(t, sheet.Power.Known)
+	assert.Equal(t, 495.0, sheet.Power.EstimatedDraw)
+	assert.Equal(t, 650.0, sheet.Power.RecommendedWattage)
+	require.NotNil(t, sheet.Power.PSUWattage)
+	assert.False(t, sheet.Power.Sufficient)
+
+	assert.Contains(t, sheet.QRCode, "<svg")
+}
+
+// TestEstimateSheetPower_Quantity tests that every unit of a part is counted in the power estimate
+func TestEstimateSheetPower_Quantity(t *testing.T) {
+	build := sheetFixture()
+	build.Components[1].Quantity = 2
+
+	power := estimateSheetPower(build.Components)
+
+	assert.True(t, power.Known)
+	assert.Equal(t, 795.0, power.EstimatedDraw)
+	assert.Equal(t, 1050.0, power.RecommendedWattage)
+	assert.False(t, power.Sufficient)
+}
+
+// TestRenderBuildSheetHTML tests that the spec sheet escapes user text and prints its sections
+func TestRenderBuildSheetHTML(t *testing.T) {
+	sheet, err := NewBuildSheet(sheetFixture(), map[int64]string{7: "Newegg"}, "https://shop.example.com/builds/42")
+	require.NoError(t, err)
+
+	output, err := RenderBuildSheetHTML(sheet)
+
+	require.NoError(t, err)
+	html := string(output)
+	assert.Contains(t, html, "<title>Shop build - Spec Sheet</title>")
+	assert.Contains(t, html, "Quiet &lt;script&gt;alert(1)&lt;/script&gt; build")
+	assert.NotContains(t, html, "<script>")
+	assert.Contains(t, html, "@media print")
+	assert.Contains(t, html, `<svg xmlns="http://www.w3.org/2000/svg"`)
+	assert.Contains(t, html, "https://shop.example.com/builds/42")
+	assert.Contains(t, html, "CPU")
+	assert.Contains(t, html, "Case Fan")
+	assert.Contains(t, html, "<li>Efficiency: 80&#43; Gold</li>")
+	assert.Contains(t, html, "1100.00 USD")
+	assert.Contains(t, html, "Some parts have no price in USA")
+	assert.Contains(t, html, "(below the recommended wattage)")
+}
+
+// TestBuildSheetURL tests that sheets link to PUBLIC_BASE_URL, or the default site when it is not set
+func TestBuildSheetURL(t *testing.T) {
+	t.Setenv("PUBLIC_BASE_URL", "")
+	assert.Equal(t, "http://localhost:3000/builds/3", BuildSheetURL(3))
+
+	t.Setenv("PUBLIC_BASE_URL", "https://shop.example.com/")
+	assert.Equal(t, "https://shop.example.com/builds/3", BuildSheetURL(3))
+}
+
+// TestBuildSheetQRCode tests that the QR code printed on a sheet scans back to the build's link, from the small
+// version holding the default link up to the largest version
+func TestBuildSheetQRCode(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		version int
+	}{
+		{baseURL: "", version: 3},
+		{baseURL: "https://shop.example.com", version: 3},
+		{baseURL: "https://shop.example.com/" + strings.Repeat("a", 40), version: 5},
+		{baseURL: "https://shop.example.com/" + strings.Repeat("b", 80), version: 7},
+		{baseURL: "https://shop.example.com/" + strings.Repeat("c", 170), version: 10},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("PUBLIC_BASE_URL", tt.baseURL)
+		buildURL := BuildSheetURL(42)
+
+		qr, err := utils.EncodeQRCode(buildURL)
+		require.NoError(t, err)
+		assert.Equal(t, tt.version, qr.Version, buildURL)
+
+		scanned, err := testutils.ScanQRCode(qr.Size, qr.Dark)
+		require.NoError(t, err, buildURL)
+		assert.Equal(t, buildURL, scanned)
+	}
+}
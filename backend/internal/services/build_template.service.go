@@ -0,0 +1,417 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+var (
+	// ErrTemplateNotFound is returned when a build template does not exist
+	ErrTemplateNotFound = errors.New("build template not found")
+	// ErrTemplateUnfillable is returned when no priced part matches a required slot of a template
+	ErrTemplateUnfillable = errors.New("template slot cannot be filled")
+)
+
+// IsAdmin returns true if userID is listed in ADMIN_USER_IDS, a comma-separated list of user ids
+func IsAdmin(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if strings.TrimSpace(id) == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// ListBuildTemplates returns every build template
+func ListBuildTemplates() ([]models.BuildTemplate, error) {
+	utils.Log(constants.SERVICE_LIST_BUILD_TEMPLATES_START, nil)
+
+	templates, err := repository.ListBuildTemplates()
+	if err != nil {
+		utils.Log(constants.SERVICE_LIST_BUILD_TEMPLATES_ERROR, err)
+		return nil, err
+	}
+
+	utils.Log(constants.SERVICE_LIST_BUILD_TEMPLATES_SUCCESS, nil, len(templates))
+	return templates, nil
+}
+
+// GetBuildTemplate returns a build template, or ErrTemplateNotFound
+func GetBuildTemplate(templateID int64) (models.BuildTemplate, error) {
+	utils.Log(constants.SERVICE_GET_BUILD_TEMPLATE_START, nil, templateID)
+
+	template, err := repository.GetBuildTemplate(templateID)
+	if err == sql.ErrNoRows {
+		err = ErrTemplateNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_BUILD_TEMPLATE_ERROR, err, templateID)
+		return models.BuildTemplate{}, err
+	}
+
+	utils.Log(constants.SERVICE_GET_BUILD_TEMPLATE_SUCCESS, nil, templateID)
+	return template, nil
+}
+
+// CreateBuildTemplate validates and stores a template created by userID
+func CreateBuildTemplate(userID string, input models.BuildTemplateInput) (models.BuildTemplate, error) {
+	utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_START, nil, input.Name, userID)
+
+	if err := normalizeBuildTemplate(&input); err != nil {
+		utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_ERROR, err, input.Name)
+		return models.BuildTemplate{}, err
+	}
+
+	template, err := repository.CreateBuildTemplate(input, userID)
+	if err != nil {
+		utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_ERROR, err, input.Name)
+		return models.BuildTemplate{}, err
+	}
+
+	utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_SUCCESS, nil, template.ID)
+	return template, nil
+}
+
+// UpdateBuildTemplate validates and replaces the definition of a template. Builds made from it are not changed.
+func UpdateBuildTemplate(templateID int64, userID string, input models.BuildTemplateInput) (models.BuildTemplate, error) {
+	utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_START, nil, input.Name, userID)
+
+	if err := normalizeBuildTemplate(&input); err != nil {
+		utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_ERROR, err, input.Name)
+		return models.BuildTemplate{}, err
+	}
+
+	template, err := repository.UpdateBuildTemplate(templateID, input)
+	if err == sql.ErrNoRows {
+		err = ErrTemplateNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_ERROR, err, input.Name)
+		return models.BuildTemplate{}, err
+	}
+
+	utils.Log(constants.SERVICE_SAVE_BUILD_TEMPLATE_SUCCESS, nil, template.ID)
+	return template, nil
+}
+
+// DeleteBuildTemplate removes a template, or returns ErrTemplateNotFound
+func DeleteBuildTemplate(templateID int64) error {
+	utils.Log(constants.SERVICE_DELETE_BUILD_TEMPLATE_START, nil, templateID)
+
+	err := repository.DeleteBuildTemplate(templateID)
+	if err == sql.ErrNoRows {
+		err = ErrTemplateNotFound
+	}
+	if err != nil {
+		utils.Log(constants.SERVICE_DELETE_BUILD_TEMPLATE_ERROR, err, templateID)
+		return err
+	}
+
+	utils.Log(constants.SERVICE_DELETE_BUILD_TEMPLATE_SUCCESS, nil, templateID)
+	return nil
+}
+
+// normalizeBuildTemplate trims and checks a template definition, filling in the default use case and currency
+func normalizeBuildTemplate(input *models.BuildTemplateInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidBuild)
+	}
+	if input.TargetBudget <= 0 {
+		return fmt.Errorf("%w: target_budget must be positive", ErrInvalidBuild)
+	}
+	if input.Currency == "" {
+		input.Currency = baseCurrency
+	}
+	if err := normalizeBuildLocale(&input.Currency, nil); err != nil {
+		return err
+	}
+
+	input.UseCase = strings.ToLower(strings.TrimSpace(input.UseCase))
+	if input.UseCase == "" {
+		input.UseCase = constants.DEFAULT_USE_CASE
+	}
+	if !IsValidGeneratorUseCase(input.UseCase) {
+		return fmt.Errorf("%w: use_case must be one of gaming, workstation, office or sff", ErrInvalidBuild)
+	}
+	if input.Resolution != nil {
+		resolution := strings.ToLower(strings.TrimSpace(*input.Resolution))
+		if resolution != "" && !IsValidResolution(resolution) {
+			return fmt.Errorf("%w: resolution must be 1080p, 1440p or 4k", ErrInvalidBuild)
+		}
+		input.Resolution = &resolution
+		if resolution == "" {
+			input.Resolution = nil
+		}
+	}
+
+	if len(input.Slots) == 0 {
+		return fmt.Errorf("%w: a template needs at least one slot", ErrInvalidBuild)
+	}
+	if len(input.Slots) > constants.TEMPLATE_MAX_SLOTS {
+		return fmt.Errorf("%w: a template can have at most %d slots", ErrInvalidBuild, constants.TEMPLATE_MAX_SLOTS)
+	}
+	seen := map[models.Category]bool{}
+	for i := range input.Slots {
+		slot := &input.Slots[i]
+		if !slot.Category.Valid() {
+			return fmt.Errorf("%w: slot %d has an unknown category %q", ErrInvalidBuild, i+1, slot.Category)
+		}
+		if seen[slot.Category] {
+			return fmt.Errorf("%w: only one slot can be a %s", ErrInvalidBuild, slot.Category)
+		}
+		seen[slot.Category] = true
+
+		if slot.Quantity == 0 {
+			slot.Quantity = 1
+		}
+		if slot.Quantity < 1 || slot.Quantity > constants.TEMPLATE_MAX_QUANTITY {
+			return fmt.Errorf("%w: %s quantity must be between 1 and %d", ErrInvalidBuild, slot.Category, constants.TEMPLATE_MAX_QUANTITY)
+		}
+		if len(slot.Constraints) > constants.TEMPLATE_MAX_CONSTRAINTS {
+			return fmt.Errorf("%w: a slot can have at most %d constraints", ErrInvalidBuild, constants.TEMPLATE_MAX_CONSTRAINTS)
+		}
+		for j := range slot.Constraints {
+			constraint := &slot.Constraints[j]
+			constraint.SpecKey = strings.TrimSpace(constraint.SpecKey)
+			if constraint.SpecKey == "" || !constraint.Operator.Valid() || constraint.Value == nil {
+				return fmt.Errorf("%w: %s constraint %d needs a spec_key, a valid operator and a value", ErrInvalidBuild, slot.Category, j+1)
+			}
+		}
+	}
+	return nil
+}
+
+// CreateBuildFromTemplate creates a build owned by userID with every slot of a template filled by the best
+// priced part that matches its constraints
+func CreateBuildFromTemplate(templateID int64, userID string, input models.BuildFromTemplate) (models.BuildFromTemplateResult, error) {
+	utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_START, nil, templateID, userID)
+
+	template, err := GetBuildTemplate(templateID)
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR, err, templateID, userID)
+		return models.BuildFromTemplateResult{}, err
+	}
+
+	create := models.UserBuildCreate{
+		UserID:      userID,
+		Name:        template.Name,
+		Description: template.Description,
+		IsPublic:    input.IsPublic,
+		Currency:    &template.Currency,
+		Region:      input.Region,
+		UseCase:     &template.UseCase,
+	}
+	if input.Name != nil {
+		create.Name = *input.Name
+	}
+	if input.Description != nil {
+		create.Description = input.Description
+	}
+	if err := normalizeBuildCreate(&create); err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR, err, templateID, userID)
+		return models.BuildFromTemplateResult{}, err
+	}
+
+	request := templateGeneratorRequest(template)
+	if create.Region != nil {
+		request.Region = *create.Region
+	}
+	if err := normalizeGeneratorRequest(&request); err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR, err, templateID, userID)
+		return models.BuildFromTemplateResult{}, err
+	}
+
+	catalog, err := loadTemplateCatalog(template, request)
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR, err, templateID, userID)
+		return models.BuildFromTemplateResult{}, err
+	}
+	filled, unfilled, err := FillTemplateFromCatalog(template, request, catalog, GetCompatibilityRules())
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR, err, templateID, userID)
+		return models.BuildFromTemplateResult{}, err
+	}
+
+	quantities := templateQuantities(template)
+	parts := make([]models.BuildVersionPart, 0, len(filled.Parts))
+	for _, part := range filled.Parts {
+		componentID, _ := strconv.ParseInt(part.Component.ID, 10, 64)
+		parts = append(parts, models.BuildVersionPart{ComponentID: componentID, Quantity: quantities[part.Component.Category]})
+	}
+
+	build, err := repository.CreateUserBuildWithParts(create, parts, buildSnapshot(userID, fmt.Sprintf("Created from template %s", template.Name)))
+	if err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR, err, templateID, userID)
+		return models.BuildFromTemplateResult{}, err
+	}
+
+	result := models.BuildFromTemplateResult{TemplateID: templateID, Unfilled: unfilled}
+	if result.Build, err = withBuildDetails(build); err != nil {
+		utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_ERROR, err, templateID, userID)
+		return models.BuildFromTemplateResult{}, err
+	}
+
+	utils.Log(constants.SERVICE_CREATE_BUILD_FROM_TEMPLATE_SUCCESS, nil, build.ID, templateID, len(unfilled))
+	return result, nil
+}
+
+func templateGeneratorRequest(template models.BuildTemplate) models.BuildGeneratorRequest {
+	request := models.BuildGeneratorRequest{Budget: template.TargetBudget, Currency: template.Currency, UseCase: template.UseCase}
+	if template.Resolution != nil {
+		request.Resolution = *template.Resolution
+	}
+	return request
+}
+
+// templateQuantities returns the units of each slot category, defaulting to one
+func templateQuantities(template models.BuildTemplate) map[models.Category]int {
+	quantities := map[models.Category]int{}
+	for _, category := range generatorCategories {
+		quantities[category] = 1
+	}
+	for _, slot := range template.Slots {
+		quantities[slot.Category] = max(slot.Quantity, 1)
+	}
+	return quantities
+}
+
+// loadTemplateCatalog prices the in-stock parts of the generator categories and the template's slot categories
+func loadTemplateCatalog(template models.BuildTemplate, request models.BuildGeneratorRequest) ([]models.GeneratedBuildPart, error) {
+	categories := append([]models.Category{}, generatorCategories...)
+	for _, slot := range template.Slots {
+		if !slices.Contains(generatorCategories, slot.Category) {
+			categories = append(categories, slot.Category)
+		}
+	}
+
+	components, prices, err := repository.ListInStockComponents(categories, request.Region)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := repository.GetExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+
+	byComponent := map[int64][]models.Price{}
+	for _, price := range prices {
+		byComponent[price.ComponentID] = append(byComponent[price.ComponentID], price)
+	}
+	return priceCatalog(components, byComponent, request.Currency, rates), nil
+}
+
+// FillTemplateFromCatalog picks the parts of a template build within its target budget. Parts that break a slot's
+// constraints are left out and prices are multiplied by the slot quantity. Required slots outside the generator
+// categories take their cheapest matching part up front; the generator then scores the core parts for the
+// template's use case. Slots still empty afterwards, such as a video card an integrated GPU made optional, get the
+// cheapest matching part that is compatible and fits the remaining budget. A required slot that stays empty is an
+// error; optional ones are returned as unfilled.
+func FillTemplateFromCatalog(template models.BuildTemplate, request models.BuildGeneratorRequest, catalog []models.GeneratedBuildPart, rules []models.CompatibilityRule) (models.GeneratedBuild, []models.Category, error) {
+	quantities := templateQuantities(template)
+	slots := map[models.Category]models.TemplateSlot{}
+	for _, slot := range template.Slots {
+		slots[slot.Category] = slot
+	}
+
+	matching := map[models.Category][]models.GeneratedBuildPart{}
+	for _, part := range catalog {
+		category := part.Component.Category
+		if !matchesTemplateSlot(part.Component, slots[category]) {
+			continue
+		}
+		part.UnitPrice = roundTo(part.UnitPrice*float64(quantities[category]), 2)
+		part.Pinned = false
+		matching[category] = append(matching[category], part)
+	}
+	for category := range matching {
+		list := matching[category]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].UnitPrice < list[j].UnitPrice })
+	}
+
+	generated := map[models.Category]bool{}
+	for _, category := range generatorCategories {
+		generated[category] = true
+	}
+
+	parts := []models.GeneratedBuildPart{}
+	for _, category := range generatorCategories {
+		parts = append(parts, matching[category]...)
+	}
+	for _, slot := range template.Slots {
+		if generated[slot.Category] || !slot.Required {
+			continue
+		}
+		if len(matching[slot.Category]) == 0 {
+			return models.GeneratedBuild{}, nil, fmt.Errorf("%w: no in-stock %s matches the template", ErrTemplateUnfillable, slot.Category)
+		}
+		extra := matching[slot.Category][0]
+		extra.Pinned = true
+		parts = append(parts, extra)
+	}
+
+	result, err := GenerateBuildFromCatalog(request, parts, rules)
+	if err != nil {
+		return models.GeneratedBuild{}, nil, err
+	}
+	build := result.Build
+
+	unfilled := []models.Category{}
+	for _, slot := range template.Slots {
+		if _, ok := findGeneratedCategory(build.Parts, slot.Category); ok {
+			continue
+		}
+		part, ok := cheapestTemplatePart(matching[slot.Category], build, request.Budget, rules)
+		if ok {
+			build.Parts = append(build.Parts, part)
+			build.Total = roundTo(build.Total+part.UnitPrice, 2)
+			continue
+		}
+		if slot.Required {
+			return models.GeneratedBuild{}, nil, fmt.Errorf("%w: no compatible %s fits the rest of the template budget", ErrTemplateUnfillable, slot.Category)
+		}
+		unfilled = append(unfilled, slot.Category)
+	}
+	return build, unfilled, nil
+}
+
+// matchesTemplateSlot accepts a component meeting every constraint of its slot; a missing spec fails the constraint
+func matchesTemplateSlot(component models.Component, slot models.TemplateSlot) bool {
+	if len(slot.Constraints) == 0 {
+		return true
+	}
+	specs := utils.ParseSpecs(component.Specs)
+	for _, constraint := range slot.Constraints {
+		if passed, known := EvaluateSpecPredicate(specs, constraint); !passed || !known {
+			return false
+		}
+	}
+	return true
+}
+
+// cheapestTemplatePart returns the cheapest candidate that fits the remaining budget and is compatible with the build
+func cheapestTemplatePart(candidates []models.GeneratedBuildPart, build models.GeneratedBuild, budget float64, rules []models.CompatibilityRule) (models.GeneratedBuildPart, bool) {
+	for _, candidate := range candidates {
+		if build.Total+candidate.UnitPrice > budget {
+			break
+		}
+		if fitsGeneratedBuild(append(build.Parts[:len(build.Parts):len(build.Parts)], candidate), candidate.Component.ID, rules) {
+			return candidate, true
+		}
+	}
+	return models.GeneratedBuildPart{}, false
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFillTemplateFromCatalog tests that template slots are filled with matching parts within the target budget
+func TestFillTemplateFromCatalog(t *testing.T) {
+	rules := []models.CompatibilityRule{{
+		ID:             "cpu-motherboard-socket",
+		SourceCategory: models.CategoryCPU,
+		TargetCategory: models.CategoryMotherboard,
+		Comparisons:    []models.RuleComparison{{SourceKey: "socket", Operator: models.SpecOperatorEquals, TargetKey: "socket"}},
+		Severity:       models.RuleSeverityError,
+		Message:        "socket mismatch",
+	}}
+
+	catalog := []models.GeneratedBuildPart{
+		catalogPart(models.CategoryCPU, "1", "Six core CPU", `{"socket": "AM4", "tdp": 65, "core_count": 6, "includes_cooler": true, "integrated_graphics": true, "performance_gaming": 40}`, 100),
+		catalogPart(models.CategoryCPU, "2", "Eight core CPU", `{"socket": "AM5", "tdp": 120, "core_count": 8, "performance_gaming": 90}`, 400),
+		catalogPart(models.CategoryCPU, "3", "Unlisted CPU", `{"socket": "AM5", "tdp": 120, "performance_gaming": 95}`, 420),
+		catalogPart(models.CategoryVideoCard, "10", "8GB GPU", `{"tdp": 100, "memory": 8, "performance_gaming": 40}`, 200),
+		catalogPart(models.CategoryVideoCard, "11", "16GB GPU", `{"tdp": 300, "memory": 16, "performance_gaming": 90}`, 800),
+		catalogPart(models.CategoryMotherboard, "20", "AM4 ATX", `{"socket": "AM4", "form_factor": "ATX", "m2_slots": [{"name": "M2_1", "interfaces": ["nvme"]}]}`, 80),
+		catalogPart(models.CategoryMotherboard, "21", "AM5 ATX", `{"socket": "AM5", "form_factor": "ATX", "m2_slots": [{"name": "M2_1", "interfaces": ["nvme"]}]}`, 150),
+		catalogPart(models.CategoryMemory, "30", "16GB DDR5", `{}`, 45),
+		catalogPart(models.CategoryCPUCooler, "40", "Tower cooler", `{"rated_tdp": 220}`, 45),
+		catalogPart(models.CategoryInternalHDD, "50", "1TB NVMe", `{"interface": "PCIe 4.0 x4 NVMe", "form_factor": "M.2-2280"}`, 60),
+		catalogPart(models.CategoryCase, "60", "ATX tower", `{"supported_form_factors": ["ATX", "Micro-ATX", "Mini-ITX"]}`, 70),
+		catalogPart(models.CategoryPowerSupply, "70", "850W", `{"wattage": 850}`, 110),
+		catalogPart(models.CategoryOS, "80", "Home OS", `{"edition": "Home"}`, 120),
+		catalogPart(models.CategoryOS, "81", "Pro OS", `{"edition": "Pro"}`, 200),
+		catalogPart(models.CategoryCaseFan, "90", "120mm fan", `{}`, 15),
+		catalogPart(models.CategoryMonitor, "100", "4K monitor", `{}`, 400),
+	}
+	request := func(template models.BuildTemplate) models.BuildGeneratorRequest {
+		request := templateGeneratorRequest(template)
+		require.NoError(t, normalizeGeneratorRequest(&request))
+		return request
+	}
+
+	t.Run("Fills slots with parts matching their constraints", func(t *testing.T) {
+		template := models.BuildTemplate{
+			Name: "Streaming", UseCase: constants.USE_CASE_GAMING, TargetBudget: 2000, Currency: "USD",
+			Slots: []models.TemplateSlot{
+				{Category: models.CategoryCPU, Required: true, Constraints: []models.SpecPredicate{{SpecKey: "core_count", Operator: models.SpecOperatorGTE, Value: 8}}},
+				{Category: models.CategoryMemory, Required: true, Quantity: 2},
+				{Category: models.CategoryOS, Required: true},
+				{Category: models.CategoryCaseFan, Quantity: 3},
+			},
+		}
+
+		build, unfilled, err := FillTemplateFromCatalog(template, request(template), catalog, rules)
+
+		require.NoError(t, err)
+		ids := generatedPartIDs(build)
+		assert.Equal(t, "2", ids[models.CategoryCPU], "the CPU without a core count cannot be shown to match")
+		assert.Equal(t, "80", ids[models.CategoryOS])
+		assert.Equal(t, "90", ids[models.CategoryCaseFan])
+		assert.Empty(t, unfilled)
+		for _, part := range build.Parts {
+			switch part.Component.Category {
+			case models.CategoryMemory:
+				assert.Equal(t, 90.0, part.UnitPrice)
+			case models.CategoryCaseFan:
+				assert.Equal(t, 45.0, part.UnitPrice)
+			}
+		}
+		assert.LessOrEqual(t, build.Total, template.TargetBudget)
+	})
+
+	t.Run("Adds a required video card the use case would skip", func(t *testing.T) {
+		template := models.BuildTemplate{
+			Name: "Home Server", UseCase: constants.USE_CASE_OFFICE, TargetBudget: 700, Currency: "USD",
+			Slots: []models.TemplateSlot{{Category: models.CategoryVideoCard, Required: true}},
+		}
+
+		build, _, err := FillTemplateFromCatalog(template, request(template), catalog, rules)
+
+		require.NoError(t, err)
+		ids := generatedPartIDs(build)
+		assert.Equal(t, "1", ids[models.CategoryCPU])
+		assert.Equal(t, "10", ids[models.CategoryVideoCard])
+	})
+
+	t.Run("Optional slot over budget is left unfilled", func(t *testing.T) {
+		template := models.BuildTemplate{
+			Name: "Office", UseCase: constants.USE_CASE_OFFICE, TargetBudget: 600, Currency: "USD",
+			Slots: []models.TemplateSlot{{Category: models.CategoryMonitor}},
+		}
+
+		build, unfilled, err := FillTemplateFromCatalog(template, request(template), catalog, rules)
+
+		require.NoError(t, err)
+		assert.NotContains(t, generatedPartIDs(build), models.CategoryMonitor)
+		assert.Equal(t, []models.Category{models.CategoryMonitor}, unfilled)
+	})
+
+	t.Run("Required slot without a matching part", func(t *testing.T) {
+		template := models.BuildTemplate{
+			Name: "Server", UseCase: constants.USE_CASE_OFFICE, TargetBudget: 1000, Currency: "USD",
+			Slots: []models.TemplateSlot{{Category: models.CategoryOS, Required: true, Constraints: []models.SpecPredicate{{SpecKey: "edition", Operator: models.SpecOperatorEquals, Value: "Server"}}}},
+		}
+
+		_, _, err := FillTemplateFromCatalog(template, request(template), catalog, rules)
+
+		assert.ErrorIs(t, err, ErrTemplateUnfillable)
+	})
+
+	t.Run("Constraints no part within budget meets", func(t *testing.T) {
+		template := models.BuildTemplate{
+			Name: "4K Gaming", UseCase: constants.USE_CASE_GAMING, TargetBudget: 1000, Currency: "USD",
+			Slots: []models.TemplateSlot{{Category: models.CategoryVideoCard, Required: true, Constraints: []models.SpecPredicate{{SpecKey: "memory", Operator: models.SpecOperatorGTE, Value: 16}}}},
+		}
+
+		_, _, err := FillTemplateFromCatalog(template, request(template), catalog, rules)
+
+		assert.ErrorIs(t, err, ErrBudgetTooLow)
+	})
+}
+
+// TestNormalizeBuildTemplate tests the validation and defaults of template definitions
+func TestNormalizeBuildTemplate(t *testing.T) {
+	valid := func() models.BuildTemplateInput {
+		return models.BuildTemplateInput{
+			Name:         " 1080p Esports ",
+			TargetBudget: 900,
+			Slots: []models.TemplateSlot{
+				{Category: models.CategoryCPU, Required: true, Constraints: []models.SpecPredicate{{SpecKey: "core_count", Operator: models.SpecOperatorGTE, Value: 6}}},
+				{Category: models.CategoryVideoCard, Required: true},
+			},
+		}
+	}
+
+	t.Run("Fills in defaults", func(t *testing.T) {
+		input := valid()
+
+		require.NoError(t, normalizeBuildTemplate(&input))
+		assert.Equal(t, "1080p Esports", input.Name)
+		assert.Equal(t, constants.DEFAULT_USE_CASE, input.UseCase)
+		assert.Equal(t, "USD", input.Currency)
+		assert.Equal(t, 1, input.Slots[0].Quantity)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(*models.BuildTemplateInput)
+	}{
+		{name: "Missing name", modify: func(input *models.BuildTemplateInput) { input.Name = " " }},
+		{name: "Budget not positive", modify: func(input *models.BuildTemplateInput) { input.TargetBudget = 0 }},
+		{name: "Unknown use case", modify: func(input *models.BuildTemplateInput) { input.UseCase = "mining" }},
+		{name: "No slots", modify: func(input *models.BuildTemplateInput) { input.Slots = nil }},
+		{name: "Unknown category", modify: func(input *models.BuildTemplateInput) { input.Slots[1].Category = "toaster" }},
+		{name: "Duplicate category", modify: func(input *models.BuildTemplateInput) { input.Slots[1].Category = models.CategoryCPU }},
+		{name: "Quantity too high", modify: func(input *models.BuildTemplateInput) { input.Slots[1].Quantity = 50 }},
+		{name: "Unknown operator", modify: func(input *models.BuildTemplateInput) { input.Slots[0].Constraints[0].Operator = "between" }},
+		{name: "Constraint without value", modify: func(input *models.BuildTemplateInput) { input.Slots[0].Constraints[0].Value = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid()
+			tt.modify(&input)
+
+			assert.ErrorIs(t, normalizeBuildTemplate(&input), ErrInvalidBuild)
+		})
+	}
+}
@@ -0,0 +1,458 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// upgradeCategories are the parts whose performance scores upgrades are ranked on
+var upgradeCategories = []models.Category{models.CategoryCPU, models.CategoryVideoCard}
+
+// cascadeCategories are the parts an upgrade may force to be replaced, in the order replacements are chosen.
+// The power supply comes last so it is sized for the rest of the build.
+var cascadeCategories = []models.Category{
+	models.CategoryMotherboard,
+	models.CategoryMemory,
+	models.CategoryCPUCooler,
+	models.CategoryCase,
+	models.CategoryPowerSupply,
+}
+
+// upgradeConflict is a part of a build that must be replaced for an upgrade to fit, and why
+type upgradeConflict struct {
+	category models.Category
+	reason   string
+}
+
+// FindBuildUpgrades ranks CPU and video card upgrades for a build visible to viewerID by performance gain per unit
+// of the build's currency. budget caps the cost of an upgrade, required replacements included. useCase and
+// resolution pick the scores compared and default to the build's use case and 1440p.
+func FindBuildUpgrades(buildID int64, viewerID string, budget *float64, useCase, resolution string) (models.BuildUpgrades, error) {
+	utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_START, nil, buildID, viewerID)
+
+	build, err := getVisibleBuild(buildID, viewerID)
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+	useCase = alternativesUseCase(useCase, build.UseCase)
+
+	if budget != nil && *budget <= 0 {
+		err := fmt.Errorf("%w: budget must be positive", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+	if !IsValidUseCase(useCase) {
+		err := fmt.Errorf("%w: use_case must be gaming, workstation or office", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+	resolution = strings.ToLower(strings.TrimSpace(resolution))
+	if resolution == "" {
+		resolution = constants.DEFAULT_RESOLUTION
+	}
+	if !IsValidResolution(resolution) {
+		err := fmt.Errorf("%w: resolution must be 1080p, 1440p or 4k", ErrInvalidBuild)
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+
+	categories := append(append([]models.Category{}, upgradeCategories...), cascadeCategories...)
+	components, prices, err := repository.ListInStockComponents(categories, build.Region)
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+	rates, err := repository.GetExchangeRates()
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+
+	byComponent := map[int64][]models.Price{}
+	for _, price := range prices {
+		byComponent[price.ComponentID] = append(byComponent[price.ComponentID], price)
+	}
+	catalog := priceCatalog(components, byComponent, strings.ToUpper(build.Currency), rates)
+
+	result, err := PlanBuildUpgrades(build, parts, catalog, GetCompatibilityRules(), useCase, resolution, budget)
+	if err != nil {
+		utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_ERROR, err, buildID)
+		return models.BuildUpgrades{}, err
+	}
+
+	utils.Log(constants.SERVICE_FIND_BUILD_UPGRADES_SUCCESS, nil, len(result.Upgrades), buildID)
+	return result, nil
+}
+
+// PlanBuildUpgrades tries every CPU and video card that scores higher than every cheaper one and beats the part in
+// the build, then pairs the best of each. The rest of the build is kept unless an upgrade breaks a compatibility
+// rule with a part, outgrows the cooler or leaves the power supply without headroom; those parts are replaced by
+// the cheapest that fit and the upgrade is flagged as cascading. Upgrades that cannot be made to fit or cost more
+// than budget are left out.
+func PlanBuildUpgrades(build models.UserBuild, parts []models.BuildComponentWithDetails, catalog []models.GeneratedBuildPart, rules []models.CompatibilityRule, useCase, resolution string, budget *float64) (models.BuildUpgrades, error) {
+	result := models.BuildUpgrades{
+		BuildID:    build.ID,
+		UseCase:    useCase,
+		Resolution: resolution,
+		Currency:   strings.ToUpper(build.Currency),
+		Region:     build.Region,
+		Budget:     budget,
+		Upgrades:   []models.BuildUpgrade{},
+	}
+	profile := generatorProfiles[useCase]
+	if profile.useCase != constants.USE_CASE_GAMING {
+		result.Resolution = ""
+	}
+
+	current := []models.GeneratedBuildPart{}
+	for _, part := range parts {
+		if part.Component != nil {
+			current = append(current, models.GeneratedBuildPart{Component: *part.Component})
+		}
+	}
+	score, ok := upgradeScore(current, profile, resolution)
+	if !ok {
+		return models.BuildUpgrades{}, fmt.Errorf("%w: the build needs a CPU with a performance score to rank upgrades", ErrInvalidBuild)
+	}
+	result.CurrentScore = roundTo(score, 1)
+
+	byCategory := map[models.Category][]models.GeneratedBuildPart{}
+	for _, part := range catalog {
+		byCategory[part.Component.Category] = append(byCategory[part.Component.Category], part)
+	}
+	for category := range byCategory {
+		list := byCategory[category]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].UnitPrice < list[j].UnitPrice })
+	}
+
+	categories := []models.Category{models.CategoryCPU}
+	if profile.needsGPU {
+		categories = append(categories, models.CategoryVideoCard)
+	}
+
+	affordable := func(upgrade models.BuildUpgrade) bool { return budget == nil || upgrade.Cost <= *budget }
+
+	singles := map[models.Category][]models.BuildUpgrade{}
+	for _, category := range categories {
+		for _, candidate := range upgradeCandidates(current, byCategory[category], profile) {
+			upgrade, ok := planUpgrade(current, []models.GeneratedBuildPart{candidate}, byCategory, rules, profile, resolution, score, result.Currency)
+			if ok && affordable(upgrade) {
+				singles[category] = append(singles[category], upgrade)
+			}
+		}
+		rankBuildUpgrades(singles[category])
+	}
+
+	// Pairs are drawn from the best affordable single upgrades of each kind
+	upgrades := append(append([]models.BuildUpgrade{}, singles[models.CategoryCPU]...), singles[models.CategoryVideoCard]...)
+	cpus, gpus := singles[models.CategoryCPU], singles[models.CategoryVideoCard]
+	for _, cpu := range cpus[:min(len(cpus), constants.UPGRADES_PAIR_POOL)] {
+		for _, gpu := range gpus[:min(len(gpus), constants.UPGRADES_PAIR_POOL)] {
+			pair := []models.GeneratedBuildPart{upgradePartCandidate(cpu.Parts[0]), upgradePartCandidate(gpu.Parts[0])}
+			if upgrade, ok := planUpgrade(current, pair, byCategory, rules, profile, resolution, score, result.Currency); ok && affordable(upgrade) {
+				upgrades = append(upgrades, upgrade)
+			}
+		}
+	}
+
+	rankBuildUpgrades(upgrades)
+	if len(upgrades) > constants.UPGRADES_MAX_RESULTS {
+		upgrades = upgrades[:constants.UPGRADES_MAX_RESULTS]
+	}
+	result.Upgrades = upgrades
+	return result, nil
+}
+
+// upgradeScore weighs the CPU score and the GPU score on the CPU's scale like the build generator. A missing video
+// card scores zero for use cases that need one.
+func upgradeScore(selected []models.GeneratedBuildPart, profile generatorProfile, resolution string) (float64, bool) {
+	cpu, ok := findGeneratedCategory(selected, models.CategoryCPU)
+	if !ok {
+		return 0, false
+	}
+	cpuScore, ok := PerformanceScore(cpu.Component, profile.useCase)
+	if !ok {
+		return 0, false
+	}
+	if !profile.needsGPU {
+		return cpuScore, true
+	}
+
+	gpuEffective := 0.0
+	if gpu, ok := findGeneratedCategory(selected, models.CategoryVideoCard); ok {
+		if gpuScore, ok := PerformanceScore(gpu.Component, profile.useCase); ok {
+			gpuEffective = effectiveGPUScore(gpuScore, profile.useCase, resolution)
+		}
+	}
+	return profile.cpuWeight*cpuScore + (1-profile.cpuWeight)*gpuEffective, true
+}
+
+// upgradeCandidates returns the catalog parts, cheapest first, that score higher than the part in the build and
+// every cheaper candidate
+func upgradeCandidates(current []models.GeneratedBuildPart, catalog []models.GeneratedBuildPart, profile generatorProfile) []models.GeneratedBuildPart {
+	best := -1.0
+	if len(catalog) > 0 {
+		if part, ok := findGeneratedCategory(current, catalog[0].Component.Category); ok {
+			best, _ = PerformanceScore(part.Component, profile.useCase)
+		}
+	}
+
+	candidates := []models.GeneratedBuildPart{}
+	for _, candidate := range catalog {
+		if score, ok := PerformanceScore(candidate.Component, profile.useCase); ok && score > best {
+			candidates = append(candidates, candidate)
+			best = score
+		}
+	}
+	return candidates
+}
+
+// planUpgrade puts changes into the build and replaces the parts they conflict with until the build fits again
+func planUpgrade(current, changes []models.GeneratedBuildPart, catalog map[models.Category][]models.GeneratedBuildPart, rules []models.CompatibilityRule, profile generatorProfile, resolution string, currentScore float64, currency string) (models.BuildUpgrade, bool) {
+	selected := append([]models.GeneratedBuildPart{}, current...)
+	changed := map[string]bool{}
+	upgrade := models.BuildUpgrade{Parts: []models.UpgradePart{}, RequiredReplacements: []models.UpgradePart{}, Reasons: []string{}}
+	for _, change := range changes {
+		replaced := replaceGeneratedPart(&selected, change)
+		changed[change.Component.ID] = true
+		upgrade.Parts = append(upgrade.Parts, newUpgradePart(change, replaced))
+		if replaced != nil {
+			upgrade.Reasons = append(upgrade.Reasons, fmt.Sprintf("%s %s replaces %s %s", change.Component.Brand, change.Component.Model, replaced.Brand, replaced.Model))
+		} else {
+			upgrade.Reasons = append(upgrade.Reasons, fmt.Sprintf("Adds %s %s", change.Component.Brand, change.Component.Model))
+		}
+	}
+
+	replacements := map[models.Category]int{}
+	fits := false
+	for round := 0; round < constants.UPGRADES_MAX_CASCADE_ROUNDS && !fits; round++ {
+		conflicts, ok := upgradeConflicts(selected, changed, current, rules)
+		if !ok {
+			return models.BuildUpgrade{}, false
+		}
+		if len(conflicts) == 0 {
+			fits = true
+			break
+		}
+
+		for _, conflict := range conflicts {
+			part, ok := cheapestReplacement(selected, catalog[conflict.category], conflict.category, changed, rules)
+			if !ok {
+				return models.BuildUpgrade{}, false
+			}
+			replaced := replaceGeneratedPart(&selected, part)
+			changed[part.Component.ID] = true
+
+			if i, ok := replacements[conflict.category]; ok {
+				upgrade.RequiredReplacements[i] = newUpgradePart(part, upgrade.RequiredReplacements[i].Replaces)
+			} else {
+				replacements[conflict.category] = len(upgrade.RequiredReplacements)
+				upgrade.RequiredReplacements = append(upgrade.RequiredReplacements, newUpgradePart(part, replaced))
+			}
+			upgrade.Reasons = append(upgrade.Reasons, fmt.Sprintf("Needs %s %s: %s", part.Component.Brand, part.Component.Model, conflict.reason))
+		}
+	}
+	if !fits {
+		return models.BuildUpgrade{}, false
+	}
+
+	score, ok := upgradeScore(selected, profile, resolution)
+	if !ok || score <= currentScore {
+		return models.BuildUpgrade{}, false
+	}
+	for _, part := range append(append([]models.UpgradePart{}, upgrade.Parts...), upgrade.RequiredReplacements...) {
+		upgrade.Cost += part.UnitPrice
+	}
+	upgrade.Cost = roundTo(upgrade.Cost, 2)
+	upgrade.Cascades = len(upgrade.RequiredReplacements) > 0
+	upgrade.Score = roundTo(score, 1)
+	upgrade.GainPercent = roundTo(100*(score-currentScore)/currentScore, 1)
+	if upgrade.Cost > 0 {
+		upgrade.GainPerDollar = roundTo(upgrade.GainPercent/upgrade.Cost, 4)
+	}
+	upgrade.Reasons = append(upgrade.Reasons, fmt.Sprintf("Raises the %s score by %.1f%% for %.2f %s",
+		describeWorkload(profile.useCase, resolution), upgrade.GainPercent, upgrade.Cost, currency))
+	return upgrade, true
+}
+
+// upgradeConflicts lists the parts that must be replaced for the changed parts to fit: those a compatibility
+// error ties to a changed part, a cooler rated below a new CPU, a cooler a new CPU no longer includes and a power
+// supply without headroom. ok is false when a conflicting part cannot be replaced, such as a video card.
+func upgradeConflicts(selected []models.GeneratedBuildPart, changed map[string]bool, current []models.GeneratedBuildPart, rules []models.CompatibilityRule) ([]upgradeConflict, bool) {
+	needed := map[models.Category]string{}
+	categoryOf := map[string]models.Category{}
+	for _, part := range selected {
+		categoryOf[part.Component.ID] = part.Component.Category
+	}
+
+	for _, issue := range buildErrors(generatedBuildParts(selected), rules) {
+		source, target := changed[issue.SourceComponentID], changed[issue.TargetComponentID]
+		if !source && !target {
+			continue
+		}
+		other := issue.TargetComponentID
+		if target {
+			other = issue.SourceComponentID
+		}
+		if source && target {
+			// Both parts are new; replace whichever was not asked for
+			if slices.Contains(cascadeCategories, categoryOf[issue.SourceComponentID]) {
+				other = issue.SourceComponentID
+			}
+		}
+		category, ok := categoryOf[other]
+		if !ok || !slices.Contains(cascadeCategories, category) {
+			return nil, false
+		}
+		if _, ok := needed[category]; !ok {
+			needed[category] = issue.Message
+		}
+	}
+
+	cpu, hasCPU := findGeneratedCategory(selected, models.CategoryCPU)
+	if hasCPU && changed[cpu.Component.ID] {
+		if cooler, ok := findGeneratedCategory(selected, models.CategoryCPUCooler); ok {
+			if !changed[cooler.Component.ID] && !coolsCPU(cooler.Component, cpu.Component) {
+				needed[models.CategoryCPUCooler] = fmt.Sprintf("%s is not rated for %s", cooler.Component.Model, cpu.Component.Model)
+			}
+		} else if _, liquid := findGeneratedCategory(selected, models.CategoryWaterCooling); !liquid {
+			included, _ := utils.GetSpecBool(utils.ParseSpecs(cpu.Component.Specs), constants.SPEC_INCLUDES_COOLER)
+			if old, ok := findGeneratedCategory(current, models.CategoryCPU); ok && !included {
+				needed[models.CategoryCPUCooler] = fmt.Sprintf("%s does not include a cooler like %s", cpu.Component.Model, old.Component.Model)
+			}
+		}
+	}
+
+	if psu, ok := findGeneratedCategory(selected, models.CategoryPowerSupply); ok && !changed[psu.Component.ID] {
+		draw, known := estimatePowerDraw(selected)
+		wattage, rated := utils.GetSpecNumber(utils.ParseSpecs(psu.Component.Specs), constants.SPEC_WATTAGE)
+		if known && rated && wattage < draw*constants.GENERATOR_PSU_HEADROOM {
+			needed[models.CategoryPowerSupply] = fmt.Sprintf("%s cannot supply an estimated %.0f W with headroom", psu.Component.Model, draw)
+		}
+	}
+
+	conflicts := []upgradeConflict{}
+	for _, category := range cascadeCategories {
+		if reason, ok := needed[category]; ok {
+			conflicts = append(conflicts, upgradeConflict{category: category, reason: reason})
+		}
+	}
+	return conflicts, true
+}
+
+// cheapestReplacement returns the cheapest part of a category that fits the rest of the build, cools its CPU when
+// it is a cooler and powers it with headroom when it is a power supply. Conflicts with unchanged parts that can be
+// replaced themselves are left to the next round, so a new motherboard can bring new memory with it.
+func cheapestReplacement(selected []models.GeneratedBuildPart, candidates []models.GeneratedBuildPart, category models.Category, changed map[string]bool, rules []models.CompatibilityRule) (models.GeneratedBuildPart, bool) {
+	cpu, hasCPU := findGeneratedCategory(selected, models.CategoryCPU)
+	for _, candidate := range candidates {
+		trial := append([]models.GeneratedBuildPart{}, selected...)
+		replaceGeneratedPart(&trial, candidate)
+
+		switch category {
+		case models.CategoryCPUCooler:
+			if hasCPU && !coolsCPU(candidate.Component, cpu.Component) {
+				continue
+			}
+		case models.CategoryPowerSupply:
+			draw, known := estimatePowerDraw(trial)
+			wattage, rated := utils.GetSpecNumber(utils.ParseSpecs(candidate.Component.Specs), constants.SPEC_WATTAGE)
+			if known && (!rated || wattage < draw*constants.GENERATOR_PSU_HEADROOM) {
+				continue
+			}
+		}
+		if fitsUpgrade(trial, candidate.Component.ID, changed, rules) {
+			return candidate, true
+		}
+	}
+	return models.GeneratedBuildPart{}, false
+}
+
+// fitsUpgrade checks that no compatibility error ties a replacement to a changed part or to one that cannot be
+// replaced
+func fitsUpgrade(selected []models.GeneratedBuildPart, componentID string, changed map[string]bool, rules []models.CompatibilityRule) bool {
+	categoryOf := map[string]models.Category{}
+	for _, part := range selected {
+		categoryOf[part.Component.ID] = part.Component.Category
+	}
+	for _, issue := range buildErrors(generatedBuildParts(selected), rules) {
+		other := ""
+		switch componentID {
+		case issue.SourceComponentID:
+			other = issue.TargetComponentID
+		case issue.TargetComponentID:
+			other = issue.SourceComponentID
+		default:
+			continue
+		}
+		if other == "" || changed[other] || !slices.Contains(cascadeCategories, categoryOf[other]) {
+			return false
+		}
+	}
+	return true
+}
+
+// replaceGeneratedPart swaps part in for the part of its category, or adds it, and returns the part it replaced
+func replaceGeneratedPart(selected *[]models.GeneratedBuildPart, part models.GeneratedBuildPart) *models.Component {
+	for i := range *selected {
+		if (*selected)[i].Component.Category == part.Component.Category {
+			replaced := (*selected)[i].Component
+			(*selected)[i] = part
+			return &replaced
+		}
+	}
+	*selected = append(*selected, part)
+	return nil
+}
+
+func findGeneratedCategory(selected []models.GeneratedBuildPart, category models.Category) (models.GeneratedBuildPart, bool) {
+	for _, part := range selected {
+		if part.Component.Category == category {
+			return part, true
+		}
+	}
+	return models.GeneratedBuildPart{}, false
+}
+
+func newUpgradePart(part models.GeneratedBuildPart, replaces *models.Component) models.UpgradePart {
+	return models.UpgradePart{
+		Category:   part.Component.Category,
+		Replaces:   replaces,
+		Component:  part.Component,
+		PriceID:    part.Price.ID,
+		RetailerID: part.Price.RetailerID,
+		UnitPrice:  part.UnitPrice,
+	}
+}
+
+func upgradePartCandidate(part models.UpgradePart) models.GeneratedBuildPart {
+	return models.GeneratedBuildPart{
+		Component: part.Component,
+		Price:     models.Price{ID: part.PriceID, RetailerID: part.RetailerID},
+		UnitPrice: part.UnitPrice,
+	}
+}
+
+// rankBuildUpgrades orders upgrades by gain per unit of currency, then by gain
+func rankBuildUpgrades(upgrades []models.BuildUpgrade) {
+	sort.SliceStable(upgrades, func(i, j int) bool {
+		if upgrades[i].GainPerDollar != upgrades[j].GainPerDollar {
+			return upgrades[i].GainPerDollar > upgrades[j].GainPerDollar
+		}
+		return upgrades[i].GainPercent > upgrades[j].GainPercent
+	})
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanBuildUpgrades tests the ranking of upgrades and the replacements they cascade into
+func TestPlanBuildUpgrades(t *testing.T) {
+	rules := []models.CompatibilityRule{
+		{
+			ID:             "cpu-motherboard-socket",
+			SourceCategory: models.CategoryCPU,
+			TargetCategory: models.CategoryMotherboard,
+			Comparisons:    []models.RuleComparison{{SourceKey: "socket", Operator: models.SpecOperatorEquals, TargetKey: "socket"}},
+			Severity:       models.RuleSeverityError,
+			Message:        "socket mismatch",
+		},
+		{
+			ID:             "motherboard-memory-type",
+			SourceCategory: models.CategoryMotherboard,
+			TargetCategory: models.CategoryMemory,
+			Comparisons:    []models.RuleComparison{{SourceKey: "memory_type", Operator: models.SpecOperatorEquals, TargetKey: "type"}},
+			Severity:       models.RuleSeverityError,
+			Message:        "memory type mismatch",
+		},
+	}
+
+	catalog := []models.GeneratedBuildPart{
+		catalogPart(models.CategoryCPU, "1", "Old CPU", `{"socket": "AM4", "tdp": 65, "includes_cooler": true, "performance_gaming": 40}`, 90),
+		catalogPart(models.CategoryCPU, "2", "AM4 refresh", `{"socket": "AM4", "tdp": 65, "includes_cooler": true, "performance_gaming": 60}`, 150),
+		catalogPart(models.CategoryCPU, "3", "AM5 CPU", `{"socket": "AM5", "tdp": 120, "performance_gaming": 90}`, 300),
+		catalogPart(models.CategoryVideoCard, "10", "Old GPU", `{"tdp": 100, "performance_gaming": 40}`, 150),
+		catalogPart(models.CategoryVideoCard, "11", "New GPU", `{"tdp": 300, "performance_gaming": 90}`, 500),
+		catalogPart(models.CategoryMotherboard, "20", "AM4 board", `{"socket": "AM4", "memory_type": "DDR4"}`, 80),
+		catalogPart(models.CategoryMotherboard, "21", "AM5 board", `{"socket": "AM5", "memory_type": "DDR5"}`, 150),
+		catalogPart(models.CategoryMemory, "30", "DDR4 kit", `{"type": "DDR4"}`, 50),
+		catalogPart(models.CategoryMemory, "31", "DDR5 kit", `{"type": "DDR5"}`, 90),
+		catalogPart(models.CategoryCPUCooler, "40", "Small cooler", `{"rated_tdp": 95}`, 20),
+		catalogPart(models.CategoryCPUCooler, "41", "Tower cooler", `{"rated_tdp": 220}`, 45),
+		catalogPart(models.CategoryPowerSupply, "70", "450W", `{"wattage": 450}`, 50),
+		catalogPart(models.CategoryPowerSupply, "71", "850W", `{"wattage": 850}`, 110),
+	}
+	current := func(ids ...string) []models.BuildComponentWithDetails {
+		parts := []models.BuildComponentWithDetails{}
+		for _, id := range ids {
+			for i := range catalog {
+				if catalog[i].Component.ID == id {
+					parts = append(parts, models.BuildComponentWithDetails{BuildComponent: models.BuildComponent{Quantity: 1}, Component: &catalog[i].Component})
+				}
+			}
+		}
+		return parts
+	}
+	build := models.UserBuild{ID: 1, Currency: "usd", Region: "USA"}
+	parts := current("1", "10", "20", "30", "70")
+
+	t.Run("Ranks by gain per dollar and flags cascades", func(t *testing.T) {
+		result, err := PlanBuildUpgrades(build, parts, catalog, rules, constants.USE_CASE_GAMING, constants.RESOLUTION_1440P, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 40.0, result.CurrentScore)
+		assert.Equal(t, "USD", result.Currency)
+		require.Len(t, result.Upgrades, 5)
+		for i := 1; i < len(result.Upgrades); i++ {
+			assert.GreaterOrEqual(t, result.Upgrades[i-1].GainPerDollar, result.Upgrades[i].GainPerDollar)
+		}
+
+		gpu := result.Upgrades[0]
+		require.Len(t, gpu.Parts, 1)
+		assert.Equal(t, "11", gpu.Parts[0].Component.ID)
+		assert.Equal(t, "10", gpu.Parts[0].Replaces.ID)
+		assert.True(t, gpu.Cascades)
+		require.Len(t, gpu.RequiredReplacements, 1)
+		assert.Equal(t, "71", gpu.RequiredReplacements[0].Component.ID, "the 450 W supply has no headroom for the new card")
+		assert.Equal(t, 610.0, gpu.Cost)
+		assert.Equal(t, 81.3, gpu.GainPercent)
+
+		assert.Len(t, result.Upgrades[1].Parts, 2)
+
+		sameSocket := result.Upgrades[2]
+		assert.Equal(t, "2", sameSocket.Parts[0].Component.ID)
+		assert.False(t, sameSocket.Cascades)
+		assert.Empty(t, sameSocket.RequiredReplacements)
+		assert.Equal(t, 150.0, sameSocket.Cost)
+
+		newSocket := result.Upgrades[4]
+		assert.Equal(t, "3", newSocket.Parts[0].Component.ID)
+		assert.True(t, newSocket.Cascades)
+		replaced := map[models.Category]string{}
+		for _, part := range newSocket.RequiredReplacements {
+			replaced[part.Category] = part.Component.ID
+		}
+		assert.Equal(t, map[models.Category]string{
+			models.CategoryMotherboard: "21",
+			models.CategoryMemory:      "31",
+			models.CategoryCPUCooler:   "41",
+		}, replaced)
+		assert.Equal(t, 585.0, newSocket.Cost)
+	})
+
+	t.Run("Budget caps the cost of an upgrade", func(t *testing.T) {
+		budget := 200.0
+
+		result, err := PlanBuildUpgrades(build, parts, catalog, rules, constants.USE_CASE_GAMING, constants.RESOLUTION_1440P, &budget)
+
+		require.NoError(t, err)
+		require.Len(t, result.Upgrades, 1)
+		assert.Equal(t, "2", result.Upgrades[0].Parts[0].Component.ID)
+	})
+
+	t.Run("Office builds only upgrade the CPU", func(t *testing.T) {
+		result, err := PlanBuildUpgrades(build, parts, catalog, rules, constants.USE_CASE_OFFICE, constants.RESOLUTION_1440P, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Resolution)
+		for _, upgrade := range result.Upgrades {
+			for _, part := range upgrade.Parts {
+				assert.Equal(t, models.CategoryCPU, part.Category)
+			}
+		}
+	})
+
+	t.Run("Build without a CPU", func(t *testing.T) {
+		_, err := PlanBuildUpgrades(build, current("10", "20"), catalog, rules, constants.USE_CASE_GAMING, constants.RESOLUTION_1440P, nil)
+
+		assert.ErrorIs(t, err, ErrInvalidBuild)
+	})
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+// ErrBuildVersionNotFound is returned when a build has no version with the requested number
+var ErrBuildVersionNotFound = errors.New("build version not found")
+
+// ListBuildVersions returns the versions of a build the viewer may see, newest first
+func ListBuildVersions(buildID int64, viewerID, page string) ([]models.BuildVersion, error) {
+	utils.Log(constants.SERVICE_LIST_BUILD_VERSIONS_START, nil, buildID, viewerID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_LIST_BUILD_VERSIONS_ERROR, err, buildID, viewerID)
+		return nil, err
+	}
+
+	versions, err := repository.ListBuildVersions(buildID, page)
+	if err != nil {
+		utils.Log(constants.SERVICE_LIST_BUILD_VERSIONS_ERROR, err, buildID, viewerID)
+		return nil, err
+	}
+
+	utils.Log(constants.SERVICE_LIST_BUILD_VERSIONS_SUCCESS, nil, len(versions), buildID)
+	return versions, nil
+}
+
+// DiffBuildVersions compares two versions of a build the viewer may see
+func DiffBuildVersions(buildID int64, from, to int, viewerID string) (models.BuildVersionDiff, error) {
+	utils.Log(constants.SERVICE_DIFF_BUILD_VERSIONS_START, nil, from, to, buildID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_DIFF_BUILD_VERSIONS_ERROR, err, from, to, buildID)
+		return models.BuildVersionDiff{}, err
+	}
+
+	fromVersion, err := getBuildVersion(buildID, from)
+	if err != nil {
+		utils.Log(constants.SERVICE_DIFF_BUILD_VERSIONS_ERROR, err, from, to, buildID)
+		return models.BuildVersionDiff{}, err
+	}
+	toVersion, err := getBuildVersion(buildID, to)
+	if err != nil {
+		utils.Log(constants.SERVICE_DIFF_BUILD_VERSIONS_ERROR, err, from, to, buildID)
+		return models.BuildVersionDiff{}, err
+	}
+
+	utils.Log(constants.SERVICE_DIFF_BUILD_VERSIONS_SUCCESS, nil, from, to, buildID)
+	return DiffVersions(fromVersion, toVersion), nil
+}
+
+// RestoreBuildVersion puts back the parts of a version of a build userID can edit and records the result as a new version.
+// A set revision must match the build's current revision.
+func RestoreBuildVersion(buildID int64, version int, userID string, revision *int) (models.UserBuildWithComponents, error) {
+	utils.Log(constants.SERVICE_RESTORE_BUILD_VERSION_START, nil, version, buildID)
+
+	if _, _, err := requireBuildRole(buildID, userID, models.BuildRoleEditor); err != nil {
+		utils.Log(constants.SERVICE_RESTORE_BUILD_VERSION_ERROR, err, version, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	snapshot, err := getBuildVersion(buildID, version)
+	if err != nil {
+		utils.Log(constants.SERVICE_RESTORE_BUILD_VERSION_ERROR, err, version, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	if err := repository.ReplaceBuildParts(buildID, snapshot.Parts, revision, buildSnapshot(userID, fmt.Sprintf("Restored version %d", version))); err != nil {
+		utils.Log(constants.SERVICE_RESTORE_BUILD_VERSION_ERROR, err, version, buildID)
+		return models.UserBuildWithComponents{}, err
+	}
+
+	utils.Log(constants.SERVICE_RESTORE_BUILD_VERSION_SUCCESS, nil, version, buildID)
+	return GetUserBuild(buildID, userID)
+}
+
+// DiffVersions lists the parts added, removed and changed from one version to another and the change in total
+func DiffVersions(from, to models.BuildVersion) models.BuildVersionDiff {
+	diff := models.BuildVersionDiff{
+		BuildID:     to.BuildID,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Added:       []models.BuildVersionPart{},
+		Removed:     []models.BuildVersionPart{},
+		Changed:     []models.BuildVersionPartChange{},
+		FromTotal:   from.TotalPrice,
+		ToTotal:     to.TotalPrice,
+		Currency:    to.Currency,
+	}
+
+	before := map[int64]models.BuildVersionPart{}
+	for _, part := range from.Parts {
+		before[part.ComponentID] = part
+	}
+	after := map[int64]models.BuildVersionPart{}
+	for _, part := range to.Parts {
+		after[part.ComponentID] = part
+
+		old, ok := before[part.ComponentID]
+		if !ok {
+			diff.Added = append(diff.Added, part)
+			continue
+		}
+		if changes := partChanges(old, part); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, models.BuildVersionPartChange{ComponentID: part.ComponentID, Before: old, After: part, Changes: changes})
+		}
+	}
+	for _, part := range from.Parts {
+		if _, ok := after[part.ComponentID]; !ok {
+			diff.Removed = append(diff.Removed, part)
+		}
+	}
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].ComponentID < diff.Changed[j].ComponentID })
+
+	if from.TotalPrice != nil && to.TotalPrice != nil && from.Currency == to.Currency {
+		delta := roundTo(*to.TotalPrice-*from.TotalPrice, 2)
+		diff.PriceDelta = &delta
+	}
+	return diff
+}
+
+// partChanges names the fields that differ between two snapshots of the same part
+func partChanges(before, after models.BuildVersionPart) []string {
+	changes := []string{}
+	if before.Quantity != after.Quantity {
+		changes = append(changes, "quantity")
+	}
+	if !equalInt64Pointers(before.SelectedPriceID, after.SelectedPriceID) {
+		changes = append(changes, "selected_price_id")
+	}
+	if !equalStringPointers(before.Notes, after.Notes) {
+		changes = append(changes, "notes")
+	}
+	if !equalFloatPointers(before.UnitPrice, after.UnitPrice) {
+		changes = append(changes, "unit_price")
+	}
+	return changes
+}
+
+// buildSnapshot returns the snapshotter that records a change to a build by actor. The repository calls it inside
+// the transaction making the change, so the version, the build's total and its completeness commit or fail together
+// with it.
+func buildSnapshot(actor, reason string) repository.BuildSnapshotter {
+	return func(build models.UserBuild, parts []models.BuildComponentWithDetails) (models.BuildVersion, bool, error) {
+		pricing, err := CalculateBuildPrice(build, parts)
+		if err != nil {
+			utils.Log(constants.SERVICE_RECORD_BUILD_VERSION_ERROR, err, build.ID)
+			return models.BuildVersion{}, false, err
+		}
+		return snapshotBuild(build.ID, parts, pricing, actor, reason), EvaluateCompleteness(parts).Complete, nil
+	}
+}
+
+// snapshotBuild captures the parts of a build with the prices used for its total
+func snapshotBuild(buildID int64, parts []models.BuildComponentWithDetails, pricing models.BuildPriceBreakdown, actor, reason string) models.BuildVersion {
+	lines := map[int64]models.BuildPriceLine{}
+	for _, line := range pricing.Lines {
+		lines[line.ComponentID] = line
+	}
+
+	version := models.BuildVersion{
+		BuildID:    buildID,
+		Parts:      []models.BuildVersionPart{},
+		TotalPrice: pricing.Total,
+		Currency:   pricing.Currency,
+		Reason:     reason,
+		CreatedBy:  actor,
+	}
+	for _, part := range parts {
+		snapshot := models.BuildVersionPart{
+			ComponentID:     part.ComponentID,
+			Quantity:        part.Quantity,
+			SelectedPriceID: part.SelectedPriceID,
+			Notes:           part.Notes,
+		}
+		if part.Component != nil {
+			snapshot.Category = part.Component.Category
+			snapshot.Brand = part.Component.Brand
+			snapshot.Model = part.Component.Model
+		}
+		if line, ok := lines[part.ComponentID]; ok {
+			snapshot.UnitPrice = line.UnitPrice
+			snapshot.LineTotal = line.LineTotal
+		}
+		version.Parts = append(version.Parts, snapshot)
+	}
+	return version
+}
+
+func getBuildVersion(buildID int64, version int) (models.BuildVersion, error) {
+	found, err := repository.GetBuildVersion(buildID, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.BuildVersion{}, ErrBuildVersionNotFound
+	}
+	return found, err
+}
+
+func equalInt64Pointers(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalStringPointers(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalFloatPointers(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
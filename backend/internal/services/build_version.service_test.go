@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiffVersions tests added, removed and changed parts and the price delta between two versions
+func TestDiffVersions(t *testing.T) {
+	price := func(value float64) *float64 { return &value }
+	cpu := models.BuildVersionPart{ComponentID: 1, Model: "Ryzen 7 7800X3D", Quantity: 1, UnitPrice: price(449)}
+	ram := models.BuildVersionPart{ComponentID: 2, Model: "Vengeance 32GB", Quantity: 1, UnitPrice: price(100)}
+	gpu := models.BuildVersionPart{ComponentID: 3, Model: "RTX 4070", Quantity: 1, UnitPrice: price(599)}
+
+	moreRAM := ram
+	moreRAM.Quantity = 2
+	cheaperCPU := cpu
+	cheaperCPU.UnitPrice = price(399)
+
+	from := models.BuildVersion{BuildID: 7, Version: 1, Parts: []models.BuildVersionPart{cpu, ram, gpu}, TotalPrice: price(1148), Currency: "USD"}
+	to := models.BuildVersion{BuildID: 7, Version: 2, Parts: []models.BuildVersionPart{cheaperCPU, moreRAM}, TotalPrice: price(599), Currency: "USD"}
+
+	t.Run("Changes between versions", func(t *testing.T) {
+		diff := DiffVersions(from, to)
+
+		assert.Empty(t, diff.Added)
+		require.Len(t, diff.Removed, 1)
+		assert.Equal(t, int64(3), diff.Removed[0].ComponentID)
+		require.Len(t, diff.Changed, 2)
+		assert.Equal(t, []string{"unit_price"}, diff.Changed[0].Changes)
+		assert.Equal(t, []string{"quantity"}, diff.Changed[1].Changes)
+		require.NotNil(t, diff.PriceDelta)
+		assert.Equal(t, -549.0, *diff.PriceDelta)
+	})
+
+	t.Run("Reverse direction", func(t *testing.T) {
+		diff := DiffVersions(to, from)
+
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, "RTX 4070", diff.Added[0].Model)
+		assert.Empty(t, diff.Removed)
+		assert.Equal(t, 549.0, *diff.PriceDelta)
+	})
+
+	t.Run("Different currencies", func(t *testing.T) {
+		euro := to
+		euro.Currency = "EUR"
+		diff := DiffVersions(from, euro)
+
+		assert.Nil(t, diff.PriceDelta)
+	})
+}
+
+// TestSnapshotBuild tests that a snapshot keeps each part with the price used for the total
+func TestSnapshotBuild(t *testing.T) {
+	unitPrice, lineTotal, total := 50.0, 100.0, 100.0
+	parts := []models.BuildComponentWithDetails{storagePart(models.CategoryCaseFan, "5", "P12", `{}`, 2)}
+	parts[0].ComponentID = 5
+	pricing := models.BuildPriceBreakdown{
+		Currency: "USD",
+		Total:    &total,
+		Lines:    []models.BuildPriceLine{{ComponentID: 5, Quantity: 2, UnitPrice: &unitPrice, LineTotal: &lineTotal}},
+	}
+
+	version := snapshotBuild(7, parts, pricing, "user-1", "Added component 5")
+
+	assert.Equal(t, int64(7), version.BuildID)
+	assert.Equal(t, "user-1", version.CreatedBy)
+	require.Len(t, version.Parts, 1)
+	assert.Equal(t, models.CategoryCaseFan, version.Parts[0].Category)
+	assert.Equal(t, 2, version.Parts[0].Quantity)
+	assert.Equal(t, 50.0, *version.Parts[0].UnitPrice)
+	assert.Equal(t, 100.0, *version.TotalPrice)
+}
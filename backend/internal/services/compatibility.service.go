@@ -0,0 +1,304 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+func GetCompatibleComponents(input models.GetCompatibleComponentsInput) ([]models.CompatibleComponent, error) {
+	buildID, category := input.BuildID, input.Category
+	utils.Log(constants.SERVICE_GET_COMPATIBLE_COMPONENTS_START, nil, buildID, category)
+
+	if _, err := getVisibleBuild(buildID, input.ViewerID); err != nil {
+		utils.Log(constants.SERVICE_GET_COMPATIBLE_COMPONENTS_ERROR, err, buildID, category)
+		return nil, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_COMPATIBLE_COMPONENTS_ERROR, err, buildID, category)
+		return nil, err
+	}
+
+	predicates := BuildSpecPredicates(parts, models.Category(category))
+
+	components, err := repository.GetComponentsBySpecPredicates(models.GetComponentsBySpecPredicatesInput{
+		Category:   category,
+		Brand:      input.Brand,
+		Predicates: predicates,
+		Page:       input.Page,
+	})
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_COMPATIBLE_COMPONENTS_ERROR, err, buildID, category)
+		return nil, err
+	}
+
+	compatible := ExplainCompatibleComponents(components, predicates)
+
+	utils.Log(constants.SERVICE_GET_COMPATIBLE_COMPONENTS_SUCCESS, nil, len(compatible), buildID, category)
+	return compatible, nil
+}
+
+// BuildSpecPredicates translates the parts of a build into predicates on components of the given category.
+// Only error severity rules restrict the listing. Rules are applied in both directions, so a case
+// restricts motherboards and a motherboard restricts cases.
+func BuildSpecPredicates(parts []models.BuildComponentWithDetails, category models.Category) []models.SpecPredicate {
+	predicates := []models.SpecPredicate{}
+	rules := GetCompatibilityRules()
+
+	for _, part := range parts {
+		if part.Component == nil {
+			continue
+		}
+		specs := utils.ParseSpecs(part.Component.Specs)
+
+		for _, rule := range rules {
+			if rule.Severity != models.RuleSeverityError {
+				continue
+			}
+
+			for _, comparison := range rule.Comparisons {
+				var sourceKey, targetKey string
+				var operator models.SpecOperator
+
+				switch {
+				case rule.TargetCategory == category && rule.SourceCategory == part.Component.Category:
+					sourceKey, targetKey, operator = comparison.SourceKey, comparison.TargetKey, comparison.Operator
+				case rule.SourceCategory == category && rule.TargetCategory == part.Component.Category:
+					sourceKey, targetKey, operator = comparison.TargetKey, comparison.SourceKey, comparison.Operator.Inverse()
+				default:
+					continue
+				}
+
+				value, ok := utils.GetSpecValue(specs, sourceKey)
+				if !ok {
+					continue
+				}
+
+				predicates = append(predicates, models.SpecPredicate{
+					SpecKey:  targetKey,
+					Operator: operator,
+					Value:    value,
+					Reason:   fmt.Sprintf("%s (%s %s %s)", rule.Description, part.Component.Brand, part.Component.Model, describeSpecValue(value)),
+				})
+			}
+		}
+	}
+
+	return predicates
+}
+
+// CheckBuildCompatibility evaluates the active rules against every part of a build the viewer may see
+func CheckBuildCompatibility(buildID int64, viewerID string) (models.CompatibilityReport, error) {
+	utils.Log(constants.SERVICE_CHECK_BUILD_COMPATIBILITY_START, nil, buildID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_CHECK_BUILD_COMPATIBILITY_ERROR, err, buildID)
+		return models.CompatibilityReport{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_CHECK_BUILD_COMPATIBILITY_ERROR, err, buildID)
+		return models.CompatibilityReport{}, err
+	}
+
+	issues := EvaluateCompatibilityRules(parts, GetCompatibilityRules())
+	issues = append(issues, AllocateStorage(parts).Issues...)
+	issues = append(issues, AnalyzePowerConnectors(parts).Issues...)
+
+	biosIssues, err := CheckBuildBIOS(parts)
+	if err != nil {
+		utils.Log(constants.SERVICE_CHECK_BUILD_COMPATIBILITY_ERROR, err, buildID)
+		return models.CompatibilityReport{}, err
+	}
+	issues = append(issues, biosIssues...)
+
+	report := NewCompatibilityReport(buildID, issues)
+
+	utils.Log(constants.SERVICE_CHECK_BUILD_COMPATIBILITY_SUCCESS, nil, buildID, len(report.Issues))
+	return report, nil
+}
+
+// NewCompatibilityReport builds a report; the build is compatible when no issue has error severity
+func NewCompatibilityReport(buildID int64, issues []models.CompatibilityIssue) models.CompatibilityReport {
+	report := models.CompatibilityReport{BuildID: buildID, Compatible: true, Issues: issues}
+	if report.Issues == nil {
+		report.Issues = []models.CompatibilityIssue{}
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == models.RuleSeverityError {
+			report.Compatible = false
+		}
+	}
+	return report
+}
+
+// EvaluateCompatibilityRules checks every source/target pair of parts against the rules.
+// A rule fires on the first failing comparison; comparisons that cannot be verified are skipped.
+func EvaluateCompatibilityRules(parts []models.BuildComponentWithDetails, rules []models.CompatibilityRule) []models.CompatibilityIssue {
+	issues := []models.CompatibilityIssue{}
+
+	for _, rule := range rules {
+		for i, source := range parts {
+			if source.Component == nil || source.Component.Category != rule.SourceCategory {
+				continue
+			}
+			sourceSpecs := utils.ParseSpecs(source.Component.Specs)
+
+			for j, target := range parts {
+				if i == j || target.Component == nil || target.Component.Category != rule.TargetCategory {
+					continue
+				}
+				targetSpecs := utils.ParseSpecs(target.Component.Specs)
+
+				for _, comparison := range rule.Comparisons {
+					expected, ok := utils.GetSpecValue(sourceSpecs, comparison.SourceKey)
+					if !ok {
+						continue
+					}
+					actual, ok := utils.GetSpecValue(targetSpecs, comparison.TargetKey)
+					if !ok {
+						continue
+					}
+					passed, known := compareSpecValues(actual, comparison.Operator, expected)
+					if !known || passed {
+						continue
+					}
+
+					issues = append(issues, models.CompatibilityIssue{
+						RuleID:            rule.ID,
+						Severity:          rule.Severity,
+						Message:           renderRuleMessage(rule, *source.Component, *target.Component, expected, actual),
+						SourceComponentID: source.Component.ID,
+						TargetComponentID: target.Component.ID,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+type ruleMessageData struct {
+	Rule        models.CompatibilityRule
+	Source      models.Component
+	Target      models.Component
+	SourceValue string
+	TargetValue string
+}
+
+func renderRuleMessage(rule models.CompatibilityRule, source, target models.Component, sourceValue, targetValue interface{}) string {
+	tmpl, err := template.New(rule.ID).Option("missingkey=error").Parse(rule.Message)
+	if err != nil {
+		return rule.Description
+	}
+
+	var buf strings.Builder
+	data := ruleMessageData{
+		Rule:        rule,
+		Source:      source,
+		Target:      target,
+		SourceValue: describeSpecValue(sourceValue),
+		TargetValue: describeSpecValue(targetValue),
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return rule.Description
+	}
+	return buf.String()
+}
+
+// ExplainCompatibleComponents marks components whose specs could not be checked against a predicate as borderline.
+// The components have already passed the predicates in SQL, so none are dropped here and pages keep their size.
+func ExplainCompatibleComponents(components []models.Component, predicates []models.SpecPredicate) []models.CompatibleComponent {
+	compatible := make([]models.CompatibleComponent, 0, len(components))
+
+	for _, component := range components {
+		specs := utils.ParseSpecs(component.Specs)
+		result := models.CompatibleComponent{Component: component}
+
+		for _, predicate := range predicates {
+			if _, known := EvaluateSpecPredicate(specs, predicate); !known {
+				result.Borderline = true
+				result.Explanation = append(result.Explanation,
+					fmt.Sprintf("%s is not listed in the specs, included without verifying: %s", predicate.SpecKey, predicate.Reason))
+			}
+		}
+
+		compatible = append(compatible, result)
+	}
+
+	return compatible
+}
+
+// EvaluateSpecPredicate checks a predicate against decoded specs.
+// known is false when the spec is missing or cannot be compared.
+func EvaluateSpecPredicate(specs map[string]interface{}, predicate models.SpecPredicate) (passed bool, known bool) {
+	actual, ok := utils.GetSpecValue(specs, predicate.SpecKey)
+	if !ok {
+		return false, false
+	}
+	return compareSpecValues(actual, predicate.Operator, predicate.Value)
+}
+
+func compareSpecValues(actual interface{}, operator models.SpecOperator, expected interface{}) (passed bool, known bool) {
+	switch operator {
+	case models.SpecOperatorEquals:
+		return utils.SpecValuesEqual(actual, expected), true
+	case models.SpecOperatorContains:
+		list, ok := utils.SpecValueToStringList(actual)
+		if !ok {
+			return false, false
+		}
+		for _, item := range list {
+			if utils.SpecValuesEqual(item, expected) {
+				return true, true
+			}
+		}
+		return false, true
+	case models.SpecOperatorIn:
+		list, ok := utils.SpecValueToStringList(expected)
+		if !ok {
+			return false, false
+		}
+		for _, item := range list {
+			if utils.SpecValuesEqual(actual, item) {
+				return true, true
+			}
+		}
+		return false, true
+	case models.SpecOperatorLTE, models.SpecOperatorGTE:
+		left, ok := utils.SpecValueToNumber(actual)
+		if !ok {
+			return false, false
+		}
+		right, ok := utils.SpecValueToNumber(expected)
+		if !ok {
+			return false, false
+		}
+		if operator == models.SpecOperatorLTE {
+			return left <= right, true
+		}
+		return left >= right, true
+	}
+	return false, false
+}
+
+func describeSpecValue(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		items, _ := utils.SpecValueToStringList(list)
+		return fmt.Sprint(items)
+	}
+	if s, ok := utils.SpecValueToString(value); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
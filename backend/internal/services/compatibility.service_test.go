@@ -0,0 +1,226 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildPart(category models.Category, model string, specs string) models.BuildComponentWithDetails {
+	return models.BuildComponentWithDetails{
+		BuildComponent: models.BuildComponent{Quantity: 1},
+		Component: &models.Component{
+			Category: category,
+			Brand:    "Test",
+			Model:    model,
+			Specs:    json.RawMessage(specs),
+		},
+	}
+}
+
+// TestBuildSpecPredicates tests translation of build parts into spec predicates
+func TestBuildSpecPredicates(t *testing.T) {
+	loadShippedRules(t)
+
+	parts := []models.BuildComponentWithDetails{
+		buildPart(models.CategoryCPU, "Ryzen 7 7800X3D", `{"socket": "AM5"}`),
+		buildPart(models.CategoryMemory, "DDR5 Kit", `{"memory_type": "DDR5"}`),
+		buildPart(models.CategoryCase, "Meshify", `{"supported_form_factors": ["ATX", "Micro-ATX"], "max_gpu_length_mm": 360}`),
+	}
+
+	t.Run("Motherboard is constrained by CPU, memory and case", func(t *testing.T) {
+		predicates := BuildSpecPredicates(parts, models.CategoryMotherboard)
+		assert.Len(t, predicates, 3)
+
+		byKey := map[string]models.SpecPredicate{}
+		for _, p := range predicates {
+			byKey[p.SpecKey] = p
+		}
+		assert.Equal(t, models.SpecOperatorEquals, byKey["socket"].Operator)
+		assert.Equal(t, "AM5", byKey["socket"].Value)
+		assert.Equal(t, models.SpecOperatorEquals, byKey["memory_type"].Operator)
+		assert.Equal(t, models.SpecOperatorIn, byKey["form_factor"].Operator)
+	})
+
+	t.Run("Video card is constrained by case length", func(t *testing.T) {
+		predicates := BuildSpecPredicates(parts, models.CategoryVideoCard)
+		assert.Len(t, predicates, 1)
+		assert.Equal(t, "length_mm", predicates[0].SpecKey)
+		assert.Equal(t, models.SpecOperatorLTE, predicates[0].Operator)
+	})
+
+	t.Run("Unrelated category has no predicates", func(t *testing.T) {
+		predicates := BuildSpecPredicates(parts, models.CategoryKeyboard)
+		assert.Empty(t, predicates)
+	})
+
+	t.Run("Warning rules do not restrict listings", func(t *testing.T) {
+		compatibilityRulesMu.Lock()
+		original := compatibilityRules
+		compatibilityRules = []models.CompatibilityRule{{
+			ID:             "advisory",
+			SourceCategory: models.CategoryCPU,
+			TargetCategory: models.CategoryMotherboard,
+			Comparisons:    []models.RuleComparison{{SourceKey: "socket", Operator: models.SpecOperatorEquals, TargetKey: "socket"}},
+			Severity:       models.RuleSeverityWarning,
+		}}
+		compatibilityRulesMu.Unlock()
+		defer func() {
+			compatibilityRulesMu.Lock()
+			compatibilityRules = original
+			compatibilityRulesMu.Unlock()
+		}()
+
+		assert.Empty(t, BuildSpecPredicates(parts, models.CategoryMotherboard))
+	})
+
+	t.Run("Missing source spec is skipped", func(t *testing.T) {
+		predicates := BuildSpecPredicates([]models.BuildComponentWithDetails{
+			buildPart(models.CategoryCPU, "Unknown", `{}`),
+		}, models.CategoryMotherboard)
+		assert.Empty(t, predicates)
+	})
+}
+
+// TestEvaluateSpecPredicate tests Go-side evaluation of spec predicates
+func TestEvaluateSpecPredicate(t *testing.T) {
+	tests := []struct {
+		name      string
+		specs     map[string]interface{}
+		predicate models.SpecPredicate
+		passed    bool
+		known     bool
+	}{
+		{
+			name:      "Equals ignores case",
+			specs:     map[string]interface{}{"socket": "am5"},
+			predicate: models.SpecPredicate{SpecKey: "socket", Operator: models.SpecOperatorEquals, Value: "AM5"},
+			passed:    true,
+			known:     true,
+		},
+		{
+			name:      "Contains on list",
+			specs:     map[string]interface{}{"supported_sockets": []interface{}{"AM4", "AM5"}},
+			predicate: models.SpecPredicate{SpecKey: "supported_sockets", Operator: models.SpecOperatorContains, Value: "AM5"},
+			passed:    true,
+			known:     true,
+		},
+		{
+			name:      "In fails when value not listed",
+			specs:     map[string]interface{}{"form_factor": "E-ATX"},
+			predicate: models.SpecPredicate{SpecKey: "form_factor", Operator: models.SpecOperatorIn, Value: []interface{}{"ATX", "Micro-ATX"}},
+			passed:    false,
+			known:     true,
+		},
+		{
+			name:      "LTE parses units",
+			specs:     map[string]interface{}{"length_mm": "336 mm"},
+			predicate: models.SpecPredicate{SpecKey: "length_mm", Operator: models.SpecOperatorLTE, Value: 360.0},
+			passed:    true,
+			known:     true,
+		},
+		{
+			name:      "Missing key is unknown",
+			specs:     map[string]interface{}{},
+			predicate: models.SpecPredicate{SpecKey: "socket", Operator: models.SpecOperatorEquals, Value: "AM5"},
+			passed:    false,
+			known:     false,
+		},
+		{
+			name:      "Non numeric value is unknown",
+			specs:     map[string]interface{}{"length_mm": "varies"},
+			predicate: models.SpecPredicate{SpecKey: "length_mm", Operator: models.SpecOperatorLTE, Value: 360.0},
+			passed:    false,
+			known:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, known := EvaluateSpecPredicate(tt.specs, tt.predicate)
+			assert.Equal(t, tt.passed, passed)
+			assert.Equal(t, tt.known, known)
+		})
+	}
+}
+
+// TestExplainCompatibleComponents tests borderline explanations and that the page filtered in SQL keeps its size
+func TestExplainCompatibleComponents(t *testing.T) {
+	predicates := []models.SpecPredicate{
+		{SpecKey: "socket", Operator: models.SpecOperatorEquals, Value: "AM5", Reason: "socket must match"},
+	}
+	components := []models.Component{
+		{ID: "1", Category: models.CategoryMotherboard, Specs: json.RawMessage(`{"socket": " am5 "}`)},
+		{ID: "2", Category: models.CategoryMotherboard, Specs: json.RawMessage(`{"socket": null}`)},
+	}
+
+	result := ExplainCompatibleComponents(components, predicates)
+	assert.Len(t, result, 2)
+
+	assert.Equal(t, "1", result[0].ID)
+	assert.False(t, result[0].Borderline)
+	assert.Empty(t, result[0].Explanation)
+
+	assert.Equal(t, "2", result[1].ID)
+	assert.True(t, result[1].Borderline)
+	assert.Len(t, result[1].Explanation, 1)
+	assert.Contains(t, result[1].Explanation[0], "socket must match")
+}
+
+// TestEvaluateCompatibilityRules tests rule evaluation and message rendering
+func TestEvaluateCompatibilityRules(t *testing.T) {
+	rules := []models.CompatibilityRule{{
+		ID:             "cpu-motherboard-socket",
+		Description:    "socket must match",
+		SourceCategory: models.CategoryCPU,
+		TargetCategory: models.CategoryMotherboard,
+		Comparisons:    []models.RuleComparison{{SourceKey: "socket", Operator: models.SpecOperatorEquals, TargetKey: "socket"}},
+		Severity:       models.RuleSeverityError,
+		Message:        "{{.Target.Model}} has {{.TargetValue}}, {{.Source.Model}} needs {{.SourceValue}}",
+	}}
+
+	t.Run("Mismatch produces a rendered issue", func(t *testing.T) {
+		parts := []models.BuildComponentWithDetails{
+			buildPart(models.CategoryCPU, "Ryzen 7 7800X3D", `{"socket": "AM5"}`),
+			buildPart(models.CategoryMotherboard, "PRO Z790-A", `{"socket": "LGA1700"}`),
+		}
+
+		issues := EvaluateCompatibilityRules(parts, rules)
+		assert.Len(t, issues, 1)
+		assert.Equal(t, "cpu-motherboard-socket", issues[0].RuleID)
+		assert.Equal(t, models.RuleSeverityError, issues[0].Severity)
+		assert.Equal(t, "PRO Z790-A has LGA1700, Ryzen 7 7800X3D needs AM5", issues[0].Message)
+
+		report := NewCompatibilityReport(1, issues)
+		assert.False(t, report.Compatible)
+	})
+
+	t.Run("Unverifiable comparison is skipped", func(t *testing.T) {
+		parts := []models.BuildComponentWithDetails{
+			buildPart(models.CategoryCPU, "Ryzen 7 7800X3D", `{"socket": "AM5"}`),
+			buildPart(models.CategoryMotherboard, "Mystery Board", `{}`),
+		}
+
+		issues := EvaluateCompatibilityRules(parts, rules)
+		assert.Empty(t, issues)
+
+		report := NewCompatibilityReport(1, issues)
+		assert.True(t, report.Compatible)
+		assert.NotNil(t, report.Issues)
+	})
+
+	t.Run("Broken template falls back to description", func(t *testing.T) {
+		broken := rules[0]
+		broken.Message = "{{.Missing}}"
+		parts := []models.BuildComponentWithDetails{
+			buildPart(models.CategoryCPU, "Ryzen 7 7800X3D", `{"socket": "AM5"}`),
+			buildPart(models.CategoryMotherboard, "PRO Z790-A", `{"socket": "LGA1700"}`),
+		}
+
+		issues := EvaluateCompatibilityRules(parts, []models.CompatibilityRule{broken})
+		assert.Len(t, issues, 1)
+		assert.Equal(t, "socket must match", issues[0].Message)
+	})
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	compatibilityRulesMu  sync.RWMutex
+	compatibilityRules    []models.CompatibilityRule
+	compatibilityRulesDir string
+)
+
+// InitializeCompatibilityRules loads the rule definitions from COMPATIBILITY_RULES_DIR and fails when they cannot
+// be loaded. Without the variable the default directory is used and a failure only logs a warning, leaving the
+// service running without rules.
+func InitializeCompatibilityRules() error {
+	dir, explicit := compatibilityRulesSource()
+	if err := LoadCompatibilityRules(dir); err != nil {
+		if explicit {
+			return err
+		}
+		utils.Log(constants.SERVICE_LOAD_COMPATIBILITY_RULES_FALLBACK, err, dir)
+	}
+	return nil
+}
+
+// LoadCompatibilityRules reads and validates every rule file in dir and makes the rules active.
+// The active rules are left untouched when any file is invalid.
+func LoadCompatibilityRules(dir string) error {
+	utils.Log(constants.SERVICE_LOAD_COMPATIBILITY_RULES_START, nil, dir)
+
+	rules, err := ReadCompatibilityRules(dir)
+	if err != nil {
+		utils.Log(constants.SERVICE_LOAD_COMPATIBILITY_RULES_ERROR, err, dir)
+		return err
+	}
+
+	compatibilityRulesMu.Lock()
+	compatibilityRules = rules
+	compatibilityRulesDir = dir
+	compatibilityRulesMu.Unlock()
+
+	utils.Log(constants.SERVICE_LOAD_COMPATIBILITY_RULES_SUCCESS, nil, len(rules), dir)
+	return nil
+}
+
+// ReloadCompatibilityRules reloads the rules from the directory they were last loaded from
+func ReloadCompatibilityRules() error {
+	compatibilityRulesMu.RLock()
+	dir := compatibilityRulesDir
+	compatibilityRulesMu.RUnlock()
+
+	if dir == "" {
+		dir, _ = compatibilityRulesSource()
+	}
+	return LoadCompatibilityRules(dir)
+}
+
+// compatibilityRulesSource returns the rules directory and whether it was set explicitly. The default directory is
+// looked up in the working directory first and then next to the executable, so the binary can run from anywhere.
+func compatibilityRulesSource() (string, bool) {
+	if dir := os.Getenv("COMPATIBILITY_RULES_DIR"); dir != "" {
+		return dir, true
+	}
+
+	dir := constants.DEFAULT_COMPATIBILITY_RULES_DIR
+	if _, err := os.Stat(dir); err == nil {
+		return dir, false
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return dir, false
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	return filepath.Join(filepath.Dir(executable), dir), false
+}
+
+// GetCompatibilityRules returns a copy of the active rules
+func GetCompatibilityRules() []models.CompatibilityRule {
+	compatibilityRulesMu.RLock()
+	defer compatibilityRulesMu.RUnlock()
+
+	rules := make([]models.CompatibilityRule, len(compatibilityRules))
+	copy(rules, compatibilityRules)
+	return rules
+}
+
+// ReadCompatibilityRules parses the .json, .yaml and .yml files directly inside dir.
+// Every problem found is reported, prefixed with the file and rule it belongs to.
+func ReadCompatibilityRules(dir string) ([]models.CompatibilityRule, error) {
+	files, err := listDefinitionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []models.CompatibilityRule{}
+	seen := map[string]string{}
+	problems := []error{}
+
+	for _, file := range files {
+		var ruleFile models.CompatibilityRuleFile
+		if err := decodeDefinitionFile(file, &ruleFile); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+
+		for i, rule := range ruleFile.Rules {
+			name := rule.ID
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			for _, problem := range ValidateCompatibilityRule(rule) {
+				problems = append(problems, fmt.Errorf("%s: rule %s: %w", file, name, problem))
+			}
+			if rule.ID != "" {
+				if other, ok := seen[rule.ID]; ok {
+					problems = append(problems, fmt.Errorf("%s: rule %s: id already defined in %s", file, name, other))
+				}
+				seen[rule.ID] = file
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return rules, nil
+}
+
+// ValidateCompatibilityRule returns every problem found in a rule definition
+func ValidateCompatibilityRule(rule models.CompatibilityRule) []error {
+	problems := []error{}
+
+	if strings.TrimSpace(rule.ID) == "" {
+		problems = append(problems, errors.New("id is required"))
+	}
+	if !rule.SourceCategory.Valid() {
+		problems = append(problems, fmt.Errorf("unknown source_category %q", rule.SourceCategory))
+	}
+	if !rule.TargetCategory.Valid() {
+		problems = append(problems, fmt.Errorf("unknown target_category %q", rule.TargetCategory))
+	}
+	if !rule.Severity.Valid() {
+		problems = append(problems, fmt.Errorf("unknown severity %q (expected error, warning or info)", rule.Severity))
+	}
+	if len(rule.Comparisons) == 0 {
+		problems = append(problems, errors.New("at least one comparison is required"))
+	}
+	for i, comparison := range rule.Comparisons {
+		if comparison.SourceKey == "" || comparison.TargetKey == "" {
+			problems = append(problems, fmt.Errorf("comparison %d: source_key and target_key are required", i+1))
+		}
+		if !comparison.Operator.Valid() {
+			problems = append(problems, fmt.Errorf("comparison %d: unknown operator %q (expected equals, contains, in, lte or gte)", i+1, comparison.Operator))
+		}
+	}
+	if strings.TrimSpace(rule.Message) == "" {
+		problems = append(problems, errors.New("message is required"))
+	} else if _, err := template.New(rule.ID).Option("missingkey=error").Parse(rule.Message); err != nil {
+		problems = append(problems, fmt.Errorf("invalid message template: %w", err))
+	}
+
+	return problems
+}
+
+// ReadRuleFixtures parses the rule test fixtures stored in dir
+func ReadRuleFixtures(dir string) ([]models.RuleFixture, error) {
+	files, err := listDefinitionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := []models.RuleFixture{}
+	for _, file := range files {
+		var fixtureFile models.RuleFixtureFile
+		if err := decodeDefinitionFile(file, &fixtureFile); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, fixture := range fixtureFile.Fixtures {
+			if fixture.Name == "" {
+				fixture.Name = filepath.Base(file)
+			}
+			fixtures = append(fixtures, fixture)
+		}
+	}
+	return fixtures, nil
+}
+
+// RunRuleFixture evaluates the rules against a fixture and reports any difference
+// between the rule ids that fired and the ones the fixture expects
+func RunRuleFixture(rules []models.CompatibilityRule, fixture models.RuleFixture) error {
+	parts := make([]models.BuildComponentWithDetails, 0, len(fixture.Components))
+	for i, fc := range fixture.Components {
+		specs, err := json.Marshal(fc.Specs)
+		if err != nil {
+			return fmt.Errorf("%s: component %d: %w", fixture.Name, i+1, err)
+		}
+		id := fc.ID
+		if id == "" {
+			id = fmt.Sprint(i + 1)
+		}
+		component := models.Component{ID: id, Category: fc.Category, Brand: fc.Brand, Model: fc.Model, Specs: specs}
+		parts = append(parts, models.BuildComponentWithDetails{
+			BuildComponent: models.BuildComponent{Quantity: 1},
+			Component:      &component,
+		})
+	}
+
+	actual := []string{}
+	for _, issue := range EvaluateCompatibilityRules(parts, rules) {
+		actual = append(actual, issue.RuleID)
+	}
+	expected := append([]string{}, fixture.ExpectedIssues...)
+	sort.Strings(actual)
+	sort.Strings(expected)
+
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		return fmt.Errorf("%s: expected issues %v, got %v", fixture.Name, expected, actual)
+	}
+	return nil
+}
+
+func listDefinitionFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	files := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// decodeDefinitionFile decodes a JSON or YAML file, rejecting unknown fields so typos surface as errors
+func decodeDefinitionFile(file string, out interface{}) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if strings.ToLower(filepath.Ext(file)) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(out)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	return decoder.Decode(out)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shippedRulesDir = "../../compatibility_rules"
+
+func loadShippedRules(t *testing.T) {
+	t.Helper()
+	require.NoError(t, LoadCompatibilityRules(shippedRulesDir))
+}
+
+func writeRuleFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+// TestShippedRuleFixtures runs every fixture in compatibility_rules/tests against the shipped rules
+func TestShippedRuleFixtures(t *testing.T) {
+	rules, err := ReadCompatibilityRules(shippedRulesDir)
+	require.NoError(t, err)
+	assert.NotEmpty(t, rules)
+
+	fixtures, err := ReadRuleFixtures(filepath.Join(shippedRulesDir, "tests"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, fixtures)
+
+	for _, fixture := range fixtures {
+		t.Run(fixture.Name, func(t *testing.T) {
+			assert.NoError(t, RunRuleFixture(rules, fixture))
+		})
+	}
+}
+
+// TestReadCompatibilityRules_Validation tests that invalid definitions are reported with their location
+func TestReadCompatibilityRules_Validation(t *testing.T) {
+	t.Run("Invalid fields are all reported", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRuleFile(t, dir, "bad.json", `{"rules": [{
+			"id": "bad-rule",
+			"source_category": "processor",
+			"target_category": "motherboard",
+			"comparisons": [{"source_key": "socket", "operator": "like", "target_key": "socket"}],
+			"severity": "fatal",
+			"message": "{{.Target.Model"
+		}]}`)
+
+		_, err := ReadCompatibilityRules(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad.json: rule bad-rule")
+		assert.Contains(t, err.Error(), `unknown source_category "processor"`)
+		assert.Contains(t, err.Error(), `unknown operator "like"`)
+		assert.Contains(t, err.Error(), `unknown severity "fatal"`)
+		assert.Contains(t, err.Error(), "invalid message template")
+	})
+
+	t.Run("Unknown YAML keys are rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRuleFile(t, dir, "typo.yaml", `
+rules:
+  - id: typo
+    source_category: cpu
+    target_categroy: motherboard
+`)
+
+		_, err := ReadCompatibilityRules(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "typo.yaml")
+	})
+
+	t.Run("Duplicate ids across files", func(t *testing.T) {
+		dir := t.TempDir()
+		rule := `{"rules": [{"id": "dup", "source_category": "cpu", "target_category": "motherboard",
+			"comparisons": [{"source_key": "socket", "operator": "equals", "target_key": "socket"}],
+			"severity": "error", "message": "mismatch"}]}`
+		writeRuleFile(t, dir, "a.json", rule)
+		writeRuleFile(t, dir, "b.json", rule)
+
+		_, err := ReadCompatibilityRules(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "id already defined in")
+	})
+
+	t.Run("YAML rules are loaded", func(t *testing.T) {
+		dir := t.TempDir()
+		writeRuleFile(t, dir, "psu.yml", `
+rules:
+  - id: case-psu
+    description: psu must fit
+    source_category: case
+    target_category: power_supply
+    comparisons:
+      - source_key: supported_psu_form_factors
+        operator: in
+        target_key: form_factor
+    severity: warning
+    message: "{{.Target.Model}} may not fit"
+`)
+		writeRuleFile(t, dir, "README.md", "ignored")
+
+		rules, err := ReadCompatibilityRules(dir)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		assert.Equal(t, models.RuleSeverityWarning, rules[0].Severity)
+		assert.Equal(t, models.SpecOperatorIn, rules[0].Comparisons[0].Operator)
+	})
+
+	t.Run("Missing directory", func(t *testing.T) {
+		_, err := ReadCompatibilityRules(filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+}
+
+// TestReloadCompatibilityRules tests that a failed reload keeps the active rules
+func TestReloadCompatibilityRules(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, dir, "rules.json", `{"rules": [{"id": "socket", "source_category": "cpu", "target_category": "motherboard",
+		"comparisons": [{"source_key": "socket", "operator": "equals", "target_key": "socket"}],
+		"severity": "error", "message": "mismatch"}]}`)
+
+	require.NoError(t, LoadCompatibilityRules(dir))
+	defer loadShippedRules(t)
+	assert.Len(t, GetCompatibilityRules(), 1)
+
+	writeRuleFile(t, dir, "broken.json", `{"rules": [{"id": ""}]}`)
+	assert.Error(t, ReloadCompatibilityRules())
+	assert.Len(t, GetCompatibilityRules(), 1)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "broken.json")))
+	writeRuleFile(t, dir, "more.json", `{"rules": [{"id": "memory", "source_category": "motherboard", "target_category": "memory",
+		"comparisons": [{"source_key": "memory_type", "operator": "equals", "target_key": "memory_type"}],
+		"severity": "error", "message": "mismatch"}]}`)
+	assert.NoError(t, ReloadCompatibilityRules())
+	assert.Len(t, GetCompatibilityRules(), 2)
+}
+
+// TestInitializeCompatibilityRules tests that only an explicitly configured rules directory is required
+func TestInitializeCompatibilityRules(t *testing.T) {
+	defer loadShippedRules(t)
+
+	t.Run("Explicit directory must load", func(t *testing.T) {
+		t.Setenv("COMPATIBILITY_RULES_DIR", filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, InitializeCompatibilityRules())
+	})
+
+	t.Run("Missing default directory only warns", func(t *testing.T) {
+		t.Setenv("COMPATIBILITY_RULES_DIR", "")
+		t.Chdir(t.TempDir())
+		assert.NoError(t, InitializeCompatibilityRules())
+	})
+
+	t.Run("Default directory in the working directory", func(t *testing.T) {
+		t.Setenv("COMPATIBILITY_RULES_DIR", "")
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "compatibility_rules"), 0o755))
+		writeRuleFile(t, filepath.Join(dir, "compatibility_rules"), "rules.json", `{"rules": [{"id": "socket", "source_category": "cpu",
+			"target_category": "motherboard", "comparisons": [{"source_key": "socket", "operator": "equals", "target_key": "socket"}],
+			"severity": "error", "message": "mismatch"}]}`)
+		t.Chdir(dir)
+
+		require.NoError(t, InitializeCompatibilityRules())
+		assert.Len(t, GetCompatibilityRules(), 1)
+	})
+}
+
+// TestRunRuleFixture_ReportsDifferences tests fixture failure output
+func TestRunRuleFixture_ReportsDifferences(t *testing.T) {
+	rules, err := ReadCompatibilityRules(shippedRulesDir)
+	require.NoError(t, err)
+
+	fixture := models.RuleFixture{
+		Name: "expects an issue that does not fire",
+		Components: []models.RuleFixtureComponent{
+			{Category: models.CategoryCPU, Model: "Ryzen 5 7600", Specs: map[string]interface{}{"socket": "AM5"}},
+			{Category: models.CategoryMotherboard, Model: "B650M", Specs: map[string]interface{}{"socket": "AM5"}},
+		},
+		ExpectedIssues: []string{"cpu-motherboard-socket"},
+	}
+
+	err = RunRuleFixture(rules, fixture)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expects an issue that does not fire")
+}
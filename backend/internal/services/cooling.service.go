@@ -0,0 +1,267 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const (
+	CoolingInsufficient = "insufficient"
+	CoolingAdequate     = "adequate"
+	CoolingExcellent    = "excellent"
+	CoolingUnknown      = "unknown"
+
+	AirflowBalanced = "balanced"
+	AirflowPositive = "positive"
+	AirflowNegative = "negative"
+	AirflowUnknown  = "unknown"
+)
+
+var coolingRatingOrder = map[string]int{
+	CoolingInsufficient: 0,
+	CoolingAdequate:     1,
+	CoolingExcellent:    2,
+}
+
+// fanGroup is a number of fans with the same airflow, counted rather than listed one by one
+type fanGroup struct {
+	cfm   float64
+	count int
+}
+
+func AnalyzeBuildCooling(buildID int64, viewerID string) (models.CoolingReport, error) {
+	utils.Log(constants.SERVICE_ANALYZE_COOLING_START, nil, buildID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_ANALYZE_COOLING_ERROR, err, buildID)
+		return models.CoolingReport{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_ANALYZE_COOLING_ERROR, err, buildID)
+		return models.CoolingReport{}, err
+	}
+
+	report := AnalyzeCooling(parts)
+	report.BuildID = buildID
+
+	utils.Log(constants.SERVICE_ANALYZE_COOLING_SUCCESS, nil, buildID, report.Rating)
+	return report, nil
+}
+
+// AnalyzeCooling rates the cooling of a build from the cooler's rated TDP against the CPU's sustained power,
+// the number of fans against the case's fan mounts, and an estimate of intake and exhaust airflow.
+// The rating is the lowest of the three.
+func AnalyzeCooling(parts []models.BuildComponentWithDetails) models.CoolingReport {
+	report := models.CoolingReport{
+		Rating:         CoolingExcellent,
+		AirflowBalance: AirflowUnknown,
+		Reasons:        []string{},
+	}
+
+	cpu := findPart(parts, models.CategoryCPU)
+	if cpu == nil {
+		report.Rating = CoolingUnknown
+		report.Reasons = append(report.Reasons, "Add a CPU to analyze cooling")
+		return report
+	}
+
+	analyzeCPUCooling(&report, parts, *cpu)
+	analyzeCaseAirflow(&report, parts)
+
+	return report
+}
+
+func analyzeCPUCooling(report *models.CoolingReport, parts []models.BuildComponentWithDetails, cpu models.Component) {
+	cpuSpecs := utils.ParseSpecs(cpu.Specs)
+	power, hasPower := utils.GetSpecNumber(cpuSpecs, constants.SPEC_SUSTAINED_POWER, constants.SPEC_TDP)
+	if hasPower {
+		report.CPUPowerW = &power
+	}
+
+	cooler := findPart(parts, models.CategoryCPUCooler)
+	if cooler == nil {
+		cooler = findPart(parts, models.CategoryWaterCooling)
+	}
+
+	var rating float64
+	var hasRating bool
+	coolerName := ""
+	switch {
+	case cooler != nil:
+		coolerName = cooler.Model
+		rating, hasRating = utils.GetSpecNumber(utils.ParseSpecs(cooler.Specs), constants.SPEC_RATED_TDP, constants.SPEC_TDP)
+	default:
+		if included, _ := utils.GetSpecBool(cpuSpecs, constants.SPEC_INCLUDES_COOLER); !included {
+			downgradeCooling(report, CoolingInsufficient, fmt.Sprintf("%s does not include a cooler; add a CPU cooler", cpu.Model))
+			return
+		}
+		// Stock coolers are designed for the rated TDP, not for sustained boost power
+		coolerName = "The stock cooler"
+		rating, hasRating = utils.GetSpecNumber(cpuSpecs, constants.SPEC_TDP)
+		downgradeCooling(report, CoolingAdequate, fmt.Sprintf("%s uses its stock cooler, which leaves little thermal headroom", cpu.Model))
+	}
+
+	if !hasRating || !hasPower || power <= 0 {
+		downgradeCooling(report, CoolingAdequate, fmt.Sprintf("The rated TDP of %s or the power of %s is not listed, so cooling capacity could not be compared", coolerName, cpu.Model))
+		return
+	}
+	report.CoolerRatingW = &rating
+	headroom := roundTo(rating/power, 2)
+	report.ThermalHeadroom = &headroom
+
+	switch {
+	case rating < power:
+		downgradeCooling(report, CoolingInsufficient, fmt.Sprintf("%s is rated for %.0f W but %s draws up to %.0f W", coolerName, rating, cpu.Model, power))
+	case headroom < constants.COOLING_EXCELLENT_HEADROOM:
+		downgradeCooling(report, CoolingAdequate, fmt.Sprintf("%s is rated for %.0f W, enough for the %.0f W of %s with little headroom", coolerName, rating, power, cpu.Model))
+	default:
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%s is rated for %.0f W, %.0f%% above the %.0f W of %s", coolerName, rating, (headroom-1)*100, power, cpu.Model))
+	}
+}
+
+// analyzeCaseAirflow counts the fans of a build against the mounts of the case and estimates their placement.
+// Radiator fans take mounts and exhaust; the strongest of the remaining fans go to intake so the case keeps a
+// slightly positive pressure.
+func analyzeCaseAirflow(report *models.CoolingReport, parts []models.BuildComponentWithDetails) {
+	pcCase := findPart(parts, models.CategoryCase)
+	if pcCase == nil {
+		downgradeCooling(report, CoolingAdequate, "Add a case to analyze airflow")
+		return
+	}
+	caseSpecs := utils.ParseSpecs(pcCase.Specs)
+
+	fans := []fanGroup{}
+	if included, ok := utils.GetSpecNumber(caseSpecs, constants.SPEC_INCLUDED_FANS); ok && included >= 1 {
+		fans = append(fans, fanGroup{cfm: constants.DEFAULT_FAN_CFM, count: int(included)})
+	}
+	radiatorFans, radiatorCFM := 0, 0.0
+	for _, part := range parts {
+		if part.Component == nil {
+			continue
+		}
+		specs := utils.ParseSpecs(part.Component.Specs)
+		quantity := part.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+
+		switch part.Component.Category {
+		case models.CategoryCaseFan:
+			cfm, ok := utils.GetSpecNumber(specs, constants.SPEC_AIRFLOW_CFM)
+			if !ok {
+				cfm = constants.DEFAULT_FAN_CFM
+			}
+			fans = append(fans, fanGroup{cfm: cfm, count: quantity})
+		case models.CategoryWaterCooling:
+			count, _ := utils.GetSpecNumber(specs, constants.SPEC_RADIATOR_FANS)
+			cfm, ok := utils.GetSpecNumber(specs, constants.SPEC_AIRFLOW_CFM)
+			if !ok {
+				cfm = constants.DEFAULT_FAN_CFM
+			}
+			if count >= 1 {
+				radiatorFans += int(count) * quantity
+				radiatorCFM += cfm * float64(int(count)*quantity)
+			}
+		}
+	}
+	sort.SliceStable(fans, func(i, j int) bool { return fans[i].cfm > fans[j].cfm })
+	caseFans := 0
+	for _, group := range fans {
+		caseFans += group.count
+	}
+	report.Fans = caseFans + radiatorFans
+
+	if mounts, ok := utils.GetSpecNumber(caseSpecs, constants.SPEC_FAN_MOUNTS); ok {
+		total := int(mounts)
+		report.FanMounts = &total
+		if report.Fans > total {
+			downgradeCooling(report, CoolingInsufficient, fmt.Sprintf("The build has %d fans but %s only has %d fan mounts", report.Fans, pcCase.Model, total))
+		}
+	}
+
+	if report.Fans == 0 {
+		downgradeCooling(report, CoolingInsufficient, fmt.Sprintf("%s has no fans; add case fans for airflow", pcCase.Model))
+		return
+	}
+	if report.Fans < constants.COOLING_EXCELLENT_FANS {
+		downgradeCooling(report, CoolingAdequate, fmt.Sprintf("Only %d fans; %d or more keep components cooler under sustained load", report.Fans, constants.COOLING_EXCELLENT_FANS))
+	}
+
+	intakeMounts, hasIntakeMounts := utils.GetSpecNumber(caseSpecs, constants.SPEC_INTAKE_FAN_MOUNTS)
+	exhaustMounts, hasExhaustMounts := utils.GetSpecNumber(caseSpecs, constants.SPEC_EXHAUST_FAN_MOUNTS)
+
+	// A single fan goes to the rear as exhaust; otherwise about half of the fans exhaust
+	exhaustTarget := report.Fans / 2
+	if exhaustTarget < 1 {
+		exhaustTarget = 1
+	}
+	caseExhaust := exhaustTarget - radiatorFans
+	if caseExhaust < 0 {
+		caseExhaust = 0
+	}
+	if caseExhaust > caseFans {
+		caseExhaust = caseFans
+	}
+	intake := caseFans - caseExhaust
+	if hasIntakeMounts && intake > int(intakeMounts) {
+		intake = int(intakeMounts)
+	}
+	if hasExhaustMounts && caseFans-intake+radiatorFans > int(exhaustMounts) {
+		intake = caseFans + radiatorFans - int(exhaustMounts)
+		if intake > caseFans {
+			intake = caseFans
+		}
+	}
+
+	// The strongest fans take the intake mounts
+	remaining := intake
+	for _, group := range fans {
+		intakeCount := min(group.count, max(remaining, 0))
+		remaining -= intakeCount
+		report.IntakeCFM += group.cfm * float64(intakeCount)
+		report.ExhaustCFM += group.cfm * float64(group.count-intakeCount)
+	}
+	report.ExhaustCFM += radiatorCFM
+	report.IntakeFans = intake
+	report.ExhaustFans = report.Fans - intake
+
+	switch {
+	case report.ExhaustCFM == 0:
+		report.AirflowBalance = AirflowPositive
+		downgradeCooling(report, CoolingInsufficient, "No fan is left for exhaust, so hot air stays in the case")
+		return
+	case report.IntakeCFM == 0:
+		report.AirflowBalance = AirflowNegative
+		downgradeCooling(report, CoolingAdequate, "Every fan exhausts; the case pulls in unfiltered air and dust through its gaps")
+		return
+	}
+
+	ratio := report.IntakeCFM / report.ExhaustCFM
+	switch {
+	case ratio < constants.AIRFLOW_BALANCE_MIN:
+		report.AirflowBalance = AirflowNegative
+		downgradeCooling(report, CoolingAdequate, fmt.Sprintf("Estimated negative pressure (%.0f CFM intake, %.0f CFM exhaust) draws dust in through unfiltered gaps", report.IntakeCFM, report.ExhaustCFM))
+	case ratio > constants.AIRFLOW_BALANCE_MAX:
+		report.AirflowBalance = AirflowPositive
+		downgradeCooling(report, CoolingAdequate, fmt.Sprintf("Estimated intake (%.0f CFM) far exceeds exhaust (%.0f CFM); add an exhaust fan", report.IntakeCFM, report.ExhaustCFM))
+	default:
+		report.AirflowBalance = AirflowBalanced
+		report.Reasons = append(report.Reasons, fmt.Sprintf("Estimated %d intake and %d exhaust fans (%.0f/%.0f CFM) give balanced airflow",
+			report.IntakeFans, report.ExhaustFans, report.IntakeCFM, report.ExhaustCFM))
+	}
+}
+
+// downgradeCooling lowers the rating to at most the given one and records why
+func downgradeCooling(report *models.CoolingReport, rating, reason string) {
+	report.Reasons = append(report.Reasons, reason)
+	if coolingRatingOrder[rating] < coolingRatingOrder[report.Rating] {
+		report.Rating = rating
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyzeCooling tests the cooling rating of a build
+func TestAnalyzeCooling(t *testing.T) {
+	cpu := storagePart(models.CategoryCPU, "1", "Core i7-14700K", `{"tdp": 125, "sustained_power_w": 253}`, 1)
+	bigCooler := storagePart(models.CategoryCPUCooler, "2", "NH-D15", `{"rated_tdp": 340}`, 1)
+	smallCooler := storagePart(models.CategoryCPUCooler, "3", "Hyper 212", `{"rated_tdp": 180}`, 1)
+	pcCase := storagePart(models.CategoryCase, "4", "Lancool 216", `{"fan_mounts": 7, "included_fans": 3}`, 1)
+	fans := func(quantity int) models.BuildComponentWithDetails {
+		return storagePart(models.CategoryCaseFan, "5", "P12", `{"airflow_cfm": 56}`, quantity)
+	}
+
+	t.Run("Excellent cooling", func(t *testing.T) {
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, bigCooler, pcCase, fans(1)})
+
+		assert.Equal(t, CoolingExcellent, report.Rating)
+		require.NotNil(t, report.ThermalHeadroom)
+		assert.Equal(t, 1.34, *report.ThermalHeadroom)
+		assert.Equal(t, 4, report.Fans)
+		assert.Equal(t, 2, report.IntakeFans)
+		assert.Equal(t, 2, report.ExhaustFans)
+		assert.Equal(t, 106.0, report.IntakeCFM)
+		assert.Equal(t, AirflowBalanced, report.AirflowBalance)
+	})
+
+	t.Run("Cooler rated below sustained power", func(t *testing.T) {
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, smallCooler, pcCase})
+
+		assert.Equal(t, CoolingInsufficient, report.Rating)
+		assert.Contains(t, report.Reasons[0], "Hyper 212 is rated for 180 W but Core i7-14700K draws up to 253 W")
+	})
+
+	t.Run("More fans than mounts", func(t *testing.T) {
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, bigCooler, pcCase, fans(5)})
+
+		assert.Equal(t, CoolingInsufficient, report.Rating)
+		assert.Equal(t, 8, report.Fans)
+		assert.Contains(t, report.Reasons, "The build has 8 fans but Lancool 216 only has 7 fan mounts")
+	})
+
+	t.Run("Fans are counted, not listed one by one", func(t *testing.T) {
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, bigCooler, pcCase, fans(2000000000)})
+
+		assert.Equal(t, 2000000003, report.Fans)
+		assert.Equal(t, CoolingInsufficient, report.Rating)
+	})
+
+	t.Run("No fans", func(t *testing.T) {
+		bare := storagePart(models.CategoryCase, "6", "Open Frame", `{"fan_mounts": 4}`, 1)
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, bigCooler, bare})
+
+		assert.Equal(t, CoolingInsufficient, report.Rating)
+		assert.Equal(t, AirflowUnknown, report.AirflowBalance)
+	})
+
+	t.Run("Radiator fans exhaust", func(t *testing.T) {
+		aio := storagePart(models.CategoryWaterCooling, "7", "Arctic LF III 360", `{"rated_tdp": 350, "radiator_fans": 3}`, 1)
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, aio, pcCase})
+
+		assert.Equal(t, 6, report.Fans)
+		assert.Equal(t, 3, report.ExhaustFans)
+		assert.Equal(t, 3, report.IntakeFans)
+		assert.Equal(t, CoolingExcellent, report.Rating)
+	})
+
+	t.Run("Intake mounts limit placement", func(t *testing.T) {
+		limited := storagePart(models.CategoryCase, "8", "Mini ITX", `{"fan_mounts": 4, "intake_fan_mounts": 1}`, 1)
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, bigCooler, limited, fans(4)})
+
+		assert.Equal(t, 1, report.IntakeFans)
+		assert.Equal(t, 3, report.ExhaustFans)
+		assert.Equal(t, AirflowNegative, report.AirflowBalance)
+		assert.Equal(t, CoolingAdequate, report.Rating)
+	})
+
+	t.Run("Stock cooler", func(t *testing.T) {
+		boxed := storagePart(models.CategoryCPU, "9", "Ryzen 5 5600", `{"tdp": 65, "includes_cooler": true}`, 1)
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{boxed, pcCase})
+
+		assert.Equal(t, CoolingAdequate, report.Rating)
+	})
+
+	t.Run("No cooler", func(t *testing.T) {
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{cpu, pcCase})
+		assert.Equal(t, CoolingInsufficient, report.Rating)
+	})
+
+	t.Run("No CPU", func(t *testing.T) {
+		report := AnalyzeCooling([]models.BuildComponentWithDetails{pcCase})
+		assert.Equal(t, CoolingUnknown, report.Rating)
+	})
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const (
+	LimiterCPU      = "cpu"
+	LimiterGPU      = "gpu"
+	LimiterBalanced = "balanced"
+	LimiterUnknown  = "unknown"
+)
+
+// useCaseProfile selects the scores compared for a use case.
+// gpuDemand scales how hard the use case loads the GPU relative to gaming.
+type useCaseProfile struct {
+	cpuKey    string
+	gpuKey    string
+	gpuDemand float64
+}
+
+var useCaseProfiles = map[string]useCaseProfile{
+	constants.USE_CASE_GAMING:      {cpuKey: constants.SPEC_PERFORMANCE_GAMING, gpuKey: constants.SPEC_PERFORMANCE_GAMING, gpuDemand: 1},
+	constants.USE_CASE_WORKSTATION: {cpuKey: constants.SPEC_PERFORMANCE_MULTI_THREAD, gpuKey: constants.SPEC_PERFORMANCE_COMPUTE, gpuDemand: 1},
+	constants.USE_CASE_OFFICE:      {cpuKey: constants.SPEC_PERFORMANCE_SINGLE_THREAD, gpuKey: constants.SPEC_PERFORMANCE_GAMING, gpuDemand: 0.4},
+}
+
+// GPU gaming scores are rated at 1440p; lower resolutions shift load towards the CPU
+var resolutionGPUFactors = map[string]float64{
+	constants.RESOLUTION_1080P: 1.3,
+	constants.RESOLUTION_1440P: 1.0,
+	constants.RESOLUTION_4K:    0.6,
+}
+
+// IsValidUseCase returns true if the use case has a performance profile
+func IsValidUseCase(useCase string) bool {
+	_, ok := useCaseProfiles[useCase]
+	return ok
+}
+
+// IsValidResolution returns true if the resolution is supported
+func IsValidResolution(resolution string) bool {
+	_, ok := resolutionGPUFactors[resolution]
+	return ok
+}
+
+// PerformanceScore returns the score of a CPU or video card for a use case
+func PerformanceScore(component models.Component, useCase string) (float64, bool) {
+	profile, ok := useCaseProfiles[useCase]
+	if !ok {
+		return 0, false
+	}
+	specs := utils.ParseSpecs(component.Specs)
+
+	switch component.Category {
+	case models.CategoryCPU:
+		if score, ok := utils.GetSpecNumber(specs, profile.cpuKey); ok {
+			return score, true
+		}
+		return utils.GetSpecNumber(specs, constants.SPEC_PERFORMANCE_GAMING)
+	case models.CategoryVideoCard:
+		if score, ok := utils.GetSpecNumber(specs, profile.gpuKey); ok {
+			return score, true
+		}
+		return utils.GetSpecNumber(specs, constants.SPEC_PERFORMANCE_GAMING)
+	}
+	return 0, false
+}
+
+// effectiveGPUScore puts a GPU score on the same scale as the CPU score for a use case and resolution
+func effectiveGPUScore(score float64, useCase, resolution string) float64 {
+	profile := useCaseProfiles[useCase]
+	factor := 1.0
+	if useCase == constants.USE_CASE_GAMING {
+		factor = resolutionGPUFactors[resolution]
+	}
+	return score * factor / profile.gpuDemand
+}
+
+// BalanceScore is 100 when both sides are equally strong and drops as one falls behind
+func BalanceScore(cpuEffective, gpuEffective float64) float64 {
+	if cpuEffective <= 0 || gpuEffective <= 0 {
+		return 0
+	}
+	return roundTo(100*math.Min(cpuEffective, gpuEffective)/math.Max(cpuEffective, gpuEffective), 1)
+}
+
+func AnalyzeBuildBottleneck(input models.BottleneckAnalysisInput) (models.BottleneckReport, error) {
+	buildID := input.BuildID
+	utils.Log(constants.SERVICE_ANALYZE_BOTTLENECK_START, nil, buildID, input.UseCase, input.Resolution)
+
+	if _, err := getVisibleBuild(buildID, input.ViewerID); err != nil {
+		utils.Log(constants.SERVICE_ANALYZE_BOTTLENECK_ERROR, err, buildID)
+		return models.BottleneckReport{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_ANALYZE_BOTTLENECK_ERROR, err, buildID)
+		return models.BottleneckReport{}, err
+	}
+
+	report := AnalyzeBottleneck(parts, input.UseCase, input.Resolution)
+	report.BuildID = buildID
+
+	if report.Limiter == LimiterCPU || report.Limiter == LimiterGPU {
+		suggestions, err := findUpgradeSuggestions(parts, report)
+		if err != nil {
+			utils.Log(constants.SERVICE_ANALYZE_BOTTLENECK_ERROR, err, buildID)
+			return models.BottleneckReport{}, err
+		}
+		report.SuggestedUpgrades = suggestions
+	}
+
+	utils.Log(constants.SERVICE_ANALYZE_BOTTLENECK_SUCCESS, nil, buildID, report.Limiter, report.BalanceScore)
+	return report, nil
+}
+
+// AnalyzeBottleneck compares the CPU and GPU of a build for a use case and resolution
+func AnalyzeBottleneck(parts []models.BuildComponentWithDetails, useCase, resolution string) models.BottleneckReport {
+	report := models.BottleneckReport{
+		UseCase:           useCase,
+		Resolution:        resolution,
+		Limiter:           LimiterUnknown,
+		SuggestedUpgrades: []models.UpgradeSuggestion{},
+	}
+
+	cpu := findPart(parts, models.CategoryCPU)
+	gpu := findPart(parts, models.CategoryVideoCard)
+
+	if cpu != nil {
+		if score, ok := PerformanceScore(*cpu, useCase); ok {
+			report.CPU = &models.PerformancePart{ComponentID: cpu.ID, Brand: cpu.Brand, Model: cpu.Model, Score: score, EffectiveScore: score}
+		}
+	}
+	if gpu != nil {
+		if score, ok := PerformanceScore(*gpu, useCase); ok {
+			effective := roundTo(effectiveGPUScore(score, useCase, resolution), 1)
+			report.GPU = &models.PerformancePart{ComponentID: gpu.ID, Brand: gpu.Brand, Model: gpu.Model, Score: score, EffectiveScore: effective}
+		}
+	}
+
+	switch {
+	case cpu == nil || gpu == nil:
+		report.Summary = "Add both a CPU and a video card to analyze their balance"
+		return report
+	case report.CPU == nil || report.GPU == nil:
+		report.Summary = "Performance scores are missing for the CPU or video card"
+		return report
+	}
+
+	report.BalanceScore = BalanceScore(report.CPU.EffectiveScore, report.GPU.EffectiveScore)
+	report.BottleneckPercent = roundTo(100-report.BalanceScore, 1)
+
+	switch {
+	case report.BalanceScore >= constants.BALANCED_RATIO*100:
+		report.Limiter = LimiterBalanced
+		report.Summary = fmt.Sprintf("%s and %s are well balanced for %s", cpu.Model, gpu.Model, describeWorkload(useCase, resolution))
+	case report.CPU.EffectiveScore < report.GPU.EffectiveScore:
+		report.Limiter = LimiterCPU
+		report.Summary = fmt.Sprintf("%s will hold back %s by about %.0f%% for %s", cpu.Model, gpu.Model, report.BottleneckPercent, describeWorkload(useCase, resolution))
+	default:
+		report.Limiter = LimiterGPU
+		report.Summary = fmt.Sprintf("%s limits performance; %s has about %.0f%% headroom for %s", gpu.Model, cpu.Model, report.BottleneckPercent, describeWorkload(useCase, resolution))
+	}
+
+	return report
+}
+
+// findUpgradeSuggestions searches the catalog for compatible replacements of the limiting part.
+// Replacements that balance the build come first, least overkill first, followed by the best partial upgrades.
+func findUpgradeSuggestions(parts []models.BuildComponentWithDetails, report models.BottleneckReport) ([]models.UpgradeSuggestion, error) {
+	category, current := models.CategoryCPU, report.CPU
+	key := useCaseProfiles[report.UseCase].cpuKey
+	if report.Limiter == LimiterGPU {
+		category, current = models.CategoryVideoCard, report.GPU
+		key = useCaseProfiles[report.UseCase].gpuKey
+	}
+
+	remaining := []models.BuildComponentWithDetails{}
+	for _, part := range parts {
+		if part.Component != nil && part.Component.ID != current.ComponentID {
+			remaining = append(remaining, part)
+		}
+	}
+
+	predicates := BuildSpecPredicates(remaining, category)
+	predicates = append(predicates, models.SpecPredicate{SpecKey: key, Operator: models.SpecOperatorGTE, Value: current.Score})
+
+	// Every candidate is ranked, not only the first page of the catalog listing
+	candidates, err := repository.GetComponentsBySpecPredicates(models.GetComponentsBySpecPredicatesInput{
+		Category:        string(category),
+		Predicates:      predicates,
+		OrderBySpec:     key,
+		OrderDescending: true,
+		Unpaged:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []models.UpgradeSuggestion{}
+	for _, candidate := range ExplainCompatibleComponents(candidates, predicates) {
+		if candidate.Borderline || candidate.ID == current.ComponentID {
+			continue
+		}
+		score, ok := PerformanceScore(candidate.Component, report.UseCase)
+		if !ok || score <= current.Score {
+			continue
+		}
+
+		balance := BalanceScore(score, report.GPU.EffectiveScore)
+		if category == models.CategoryVideoCard {
+			balance = BalanceScore(report.CPU.EffectiveScore, effectiveGPUScore(score, report.UseCase, report.Resolution))
+		}
+		suggestions = append(suggestions, models.UpgradeSuggestion{Component: candidate.Component, Score: score, BalanceScore: balance})
+	}
+
+	RankUpgradeSuggestions(suggestions)
+	if len(suggestions) > constants.MAX_UPGRADE_SUGGESTIONS {
+		suggestions = suggestions[:constants.MAX_UPGRADE_SUGGESTIONS]
+	}
+	return suggestions, nil
+}
+
+// RankUpgradeSuggestions orders suggestions that balance the build by ascending score,
+// then the remaining ones by descending balance
+func RankUpgradeSuggestions(suggestions []models.UpgradeSuggestion) {
+	threshold := constants.BALANCED_RATIO * 100
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		aBalanced, bBalanced := a.BalanceScore >= threshold, b.BalanceScore >= threshold
+		if aBalanced != bBalanced {
+			return aBalanced
+		}
+		if aBalanced {
+			return a.Score < b.Score
+		}
+		return a.BalanceScore > b.BalanceScore
+	})
+}
+
+func findPart(parts []models.BuildComponentWithDetails, category models.Category) *models.Component {
+	for _, part := range parts {
+		if part.Component != nil && part.Component.Category == category {
+			return part.Component
+		}
+	}
+	return nil
+}
+
+func describeWorkload(useCase, resolution string) string {
+	if useCase == constants.USE_CASE_GAMING {
+		return fmt.Sprintf("%s gaming", resolution)
+	}
+	return fmt.Sprintf("%s use", useCase)
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
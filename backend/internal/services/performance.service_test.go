@@ -0,0 +1,123 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyzeBottleneck tests CPU/GPU balance estimation
+func TestAnalyzeBottleneck(t *testing.T) {
+	cpu := storagePart(models.CategoryCPU, "1", "Ryzen 5 3600", `{"socket": "AM4", "performance_gaming": 50, "performance_multi_thread": 40}`, 1)
+	gpu := storagePart(models.CategoryVideoCard, "2", "RTX 4080", `{"performance_gaming": 80, "performance_compute": 85}`, 1)
+
+	t.Run("Slow CPU limits a fast GPU at 1080p", func(t *testing.T) {
+		report := AnalyzeBottleneck([]models.BuildComponentWithDetails{cpu, gpu}, constants.USE_CASE_GAMING, constants.RESOLUTION_1080P)
+
+		assert.Equal(t, LimiterCPU, report.Limiter)
+		assert.Equal(t, 104.0, report.GPU.EffectiveScore)
+		assert.Equal(t, 48.1, report.BalanceScore)
+		assert.Equal(t, 51.9, report.BottleneckPercent)
+		assert.Contains(t, report.Summary, "Ryzen 5 3600 will hold back RTX 4080")
+	})
+
+	t.Run("Higher resolution shifts load to the GPU", func(t *testing.T) {
+		fastCPU := storagePart(models.CategoryCPU, "3", "Ryzen 7 7800X3D", `{"performance_gaming": 95}`, 1)
+		report := AnalyzeBottleneck([]models.BuildComponentWithDetails{fastCPU, gpu}, constants.USE_CASE_GAMING, constants.RESOLUTION_4K)
+
+		assert.Equal(t, LimiterGPU, report.Limiter)
+		assert.Equal(t, 48.0, report.GPU.EffectiveScore)
+	})
+
+	t.Run("Balanced parts", func(t *testing.T) {
+		matched := storagePart(models.CategoryCPU, "4", "Core i7-14700K", `{"performance_gaming": 82}`, 1)
+		report := AnalyzeBottleneck([]models.BuildComponentWithDetails{matched, gpu}, constants.USE_CASE_GAMING, constants.RESOLUTION_1440P)
+
+		assert.Equal(t, LimiterBalanced, report.Limiter)
+		assert.GreaterOrEqual(t, report.BalanceScore, 90.0)
+	})
+
+	t.Run("Workstation compares multi thread and compute scores", func(t *testing.T) {
+		report := AnalyzeBottleneck([]models.BuildComponentWithDetails{cpu, gpu}, constants.USE_CASE_WORKSTATION, constants.RESOLUTION_4K)
+
+		assert.Equal(t, 40.0, report.CPU.Score)
+		assert.Equal(t, 85.0, report.GPU.EffectiveScore)
+		assert.Equal(t, LimiterCPU, report.Limiter)
+	})
+
+	t.Run("Missing video card", func(t *testing.T) {
+		report := AnalyzeBottleneck([]models.BuildComponentWithDetails{cpu}, constants.USE_CASE_GAMING, constants.RESOLUTION_1440P)
+
+		assert.Equal(t, LimiterUnknown, report.Limiter)
+		assert.NotNil(t, report.SuggestedUpgrades)
+	})
+
+	t.Run("Missing scores", func(t *testing.T) {
+		unscored := storagePart(models.CategoryVideoCard, "5", "Mystery GPU", `{}`, 1)
+		report := AnalyzeBottleneck([]models.BuildComponentWithDetails{cpu, unscored}, constants.USE_CASE_GAMING, constants.RESOLUTION_1440P)
+
+		assert.Equal(t, LimiterUnknown, report.Limiter)
+		assert.Nil(t, report.GPU)
+	})
+}
+
+// TestRankUpgradeSuggestions tests ordering of upgrade suggestions
+func TestRankUpgradeSuggestions(t *testing.T) {
+	suggestions := []models.UpgradeSuggestion{
+		{Component: models.Component{ID: "a"}, Score: 70, BalanceScore: 87.5},
+		{Component: models.Component{ID: "b"}, Score: 99, BalanceScore: 92},
+		{Component: models.Component{ID: "c"}, Score: 80, BalanceScore: 100},
+		{Component: models.Component{ID: "d"}, Score: 60, BalanceScore: 75},
+	}
+
+	RankUpgradeSuggestions(suggestions)
+
+	ids := []string{}
+	for _, s := range suggestions {
+		ids = append(ids, s.Component.ID)
+	}
+	assert.Equal(t, []string{"c", "b", "a", "d"}, ids)
+}
+
+// TestFindUpgradeSuggestions tests the catalog search for the limiting part
+func TestFindUpgradeSuggestions(t *testing.T) {
+	loadShippedRules(t)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	originalDB := utils.DB
+	utils.DB = db
+	defer func() { utils.DB = originalDB }()
+
+	cpu := storagePart(models.CategoryCPU, "1", "Ryzen 5 3600", `{"socket": "AM4", "performance_gaming": 50}`, 1)
+	board := storagePart(models.CategoryMotherboard, "2", "B550", `{"socket": "AM4"}`, 1)
+	gpu := storagePart(models.CategoryVideoCard, "3", "RTX 4080", `{"performance_gaming": 80}`, 1)
+	parts := []models.BuildComponentWithDetails{cpu, board, gpu}
+
+	rows := sqlmock.NewRows([]string{"id", "category", "brand", "model", "sku", "upc", "specs", "created_at"}).
+		AddRow("1", "cpu", "AMD", "Ryzen 5 3600", nil, nil, []byte(`{"socket": "AM4", "performance_gaming": 50}`), time.Now()).
+		AddRow("10", "cpu", "AMD", "Ryzen 5 5600X", nil, nil, []byte(`{"socket": "AM4", "performance_gaming": 64}`), time.Now()).
+		AddRow("11", "cpu", "AMD", "Ryzen 7 5800X3D", nil, nil, []byte(`{"socket": "AM4", "performance_gaming": 78}`), time.Now()).
+		AddRow("12", "cpu", "AMD", "Ryzen 7 5700X", nil, nil, []byte(`{"socket": "AM4"}`), time.Now())
+	mock.ExpectQuery("SELECT (.+) FROM components WHERE category = \\$1 AND (.+) ORDER BY (.+)::numeric DESC NULLS LAST, id$").
+		WithArgs("cpu", "socket", "AM4", "performance_gaming", 50.0, "performance_gaming").
+		WillReturnRows(rows)
+
+	report := AnalyzeBottleneck(parts, constants.USE_CASE_GAMING, constants.RESOLUTION_1440P)
+	require.Equal(t, LimiterCPU, report.Limiter)
+
+	suggestions, err := findUpgradeSuggestions(parts, report)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "11", suggestions[0].Component.ID)
+	assert.Equal(t, 97.5, suggestions[0].BalanceScore)
+	assert.Equal(t, "10", suggestions[1].Component.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
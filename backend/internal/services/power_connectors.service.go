@@ -0,0 +1,372 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const (
+	powerRuleNoPSU             = "power-no-psu"
+	powerRuleConnectorsUnknown = "power-connectors-unknown"
+	powerRule12VHPWRAdapter    = "power-12vhpwr-adapter"
+	powerRule12VHPWRMissing    = "power-12vhpwr-insufficient"
+	powerRulePCIeMissing       = "power-pcie-insufficient"
+	powerRuleEPSPartial        = "power-eps-partial"
+	powerRuleEPSMissing        = "power-eps-insufficient"
+	powerRuleSataAdapter       = "power-sata-adapter"
+	powerRuleSataMissing       = "power-sata-insufficient"
+)
+
+// powerDemand is the number of connectors the parts of a build need
+type powerDemand struct {
+	pcie8     int
+	pcie6     int
+	hpwr      int
+	gpuID     string
+	gpuKnown  bool
+	hasGPU    bool
+	eps       int
+	boardID   string
+	epsKnown  bool
+	hasBoard  bool
+	sata      int
+	sataParts []string
+}
+
+func GetBuildPowerConnectors(buildID int64, viewerID string) (models.PowerConnectorReport, error) {
+	utils.Log(constants.SERVICE_GET_POWER_CONNECTORS_START, nil, buildID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_GET_POWER_CONNECTORS_ERROR, err, buildID)
+		return models.PowerConnectorReport{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_POWER_CONNECTORS_ERROR, err, buildID)
+		return models.PowerConnectorReport{}, err
+	}
+
+	report := AnalyzePowerConnectors(parts)
+	report.BuildID = buildID
+
+	utils.Log(constants.SERVICE_GET_POWER_CONNECTORS_SUCCESS, nil, buildID, len(report.Checks), len(report.Issues))
+	return report, nil
+}
+
+// AnalyzePowerConnectors compares the PCIe, 12VHPWR, EPS and SATA power connectors the parts of a build need
+// with the ones the power supply provides. Shortfalls that a safe adapter covers are warnings; the rest are errors.
+func AnalyzePowerConnectors(parts []models.BuildComponentWithDetails) models.PowerConnectorReport {
+	report := models.PowerConnectorReport{
+		Checks: []models.PowerConnectorCheck{},
+		Issues: []models.CompatibilityIssue{},
+	}
+
+	demand := collectPowerDemand(parts)
+	if !demand.hasGPU && !demand.hasBoard && demand.sata == 0 {
+		return report
+	}
+
+	psu := findPart(parts, models.CategoryPowerSupply)
+	if psu == nil {
+		report.Issues = append(report.Issues, powerIssue(powerRuleNoPSU, models.RuleSeverityInfo,
+			"Add a power supply to check power connectors", "", ""))
+		return report
+	}
+
+	var available map[string]int
+	if !decodeSpecInto(utils.ParseSpecs(psu.Specs), constants.SPEC_PSU_CONNECTORS, &available) {
+		report.Issues = append(report.Issues, powerIssue(powerRuleConnectorsUnknown, models.RuleSeverityInfo,
+			fmt.Sprintf("%s does not list its connectors, power cabling could not be checked", psu.Model), psu.ID, ""))
+		return report
+	}
+	available = normalizeConnectorCounts(available)
+
+	if demand.hasGPU {
+		if demand.gpuKnown {
+			spare8 := checkGPUPower(&report, psu, demand, available)
+			if demand.pcie6 > 0 {
+				checkPCIe6Pin(&report, psu, demand, available, spare8)
+			}
+		} else {
+			report.Checks = append(report.Checks, unknownConnectorCheck(constants.CONNECTOR_PCIE_8PIN,
+				"The video card does not list its power connectors"))
+		}
+	}
+
+	if demand.hasBoard {
+		if demand.epsKnown {
+			checkEPSPower(&report, psu, demand, available)
+		} else {
+			report.Checks = append(report.Checks, unknownConnectorCheck(constants.CONNECTOR_EPS_8PIN,
+				"The motherboard does not list its EPS 8-pin headers"))
+		}
+	}
+
+	if demand.sata > 0 {
+		checkSataPower(&report, psu, demand, available)
+	}
+
+	return report
+}
+
+// checkGPUPower checks the 12VHPWR and PCIe 8-pin connectors of the video cards and
+// returns how many PCIe 8-pin connectors remain free afterwards
+func checkGPUPower(report *models.PowerConnectorReport, psu *models.Component, demand powerDemand, available map[string]int) int {
+	spare8 := available[constants.CONNECTOR_PCIE_8PIN] - demand.pcie8
+
+	if demand.pcie8 > 0 {
+		check := models.PowerConnectorCheck{
+			Connector: constants.CONNECTOR_PCIE_8PIN,
+			Required:  demand.pcie8,
+			Available: available[constants.CONNECTOR_PCIE_8PIN],
+			Status:    models.ConnectorStatusOK,
+			Message:   fmt.Sprintf("%s provides enough PCIe 8-pin connectors", psu.Model),
+		}
+		if spare8 < 0 {
+			check.Status = models.ConnectorStatusInsufficient
+			check.Message = fmt.Sprintf("The video card needs %d PCIe 8-pin connectors but %s provides %d",
+				demand.pcie8, psu.Model, check.Available)
+			check.Adapters = []models.AdapterSuggestion{{
+				Description: "6-pin to 8-pin or Molex to PCIe adapters overload connectors rated for less power; use a power supply with more PCIe 8-pin cables",
+				Quantity:    -spare8,
+				Safe:        false,
+			}}
+			report.Issues = append(report.Issues, powerIssue(powerRulePCIeMissing, models.RuleSeverityError, check.Message, psu.ID, demand.gpuID))
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	if spare8 < 0 {
+		spare8 = 0
+	}
+
+	if demand.hpwr > 0 {
+		check := models.PowerConnectorCheck{
+			Connector: constants.CONNECTOR_12VHPWR,
+			Required:  demand.hpwr,
+			Available: available[constants.CONNECTOR_12VHPWR],
+			Status:    models.ConnectorStatusOK,
+			Message:   fmt.Sprintf("%s provides a native 12VHPWR cable", psu.Model),
+		}
+		missing := demand.hpwr - check.Available
+		if missing > 0 {
+			needed := missing * constants.PCIE_8PIN_PER_12VHPWR
+			if spare8 >= needed {
+				check.Status = models.ConnectorStatusAdapter
+				check.Message = fmt.Sprintf("%s has no free 12VHPWR cable; the video card's adapter can be fed from %d PCIe 8-pin cables",
+					psu.Model, needed)
+				check.Adapters = []models.AdapterSuggestion{{
+					Description: fmt.Sprintf("12VHPWR to %dx PCIe 8-pin adapter, one separate cable per 8-pin plug", constants.PCIE_8PIN_PER_12VHPWR),
+					Quantity:    missing,
+					Safe:        true,
+				}}
+				spare8 -= needed
+				report.Issues = append(report.Issues, powerIssue(powerRule12VHPWRAdapter, models.RuleSeverityWarning, check.Message, psu.ID, demand.gpuID))
+			} else {
+				check.Status = models.ConnectorStatusInsufficient
+				check.Message = fmt.Sprintf("The video card needs a 12VHPWR cable; %s has none free and only %d spare PCIe 8-pin connectors for an adapter needing %d",
+					psu.Model, spare8, needed)
+				check.Adapters = []models.AdapterSuggestion{{
+					Description: "Partially populated or daisy-chained 12VHPWR adapters limit the card's power and can overheat",
+					Quantity:    missing,
+					Safe:        false,
+				}}
+				spare8 = 0
+				report.Issues = append(report.Issues, powerIssue(powerRule12VHPWRMissing, models.RuleSeverityError, check.Message, psu.ID, demand.gpuID))
+			}
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	return spare8
+}
+
+// checkPCIe6Pin checks 6-pin connectors, which native 6-pin cables and spare 6+2 pin connectors can feed
+func checkPCIe6Pin(report *models.PowerConnectorReport, psu *models.Component, demand powerDemand, available map[string]int, spare8 int) {
+	check := models.PowerConnectorCheck{
+		Connector: constants.CONNECTOR_PCIE_6PIN,
+		Required:  demand.pcie6,
+		Available: available[constants.CONNECTOR_PCIE_6PIN] + spare8,
+		Status:    models.ConnectorStatusOK,
+		Message:   fmt.Sprintf("%s provides enough PCIe 6-pin or 6+2 pin connectors", psu.Model),
+	}
+	if check.Available < check.Required {
+		check.Status = models.ConnectorStatusInsufficient
+		check.Message = fmt.Sprintf("The video card needs %d PCIe 6-pin connectors but %s has %d free",
+			demand.pcie6, psu.Model, check.Available)
+		check.Adapters = []models.AdapterSuggestion{{
+			Description: "Molex to PCIe 6-pin adapters draw more current than Molex connectors are rated for",
+			Quantity:    check.Required - check.Available,
+			Safe:        false,
+		}}
+		report.Issues = append(report.Issues, powerIssue(powerRulePCIeMissing, models.RuleSeverityError, check.Message, psu.ID, demand.gpuID))
+	}
+	report.Checks = append(report.Checks, check)
+}
+
+// checkEPSPower checks the CPU power headers. The first EPS header is required; the others only
+// matter under heavy load, so a power supply covering part of them gets a warning.
+func checkEPSPower(report *models.PowerConnectorReport, psu *models.Component, demand powerDemand, available map[string]int) {
+	check := models.PowerConnectorCheck{
+		Connector: constants.CONNECTOR_EPS_8PIN,
+		Required:  demand.eps,
+		Available: available[constants.CONNECTOR_EPS_8PIN],
+		Status:    models.ConnectorStatusOK,
+		Message:   fmt.Sprintf("%s provides a cable for every EPS 8-pin header", psu.Model),
+	}
+	switch {
+	case check.Available >= check.Required:
+	case check.Available > 0:
+		check.Status = models.ConnectorStatusInsufficient
+		check.Message = fmt.Sprintf("The motherboard has %d EPS 8-pin headers but %s provides %d; the CPU will run, but heavy overclocking needs every header connected",
+			check.Required, psu.Model, check.Available)
+		report.Issues = append(report.Issues, powerIssue(powerRuleEPSPartial, models.RuleSeverityWarning, check.Message, psu.ID, demand.boardID))
+	default:
+		check.Status = models.ConnectorStatusInsufficient
+		check.Message = fmt.Sprintf("%s has no EPS 8-pin cable to power the CPU", psu.Model)
+		check.Adapters = []models.AdapterSuggestion{{
+			Description: "PCIe 8-pin to EPS 8-pin adapters swap 12V and ground pins between the two standards",
+			Quantity:    1,
+			Safe:        false,
+		}}
+		report.Issues = append(report.Issues, powerIssue(powerRuleEPSMissing, models.RuleSeverityError, check.Message, psu.ID, demand.boardID))
+	}
+	report.Checks = append(report.Checks, check)
+}
+
+// checkSataPower checks SATA power for drives. Crimped Molex to SATA adapters are used first,
+// then splitters feeding up to SATA_DEVICES_PER_SPLIT drives from one connector.
+func checkSataPower(report *models.PowerConnectorReport, psu *models.Component, demand powerDemand, available map[string]int) {
+	check := models.PowerConnectorCheck{
+		Connector: constants.CONNECTOR_SATA,
+		Required:  demand.sata,
+		Available: available[constants.CONNECTOR_SATA],
+		Status:    models.ConnectorStatusOK,
+		Message:   fmt.Sprintf("%s provides enough SATA power connectors", psu.Model),
+	}
+	target := strings.Join(demand.sataParts, ",")
+
+	missing := check.Required - check.Available
+	if missing <= 0 {
+		report.Checks = append(report.Checks, check)
+		return
+	}
+
+	molex := available[constants.CONNECTOR_MOLEX]
+	if molex > missing {
+		molex = missing
+	}
+	splits := missing - molex
+	if molex > 0 {
+		check.Adapters = append(check.Adapters, models.AdapterSuggestion{
+			Description: "Crimped Molex to SATA power adapter, one drive per Molex connector (avoid moulded adapters)",
+			Quantity:    molex,
+			Safe:        true,
+		})
+	}
+
+	if splits <= check.Available*(constants.SATA_DEVICES_PER_SPLIT-1) {
+		if splits > 0 {
+			check.Adapters = append(check.Adapters, models.AdapterSuggestion{
+				Description: fmt.Sprintf("SATA power splitter, at most %d drives per connector", constants.SATA_DEVICES_PER_SPLIT),
+				Quantity:    splits,
+				Safe:        true,
+			})
+		}
+		check.Status = models.ConnectorStatusAdapter
+		check.Message = fmt.Sprintf("The build has %d drives needing SATA power but %s provides %d connectors; adapters cover the rest",
+			check.Required, psu.Model, check.Available)
+		report.Issues = append(report.Issues, powerIssue(powerRuleSataAdapter, models.RuleSeverityWarning, check.Message, psu.ID, target))
+	} else {
+		check.Adapters = append(check.Adapters, models.AdapterSuggestion{
+			Description: fmt.Sprintf("Splitting one SATA power connector across more than %d drives exceeds its current rating", constants.SATA_DEVICES_PER_SPLIT),
+			Quantity:    splits,
+			Safe:        false,
+		})
+		check.Status = models.ConnectorStatusInsufficient
+		check.Message = fmt.Sprintf("The build has %d drives needing SATA power but %s provides %d connectors, too few to cover safely with adapters",
+			check.Required, psu.Model, check.Available)
+		report.Issues = append(report.Issues, powerIssue(powerRuleSataMissing, models.RuleSeverityError, check.Message, psu.ID, target))
+	}
+	report.Checks = append(report.Checks, check)
+}
+
+func collectPowerDemand(parts []models.BuildComponentWithDetails) powerDemand {
+	demand := powerDemand{gpuKnown: true}
+
+	for _, part := range parts {
+		if part.Component == nil {
+			continue
+		}
+		component := part.Component
+		specs := utils.ParseSpecs(component.Specs)
+		quantity := part.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+
+		switch component.Category {
+		case models.CategoryVideoCard:
+			if !demand.hasGPU {
+				demand.gpuID = component.ID
+			}
+			demand.hasGPU = true
+			var connectors map[string]int
+			if !decodeSpecInto(specs, constants.SPEC_POWER_CONNECTORS, &connectors) {
+				demand.gpuKnown = false
+				continue
+			}
+			connectors = normalizeConnectorCounts(connectors)
+			demand.pcie8 += connectors[constants.CONNECTOR_PCIE_8PIN] * quantity
+			demand.pcie6 += connectors[constants.CONNECTOR_PCIE_6PIN] * quantity
+			demand.hpwr += connectors[constants.CONNECTOR_12VHPWR] * quantity
+		case models.CategoryMotherboard:
+			if demand.hasBoard {
+				continue
+			}
+			demand.hasBoard = true
+			demand.boardID = component.ID
+			if headers, ok := utils.GetSpecNumber(specs, constants.SPEC_EPS_8PIN_HEADERS); ok {
+				demand.eps = int(headers)
+				demand.epsKnown = true
+			}
+		}
+	}
+
+	for _, device := range collectStorageDevices(parts) {
+		if device.slotType == constants.STORAGE_SLOT_SATA {
+			demand.sata += device.units
+			if !containsFold(demand.sataParts, device.allocation.ComponentID) {
+				demand.sataParts = append(demand.sataParts, device.allocation.ComponentID)
+			}
+		}
+	}
+
+	return demand
+}
+
+// normalizeConnectorCounts lowercases connector names and treats 12V-2x6 as 12VHPWR
+func normalizeConnectorCounts(counts map[string]int) map[string]int {
+	normalized := map[string]int{}
+	for name, count := range counts {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "12v-2x6" || name == "12v_2x6" {
+			name = constants.CONNECTOR_12VHPWR
+		}
+		normalized[name] += count
+	}
+	return normalized
+}
+
+func unknownConnectorCheck(connector, message string) models.PowerConnectorCheck {
+	return models.PowerConnectorCheck{Connector: connector, Status: models.ConnectorStatusUnknown, Message: message}
+}
+
+func powerIssue(ruleID string, severity models.RuleSeverity, message, sourceID, targetID string) models.CompatibilityIssue {
+	return models.CompatibilityIssue{RuleID: ruleID, Severity: severity, Message: message, SourceComponentID: sourceID, TargetComponentID: targetID}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findConnectorCheck(t *testing.T, report models.PowerConnectorReport, connector string) models.PowerConnectorCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Connector == connector {
+			return check
+		}
+	}
+	require.Failf(t, "missing check", "no check for connector %s", connector)
+	return models.PowerConnectorCheck{}
+}
+
+// TestAnalyzePowerConnectors tests connector checks between the power supply and the parts it feeds
+func TestAnalyzePowerConnectors(t *testing.T) {
+	psu := func(connectors string) models.BuildComponentWithDetails {
+		return storagePart(models.CategoryPowerSupply, "1", "RM850x", `{"connectors": `+connectors+`}`, 1)
+	}
+	board := storagePart(models.CategoryMotherboard, "2", "X670E", `{"eps_8pin_headers": 2}`, 1)
+	gpu8 := storagePart(models.CategoryVideoCard, "3", "RX 7900 XTX", `{"power_connectors": {"pcie_8pin": 3}}`, 1)
+	gpuHPWR := storagePart(models.CategoryVideoCard, "4", "RTX 4090", `{"power_connectors": {"12VHPWR": 1}}`, 1)
+	hdd := func(quantity int) models.BuildComponentWithDetails {
+		return storagePart(models.CategoryInternalHDD, "5", "IronWolf 8TB", `{"interface": "SATA 6.0 Gb/s", "form_factor": "3.5"}`, quantity)
+	}
+
+	t.Run("Everything connected natively", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{
+			psu(`{"pcie_8pin": 4, "12v-2x6": 1, "eps_8pin": 2, "sata": 8}`), board, gpuHPWR, hdd(2),
+		})
+
+		assert.Empty(t, report.Issues)
+		assert.Equal(t, models.ConnectorStatusOK, findConnectorCheck(t, report, "12vhpwr").Status)
+		assert.Equal(t, models.ConnectorStatusOK, findConnectorCheck(t, report, "eps_8pin").Status)
+		assert.Equal(t, models.ConnectorStatusOK, findConnectorCheck(t, report, "sata").Status)
+	})
+
+	t.Run("12VHPWR through an adapter", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{psu(`{"pcie_8pin": 4, "eps_8pin": 2}`), gpuHPWR})
+
+		check := findConnectorCheck(t, report, "12vhpwr")
+		assert.Equal(t, models.ConnectorStatusAdapter, check.Status)
+		require.Len(t, check.Adapters, 1)
+		assert.True(t, check.Adapters[0].Safe)
+		assert.Equal(t, []string{powerRule12VHPWRAdapter}, issueIDs(report.Issues))
+	})
+
+	t.Run("12VHPWR adapter without enough 8-pin cables", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{psu(`{"pcie_8pin": 2}`), gpuHPWR})
+
+		check := findConnectorCheck(t, report, "12vhpwr")
+		assert.Equal(t, models.ConnectorStatusInsufficient, check.Status)
+		assert.False(t, check.Adapters[0].Safe)
+		assert.Equal(t, []string{powerRule12VHPWRMissing}, issueIDs(report.Issues))
+	})
+
+	t.Run("Too few PCIe 8-pin connectors", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{psu(`{"pcie_8pin": 2, "pcie_6pin": 2}`), gpu8})
+
+		check := findConnectorCheck(t, report, "pcie_8pin")
+		assert.Equal(t, 3, check.Required)
+		assert.Equal(t, models.ConnectorStatusInsufficient, check.Status)
+		assert.Equal(t, []string{powerRulePCIeMissing}, issueIDs(report.Issues))
+	})
+
+	t.Run("EPS headers partially covered", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{psu(`{"eps_8pin": 1}`), board})
+
+		assert.Equal(t, []string{powerRuleEPSPartial}, issueIDs(report.Issues))
+		assert.Equal(t, models.RuleSeverityWarning, report.Issues[0].Severity)
+	})
+
+	t.Run("No EPS cable", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{psu(`{"pcie_8pin": 2}`), board})
+
+		assert.Equal(t, []string{powerRuleEPSMissing}, issueIDs(report.Issues))
+		assert.False(t, findConnectorCheck(t, report, "eps_8pin").Adapters[0].Safe)
+	})
+
+	t.Run("SATA shortfall covered by Molex and splitters", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{psu(`{"sata": 2, "molex": 1}`), hdd(4)})
+
+		check := findConnectorCheck(t, report, "sata")
+		assert.Equal(t, models.ConnectorStatusAdapter, check.Status)
+		require.Len(t, check.Adapters, 2)
+		assert.Equal(t, 1, check.Adapters[0].Quantity)
+		assert.Equal(t, 1, check.Adapters[1].Quantity)
+		assert.Equal(t, []string{powerRuleSataAdapter}, issueIDs(report.Issues))
+	})
+
+	t.Run("SATA shortfall too large for adapters", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{psu(`{"sata": 1}`), hdd(4)})
+
+		assert.Equal(t, models.ConnectorStatusInsufficient, findConnectorCheck(t, report, "sata").Status)
+		assert.Equal(t, []string{powerRuleSataMissing}, issueIDs(report.Issues))
+	})
+
+	t.Run("Unknown connector specs", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{
+			storagePart(models.CategoryPowerSupply, "1", "Generic 500W", `{}`, 1), gpu8,
+		})
+
+		assert.Empty(t, report.Checks)
+		assert.Equal(t, []string{powerRuleConnectorsUnknown}, issueIDs(report.Issues))
+	})
+
+	t.Run("No power supply", func(t *testing.T) {
+		report := AnalyzePowerConnectors([]models.BuildComponentWithDetails{gpu8})
+		assert.Equal(t, []string{powerRuleNoPSU}, issueIDs(report.Issues))
+
+		report = AnalyzePowerConnectors([]models.BuildComponentWithDetails{})
+		assert.Empty(t, report.Issues)
+	})
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mateuse/desktop-builder-backend/internal/constants"
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/mateuse/desktop-builder-backend/internal/repository"
+	"github.com/mateuse/desktop-builder-backend/internal/utils"
+)
+
+const (
+	storageRuleNoMotherboard      = "storage-no-motherboard"
+	storageRuleSlotsUnknown       = "storage-slots-unknown"
+	storageRuleM2Oversubscribed   = "storage-m2-oversubscribed"
+	storageRuleSataOversubscribed = "storage-sata-oversubscribed"
+	storageRuleSataDisabled       = "storage-sata-disabled-by-m2"
+	storageRulePCIeOversubscribed = "storage-pcie-oversubscribed"
+	storageRuleGPULanesShared     = "storage-gpu-lanes-shared"
+)
+
+// storageDevice is a storage part waiting for a slot for each of its units
+type storageDevice struct {
+	allocation models.StorageAllocation
+	slotType   string
+	units      int
+}
+
+// storageBoard is the slot layout read from a motherboard's specs
+type storageBoard struct {
+	name      string
+	m2Slots   []models.M2Slot
+	pcieSlots []models.PCIeSlot
+	sataPorts int
+	gpuSlot   string
+	known     bool
+}
+
+func GetBuildStorageAllocation(buildID int64, viewerID string) (models.StorageAllocationReport, error) {
+	utils.Log(constants.SERVICE_GET_STORAGE_ALLOCATION_START, nil, buildID)
+
+	if _, err := getVisibleBuild(buildID, viewerID); err != nil {
+		utils.Log(constants.SERVICE_GET_STORAGE_ALLOCATION_ERROR, err, buildID)
+		return models.StorageAllocationReport{}, err
+	}
+
+	parts, err := repository.GetBuildParts(buildID)
+	if err != nil {
+		utils.Log(constants.SERVICE_GET_STORAGE_ALLOCATION_ERROR, err, buildID)
+		return models.StorageAllocationReport{}, err
+	}
+
+	report := AllocateStorage(parts)
+	report.BuildID = buildID
+
+	utils.Log(constants.SERVICE_GET_STORAGE_ALLOCATION_SUCCESS, nil, buildID, len(report.Allocation), len(report.Unallocated))
+	return report, nil
+}
+
+// AllocateStorage assigns every storage device in a build to an M.2 slot, a SATA port or a PCIe slot.
+// M.2 drives are placed first, preferring slots that neither disable SATA ports still needed nor share
+// lanes with another slot, then add-in cards, then SATA devices on the ports that remain enabled.
+func AllocateStorage(parts []models.BuildComponentWithDetails) models.StorageAllocationReport {
+	report := models.StorageAllocationReport{
+		Allocation:        map[string]models.StorageAllocation{},
+		Unallocated:       []models.StorageAllocation{},
+		SataPortsDisabled: []string{},
+		Issues:            []models.CompatibilityIssue{},
+	}
+
+	devices := collectStorageDevices(parts)
+	if len(devices) == 0 {
+		return report
+	}
+
+	board, hasBoard := findStorageBoard(parts)
+	if !hasBoard {
+		report.Unallocated = allocationsOf(devices)
+		report.Issues = append(report.Issues, storageIssue(storageRuleNoMotherboard, models.RuleSeverityInfo,
+			"Add a motherboard to allocate storage devices", ""))
+		return report
+	}
+	if !board.known {
+		report.Unallocated = allocationsOf(devices)
+		report.Issues = append(report.Issues, storageIssue(storageRuleSlotsUnknown, models.RuleSeverityInfo,
+			fmt.Sprintf("%s does not list its M.2 slots or SATA ports, storage could not be checked", board.name), ""))
+		return report
+	}
+	report.SataPortsTotal = board.sataPorts
+
+	hasGPU := false
+	for _, part := range parts {
+		if part.Component != nil && part.Component.Category == models.CategoryVideoCard {
+			hasGPU = true
+		}
+	}
+
+	sataDemand := 0
+	for _, device := range devices {
+		if device.slotType == constants.STORAGE_SLOT_SATA {
+			sataDemand += device.units
+		}
+	}
+
+	usedSlots := map[string]bool{}
+	disabledPorts := map[int]string{}
+
+	// M.2 drives
+	for _, device := range devices {
+		if device.slotType != constants.STORAGE_SLOT_M2 {
+			continue
+		}
+		for _, unit := range device.unitAllocations() {
+			slot, ok := pickM2Slot(board, unit.Interface, usedSlots, disabledPorts, sataDemand, hasGPU)
+			if !ok {
+				report.Unallocated = append(report.Unallocated, unit)
+				report.Issues = append(report.Issues, storageIssue(storageRuleM2Oversubscribed, models.RuleSeverityError,
+					fmt.Sprintf("%s %s needs an M.2 slot supporting %s but every suitable slot on %s is in use",
+						unit.Brand, unit.Model, strings.ToUpper(unit.Interface), board.name),
+					unit.ComponentID))
+				continue
+			}
+
+			usedSlots[slot.Name] = true
+			for _, port := range slot.DisablesSataPorts {
+				disabledPorts[port] = slot.Name
+			}
+			if slot.SharesLanesWith != "" && slot.SharesLanesWith == board.gpuSlot && hasGPU {
+				report.GPUSlotLanes = reducedGPULanes(board)
+				report.Issues = append(report.Issues, storageIssue(storageRuleGPULanesShared, models.RuleSeverityWarning,
+					fmt.Sprintf("Using %s shares lanes with %s, so the video card runs at x%d", slot.Name, board.gpuSlot, report.GPUSlotLanes),
+					unit.ComponentID))
+			}
+
+			unit.Slot = slot.Name
+			report.Allocation[slot.Name] = unit
+		}
+	}
+
+	// Add-in cards
+	for _, device := range devices {
+		if device.slotType != constants.STORAGE_SLOT_PCIE {
+			continue
+		}
+		for _, unit := range device.unitAllocations() {
+			slot, ok := pickPCIeSlot(board, usedSlots, hasGPU)
+			if !ok {
+				report.Unallocated = append(report.Unallocated, unit)
+				report.Issues = append(report.Issues, storageIssue(storageRulePCIeOversubscribed, models.RuleSeverityError,
+					fmt.Sprintf("%s %s needs a free PCIe slot but none is left on %s", unit.Brand, unit.Model, board.name),
+					unit.ComponentID))
+				continue
+			}
+			usedSlots[slot.Name] = true
+			unit.Slot = slot.Name
+			report.Allocation[slot.Name] = unit
+		}
+	}
+
+	// SATA devices
+	ports := []string{}
+	for port := 1; port <= board.sataPorts; port++ {
+		if _, disabled := disabledPorts[port]; disabled {
+			report.SataPortsDisabled = append(report.SataPortsDisabled, fmt.Sprintf(constants.SATA_PORT_NAME_BASE, port))
+			continue
+		}
+		ports = append(ports, fmt.Sprintf(constants.SATA_PORT_NAME_BASE, port))
+	}
+
+	next := 0
+	for _, device := range devices {
+		if device.slotType != constants.STORAGE_SLOT_SATA {
+			continue
+		}
+		for _, unit := range device.unitAllocations() {
+			if next >= len(ports) {
+				report.Unallocated = append(report.Unallocated, unit)
+				if next < board.sataPorts {
+					report.Issues = append(report.Issues, storageIssue(storageRuleSataDisabled, models.RuleSeverityError,
+						fmt.Sprintf("%s %s has no SATA port left because %s disabled by the M.2 slots in use",
+							unit.Brand, unit.Model, describeDisabledPorts(disabledPorts)),
+						unit.ComponentID))
+				} else {
+					report.Issues = append(report.Issues, storageIssue(storageRuleSataOversubscribed, models.RuleSeverityError,
+						fmt.Sprintf("%s %s needs a SATA port but all %d ports on %s are in use",
+							unit.Brand, unit.Model, board.sataPorts, board.name),
+						unit.ComponentID))
+				}
+				next++
+				continue
+			}
+			unit.Slot = ports[next]
+			report.Allocation[ports[next]] = unit
+			next++
+		}
+	}
+
+	return report
+}
+
+// collectStorageDevices lists the storage parts of a build with their quantity, classifying each by the slot it needs
+func collectStorageDevices(parts []models.BuildComponentWithDetails) []storageDevice {
+	devices := []storageDevice{}
+
+	for _, part := range parts {
+		if part.Component == nil {
+			continue
+		}
+		component := part.Component
+		if component.Category != models.CategoryInternalHDD && component.Category != models.CategoryOpticalDrive {
+			continue
+		}
+
+		specs := utils.ParseSpecs(component.Specs)
+		iface, _ := utils.GetSpecString(specs, constants.SPEC_INTERFACE)
+		iface = strings.ToLower(iface)
+		form, _ := utils.GetSpecString(specs, constants.SPEC_FORM_FACTOR)
+		form = strings.ToLower(form)
+
+		slotType := constants.STORAGE_SLOT_SATA
+		storageInterface := constants.STORAGE_SATA
+		switch {
+		case component.Category == models.CategoryOpticalDrive:
+		case strings.Contains(form, constants.STORAGE_FORM_M2) || strings.Contains(form, "m2"):
+			slotType = constants.STORAGE_SLOT_M2
+			if !strings.Contains(iface, constants.STORAGE_SATA) {
+				storageInterface = constants.STORAGE_NVME
+			}
+		case strings.Contains(form, "add-in") || strings.Contains(form, "aic") || strings.Contains(form, "pcie"):
+			slotType = constants.STORAGE_SLOT_PCIE
+			storageInterface = constants.STORAGE_NVME
+		}
+
+		devices = append(devices, storageDevice{
+			slotType: slotType,
+			units:    max(part.Quantity, 1),
+			allocation: models.StorageAllocation{
+				ComponentID: component.ID,
+				Brand:       component.Brand,
+				Model:       component.Model,
+				Interface:   storageInterface,
+				SlotType:    slotType,
+			},
+		})
+	}
+
+	// NVMe drives claim M.2 slots before M.2 SATA drives, which can only use the slots supporting SATA
+	sort.SliceStable(devices, func(i, j int) bool {
+		return devices[i].allocation.Interface == constants.STORAGE_NVME && devices[j].allocation.Interface != constants.STORAGE_NVME
+	})
+	return devices
+}
+
+func findStorageBoard(parts []models.BuildComponentWithDetails) (storageBoard, bool) {
+	for _, part := range parts {
+		if part.Component == nil || part.Component.Category != models.CategoryMotherboard {
+			continue
+		}
+		specs := utils.ParseSpecs(part.Component.Specs)
+		board := storageBoard{name: strings.TrimSpace(part.Component.Brand + " " + part.Component.Model)}
+
+		_, hasM2 := utils.GetSpecValue(specs, constants.SPEC_M2_SLOTS)
+		sata, hasSata := utils.GetSpecNumber(specs, constants.SPEC_SATA_PORTS)
+		board.known = hasM2 || hasSata
+		board.sataPorts = int(sata)
+
+		decodeSpecInto(specs, constants.SPEC_M2_SLOTS, &board.m2Slots)
+		decodeSpecInto(specs, constants.SPEC_PCIE_SLOTS, &board.pcieSlots)
+		for i := range board.m2Slots {
+			if board.m2Slots[i].Name == "" {
+				board.m2Slots[i].Name = fmt.Sprintf("M2_%d", i+1)
+			}
+			if len(board.m2Slots[i].Interfaces) == 0 {
+				board.m2Slots[i].Interfaces = []string{constants.STORAGE_NVME}
+			}
+		}
+
+		board.gpuSlot = constants.DEFAULT_GPU_SLOT
+		if gpuSlot, ok := utils.GetSpecString(specs, constants.SPEC_GPU_SLOT); ok {
+			board.gpuSlot = gpuSlot
+		} else if len(board.pcieSlots) > 0 {
+			board.gpuSlot = board.pcieSlots[0].Name
+		}
+		return board, true
+	}
+	return storageBoard{}, false
+}
+
+// pickM2Slot returns the free slot supporting iface with the fewest side effects
+func pickM2Slot(board storageBoard, iface string, used map[string]bool, disabled map[int]string, sataDemand int, hasGPU bool) (models.M2Slot, bool) {
+	best := -1
+	bestCost := 0
+	for i, slot := range board.m2Slots {
+		if used[slot.Name] || !containsFold(slot.Interfaces, iface) {
+			continue
+		}
+		if slot.SharesLanesWith != "" && slot.SharesLanesWith != board.gpuSlot && used[slot.SharesLanesWith] {
+			continue
+		}
+
+		cost := 0
+		if slot.SharesLanesWith != "" {
+			cost += 2
+			if slot.SharesLanesWith == board.gpuSlot && hasGPU {
+				cost += 1
+			}
+		}
+		if len(slot.DisablesSataPorts) > 0 {
+			newlyDisabled := 0
+			for _, port := range slot.DisablesSataPorts {
+				if _, already := disabled[port]; !already && port <= board.sataPorts {
+					newlyDisabled++
+				}
+			}
+			if board.sataPorts-len(disabled)-newlyDisabled < sataDemand {
+				cost += 10
+			} else {
+				cost += 1
+			}
+		}
+
+		if best == -1 || cost < bestCost {
+			best, bestCost = i, cost
+		}
+	}
+	if best == -1 {
+		return models.M2Slot{}, false
+	}
+	return board.m2Slots[best], true
+}
+
+// pickPCIeSlot returns a free PCIe slot for an add-in card, leaving the GPU slot to the video card
+func pickPCIeSlot(board storageBoard, used map[string]bool, hasGPU bool) (models.PCIeSlot, bool) {
+	blocked := map[string]bool{}
+	for _, slot := range board.m2Slots {
+		if used[slot.Name] && slot.SharesLanesWith != "" && slot.SharesLanesWith != board.gpuSlot {
+			blocked[slot.SharesLanesWith] = true
+		}
+	}
+	for _, slot := range board.pcieSlots {
+		if used[slot.Name] || blocked[slot.Name] || (hasGPU && slot.Name == board.gpuSlot) || slot.Lanes < 4 {
+			continue
+		}
+		return slot, true
+	}
+	return models.PCIeSlot{}, false
+}
+
+func reducedGPULanes(board storageBoard) int {
+	for _, slot := range board.pcieSlots {
+		if slot.Name == board.gpuSlot && slot.Lanes > 0 {
+			return slot.Lanes / 2
+		}
+	}
+	return 8
+}
+
+func describeDisabledPorts(disabled map[int]string) string {
+	ports := make([]int, 0, len(disabled))
+	for port := range disabled {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+
+	names := make([]string, len(ports))
+	for i, port := range ports {
+		names[i] = fmt.Sprintf(constants.SATA_PORT_NAME_BASE+" (%s)", port, disabled[port])
+	}
+	if len(names) == 1 {
+		return names[0] + " is"
+	}
+	return strings.Join(names, ", ") + " are"
+}
+
+func allocationsOf(devices []storageDevice) []models.StorageAllocation {
+	allocations := []models.StorageAllocation{}
+	for _, device := range devices {
+		allocations = append(allocations, device.unitAllocations()...)
+	}
+	return allocations
+}
+
+// unitAllocations lists one allocation per unit of a device; part quantities are capped at MAX_PART_QUANTITY when
+// they are stored, and never more than that many units are listed
+func (device storageDevice) unitAllocations() []models.StorageAllocation {
+	units := min(device.units, constants.MAX_PART_QUANTITY)
+	allocations := make([]models.StorageAllocation, units)
+	for i := range allocations {
+		allocations[i] = device.allocation
+		allocations[i].Unit = i + 1
+	}
+	return allocations
+}
+
+func storageIssue(ruleID string, severity models.RuleSeverity, message, componentID string) models.CompatibilityIssue {
+	return models.CompatibilityIssue{RuleID: ruleID, Severity: severity, Message: message, TargetComponentID: componentID}
+}
+
+// decodeSpecInto re-decodes a structured spec value (a list of objects) into out
+func decodeSpecInto(specs map[string]interface{}, key string, out interface{}) bool {
+	value, ok := utils.GetSpecValue(specs, key)
+	if !ok {
+		return false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
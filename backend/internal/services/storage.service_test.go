@@ -0,0 +1,126 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mateuse/desktop-builder-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBoardSpecs = `{
+	"sata_ports": 6,
+	"m2_slots": [
+		{"name": "M2_1", "interfaces": ["nvme"]},
+		{"name": "M2_2", "interfaces": ["nvme", "sata"], "disables_sata_ports": [5, 6]},
+		{"name": "M2_3", "interfaces": ["nvme"], "shares_lanes_with": "PCIEX16_1"}
+	],
+	"pcie_slots": [
+		{"name": "PCIEX16_1", "lanes": 16},
+		{"name": "PCIEX16_2", "lanes": 4}
+	]
+}`
+
+func storagePart(category models.Category, id, model, specs string, quantity int) models.BuildComponentWithDetails {
+	part := buildPart(category, model, specs)
+	part.Quantity = quantity
+	part.Component.ID = id
+	return part
+}
+
+func issueIDs(issues []models.CompatibilityIssue) []string {
+	ids := []string{}
+	for _, issue := range issues {
+		ids = append(ids, issue.RuleID)
+	}
+	return ids
+}
+
+// TestAllocateStorage tests slot allocation for storage devices
+func TestAllocateStorage(t *testing.T) {
+	board := storagePart(models.CategoryMotherboard, "10", "X670E", testBoardSpecs, 1)
+	nvme := func(quantity int) models.BuildComponentWithDetails {
+		return storagePart(models.CategoryInternalHDD, "20", "990 Pro", `{"interface": "PCIe 4.0 x4 NVMe", "form_factor": "M.2-2280"}`, quantity)
+	}
+	sata := func(quantity int) models.BuildComponentWithDetails {
+		return storagePart(models.CategoryInternalHDD, "30", "IronWolf 8TB", `{"interface": "SATA 6.0 Gb/s", "form_factor": "3.5"}`, quantity)
+	}
+	gpu := storagePart(models.CategoryVideoCard, "40", "RTX 4080", `{}`, 1)
+
+	t.Run("More NVMe drives than M.2 slots", func(t *testing.T) {
+		report := AllocateStorage([]models.BuildComponentWithDetails{board, nvme(4)})
+
+		assert.Len(t, report.Allocation, 3)
+		assert.Len(t, report.Unallocated, 1)
+		assert.Contains(t, issueIDs(report.Issues), storageRuleM2Oversubscribed)
+	})
+
+	t.Run("Avoids disabling SATA ports that are needed", func(t *testing.T) {
+		report := AllocateStorage([]models.BuildComponentWithDetails{board, nvme(2), sata(6), gpu})
+
+		assert.Contains(t, report.Allocation, "M2_1")
+		assert.Contains(t, report.Allocation, "M2_3")
+		assert.NotContains(t, report.Allocation, "M2_2")
+		assert.Contains(t, report.Allocation, "SATA_6")
+		assert.Empty(t, report.Unallocated)
+		assert.Equal(t, 8, report.GPUSlotLanes)
+		assert.Equal(t, []string{storageRuleGPULanesShared}, issueIDs(report.Issues))
+	})
+
+	t.Run("Disabled SATA ports cause a conflict when every M.2 slot is needed", func(t *testing.T) {
+		report := AllocateStorage([]models.BuildComponentWithDetails{board, nvme(3), sata(6)})
+
+		assert.Len(t, report.Allocation, 7)
+		assert.Len(t, report.Unallocated, 2)
+		assert.Equal(t, []string{"SATA_5", "SATA_6"}, report.SataPortsDisabled)
+		assert.Equal(t, []string{storageRuleSataDisabled, storageRuleSataDisabled}, issueIDs(report.Issues))
+		assert.Contains(t, report.Issues[0].Message, "SATA_5 (M2_2), SATA_6 (M2_2) are disabled")
+	})
+
+	t.Run("More SATA devices than ports", func(t *testing.T) {
+		report := AllocateStorage([]models.BuildComponentWithDetails{board, sata(6),
+			storagePart(models.CategoryOpticalDrive, "50", "BD Writer", `{}`, 1)})
+
+		assert.Len(t, report.Unallocated, 1)
+		assert.Equal(t, []string{storageRuleSataOversubscribed}, issueIDs(report.Issues))
+	})
+
+	t.Run("M.2 SATA drives need a slot supporting SATA", func(t *testing.T) {
+		m2Sata := storagePart(models.CategoryInternalHDD, "60", "860 EVO M.2", `{"interface": "SATA", "form_factor": "M.2-2280"}`, 2)
+		report := AllocateStorage([]models.BuildComponentWithDetails{board, m2Sata})
+
+		assert.Equal(t, "M2_2", report.Allocation["M2_2"].Slot)
+		assert.Len(t, report.Unallocated, 1)
+		assert.Equal(t, []string{storageRuleM2Oversubscribed}, issueIDs(report.Issues))
+	})
+
+	t.Run("Add-in cards skip the GPU slot", func(t *testing.T) {
+		aic := storagePart(models.CategoryInternalHDD, "70", "P5800X", `{"interface": "NVMe", "form_factor": "Add-in Card"}`, 2)
+		report := AllocateStorage([]models.BuildComponentWithDetails{board, aic, gpu})
+
+		assert.Contains(t, report.Allocation, "PCIEX16_2")
+		assert.Len(t, report.Unallocated, 1)
+		assert.Equal(t, []string{storageRulePCIeOversubscribed}, issueIDs(report.Issues))
+	})
+
+	t.Run("No motherboard", func(t *testing.T) {
+		report := AllocateStorage([]models.BuildComponentWithDetails{nvme(1)})
+
+		assert.Empty(t, report.Allocation)
+		assert.Len(t, report.Unallocated, 1)
+		assert.Equal(t, []string{storageRuleNoMotherboard}, issueIDs(report.Issues))
+	})
+
+	t.Run("Motherboard without slot specs", func(t *testing.T) {
+		bare := storagePart(models.CategoryMotherboard, "11", "Unknown", `{"socket": "AM5"}`, 1)
+		report := AllocateStorage([]models.BuildComponentWithDetails{bare, nvme(1)})
+
+		assert.Equal(t, []string{storageRuleSlotsUnknown}, issueIDs(report.Issues))
+	})
+
+	t.Run("No storage", func(t *testing.T) {
+		report := AllocateStorage([]models.BuildComponentWithDetails{board, gpu})
+
+		assert.Empty(t, report.Allocation)
+		assert.Empty(t, report.Issues)
+	})
+}
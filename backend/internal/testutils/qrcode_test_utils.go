@@ -0,0 +1,42 @@
+package testutils
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// Pixels drawn per module and light modules left around a code when it is rendered for scanning
+const (
+	qrScanScale     = 4
+	qrScanQuietZone = 4
+)
+
+// ScanQRCode renders a QR code of size modules, where dark reports the colour of each module, and reads it back
+// with the ZXing QR code reader, the way a phone camera would
+func ScanQRCode(size int, dark func(x, y int) bool) (string, error) {
+	side := (size + 2*qrScanQuietZone) * qrScanScale
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			mx, my := x/qrScanScale-qrScanQuietZone, y/qrScanScale-qrScanQuietZone
+			if mx >= 0 && my >= 0 && mx < size && my < size && dark(mx, my) {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", err
+	}
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, nil)
+	if err != nil {
+		return "", err
+	}
+	return result.GetText(), nil
+}
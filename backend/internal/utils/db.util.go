@@ -105,6 +105,15 @@ func GenerateSelectQuery(input models.GenerateSelectQueryInput) (string, error)
 		query += fmt.Sprintf(" WHERE %s", input.WhereClause)
 	}
 
+	if input.OrderBy != "" {
+		query += fmt.Sprintf(" ORDER BY %s", input.OrderBy)
+	}
+
+	if input.Unpaged {
+		Log(constants.DB_UTIL_GENERATE_SELECT_QUERY_SUCCESS, nil, input.Table)
+		return query, nil
+	}
+
 	if limitAndOffset.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d", limitAndOffset.Limit)
 	}
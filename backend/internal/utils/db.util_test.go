@@ -203,6 +203,29 @@ func TestGenerateSelectQuery(t *testing.T) {
 			},
 			expected: "SELECT id, category, brand, model FROM components WHERE category = 'gpu' AND brand = 'nvidia' LIMIT 50 OFFSET 50",
 		},
+		{
+			name: "unpaged query",
+			input: models.GenerateSelectQueryInput{
+				Table:       "components",
+				Columns:     []string{"id", "name"},
+				WhereClause: "category = 'cpu'",
+				OrderBy:     "id",
+				Page:        "2",
+				Unpaged:     true,
+			},
+			expected: "SELECT id, name FROM components WHERE category = 'cpu' ORDER BY id",
+		},
+		{
+			name: "query with order by",
+			input: models.GenerateSelectQueryInput{
+				Table:       "user_builds",
+				Columns:     []string{"id", "name"},
+				WhereClause: "is_public = true",
+				OrderBy:     "created_at DESC, id DESC",
+				Page:        "2",
+			},
+			expected: "SELECT id, name FROM user_builds WHERE is_public = true ORDER BY created_at DESC, id DESC LIMIT 50 OFFSET 50",
+		},
 		{
 			name: "query with page 0 (should default to page 1)",
 			input: models.GenerateSelectQueryInput{
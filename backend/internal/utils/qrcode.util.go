@@ -0,0 +1,502 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QR codes are encoded in byte mode at error correction level M, which restores up to 15% of a damaged code.
+// Versions 1 to 10 hold up to 213 bytes, plenty for a link.
+
+// qrBlocks describes the error correction blocks of a version at level M
+type qrBlocks struct {
+	ecPerBlock   int
+	group1Blocks int
+	group1Data   int
+	group2Blocks int
+	group2Data   int
+}
+
+var qrVersionBlocks = []qrBlocks{
+	1:  {10, 1, 16, 0, 0},
+	2:  {16, 1, 28, 0, 0},
+	3:  {26, 1, 44, 0, 0},
+	4:  {18, 2, 32, 0, 0},
+	5:  {24, 2, 43, 0, 0},
+	6:  {16, 4, 27, 0, 0},
+	7:  {18, 4, 31, 0, 0},
+	8:  {22, 2, 38, 2, 39},
+	9:  {22, 3, 36, 2, 37},
+	10: {26, 4, 43, 1, 44},
+}
+
+var qrAlignmentPositions = [][]int{
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+const (
+	qrMaxVersion = 10
+	qrQuietZone  = 4
+	// Format bits of error correction level M
+	qrLevelMBits = 0
+)
+
+// QRCode is a square grid of dark and light modules
+type QRCode struct {
+	Version  int
+	Size     int
+	Mask     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// EncodeQRCode encodes text in the smallest QR code version that holds it
+func EncodeQRCode(text string) (*QRCode, error) {
+	data := []byte(text)
+	version := 0
+	for v := 1; v <= qrMaxVersion; v++ {
+		if len(data) <= qrByteCapacity(v) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("text of %d bytes does not fit in a QR code of version %d", len(data), qrMaxVersion)
+	}
+
+	codewords := qrInterleave(version, qrDataCodewords(version, data))
+
+	qr := newQRCode(version)
+	qr.drawFunctionPatterns()
+	qr.drawCodewords(codewords)
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask)
+	}
+	qr.Mask = best
+	qr.applyMask(best)
+	qr.drawFormatBits(best)
+	return qr, nil
+}
+
+// Dark reports whether the module at column x and row y is dark
+func (q *QRCode) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < q.Size && y < q.Size && q.modules[y][x]
+}
+
+// SVG draws the code with a quiet zone as a scalable SVG image, one unit per module
+func (q *QRCode) SVG() string {
+	size := q.Size + 2*qrQuietZone
+	var path strings.Builder
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.modules[y][x] {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+qrQuietZone, y+qrQuietZone)
+			}
+		}
+	}
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`, size, size, size, size, path.String())
+}
+
+func qrTotalDataCodewords(version int) int {
+	blocks := qrVersionBlocks[version]
+	return blocks.group1Blocks*blocks.group1Data + blocks.group2Blocks*blocks.group2Data
+}
+
+func qrCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+func qrByteCapacity(version int) int {
+	return (qrTotalDataCodewords(version)*8 - 4 - qrCountBits(version)) / 8
+}
+
+// qrDataCodewords puts the byte mode header, data, terminator and padding into the data codewords of a version
+func qrDataCodewords(version int, data []byte) []byte {
+	capacity := qrTotalDataCodewords(version) * 8
+	bits := make([]bool, 0, capacity)
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, (value>>i)&1 == 1)
+		}
+	}
+
+	appendBits(0b0100, 4)
+	appendBits(len(data), qrCountBits(version))
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	appendBits(0, min(4, capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+
+	codewords := make([]byte, 0, capacity/8)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << (7 - j)
+			}
+		}
+		codewords = append(codewords, b)
+	}
+	for pad := byte(0xEC); len(codewords) < capacity/8; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+	return codewords
+}
+
+// qrInterleave splits data codewords into blocks, adds their error correction and interleaves the blocks
+func qrInterleave(version int, data []byte) []byte {
+	layout := qrVersionBlocks[version]
+	blocks := [][]byte{}
+	for i := 0; i < layout.group1Blocks; i++ {
+		blocks = append(blocks, data[:layout.group1Data])
+		data = data[layout.group1Data:]
+	}
+	for i := 0; i < layout.group2Blocks; i++ {
+		blocks = append(blocks, data[:layout.group2Data])
+		data = data[layout.group2Data:]
+	}
+
+	generator := qrGeneratorPolynomial(layout.ecPerBlock)
+	result := []byte{}
+	maxData := max(layout.group1Data, layout.group2Data)
+	for i := 0; i < maxData; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	ec := make([][]byte, len(blocks))
+	for i, block := range blocks {
+		ec[i] = qrErrorCorrection(block, generator)
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for _, block := range ec {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// qrMultiply multiplies in GF(256) with the QR reducing polynomial x^8 + x^4 + x^3 + x^2 + 1
+func qrMultiply(a, b byte) byte {
+	var product byte
+	for i := 7; i >= 0; i-- {
+		carry := product&0x80 != 0
+		product <<= 1
+		if carry {
+			product ^= 0x1D
+		}
+		if (b>>i)&1 == 1 {
+			product ^= a
+		}
+	}
+	return product
+}
+
+// qrGeneratorPolynomial returns the coefficients, highest power first without the leading 1, of the product of
+// (x - 2^i) for i below degree
+func qrGeneratorPolynomial(degree int) []byte {
+	coefficients := make([]byte, degree)
+	coefficients[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			coefficients[j] = qrMultiply(coefficients[j], root)
+			if j+1 < degree {
+				coefficients[j] ^= coefficients[j+1]
+			}
+		}
+		root = qrMultiply(root, 2)
+	}
+	return coefficients
+}
+
+// qrErrorCorrection returns the Reed-Solomon remainder of data divided by the generator polynomial
+func qrErrorCorrection(data, generator []byte) []byte {
+	remainder := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[len(remainder)-1] = 0
+		for i := range remainder {
+			remainder[i] ^= qrMultiply(generator[i], factor)
+		}
+	}
+	return remainder
+}
+
+func newQRCode(version int) *QRCode {
+	size := version*4 + 17
+	qr := &QRCode{Version: version, Size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range qr.modules {
+		qr.modules[i] = make([]bool, size)
+		qr.function[i] = make([]bool, size)
+	}
+	return qr
+}
+
+func (q *QRCode) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and version information, and reserves the
+// format information areas
+func (q *QRCode) drawFunctionPatterns() {
+	for i := 0; i < q.Size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	for _, corner := range [][2]int{{3, 3}, {q.Size - 4, 3}, {3, q.Size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || y < 0 || x >= q.Size || y >= q.Size {
+					continue
+				}
+				distance := max(abs(dx), abs(dy))
+				q.setFunction(x, y, distance != 2 && distance != 4)
+			}
+		}
+	}
+
+	if q.Version >= 2 {
+		positions := qrAlignmentPositions[q.Version]
+		last := len(positions) - 1
+		for i, cy := range positions {
+			for j, cx := range positions {
+				// Skip the three corners taken by finder patterns
+				if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+					continue
+				}
+				for dy := -2; dy <= 2; dy++ {
+					for dx := -2; dx <= 2; dx++ {
+						q.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+					}
+				}
+			}
+		}
+	}
+
+	q.drawFormatBits(0)
+	q.drawVersionBits()
+}
+
+// drawFormatBits draws both copies of the error correction level and mask, and the dark module
+func (q *QRCode) drawFormatBits(mask int) {
+	bits := qrFormatBits(mask)
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.Size-15+i, bit(i))
+	}
+	q.setFunction(8, q.Size-8, true)
+}
+
+// qrFormatBits returns the 15 format bits of level M and a mask, BCH encoded and masked
+func qrFormatBits(mask int) int {
+	data := qrLevelMBits<<3 | mask
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = remainder<<1 ^ (remainder>>9)*0x537
+	}
+	return (data<<10 | remainder) ^ 0x5412
+}
+
+// drawVersionBits draws both copies of the version information of versions 7 and up
+func (q *QRCode) drawVersionBits() {
+	if q.Version < 7 {
+		return
+	}
+	bits := qrVersionBits(q.Version)
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := q.Size-11+i%3, i/3
+		q.setFunction(a, b, dark)
+		q.setFunction(b, a, dark)
+	}
+}
+
+// qrVersionBits returns the 18 BCH encoded version bits
+func qrVersionBits(version int) int {
+	remainder := version
+	for i := 0; i < 12; i++ {
+		remainder = remainder<<1 ^ (remainder>>11)*0x1F25
+	}
+	return version<<12 | remainder
+}
+
+// drawCodewords places the codewords in the two-module wide columns that zigzag up and down from the bottom right
+func (q *QRCode) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vertical := 0; vertical < q.Size; vertical++ {
+			y := vertical
+			if upward {
+				y = q.Size - 1 - vertical
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if q.function[y][x] {
+					continue
+				}
+				if i < len(codewords)*8 {
+					q.modules[y][x] = (codewords[i>>3]>>(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by a mask pattern; applying it twice undoes it
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.function[y][x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard a masked code is to scan: long runs, 2x2 blocks, finder-like patterns and an uneven
+// share of dark modules all add to it
+func (q *QRCode) penalty() int {
+	penalty := 0
+	line := make([]bool, q.Size)
+	for _, horizontal := range []bool{true, false} {
+		for i := 0; i < q.Size; i++ {
+			for j := 0; j < q.Size; j++ {
+				if horizontal {
+					line[j] = q.modules[i][j]
+				} else {
+					line[j] = q.modules[j][i]
+				}
+			}
+			penalty += qrLinePenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.Size && y+1 < q.Size {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+	total := q.Size * q.Size
+	penalty += abs(dark*100/total-50) / 5 * 10
+	return penalty
+}
+
+var (
+	qrFinderBefore = []bool{false, false, false, false, true, false, true, true, true, false, true}
+	qrFinderAfter  = []bool{true, false, true, true, true, false, true, false, false, false, false}
+)
+
+func qrLinePenalty(line []bool) int {
+	penalty := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += run - 2
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(qrFinderBefore) <= len(line); i++ {
+		for _, pattern := range [][]bool{qrFinderBefore, qrFinderAfter} {
+			matches := true
+			for j, dark := range pattern {
+				if line[i+j] != dark {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				penalty += 40
+			}
+		}
+	}
+	return penalty
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRErrorCorrection(t *testing.T) {
+	// Version 1-M encoding of HELLO WORLD from the specification's worked example
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+
+	ec := qrErrorCorrection(data, qrGeneratorPolynomial(10))
+
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ec)
+}
+
+func TestQRFormatAndVersionBits(t *testing.T) {
+	assert.Equal(t, 0b101010000010010, qrFormatBits(0))
+	assert.Equal(t, 0b100010111111001, qrFormatBits(4))
+	assert.Equal(t, 0x07C94, qrVersionBits(7))
+	assert.Equal(t, 0x0A4D3, qrVersionBits(10))
+}
+
+func TestEncodeQRCode(t *testing.T) {
+	t.Run("Short link fits a small version", func(t *testing.T) {
+		qr, err := EncodeQRCode("https://shop.example.com/builds/42")
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, qr.Version)
+		assert.Equal(t, 29, qr.Size)
+
+		// Finder patterns in three corners, none in the bottom right
+		for _, corner := range [][2]int{{0, 0}, {qr.Size - 7, 0}, {0, qr.Size - 7}} {
+			assert.True(t, qr.Dark(corner[0], corner[1]))
+			assert.True(t, qr.Dark(corner[0]+3, corner[1]+3))
+			assert.False(t, qr.Dark(corner[0]+1, corner[1]+1))
+		}
+		// Alignment pattern centre and ring
+		assert.True(t, qr.Dark(22, 22))
+		assert.False(t, qr.Dark(21, 22))
+		assert.True(t, qr.Dark(20, 22))
+		// Timing pattern and dark module
+		assert.True(t, qr.Dark(8, 6))
+		assert.False(t, qr.Dark(9, 6))
+		assert.True(t, qr.Dark(8, qr.Size-8))
+	})
+
+	t.Run("Longer text picks a larger version", func(t *testing.T) {
+		qr, err := EncodeQRCode(strings.Repeat("a", 150))
+
+		require.NoError(t, err)
+		assert.Equal(t, 8, qr.Version)
+		assert.Equal(t, 49, qr.Size)
+	})
+
+	t.Run("Text too long", func(t *testing.T) {
+		_, err := EncodeQRCode(strings.Repeat("a", 214))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("SVG includes a quiet zone", func(t *testing.T) {
+		qr, err := EncodeQRCode("HELLO")
+		require.NoError(t, err)
+
+		svg := qr.SVG()
+
+		assert.True(t, strings.HasPrefix(svg, "<svg"))
+		assert.Contains(t, svg, `viewBox="0 0 29 29"`)
+		assert.Contains(t, svg, "M4,4h1v1h-1z")
+	})
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var leadingNumberPattern = regexp.MustCompile(`^-?[0-9]+(?:\.[0-9]+)?`)
+
+// ParseSpecs decodes a component specs document into a map.
+// Invalid or empty documents yield an empty map so callers can treat every key as missing.
+func ParseSpecs(raw json.RawMessage) map[string]interface{} {
+	specs := map[string]interface{}{}
+	if len(raw) == 0 {
+		return specs
+	}
+	if err := json.Unmarshal(raw, &specs); err != nil || specs == nil {
+		return map[string]interface{}{}
+	}
+	return specs
+}
+
+// GetSpecValue returns the value stored under the first key present in specs
+func GetSpecValue(specs map[string]interface{}, keys ...string) (interface{}, bool) {
+	for _, key := range keys {
+		if value, ok := specs[key]; ok && value != nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// GetSpecString returns a spec value formatted as a trimmed string
+func GetSpecString(specs map[string]interface{}, keys ...string) (string, bool) {
+	value, ok := GetSpecValue(specs, keys...)
+	if !ok {
+		return "", false
+	}
+	return SpecValueToString(value)
+}
+
+// GetSpecNumber returns a spec value as a number, accepting strings such as "320 mm" or "3.6 GHz"
+func GetSpecNumber(specs map[string]interface{}, keys ...string) (float64, bool) {
+	value, ok := GetSpecValue(specs, keys...)
+	if !ok {
+		return 0, false
+	}
+	return SpecValueToNumber(value)
+}
+
+// GetSpecStringList returns a spec value as a list of strings; a scalar is treated as a list of one
+func GetSpecStringList(specs map[string]interface{}, keys ...string) ([]string, bool) {
+	value, ok := GetSpecValue(specs, keys...)
+	if !ok {
+		return nil, false
+	}
+	return SpecValueToStringList(value)
+}
+
+// GetSpecBool returns a spec value as a boolean, accepting "yes"/"no" style strings
+func GetSpecBool(specs map[string]interface{}, keys ...string) (bool, bool) {
+	value, ok := GetSpecValue(specs, keys...)
+	if !ok {
+		return false, false
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case float64:
+		return v != 0, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "yes", "y", "1":
+			return true, true
+		case "false", "no", "n", "0":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// SpecValueToString formats a scalar spec value as a string
+func SpecValueToString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// SpecValueToNumber converts a spec value to a number using its leading numeric part
+func SpecValueToNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		match := leadingNumberPattern.FindString(strings.TrimSpace(v))
+		if match == "" {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(match, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// SpecValueToStringList converts a spec value to a list of strings
+func SpecValueToStringList(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := SpecValueToString(item); ok {
+				list = append(list, s)
+			}
+		}
+		return list, true
+	case []string:
+		return v, true
+	default:
+		if s, ok := SpecValueToString(v); ok {
+			return []string{s}, true
+		}
+	}
+	return nil, false
+}
+
+// SpecValuesEqual compares two spec values case-insensitively
+func SpecValuesEqual(a, b interface{}) bool {
+	left, ok := SpecValueToString(a)
+	if !ok {
+		left = fmt.Sprint(a)
+	}
+	right, ok := SpecValueToString(b)
+	if !ok {
+		right = fmt.Sprint(b)
+	}
+	return strings.EqualFold(left, right)
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSpecs(t *testing.T) {
+	t.Run("Valid document", func(t *testing.T) {
+		specs := ParseSpecs(json.RawMessage(`{"socket": "AM5", "cores": 8}`))
+		assert.Equal(t, "AM5", specs["socket"])
+		assert.Equal(t, 8.0, specs["cores"])
+	})
+
+	t.Run("Invalid document yields empty map", func(t *testing.T) {
+		assert.Empty(t, ParseSpecs(json.RawMessage(`not json`)))
+		assert.Empty(t, ParseSpecs(nil))
+		assert.Empty(t, ParseSpecs(json.RawMessage(`null`)))
+	})
+}
+
+func TestGetSpecHelpers(t *testing.T) {
+	specs := ParseSpecs(json.RawMessage(`{
+		"type": "DDR4",
+		"base_clock": "3.6 GHz",
+		"wattage": 850,
+		"supported_sockets": ["AM4", "AM5"],
+		"integrated_graphics": "yes",
+		"empty": null
+	}`))
+
+	t.Run("First present key wins", func(t *testing.T) {
+		value, ok := GetSpecString(specs, "memory_type", "type")
+		assert.True(t, ok)
+		assert.Equal(t, "DDR4", value)
+	})
+
+	t.Run("Null values are missing", func(t *testing.T) {
+		_, ok := GetSpecValue(specs, "empty")
+		assert.False(t, ok)
+	})
+
+	t.Run("Numbers with units", func(t *testing.T) {
+		value, ok := GetSpecNumber(specs, "base_clock")
+		assert.True(t, ok)
+		assert.Equal(t, 3.6, value)
+
+		value, ok = GetSpecNumber(specs, "wattage")
+		assert.True(t, ok)
+		assert.Equal(t, 850.0, value)
+
+		_, ok = GetSpecNumber(specs, "type")
+		assert.False(t, ok)
+	})
+
+	t.Run("String lists", func(t *testing.T) {
+		value, ok := GetSpecStringList(specs, "supported_sockets")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"AM4", "AM5"}, value)
+
+		value, ok = GetSpecStringList(specs, "type")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"DDR4"}, value)
+	})
+
+	t.Run("Booleans", func(t *testing.T) {
+		value, ok := GetSpecBool(specs, "integrated_graphics")
+		assert.True(t, ok)
+		assert.True(t, value)
+
+		_, ok = GetSpecBool(specs, "type")
+		assert.False(t, ok)
+	})
+}
+
+func TestSpecValuesEqual(t *testing.T) {
+	assert.True(t, SpecValuesEqual("AM5", "am5"))
+	assert.True(t, SpecValuesEqual(8.0, "8"))
+	assert.False(t, SpecValuesEqual("AM4", "AM5"))
+}